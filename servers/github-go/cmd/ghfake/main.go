@@ -0,0 +1,76 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/github/github-mcp-server/internal/ghfake"
+	"github.com/spf13/cobra"
+)
+
+var (
+	address  string
+	fixtures []string
+
+	rootCmd = &cobra.Command{
+		Use:   "ghfake",
+		Short: "Serve a fake GitHub API",
+		Long: `Serve an in-memory fake of the GitHub REST and GraphQL APIs, seeded from fixture files, for
+offline demos and tests. Point the MCP server at it with --gh-host http://<address>. Any token
+is accepted unless the fixtures list tokens, and all changes are lost when the fake stops.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			var loaded []*ghfake.Fixture
+			for _, path := range fixtures {
+				fixture, err := ghfake.LoadFixture(path)
+				if err != nil {
+					return err
+				}
+				loaded = append(loaded, fixture)
+			}
+			fake, err := ghfake.New(loaded...)
+			if err != nil {
+				return fmt.Errorf("failed to seed the fake: %w", err)
+			}
+
+			listener, err := net.Listen("tcp", address)
+			if err != nil {
+				return fmt.Errorf("failed to listen: %w", err)
+			}
+			httpServer := &http.Server{
+				Handler:           fake,
+				ReadHeaderTimeout: 10 * time.Second,
+			}
+
+			ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+			defer stop()
+			go func() {
+				<-ctx.Done()
+				_ = httpServer.Shutdown(context.Background())
+			}()
+
+			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Fake GitHub listening, use --gh-host http://%s\n", listener.Addr())
+			if err := httpServer.Serve(listener); !errors.Is(err, http.ErrServerClosed) {
+				return err
+			}
+			return nil
+		},
+	}
+)
+
+func main() {
+	rootCmd.Flags().StringVar(&address, "address", "localhost:8080", "Address to listen on")
+	rootCmd.Flags().StringArrayVar(&fixtures, "fixtures", nil, "JSON fixture file to seed the fake with, can be repeated")
+	rootCmd.SilenceUsage = true
+
+	if err := rootCmd.Execute(); err != nil {
+		os.Exit(1)
+	}
+}
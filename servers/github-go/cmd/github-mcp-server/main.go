@@ -4,9 +4,14 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"strings"
 
 	"github.com/github/github-mcp-server/internal/ghmcp"
+	"github.com/github/github-mcp-server/pkg/audit"
 	"github.com/github/github-mcp-server/pkg/github"
+	mcplog "github.com/github/github-mcp-server/pkg/log"
+	"github.com/github/github-mcp-server/pkg/toolsets"
+	"github.com/github/github-mcp-server/pkg/translations"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 )
@@ -29,9 +34,25 @@ var (
 		Short: "Start stdio server",
 		Long:  `Start a server that communicates via standard input/output streams using JSON-RPC messages.`,
 		RunE: func(_ *cobra.Command, _ []string) error {
-			// This is the correct place to check for the token
+			appConfig, err := appAuthConfig()
+			if err != nil {
+				return err
+			}
+
+			accounts, err := accountConfigs()
+			if err != nil {
+				return err
+			}
+
+			locales, err := localeConfig()
+			if err != nil {
+				return err
+			}
+
+			// This is the correct place to check for the token. It may be left out if the
+			// server only acts as named accounts, each of which has a token of its own.
 			token := os.Getenv("GITHUB_PERSONAL_ACCESS_TOKEN")
-			if token == "" {
+			if token == "" && appConfig == nil && len(accounts) == 0 {
 				return fmt.Errorf("GITHUB_PERSONAL_ACCESS_TOKEN not set in environment")
 			}
 
@@ -44,40 +65,267 @@ var (
 				Version:              version,
 				Host:                 viper.GetString("host"),
 				Token:                token,
+				App:                  appConfig,
+				Accounts:             accounts,
+				RateLimit:            rateLimitConfig(),
+				Cache:                cacheConfig(),
+				Audit:                auditConfig(),
+				MetricsAddress:       viper.GetString("metrics_address"),
+				Tracing:              tracingConfig(),
 				EnabledToolsets:      enabledToolsets,
 				DynamicToolsets:      viper.GetBool("dynamic_toolsets"),
 				ReadOnly:             viper.GetBool("read-only"),
+				DryRun:               viper.GetBool("dry_run"),
+				Confirmation:         confirmationConfig(),
+				Tools:                toolFilter(),
+				Scope:                repositoryScope(),
+				ResponseBudget:       responseBudgetConfig(),
+				ToolDescriptions:     viper.GetStringMapString("tool_descriptions"),
+				Locales:              locales,
 				ExportTranslations:   viper.GetBool("export-translations"),
 				EnableCommandLogging: viper.GetBool("enable-command-logging"),
 				LogFilePath:          viper.GetString("log-file"),
+				Redaction:            redactionConfig(),
 			}
 
 			return ghmcp.RunStdioServer(stdioServerConfig)
 		},
 	}
+
+	httpCmd = &cobra.Command{
+		Use:   "http",
+		Short: "Start streamable HTTP server",
+		Long:  `Start a server that communicates via MCP streamable HTTP at /mcp, with an SSE fallback at /sse for older clients and a health check at /health.`,
+		RunE: func(_ *cobra.Command, _ []string) error {
+			appConfig, err := appAuthConfig()
+			if err != nil {
+				return err
+			}
+
+			accounts, err := accountConfigs()
+			if err != nil {
+				return err
+			}
+
+			locales, err := localeConfig()
+			if err != nil {
+				return err
+			}
+
+			// The token is optional here, clients bring their own in the Authorization header.
+			// Requests without one are only served with it given --allow-unauthenticated.
+			token := os.Getenv("GITHUB_PERSONAL_ACCESS_TOKEN")
+
+			var enabledToolsets []string
+			if err := viper.UnmarshalKey("toolsets", &enabledToolsets); err != nil {
+				return fmt.Errorf("failed to unmarshal toolsets: %w", err)
+			}
+
+			httpServerConfig := ghmcp.HTTPServerConfig{
+				Version:              version,
+				Host:                 viper.GetString("host"),
+				Token:                token,
+				App:                  appConfig,
+				AllowUnauthenticated: viper.GetBool("http_allow_unauthenticated"),
+				Accounts:             accounts,
+				RateLimit:            rateLimitConfig(),
+				Cache:                cacheConfig(),
+				Audit:                auditConfig(),
+				MetricsAddress:       viper.GetString("metrics_address"),
+				Tracing:              tracingConfig(),
+				EnabledToolsets:      enabledToolsets,
+				DynamicToolsets:      viper.GetBool("dynamic_toolsets"),
+				ReadOnly:             viper.GetBool("read-only"),
+				DryRun:               viper.GetBool("dry_run"),
+				Confirmation:         confirmationConfig(),
+				Tools:                toolFilter(),
+				Scope:                repositoryScope(),
+				ResponseBudget:       responseBudgetConfig(),
+				ToolDescriptions:     viper.GetStringMapString("tool_descriptions"),
+				Locales:              locales,
+				ExportTranslations:   viper.GetBool("export-translations"),
+				LogFilePath:          viper.GetString("log-file"),
+				Redaction:            redactionConfig(),
+				Address:              viper.GetString("http_address"),
+				ShutdownTimeout:      viper.GetDuration("http_shutdown_timeout"),
+			}
+
+			return ghmcp.RunHTTPServer(httpServerConfig)
+		},
+	}
+
+	verifyAuditLogCmd = &cobra.Command{
+		Use:   "verify-audit-log <file>",
+		Short: "Verify the hash chain of an audit log",
+		Long:  `Check that no record of an audit log written with --audit-log-hash-chain has been changed, removed or reordered.`,
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			file, err := os.Open(args[0]) //nolint:gosec // the path is provided by the operator
+			if err != nil {
+				return fmt.Errorf("failed to open audit log: %w", err)
+			}
+			defer func() { _ = file.Close() }()
+
+			count, err := audit.Verify(file)
+			if err != nil {
+				return err
+			}
+			cmd.Printf("%d records verified\n", count)
+			return nil
+		},
+	}
+
+	exportTranslationsCmd = &cobra.Command{
+		Use:   "export-translations [locale]",
+		Short: "Export a template of the translatable texts",
+		Long:  `Write a JSON template with every translatable text of the tools and resources. Given a locale, the texts its file in --locales-dir already translates are kept, and the keys that are missing from the file or no longer used are reported.`,
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			defaults, err := ghmcp.TranslationKeys()
+			if err != nil {
+				return fmt.Errorf("failed to collect translation keys: %w", err)
+			}
+
+			locale := translations.DefaultLocale
+			var locales *translations.Locales
+			if len(args) == 1 {
+				locale = args[0]
+				if dir := viper.GetString("locales_dir"); dir != "" {
+					if locales, err = translations.LoadLocales(dir); err != nil {
+						return err
+					}
+				}
+			}
+
+			template, missing, stale := locales.Template(locale, defaults)
+			data, err := json.MarshalIndent(template, "", "  ")
+			if err != nil {
+				return fmt.Errorf("failed to marshal translations: %w", err)
+			}
+			data = append(data, '\n')
+
+			output, _ := cmd.Flags().GetString("output")
+			if output == "" {
+				_, err = cmd.OutOrStdout().Write(data)
+			} else {
+				err = os.WriteFile(output, data, 0600)
+			}
+			if err != nil {
+				return fmt.Errorf("failed to write translations: %w", err)
+			}
+
+			if len(args) == 1 {
+				reportKeys(cmd, fmt.Sprintf("missing from %s", locale), missing)
+				reportKeys(cmd, fmt.Sprintf("no longer used by %s", locale), stale)
+			}
+			return nil
+		},
+	}
 )
 
 func init() {
 	cobra.OnInitialize(initConfig)
 
 	rootCmd.SetVersionTemplate("{{.Short}}\n{{.Version}}\n")
+	rootCmd.PersistentFlags().String("config", "", "Path to a YAML or JSON configuration file, see the README for its format")
 	rootCmd.PersistentFlags().StringSlice("toolsets", github.DefaultTools, "An optional comma separated list of groups of tools to allow, defaults to enabling all")
 	rootCmd.PersistentFlags().Bool("dynamic-toolsets", false, "Enable dynamic toolsets")
 	rootCmd.PersistentFlags().Bool("read-only", false, "Restrict the server to read-only operations")
+	rootCmd.PersistentFlags().Bool("dry-run", false, "Have write tools return the GitHub API requests they would send instead of sending them")
+	rootCmd.PersistentFlags().Bool("confirm-destructive-tools", false, "Ask the user to confirm every call of a destructive tool, such as merge_pull_request or delete_file")
+	rootCmd.PersistentFlags().String("confirm-fallback", ghmcp.ConfirmationFallbackDeny, "What to do with destructive tool calls from clients that can't ask for confirmation, \"deny\" or \"allow\" and mark them as unconfirmed in the audit log")
+	rootCmd.PersistentFlags().StringSlice("allowed-tools", nil, "An optional comma separated list of the only tools to offer from the enabled toolsets")
+	rootCmd.PersistentFlags().StringSlice("denied-tools", nil, "An optional comma separated list of tools never to offer, even from enabled toolsets")
+	rootCmd.PersistentFlags().StringSlice("allowed-repos", nil, "An optional comma separated list of owner/repo glob patterns of the only repositories tools can touch")
+	rootCmd.PersistentFlags().StringSlice("denied-repos", nil, "An optional comma separated list of owner/repo glob patterns of repositories tools can never touch")
+	rootCmd.PersistentFlags().StringSlice("allowed-read-repos", nil, "Like --allowed-repos, but only for read-only tools and resources")
+	rootCmd.PersistentFlags().StringSlice("denied-read-repos", nil, "Like --denied-repos, but only for read-only tools and resources")
+	rootCmd.PersistentFlags().StringSlice("allowed-write-repos", nil, "Like --allowed-repos, but only for write tools")
+	rootCmd.PersistentFlags().StringSlice("denied-write-repos", nil, "Like --denied-repos, but only for write tools")
+	rootCmd.PersistentFlags().Int("response-budget", 0, "Split tool results larger than this many bytes into parts that are fetched with continue_result, disabled if 0")
+	rootCmd.PersistentFlags().Duration("response-budget-ttl", ghmcp.DefaultResponseBudgetTTL, "How long the remaining parts of a split tool result can be fetched")
 	rootCmd.PersistentFlags().String("log-file", "", "Path to log file")
 	rootCmd.PersistentFlags().Bool("enable-command-logging", false, "When enabled, the server will log all command requests and responses to the log file")
+	rootCmd.PersistentFlags().String("metrics-address", "", "Address to serve Prometheus metrics on at /metrics, e.g. \"127.0.0.1:9090\", disabled if empty")
+	rootCmd.PersistentFlags().String("trace-exporter", "", "Export a trace of every tool call, \"otlp\" for OTLP/HTTP or \"file\" for a local JSON file, disabled if empty")
+	rootCmd.PersistentFlags().String("trace-endpoint", "", "OTLP/HTTP endpoint URL to export traces to, defaults to the OTEL_EXPORTER_OTLP_* environment variables")
+	rootCmd.PersistentFlags().String("trace-file", "", "Path to append spans to with the file trace exporter")
+	rootCmd.PersistentFlags().StringSlice("log-redact-patterns", nil, "Additional regular expressions whose matches are redacted from log output")
+	rootCmd.PersistentFlags().Float64("log-redact-entropy-threshold", mcplog.DefaultEntropyThreshold, "Redact long tokens whose entropy in bits per character is above this from log output, negative to disable")
 	rootCmd.PersistentFlags().Bool("export-translations", false, "Save translations to a JSON file")
+	rootCmd.PersistentFlags().String("locale", "", "Locale to describe tools in, for sessions of clients without a locale in client_locales, from a file in --locales-dir")
+	rootCmd.PersistentFlags().String("locales-dir", "", "Directory with a JSON file of translations per locale, e.g. fr.json")
 	rootCmd.PersistentFlags().String("gh-host", "", "Specify the GitHub hostname (for GitHub Enterprise etc.)")
+	rootCmd.PersistentFlags().StringSlice("accounts", nil, "An optional comma separated list of named accounts, each authenticated with GITHUB_PERSONAL_ACCESS_TOKEN_<NAME>")
+	rootCmd.PersistentFlags().Int64("app-id", 0, "Authenticate as the GitHub App with this ID instead of with a personal access token")
+	rootCmd.PersistentFlags().String("app-private-key-file", "", "Path to the PEM encoded private key of the GitHub App")
+	rootCmd.PersistentFlags().Int64("app-installation-id", 0, "GitHub App installation to use for tools without an owner argument")
+	rootCmd.PersistentFlags().Int("rate-limit-max-retries", ghmcp.DefaultRateLimitMaxRetries, "How many times to retry a request that hit a GitHub API rate limit, 0 disables retries")
+	rootCmd.PersistentFlags().Duration("rate-limit-max-wait", ghmcp.DefaultRateLimitMaxWait, "The longest a single request may wait on GitHub API rate limits before giving up")
+	rootCmd.PersistentFlags().Bool("cache", false, "Cache REST API responses and revalidate them with conditional requests, which don't count against the rate limit")
+	rootCmd.PersistentFlags().Int64("cache-max-bytes", ghmcp.DefaultCacheMaxBytes, "Size limit of the response cache in bytes")
+	rootCmd.PersistentFlags().Duration("cache-ttl", ghmcp.DefaultCacheTTL, "How long cached responses are kept, 0 keeps them until evicted")
+	rootCmd.PersistentFlags().String("cache-dir", "", "Directory to persist the response cache in across restarts, kept in memory only if empty")
+	rootCmd.PersistentFlags().String("audit-log", "", "Path to a JSONL file to append a record of every write tool call to")
+	rootCmd.PersistentFlags().Bool("audit-log-hash-chain", false, "Chain audit records together with hashes, so that tampering can be detected with verify-audit-log")
 
+	_ = viper.BindPFlag("config", rootCmd.PersistentFlags().Lookup("config"))
 	_ = viper.BindPFlag("toolsets", rootCmd.PersistentFlags().Lookup("toolsets"))
 	_ = viper.BindPFlag("dynamic_toolsets", rootCmd.PersistentFlags().Lookup("dynamic-toolsets"))
 	_ = viper.BindPFlag("read-only", rootCmd.PersistentFlags().Lookup("read-only"))
+	_ = viper.BindPFlag("dry_run", rootCmd.PersistentFlags().Lookup("dry-run"))
+	_ = viper.BindPFlag("confirm_destructive_tools", rootCmd.PersistentFlags().Lookup("confirm-destructive-tools"))
+	_ = viper.BindPFlag("confirm_fallback", rootCmd.PersistentFlags().Lookup("confirm-fallback"))
+	_ = viper.BindPFlag("allowed_tools", rootCmd.PersistentFlags().Lookup("allowed-tools"))
+	_ = viper.BindPFlag("denied_tools", rootCmd.PersistentFlags().Lookup("denied-tools"))
+	_ = viper.BindPFlag("allowed_repos", rootCmd.PersistentFlags().Lookup("allowed-repos"))
+	_ = viper.BindPFlag("denied_repos", rootCmd.PersistentFlags().Lookup("denied-repos"))
+	_ = viper.BindPFlag("allowed_read_repos", rootCmd.PersistentFlags().Lookup("allowed-read-repos"))
+	_ = viper.BindPFlag("denied_read_repos", rootCmd.PersistentFlags().Lookup("denied-read-repos"))
+	_ = viper.BindPFlag("allowed_write_repos", rootCmd.PersistentFlags().Lookup("allowed-write-repos"))
+	_ = viper.BindPFlag("denied_write_repos", rootCmd.PersistentFlags().Lookup("denied-write-repos"))
+	_ = viper.BindPFlag("response_budget", rootCmd.PersistentFlags().Lookup("response-budget"))
+	_ = viper.BindPFlag("response_budget_ttl", rootCmd.PersistentFlags().Lookup("response-budget-ttl"))
 	_ = viper.BindPFlag("log-file", rootCmd.PersistentFlags().Lookup("log-file"))
 	_ = viper.BindPFlag("enable-command-logging", rootCmd.PersistentFlags().Lookup("enable-command-logging"))
+	_ = viper.BindPFlag("metrics_address", rootCmd.PersistentFlags().Lookup("metrics-address"))
+	_ = viper.BindPFlag("trace_exporter", rootCmd.PersistentFlags().Lookup("trace-exporter"))
+	_ = viper.BindPFlag("trace_endpoint", rootCmd.PersistentFlags().Lookup("trace-endpoint"))
+	_ = viper.BindPFlag("trace_file", rootCmd.PersistentFlags().Lookup("trace-file"))
+	_ = viper.BindPFlag("log_redact_patterns", rootCmd.PersistentFlags().Lookup("log-redact-patterns"))
+	_ = viper.BindPFlag("log_redact_entropy_threshold", rootCmd.PersistentFlags().Lookup("log-redact-entropy-threshold"))
 	_ = viper.BindPFlag("export-translations", rootCmd.PersistentFlags().Lookup("export-translations"))
+	_ = viper.BindPFlag("locale", rootCmd.PersistentFlags().Lookup("locale"))
+	_ = viper.BindPFlag("locales_dir", rootCmd.PersistentFlags().Lookup("locales-dir"))
+	_ = rootCmd.PersistentFlags().MarkDeprecated("export-translations", "use the export-translations command instead")
 	_ = viper.BindPFlag("host", rootCmd.PersistentFlags().Lookup("gh-host"))
+	_ = viper.BindPFlag("accounts", rootCmd.PersistentFlags().Lookup("accounts"))
+	_ = viper.BindPFlag("app_id", rootCmd.PersistentFlags().Lookup("app-id"))
+	_ = viper.BindPFlag("app_private_key_file", rootCmd.PersistentFlags().Lookup("app-private-key-file"))
+	_ = viper.BindPFlag("app_installation_id", rootCmd.PersistentFlags().Lookup("app-installation-id"))
+	_ = viper.BindPFlag("rate_limit_max_retries", rootCmd.PersistentFlags().Lookup("rate-limit-max-retries"))
+	_ = viper.BindPFlag("rate_limit_max_wait", rootCmd.PersistentFlags().Lookup("rate-limit-max-wait"))
+	_ = viper.BindPFlag("cache", rootCmd.PersistentFlags().Lookup("cache"))
+	_ = viper.BindPFlag("cache_max_bytes", rootCmd.PersistentFlags().Lookup("cache-max-bytes"))
+	_ = viper.BindPFlag("cache_ttl", rootCmd.PersistentFlags().Lookup("cache-ttl"))
+	_ = viper.BindPFlag("cache_dir", rootCmd.PersistentFlags().Lookup("cache-dir"))
+	_ = viper.BindPFlag("audit_log", rootCmd.PersistentFlags().Lookup("audit-log"))
+	_ = viper.BindPFlag("audit_log_hash_chain", rootCmd.PersistentFlags().Lookup("audit-log-hash-chain"))
+
+	httpCmd.Flags().String("address", ghmcp.DefaultHTTPAddress, "Address to listen on for HTTP connections")
+	httpCmd.Flags().Duration("shutdown-timeout", ghmcp.DefaultShutdownTimeout, "How long to wait for in-flight requests to complete when shutting down")
+	httpCmd.Flags().Bool("allow-unauthenticated", false, "Serve requests without an Authorization header with GITHUB_PERSONAL_ACCESS_TOKEN or the GitHub App")
+
+	_ = viper.BindPFlag("http_address", httpCmd.Flags().Lookup("address"))
+	_ = viper.BindPFlag("http_shutdown_timeout", httpCmd.Flags().Lookup("shutdown-timeout"))
+	_ = viper.BindPFlag("http_allow_unauthenticated", httpCmd.Flags().Lookup("allow-unauthenticated"))
+
+	exportTranslationsCmd.Flags().StringP("output", "o", "", "Path to write the template to, stdout if empty")
 
 	rootCmd.AddCommand(stdioCmd)
+	rootCmd.AddCommand(httpCmd)
+	rootCmd.AddCommand(verifyAuditLogCmd)
+	rootCmd.AddCommand(exportTranslationsCmd)
 }
 
 func initConfig() {
@@ -92,6 +340,15 @@ func initConfig() {
 		}
 	}
 
+	// The configuration file is merged over the .env file, and flags and environment
+	// variables take precedence over both.
+	if configFile := viper.GetString("config"); configFile != "" {
+		viper.SetConfigFile(configFile)
+		if err := viper.MergeInConfig(); err != nil {
+			sendErrorAndExit("Failed to read configuration file", err)
+		}
+	}
+
 	token := os.Getenv("GITHUB_PERSONAL_ACCESS_TOKEN")
 	if token == "" {
 		token = viper.GetString("GITHUB_PERSONAL_ACCESS_TOKEN")
@@ -102,6 +359,211 @@ func initConfig() {
 	}
 }
 
+// accountConfigs returns the named accounts listed in --accounts. Following the alias
+// convention used for the .env file, the token for an account called "work" is read from
+// GITHUB_PERSONAL_ACCESS_TOKEN_WORK, and its host, if different, from GITHUB_HOST_WORK.
+func accountConfigs() ([]ghmcp.AccountConfig, error) {
+	var names []string
+	if err := viper.UnmarshalKey("accounts", &names); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal accounts: %w", err)
+	}
+
+	accounts := make([]ghmcp.AccountConfig, 0, len(names))
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+
+		suffix := strings.ToUpper(strings.ReplaceAll(name, "-", "_"))
+		token := os.Getenv("GITHUB_PERSONAL_ACCESS_TOKEN_" + suffix)
+		if token == "" {
+			token = viper.GetString("GITHUB_PERSONAL_ACCESS_TOKEN_" + suffix)
+		}
+		if token == "" {
+			return nil, fmt.Errorf("GITHUB_PERSONAL_ACCESS_TOKEN_%s not set in environment for account %s", suffix, name)
+		}
+
+		host := os.Getenv("GITHUB_HOST_" + suffix)
+		if host == "" {
+			host = viper.GetString("host")
+		}
+
+		accounts = append(accounts, ghmcp.AccountConfig{
+			Name:  name,
+			Token: token,
+			Host:  host,
+		})
+	}
+
+	return accounts, nil
+}
+
+// appAuthConfig returns the GitHub App configuration, or nil if no app ID is configured.
+// The private key is read from --app-private-key-file, or from the GITHUB_APP_PRIVATE_KEY
+// environment variable, which is more convenient when running in a container.
+func appAuthConfig() (*ghmcp.AppAuthConfig, error) {
+	appID := viper.GetInt64("app_id")
+	if appID == 0 {
+		return nil, nil
+	}
+
+	var privateKey []byte
+	if path := viper.GetString("app_private_key_file"); path != "" {
+		data, err := os.ReadFile(path) //nolint:gosec // the path is provided by the operator
+		if err != nil {
+			return nil, fmt.Errorf("failed to read GitHub App private key: %w", err)
+		}
+		privateKey = data
+	} else if key := viper.GetString("app_private_key"); key != "" {
+		privateKey = []byte(key)
+	} else {
+		return nil, fmt.Errorf("a GitHub App private key is required when --app-id is set")
+	}
+
+	return &ghmcp.AppAuthConfig{
+		AppID:          appID,
+		PrivateKey:     privateKey,
+		InstallationID: viper.GetInt64("app_installation_id"),
+	}, nil
+}
+
+func rateLimitConfig() ghmcp.RateLimitConfig {
+	return ghmcp.RateLimitConfig{
+		MaxRetries: viper.GetInt("rate_limit_max_retries"),
+		MaxWait:    viper.GetDuration("rate_limit_max_wait"),
+	}
+}
+
+// toolFilter returns the individually allowed and denied tools.
+func toolFilter() toolsets.ToolFilter {
+	return toolsets.ToolFilter{
+		Allowed: viper.GetStringSlice("allowed_tools"),
+		Denied:  viper.GetStringSlice("denied_tools"),
+	}
+}
+
+// repositoryScope returns the repositories tools and resources are restricted to, or nil if
+// they are not restricted.
+func repositoryScope() *ghmcp.RepositoryScope {
+	scope := &ghmcp.RepositoryScope{
+		All: ghmcp.RepositoryRules{
+			Allow: viper.GetStringSlice("allowed_repos"),
+			Deny:  viper.GetStringSlice("denied_repos"),
+		},
+		Read: ghmcp.RepositoryRules{
+			Allow: viper.GetStringSlice("allowed_read_repos"),
+			Deny:  viper.GetStringSlice("denied_read_repos"),
+		},
+		Write: ghmcp.RepositoryRules{
+			Allow: viper.GetStringSlice("allowed_write_repos"),
+			Deny:  viper.GetStringSlice("denied_write_repos"),
+		},
+	}
+	if !scope.Restricts() {
+		return nil
+	}
+	return scope
+}
+
+// responseBudgetConfig returns the response budget, or nil if tool results are not split.
+func responseBudgetConfig() *ghmcp.ResponseBudgetConfig {
+	maxBytes := viper.GetInt("response_budget")
+	if maxBytes == 0 {
+		return nil
+	}
+	return &ghmcp.ResponseBudgetConfig{
+		MaxBytes: maxBytes,
+		TTL:      viper.GetDuration("response_budget_ttl"),
+	}
+}
+
+// localeConfig returns the locale configuration, or nil if tools are only described with the
+// built-in texts. Clients are given their locale by name under client_locales in the
+// configuration file.
+func localeConfig() (*ghmcp.LocaleConfig, error) {
+	dir := viper.GetString("locales_dir")
+	locale := viper.GetString("locale")
+	if dir == "" {
+		if locale != "" && locale != translations.DefaultLocale {
+			return nil, fmt.Errorf("--locale requires --locales-dir")
+		}
+		return nil, nil
+	}
+	return &ghmcp.LocaleConfig{
+		Dir:     dir,
+		Default: locale,
+		Clients: viper.GetStringMapString("client_locales"),
+	}, nil
+}
+
+// reportKeys lists translation keys on stderr, under a heading that says what they are.
+func reportKeys(cmd *cobra.Command, what string, keys []string) {
+	if len(keys) == 0 {
+		return
+	}
+	fmt.Fprintf(cmd.ErrOrStderr(), "%d keys %s:\n", len(keys), what)
+	for _, key := range keys {
+		fmt.Fprintf(cmd.ErrOrStderr(), "  %s\n", key)
+	}
+}
+
+// cacheConfig returns the response cache configuration, or nil if caching is disabled.
+func cacheConfig() *ghmcp.CacheConfig {
+	if !viper.GetBool("cache") {
+		return nil
+	}
+	return &ghmcp.CacheConfig{
+		MaxBytes: viper.GetInt64("cache_max_bytes"),
+		TTL:      viper.GetDuration("cache_ttl"),
+		Dir:      viper.GetString("cache_dir"),
+	}
+}
+
+// redactionConfig returns how secrets are redacted from log output.
+func redactionConfig() mcplog.RedactionConfig {
+	return mcplog.RedactionConfig{
+		Patterns:         viper.GetStringSlice("log_redact_patterns"),
+		EntropyThreshold: viper.GetFloat64("log_redact_entropy_threshold"),
+	}
+}
+
+// tracingConfig returns the trace export configuration, or nil if tracing is disabled.
+func tracingConfig() *ghmcp.TracingConfig {
+	exporter := viper.GetString("trace_exporter")
+	if exporter == "" {
+		return nil
+	}
+	return &ghmcp.TracingConfig{
+		Exporter: exporter,
+		Endpoint: viper.GetString("trace_endpoint"),
+		File:     viper.GetString("trace_file"),
+	}
+}
+
+// confirmationConfig returns the confirmation policy for destructive tools, or nil if they
+// don't need to be confirmed.
+func confirmationConfig() *ghmcp.ConfirmationConfig {
+	if !viper.GetBool("confirm_destructive_tools") {
+		return nil
+	}
+	return &ghmcp.ConfirmationConfig{
+		Fallback: viper.GetString("confirm_fallback"),
+	}
+}
+
+// auditConfig returns the audit log configuration, or nil if no audit log is configured.
+func auditConfig() *ghmcp.AuditConfig {
+	path := viper.GetString("audit_log")
+	if path == "" {
+		return nil
+	}
+	return &ghmcp.AuditConfig{
+		Path:      path,
+		HashChain: viper.GetBool("audit_log_hash_chain"),
+	}
+}
+
 func sendErrorAndExit(message string, err error) {
 	errorResponse := map[string]interface{}{
 		"jsonrpc": "2.0",
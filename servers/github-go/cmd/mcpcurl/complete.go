@@ -0,0 +1,239 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// splitWords splits a shell line into words at unquoted whitespace. Single and double quotes
+// group words with spaces, and a backslash escapes the next character outside of single quotes.
+// If the line ends within a word, partial is true and the word is the last one returned.
+func splitWords(line string) (words []string, partial bool, err error) {
+	var (
+		word    strings.Builder
+		inWord  bool
+		quote   rune
+		escaped bool
+	)
+	for _, r := range line {
+		switch {
+		case escaped:
+			word.WriteRune(r)
+			escaped = false
+		case r == '\\' && quote != '\'':
+			escaped, inWord = true, true
+		case quote != 0 && r == quote:
+			quote = 0
+		case quote != 0:
+			word.WriteRune(r)
+		case r == '"' || r == '\'':
+			quote, inWord = r, true
+		case r == ' ' || r == '\t':
+			if inWord {
+				words = append(words, word.String())
+				word.Reset()
+				inWord = false
+			}
+		default:
+			word.WriteRune(r)
+			inWord = true
+		}
+	}
+	if inWord {
+		words = append(words, word.String())
+	}
+	if quote != 0 {
+		err = fmt.Errorf("unterminated %c quote", quote)
+	}
+	return words, inWord, err
+}
+
+// complete returns the candidates for the word that ends line, along with that word. Every
+// candidate starts with the word.
+func (s *shell) complete(line string) (string, []string) {
+	words, partial, _ := splitWords(line)
+	current := ""
+	if partial {
+		current, words = words[len(words)-1], words[:len(words)-1]
+	}
+
+	var candidates []string
+	switch {
+	case len(words) == 0:
+		for _, command := range shellCommands {
+			candidates = append(candidates, command.name)
+		}
+	case words[0] == "call" || words[0] == "describe":
+		tools := s.listTools()
+		if len(words) == 1 {
+			for _, tool := range tools {
+				candidates = append(candidates, tool.Name)
+			}
+			break
+		}
+		if i := slices.IndexFunc(tools, func(tool mcp.Tool) bool { return tool.Name == words[1] }); i >= 0 && words[0] == "call" {
+			candidates = argumentCandidates(current, words[2:], toolParameters(tools[i]))
+		}
+	case words[0] == "read" && len(words) == 1:
+		for _, resource := range s.listResources() {
+			candidates = append(candidates, resource.URI)
+		}
+		for _, template := range s.listTemplates() {
+			if template.URITemplate != nil {
+				candidates = append(candidates, template.URITemplate.Raw())
+			}
+		}
+	case words[0] == "prompt":
+		prompts := s.listPrompts()
+		if len(words) == 1 {
+			for _, prompt := range prompts {
+				candidates = append(candidates, prompt.Name)
+			}
+			break
+		}
+		if i := slices.IndexFunc(prompts, func(prompt mcp.Prompt) bool { return prompt.Name == words[1] }); i >= 0 {
+			candidates = argumentCandidates(current, words[2:], promptParameters(prompts[i]))
+		}
+	}
+
+	matching := candidates[:0]
+	for _, candidate := range candidates {
+		if strings.HasPrefix(candidate, current) {
+			matching = append(matching, candidate)
+		}
+	}
+	sort.Strings(matching)
+	return current, slices.Compact(matching)
+}
+
+// parameter is an argument that a tool or prompt accepts.
+type parameter struct {
+	name     string
+	typ      string
+	enum     []string
+	required bool
+}
+
+// toolParameters returns the arguments of a tool from its input schema.
+func toolParameters(tool mcp.Tool) []parameter {
+	params := make([]parameter, 0, len(tool.InputSchema.Properties))
+	for name, raw := range tool.InputSchema.Properties {
+		param := parameter{name: name, required: slices.Contains(tool.InputSchema.Required, name)}
+		if property, ok := raw.(map[string]any); ok {
+			param.typ, _ = property["type"].(string)
+			if enum, ok := property["enum"].([]any); ok {
+				for _, value := range enum {
+					param.enum = append(param.enum, fmt.Sprint(value))
+				}
+			}
+		}
+		params = append(params, param)
+	}
+	sort.Slice(params, func(i, j int) bool { return params[i].name < params[j].name })
+	return params
+}
+
+// promptParameters returns the arguments of a prompt, which are all strings.
+func promptParameters(prompt mcp.Prompt) []parameter {
+	params := make([]parameter, 0, len(prompt.Arguments))
+	for _, argument := range prompt.Arguments {
+		params = append(params, parameter{name: argument.Name, typ: "string", required: argument.Required})
+	}
+	return params
+}
+
+// argumentCandidates completes "name=value" words: the names of the parameters that are not
+// given yet, or the values of a parameter with a known set of them.
+func argumentCandidates(current string, given []string, params []parameter) []string {
+	var candidates []string
+	if name, _, ok := strings.Cut(current, "="); ok {
+		i := slices.IndexFunc(params, func(param parameter) bool { return param.name == name })
+		if i < 0 {
+			return nil
+		}
+		values := params[i].enum
+		if params[i].typ == "boolean" {
+			values = []string{"false", "true"}
+		}
+		for _, value := range values {
+			candidates = append(candidates, name+"="+value)
+		}
+		return candidates
+	}
+
+	for _, param := range params {
+		if !slices.ContainsFunc(given, func(word string) bool { return strings.HasPrefix(word, param.name+"=") }) {
+			candidates = append(candidates, param.name+"=")
+		}
+	}
+	return candidates
+}
+
+// parseArguments converts "name=value" words into arguments. Values are parsed by the type of
+// their parameter: numbers and booleans as such, arrays as JSON or comma separated strings,
+// and objects as JSON.
+func parseArguments(words []string, params []parameter) (map[string]any, error) {
+	arguments := make(map[string]any, len(words))
+	for _, word := range words {
+		name, value, ok := strings.Cut(word, "=")
+		if !ok {
+			return nil, fmt.Errorf("argument %q must be given as name=value", word)
+		}
+		i := slices.IndexFunc(params, func(param parameter) bool { return param.name == name })
+		if i < 0 {
+			return nil, fmt.Errorf("unknown argument %s", name)
+		}
+		parsed, err := parseValue(value, params[i])
+		if err != nil {
+			return nil, fmt.Errorf("invalid value for %s: %w", name, err)
+		}
+		arguments[name] = parsed
+	}
+
+	var missing []string
+	for _, param := range params {
+		if _, ok := arguments[param.name]; param.required && !ok {
+			missing = append(missing, param.name)
+		}
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("missing required arguments: %s", strings.Join(missing, ", "))
+	}
+	return arguments, nil
+}
+
+func parseValue(value string, param parameter) (any, error) {
+	if len(param.enum) > 0 && !slices.Contains(param.enum, value) {
+		return nil, fmt.Errorf("must be one of: %s", strings.Join(param.enum, ", "))
+	}
+
+	switch param.typ {
+	case "number", "integer":
+		return strconv.ParseFloat(value, 64)
+	case "boolean":
+		return strconv.ParseBool(value)
+	case "array":
+		if !strings.HasPrefix(strings.TrimSpace(value), "[") {
+			return strings.Split(value, ","), nil
+		}
+		var items []any
+		if err := json.Unmarshal([]byte(value), &items); err != nil {
+			return nil, err
+		}
+		return items, nil
+	case "object":
+		var object map[string]any
+		if err := json.Unmarshal([]byte(value), &object); err != nil {
+			return nil, err
+		}
+		return object, nil
+	default:
+		return value, nil
+	}
+}
@@ -0,0 +1,177 @@
+package main
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testShell returns a shell that knows the tools, resources and prompts of a server, as if
+// it had listed them. The tools are decoded from JSON like those the server sends.
+func testShell(t *testing.T) *shell {
+	t.Helper()
+
+	var tools []mcp.Tool
+	require.NoError(t, json.Unmarshal([]byte(`[
+		{
+			"name": "list_issues",
+			"inputSchema": {
+				"type": "object",
+				"properties": {
+					"owner": {"type": "string"},
+					"repo": {"type": "string"},
+					"state": {"type": "string", "enum": ["open", "closed"]}
+				},
+				"required": ["owner", "repo"]
+			}
+		},
+		{
+			"name": "list_pull_requests",
+			"inputSchema": {"type": "object", "properties": {"owner": {"type": "string"}}}
+		},
+		{
+			"name": "create_pull_request",
+			"inputSchema": {
+				"type": "object",
+				"properties": {
+					"title": {"type": "string"},
+					"draft": {"type": "boolean"}
+				}
+			}
+		}
+	]`), &tools))
+
+	return &shell{
+		tools: tools,
+		resources: []mcp.Resource{
+			{URI: "repo://octocat/hello-world/contents/README.md"},
+		},
+		templates: []mcp.ResourceTemplate{
+			mcp.NewResourceTemplate("repo://{owner}/{repo}/contents{/path*}", "Repository content"),
+		},
+		prompts: []mcp.Prompt{
+			{
+				Name: "summarize_issue",
+				Arguments: []mcp.PromptArgument{
+					{Name: "owner", Required: true},
+					{Name: "issue_number", Required: true},
+				},
+			},
+		},
+	}
+}
+
+func Test_ShellComplete(t *testing.T) {
+	tests := []struct {
+		name               string
+		line               string
+		expectedWord       string
+		expectedCandidates []string
+	}{
+		{
+			name:               "commands",
+			line:               "",
+			expectedCandidates: []string{"call", "describe", "exit", "help", "prompt", "prompts", "read", "resources", "templates", "tools"},
+		},
+		{
+			name:               "partial command",
+			line:               "pr",
+			expectedWord:       "pr",
+			expectedCandidates: []string{"prompt", "prompts"},
+		},
+		{
+			name:               "tools to call",
+			line:               "call ",
+			expectedCandidates: []string{"create_pull_request", "list_issues", "list_pull_requests"},
+		},
+		{
+			name:               "partial tool",
+			line:               "describe list_",
+			expectedWord:       "list_",
+			expectedCandidates: []string{"list_issues", "list_pull_requests"},
+		},
+		{
+			name:               "quoted partial tool",
+			line:               `call "list_i`,
+			expectedWord:       "list_i",
+			expectedCandidates: []string{"list_issues"},
+		},
+		{
+			name:               "argument names",
+			line:               "call list_issues ",
+			expectedCandidates: []string{"owner=", "repo=", "state="},
+		},
+		{
+			name:               "argument names not given yet",
+			line:               "call list_issues owner=octocat ",
+			expectedCandidates: []string{"repo=", "state="},
+		},
+		{
+			name:               "partial argument name",
+			line:               "call list_issues st",
+			expectedWord:       "st",
+			expectedCandidates: []string{"state="},
+		},
+		{
+			name:               "enum values",
+			line:               "call list_issues state=",
+			expectedWord:       "state=",
+			expectedCandidates: []string{"state=closed", "state=open"},
+		},
+		{
+			name:               "boolean values",
+			line:               "call create_pull_request draft=t",
+			expectedWord:       "draft=t",
+			expectedCandidates: []string{"draft=true"},
+		},
+		{
+			name:         "values of free text",
+			line:         "call create_pull_request title=",
+			expectedWord: "title=",
+		},
+		{
+			name:         "unknown argument",
+			line:         "call create_pull_request body=",
+			expectedWord: "body=",
+		},
+		{
+			name: "unknown tool",
+			line: "call list_commits ",
+		},
+		{
+			name: "describe takes no arguments",
+			line: "describe list_issues ",
+		},
+		{
+			name:               "resources and templates",
+			line:               "read repo://",
+			expectedWord:       "repo://",
+			expectedCandidates: []string{"repo://octocat/hello-world/contents/README.md", "repo://{owner}/{repo}/contents{/path*}"},
+		},
+		{
+			name:               "prompt arguments",
+			line:               "prompt summarize_issue ",
+			expectedCandidates: []string{"issue_number=", "owner="},
+		},
+		{
+			name: "commands without arguments",
+			line: "tools ",
+		},
+	}
+
+	s := testShell(t)
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			word, candidates := s.complete(tc.line)
+			assert.Equal(t, tc.expectedWord, word)
+			if len(tc.expectedCandidates) == 0 {
+				assert.Empty(t, candidates)
+				return
+			}
+			assert.Equal(t, tc.expectedCandidates, candidates)
+		})
+	}
+}
@@ -0,0 +1,215 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"unicode"
+)
+
+// lineReader reads the lines typed into the shell.
+type lineReader interface {
+	ReadLine(prompt string) (string, error)
+}
+
+// completeFunc returns the candidates for the word that ends line, along with that word.
+type completeFunc func(line string) (string, []string)
+
+// newLineReader returns an editor with history and tab completion if in is a terminal, and
+// otherwise reads plain lines without prompting, e.g. from a script piped into the shell.
+func newLineReader(in *os.File, out io.Writer, complete completeFunc) lineReader {
+	if raw, ok := rawMode(in); ok {
+		return &editor{in: bufio.NewReader(in), out: out, raw: raw, complete: complete}
+	}
+	return &plainReader{scanner: bufio.NewScanner(in)}
+}
+
+// plainReader reads lines as they are.
+type plainReader struct {
+	scanner *bufio.Scanner
+}
+
+func (r *plainReader) ReadLine(_ string) (string, error) {
+	if !r.scanner.Scan() {
+		if err := r.scanner.Err(); err != nil {
+			return "", err
+		}
+		return "", io.EOF
+	}
+	return r.scanner.Text(), nil
+}
+
+// editor reads lines from a terminal in raw mode, so that it can move the cursor, recall
+// earlier lines with the up and down keys, and complete words with tab.
+type editor struct {
+	in       *bufio.Reader
+	out      io.Writer
+	raw      func() (restore func(), err error)
+	complete completeFunc
+	history  []string
+
+	prompt string
+	line   []rune
+	pos    int
+}
+
+// ReadLine reads a line. Ctrl-C discards the line typed so far, and Ctrl-D on an empty line
+// returns io.EOF.
+func (e *editor) ReadLine(prompt string) (string, error) {
+	restore, err := e.raw()
+	if err != nil {
+		return "", err
+	}
+	defer restore()
+
+	e.prompt, e.line, e.pos = prompt, nil, 0
+	recalled := len(e.history)
+	e.redraw()
+
+	for {
+		r, _, err := e.in.ReadRune()
+		if err != nil {
+			return "", err
+		}
+
+		switch r {
+		case '\r', '\n':
+			_, _ = io.WriteString(e.out, "\r\n")
+			line := string(e.line)
+			if strings.TrimSpace(line) != "" {
+				e.history = append(e.history, line)
+			}
+			return line, nil
+		case 3: // Ctrl-C
+			_, _ = io.WriteString(e.out, "^C\r\n")
+			e.line, e.pos = nil, 0
+			recalled = len(e.history)
+		case 4: // Ctrl-D
+			if len(e.line) == 0 {
+				_, _ = io.WriteString(e.out, "\r\n")
+				return "", io.EOF
+			}
+			e.delete(e.pos)
+		case 1: // Ctrl-A
+			e.pos = 0
+		case 5: // Ctrl-E
+			e.pos = len(e.line)
+		case 21: // Ctrl-U
+			e.line, e.pos = e.line[e.pos:], 0
+		case 127, 8: // Backspace
+			if e.pos > 0 {
+				e.pos--
+				e.delete(e.pos)
+			}
+		case '\t':
+			e.completeWord()
+		case 27: // Escape sequences of the arrow, home, end and delete keys
+			recalled = e.escape(recalled)
+		default:
+			if unicode.IsPrint(r) {
+				e.insert(string(r))
+			}
+		}
+		e.redraw()
+	}
+}
+
+// escape handles an escape sequence, and returns which line of the history is recalled.
+func (e *editor) escape(recalled int) int {
+	if next, _, err := e.in.ReadRune(); err != nil || (next != '[' && next != 'O') {
+		return recalled
+	}
+	key, _, err := e.in.ReadRune()
+	if err != nil {
+		return recalled
+	}
+
+	switch key {
+	case 'A': // Up
+		if recalled > 0 {
+			recalled--
+			e.line = []rune(e.history[recalled])
+			e.pos = len(e.line)
+		}
+	case 'B': // Down
+		if recalled < len(e.history) {
+			recalled++
+			e.line = nil
+			if recalled < len(e.history) {
+				e.line = []rune(e.history[recalled])
+			}
+			e.pos = len(e.line)
+		}
+	case 'C': // Right
+		if e.pos < len(e.line) {
+			e.pos++
+		}
+	case 'D': // Left
+		if e.pos > 0 {
+			e.pos--
+		}
+	case 'H':
+		e.pos = 0
+	case 'F':
+		e.pos = len(e.line)
+	case '3': // Delete, sent as "\x1b[3~"
+		if tilde, _, err := e.in.ReadRune(); err == nil && tilde == '~' {
+			e.delete(e.pos)
+		}
+	}
+	return recalled
+}
+
+func (e *editor) insert(s string) {
+	runes := []rune(s)
+	e.line = append(e.line[:e.pos], append(runes, e.line[e.pos:]...)...)
+	e.pos += len(runes)
+}
+
+func (e *editor) delete(i int) {
+	if i < len(e.line) {
+		e.line = append(e.line[:i], e.line[i+1:]...)
+	}
+}
+
+// completeWord completes the word before the cursor. A single candidate is inserted, followed
+// by a space unless it is an argument name waiting for its value. Several candidates are
+// completed as far as they agree, and listed if they don't agree any further.
+func (e *editor) completeWord() {
+	word, candidates := e.complete(string(e.line[:e.pos]))
+	switch len(candidates) {
+	case 0:
+		_, _ = io.WriteString(e.out, "\a")
+	case 1:
+		e.insert(strings.TrimPrefix(candidates[0], word))
+		if !strings.HasSuffix(candidates[0], "=") {
+			e.insert(" ")
+		}
+	default:
+		if prefix := commonPrefix(candidates); len(prefix) > len(word) {
+			e.insert(strings.TrimPrefix(prefix, word))
+			return
+		}
+		_, _ = fmt.Fprintf(e.out, "\r\n%s\r\n", strings.Join(candidates, "  "))
+	}
+}
+
+// redraw writes the prompt and line again, and puts the cursor back in place.
+func (e *editor) redraw() {
+	_, _ = fmt.Fprintf(e.out, "\r%s%s\x1b[K", e.prompt, string(e.line))
+	if back := len(e.line) - e.pos; back > 0 {
+		_, _ = fmt.Fprintf(e.out, "\x1b[%dD", back)
+	}
+}
+
+func commonPrefix(words []string) string {
+	prefix := words[0]
+	for _, word := range words[1:] {
+		for !strings.HasPrefix(word, prefix) {
+			prefix = prefix[:len(prefix)-1]
+		}
+	}
+	return prefix
+}
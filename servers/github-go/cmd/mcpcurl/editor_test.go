@@ -0,0 +1,148 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_commonPrefix(t *testing.T) {
+	tests := []struct {
+		name     string
+		words    []string
+		expected string
+	}{
+		{name: "single word", words: []string{"tools"}, expected: "tools"},
+		{name: "shared prefix", words: []string{"list_issues", "list_pull_requests"}, expected: "list_"},
+		{name: "one word is the prefix", words: []string{"prompt", "prompts"}, expected: "prompt"},
+		{name: "nothing shared", words: []string{"call", "tools"}, expected: ""},
+		{name: "equal words", words: []string{"state=open", "state=open"}, expected: "state=open"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, commonPrefix(tc.words))
+		})
+	}
+}
+
+// completeWith returns a completeFunc that offers the candidates starting with the last word
+// of the line.
+func completeWith(candidates ...string) completeFunc {
+	return func(line string) (string, []string) {
+		word := line[strings.LastIndex(line, " ")+1:]
+		var matching []string
+		for _, candidate := range candidates {
+			if strings.HasPrefix(candidate, word) {
+				matching = append(matching, candidate)
+			}
+		}
+		return word, matching
+	}
+}
+
+func Test_EditorCompleteWord(t *testing.T) {
+	tests := []struct {
+		name           string
+		candidates     []string
+		line           string
+		pos            int
+		expectedLine   string
+		expectedPos    int
+		expectedOutput string
+	}{
+		{
+			name:           "no candidates",
+			candidates:     []string{"tools"},
+			line:           "exi",
+			pos:            3,
+			expectedLine:   "exi",
+			expectedPos:    3,
+			expectedOutput: "\a",
+		},
+		{
+			name:         "single candidate",
+			candidates:   []string{"tools", "templates"},
+			line:         "to",
+			pos:          2,
+			expectedLine: "tools ",
+			expectedPos:  6,
+		},
+		{
+			name:         "argument name waits for its value",
+			candidates:   []string{"owner=", "repo="},
+			line:         "call list_issues ow",
+			pos:          19,
+			expectedLine: "call list_issues owner=",
+			expectedPos:  23,
+		},
+		{
+			name:         "candidates completed as far as they agree",
+			candidates:   []string{"list_issues", "list_pull_requests"},
+			line:         "call l",
+			pos:          6,
+			expectedLine: "call list_",
+			expectedPos:  10,
+		},
+		{
+			name:           "candidates listed once they don't agree any further",
+			candidates:     []string{"list_issues", "list_pull_requests"},
+			line:           "call list_",
+			pos:            10,
+			expectedLine:   "call list_",
+			expectedPos:    10,
+			expectedOutput: "\r\nlist_issues  list_pull_requests\r\n",
+		},
+		{
+			name:         "word before the cursor",
+			candidates:   []string{"list_issues"},
+			line:         "call list owner=octocat",
+			pos:          9,
+			expectedLine: "call list_issues  owner=octocat",
+			expectedPos:  17,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var out bytes.Buffer
+			e := &editor{out: &out, complete: completeWith(tc.candidates...), line: []rune(tc.line), pos: tc.pos}
+			e.completeWord()
+			assert.Equal(t, tc.expectedLine, string(e.line))
+			assert.Equal(t, tc.expectedPos, e.pos)
+			assert.Equal(t, tc.expectedOutput, out.String())
+		})
+	}
+}
+
+func Test_EditorReadLine(t *testing.T) {
+	input := strings.Join([]string{
+		"to\t\r",                    // completed to "tools "
+		"help\r",                    // kept in the history
+		"\x1b[A\x1b[A\r",            // recalls "tools "
+		"exit\x1b[D\x1b[D\x1b[3~\r", // deletes the "i"
+		"discarded\x03call\r",
+		"\x04",
+	}, "")
+	restored := 0
+	e := &editor{
+		in:       bufio.NewReader(strings.NewReader(input)),
+		out:      io.Discard,
+		raw:      func() (func(), error) { return func() { restored++ }, nil },
+		complete: completeWith("tools", "templates"),
+	}
+
+	for _, expected := range []string{"tools ", "help", "tools ", "ext", "call"} {
+		line, err := e.ReadLine("> ")
+		require.NoError(t, err)
+		assert.Equal(t, expected, line)
+	}
+	_, err := e.ReadLine("> ")
+	assert.ErrorIs(t, err, io.EOF)
+	assert.Equal(t, 6, restored, "the terminal is restored after every line")
+}
@@ -11,6 +11,7 @@ import (
 	"os/exec"
 	"slices"
 	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
@@ -106,6 +107,11 @@ var (
 				return nil
 			}
 
+			// The shell can connect to a server by URL instead, and checks its flags itself
+			if cmd == shellCmd {
+				return nil
+			}
+
 			// Check if the required global flag is provided
 			serverCmd, _ := cmd.Flags().GetString("stdio-server-cmd")
 			if serverCmd == "" {
@@ -155,9 +161,8 @@ var (
 func main() {
 	rootCmd.AddCommand(schemaCmd)
 
-	// Add global flag for stdio server command
-	rootCmd.PersistentFlags().String("stdio-server-cmd", "", "Shell command to invoke MCP server via stdio (required)")
-	_ = rootCmd.MarkPersistentFlagRequired("stdio-server-cmd")
+	// Add global flag for stdio server command, which every command but shell requires
+	rootCmd.PersistentFlags().String("stdio-server-cmd", "", "Shell command to invoke MCP server via stdio (required unless shell is given --url)")
 
 	// Add global flag for pretty printing
 	rootCmd.PersistentFlags().Bool("pretty", true, "Pretty print MCP response (only for JSON or JSONL responses)")
@@ -165,6 +170,19 @@ func main() {
 	// Add the tools command to the root command
 	rootCmd.AddCommand(toolsCmd)
 
+	// Add the shell command, which can also connect to servers over HTTP
+	shellCmd.Flags().String("url", "", "URL of a streamable HTTP or SSE MCP server to connect to instead of --stdio-server-cmd")
+	shellCmd.Flags().Bool("sse", false, "Connect to --url with the SSE transport instead of streamable HTTP")
+	shellCmd.Flags().StringArray("header", nil, "HTTP header to send to --url, as \"Name: value\", can be repeated")
+	rootCmd.AddCommand(shellCmd)
+
+	// Add the commands that record sessions and replay them against another server build
+	recordCmd.Flags().StringP("output", "o", "session.jsonl", "File to save the recorded messages to, as JSONL")
+	rootCmd.AddCommand(recordCmd)
+	replayCmd.Flags().StringArray("ignore", nil, "JSON path to leave out of the diff, such as result.content[*].text.**.updated_at, can be repeated")
+	replayCmd.Flags().Duration("timeout", 30*time.Second, "How long to wait for each response")
+	rootCmd.AddCommand(replayCmd)
+
 	// Execute the root command once to parse flags
 	_ = rootCmd.ParseFlags(os.Args[1:])
 
@@ -0,0 +1,173 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+
+	"github.com/spf13/cobra"
+)
+
+// recordedMessage is a line of a recording: a JSON-RPC message, and whether the client or
+// the server sent it.
+type recordedMessage struct {
+	From    string          `json:"from"`
+	Message json.RawMessage `json:"message"`
+}
+
+const (
+	fromClient = "client"
+	fromServer = "server"
+)
+
+// jsonrpcMessage holds the fields that tell JSON-RPC requests, notifications and responses
+// apart. Requests have a method and an ID, notifications only a method, and responses only
+// an ID.
+type jsonrpcMessage struct {
+	ID     json.RawMessage `json:"id,omitempty"`
+	Method string          `json:"method,omitempty"`
+	Params json.RawMessage `json:"params,omitempty"`
+}
+
+func (m jsonrpcMessage) isRequest() bool  { return m.Method != "" && len(m.ID) > 0 }
+func (m jsonrpcMessage) isResponse() bool { return m.Method == "" && len(m.ID) > 0 }
+
+var recordCmd = &cobra.Command{
+	Use:   "record",
+	Short: "Record a session with an MCP server",
+	Long: `Start the MCP server given by --stdio-server-cmd and relay the JSON-RPC messages between it and
+the client on stdin and stdout, saving every message to a JSONL file for replay. Configure an
+MCP host to run mcpcurl record in place of the server to capture its sessions.`,
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		output, _ := cmd.Flags().GetString("output")
+		serverCmd, _ := cmd.Flags().GetString("stdio-server-cmd")
+
+		file, err := os.Create(output)
+		if err != nil {
+			return fmt.Errorf("failed to create recording: %w", err)
+		}
+		defer func() { _ = file.Close() }()
+
+		server, err := startServer(serverCmd, os.Stderr)
+		if err != nil {
+			return err
+		}
+
+		r := &recorder{w: file}
+		go func() {
+			// The server exits once the client closes its input
+			_ = r.relay(fromClient, os.Stdin, server.stdin)
+			_ = server.stdin.Close()
+		}()
+		relayErr := r.relay(fromServer, server.stdout, os.Stdout)
+		if err := server.cmd.Wait(); err != nil {
+			return fmt.Errorf("server failed: %w", err)
+		}
+		return relayErr
+	},
+}
+
+// recorder saves the messages relayed in both directions to a single recording.
+type recorder struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// relay copies messages, one per line, from src to dst and records them as sent by from.
+// Lines that are not JSON are relayed without being recorded.
+func (r *recorder) relay(from string, src io.Reader, dst io.Writer) error {
+	reader := bufio.NewReader(src)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if len(line) > 0 {
+			if _, err := dst.Write(line); err != nil {
+				return err
+			}
+			if err := r.record(from, line); err != nil {
+				return err
+			}
+		}
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+func (r *recorder) record(from string, line []byte) error {
+	var message bytes.Buffer
+	if err := json.Compact(&message, line); err != nil {
+		return nil
+	}
+	entry, err := json.Marshal(recordedMessage{From: from, Message: message.Bytes()})
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	_, err = r.w.Write(append(entry, '\n'))
+	return err
+}
+
+// readRecording reads the messages of a recording.
+func readRecording(path string) ([]recordedMessage, error) {
+	data, err := os.ReadFile(path) //nolint:gosec // mcpcurl reads the recording the user asks for
+	if err != nil {
+		return nil, fmt.Errorf("failed to read recording: %w", err)
+	}
+
+	var messages []recordedMessage
+	for i, line := range strings.Split(string(data), "\n") {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		var message recordedMessage
+		if err := json.Unmarshal([]byte(line), &message); err != nil {
+			return nil, fmt.Errorf("invalid message on line %d of %s: %w", i+1, path, err)
+		}
+		if message.From != fromClient && message.From != fromServer {
+			return nil, fmt.Errorf("invalid sender %q on line %d of %s", message.From, i+1, path)
+		}
+		messages = append(messages, message)
+	}
+	return messages, nil
+}
+
+// serverProcess is a running stdio MCP server.
+type serverProcess struct {
+	cmd    *exec.Cmd
+	stdin  io.WriteCloser
+	stdout io.Reader
+}
+
+// startServer starts the server command, passing its standard error through to stderr.
+func startServer(serverCmd string, stderr io.Writer) (*serverProcess, error) {
+	cmdParts := strings.Fields(serverCmd)
+	if len(cmdParts) == 0 {
+		return nil, fmt.Errorf("empty command")
+	}
+
+	cmd := exec.Command(cmdParts[0], cmdParts[1:]...) //nolint:gosec //mcpcurl is a test command that needs to execute arbitrary shell commands
+	cmd.Stderr = stderr
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create stdin pipe: %w", err)
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create stdout pipe: %w", err)
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("failed to start command: %w", err)
+	}
+	return &serverProcess{cmd: cmd, stdin: stdin, stdout: stdout}, nil
+}
@@ -0,0 +1,358 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/josephburnett/jd/v2"
+	"github.com/spf13/cobra"
+)
+
+var replayCmd = &cobra.Command{
+	Use:   "replay <recording>",
+	Short: "Replay a recorded session against an MCP server",
+	Long: `Start the MCP server given by --stdio-server-cmd, send it the client messages of a recording made
+with mcpcurl record, and diff its responses against the recorded ones. Requests the server
+sends to the client are answered with the recorded answers. Fields that change from run to run,
+such as timestamps, can be left out of the diff with --ignore.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		serverCmd, _ := cmd.Flags().GetString("stdio-server-cmd")
+		timeout, _ := cmd.Flags().GetDuration("timeout")
+		paths, _ := cmd.Flags().GetStringArray("ignore")
+
+		ignore := make([][]string, 0, len(paths))
+		for _, path := range paths {
+			segments, err := parseIgnorePath(path)
+			if err != nil {
+				return err
+			}
+			ignore = append(ignore, segments)
+		}
+
+		messages, err := readRecording(args[0])
+		if err != nil {
+			return err
+		}
+		server, err := startServer(serverCmd, os.Stderr)
+		if err != nil {
+			return err
+		}
+		defer func() {
+			_ = server.stdin.Close()
+			_ = server.cmd.Wait()
+		}()
+
+		r := newReplayer(server, messages, cmd.OutOrStdout())
+		go r.receive()
+		return r.replay(timeout, ignore)
+	},
+}
+
+// replayer sends the client messages of a recording to a server, and diffs the responses.
+type replayer struct {
+	server   *serverProcess
+	messages []recordedMessage
+	out      io.Writer
+
+	// answers are the recorded responses of the client to requests of the server, in order
+	answers []json.RawMessage
+
+	mu      sync.Mutex
+	pending map[string]chan json.RawMessage
+	closed  chan struct{}
+
+	// writeMu keeps messages from being interleaved. It is not mu, so that receive can go on
+	// reading while a write waits for the server to read its stdin.
+	writeMu sync.Mutex
+}
+
+func newReplayer(server *serverProcess, messages []recordedMessage, out io.Writer) *replayer {
+	r := &replayer{
+		server:   server,
+		messages: messages,
+		out:      out,
+		pending:  make(map[string]chan json.RawMessage),
+		closed:   make(chan struct{}),
+	}
+	for _, message := range messages {
+		var m jsonrpcMessage
+		if message.From == fromClient && json.Unmarshal(message.Message, &m) == nil && m.isResponse() {
+			r.answers = append(r.answers, message.Message)
+		}
+	}
+	return r
+}
+
+// replay sends the recorded client requests and notifications in order. It waits for the
+// response to each request before sending the next message, and prints how it differs from
+// the recorded response.
+func (r *replayer) replay(timeout time.Duration, ignore [][]string) error {
+	var requests, differing int
+	for i, message := range r.messages {
+		var m jsonrpcMessage
+		if message.From != fromClient || json.Unmarshal(message.Message, &m) != nil || m.isResponse() {
+			continue
+		}
+		if !m.isRequest() {
+			if err := r.send(message.Message); err != nil {
+				return err
+			}
+			continue
+		}
+
+		recorded, ok := r.recordedResponse(i, m.ID)
+		if !ok {
+			// The session ended before the server responded, so there is nothing to compare
+			if err := r.send(message.Message); err != nil {
+				return err
+			}
+			continue
+		}
+
+		response := make(chan json.RawMessage, 1)
+		r.mu.Lock()
+		r.pending[compactJSON(m.ID)] = response
+		r.mu.Unlock()
+		if err := r.send(message.Message); err != nil {
+			return err
+		}
+
+		var replayed json.RawMessage
+		select {
+		case replayed = <-response:
+		case <-r.closed:
+			return fmt.Errorf("server exited before responding to %s", describeRequest(m))
+		case <-time.After(timeout):
+			return fmt.Errorf("no response to %s within %s", describeRequest(m), timeout)
+		}
+
+		requests++
+		diff, err := diffResponses(recorded, replayed, ignore)
+		if err != nil {
+			return fmt.Errorf("failed to diff the responses to %s: %w", describeRequest(m), err)
+		}
+		if diff != "" {
+			differing++
+			_, _ = fmt.Fprintf(r.out, "%s:\n%s\n", describeRequest(m), diff)
+		}
+	}
+
+	if differing > 0 {
+		return fmt.Errorf("%d of %d responses differ from the recording", differing, requests)
+	}
+	_, _ = fmt.Fprintf(r.out, "All %d responses match the recording\n", requests)
+	return nil
+}
+
+// recordedResponse returns the response of the server to the request at index i.
+func (r *replayer) recordedResponse(i int, id json.RawMessage) (json.RawMessage, bool) {
+	for _, message := range r.messages[i+1:] {
+		var m jsonrpcMessage
+		if message.From == fromServer && json.Unmarshal(message.Message, &m) == nil && m.isResponse() && compactJSON(m.ID) == compactJSON(id) {
+			return message.Message, true
+		}
+	}
+	return nil, false
+}
+
+// receive reads the messages of the server, handing responses to the requests waiting for
+// them and answering the requests of the server. Notifications are dropped.
+func (r *replayer) receive() {
+	defer close(r.closed)
+	scanner := bufio.NewScanner(r.server.stdout)
+	scanner.Buffer(nil, 64*1024*1024)
+	for scanner.Scan() {
+		line := append([]byte(nil), scanner.Bytes()...)
+		var m jsonrpcMessage
+		if json.Unmarshal(line, &m) != nil {
+			continue
+		}
+		switch {
+		case m.isResponse():
+			r.mu.Lock()
+			response, ok := r.pending[compactJSON(m.ID)]
+			delete(r.pending, compactJSON(m.ID))
+			r.mu.Unlock()
+			if ok {
+				response <- line
+			}
+		case m.isRequest():
+			_ = r.answer(m)
+		}
+	}
+}
+
+// answer responds to a request of the server with the next recorded answer of the client,
+// or with an error once there are none left.
+func (r *replayer) answer(request jsonrpcMessage) error {
+	r.mu.Lock()
+	var answer map[string]any
+	if len(r.answers) > 0 {
+		_ = json.Unmarshal(r.answers[0], &answer)
+		r.answers = r.answers[1:]
+	}
+	r.mu.Unlock()
+
+	if answer == nil {
+		answer = map[string]any{
+			"jsonrpc": "2.0",
+			"error":   map[string]any{"code": -32603, "message": "no answer to " + request.Method + " was recorded"},
+		}
+	}
+	answer["id"] = request.ID
+	message, err := json.Marshal(answer)
+	if err != nil {
+		return err
+	}
+	return r.send(message)
+}
+
+func (r *replayer) send(message json.RawMessage) error {
+	r.writeMu.Lock()
+	defer r.writeMu.Unlock()
+	if _, err := r.server.stdin.Write(append(append([]byte(nil), message...), '\n')); err != nil {
+		return fmt.Errorf("failed to write to server: %w", err)
+	}
+	return nil
+}
+
+// describeRequest names a request by its method and the tool, prompt or resource it is for.
+func describeRequest(m jsonrpcMessage) string {
+	var params struct {
+		Name string `json:"name"`
+		URI  string `json:"uri"`
+	}
+	_ = json.Unmarshal(m.Params, &params)
+	description := m.Method
+	if target := params.Name + params.URI; target != "" {
+		description += " " + target
+	}
+	return fmt.Sprintf("%s (id %s)", description, compactJSON(m.ID))
+}
+
+func compactJSON(raw json.RawMessage) string {
+	var b bytes.Buffer
+	if err := json.Compact(&b, raw); err != nil {
+		return string(raw)
+	}
+	return b.String()
+}
+
+// diffResponses diffs a replayed response against the recorded one, with jd as the tool
+// snapshots are diffed. Text content that is JSON, like most tool results, is diffed as
+// JSON, and the ignored paths are removed from both responses first.
+func diffResponses(recorded, replayed json.RawMessage, ignore [][]string) (string, error) {
+	nodes := make([]jd.JsonNode, 2)
+	for i, raw := range []json.RawMessage{recorded, replayed} {
+		var value any
+		if err := json.Unmarshal(raw, &value); err != nil {
+			return "", err
+		}
+		expandJSONText(value)
+		for _, path := range ignore {
+			removePath(value, path)
+		}
+		node, err := jd.NewJsonNode(value)
+		if err != nil {
+			return "", err
+		}
+		nodes[i] = node
+	}
+	return nodes[0].Diff(nodes[1], jd.SET).Render(), nil
+}
+
+// expandJSONText replaces the strings of "text" fields that hold a JSON object or array with
+// the value they hold.
+func expandJSONText(value any) {
+	switch v := value.(type) {
+	case map[string]any:
+		for key, child := range v {
+			if text, ok := child.(string); ok && key == "text" {
+				var parsed any
+				if trimmed := strings.TrimSpace(text); (strings.HasPrefix(trimmed, "{") || strings.HasPrefix(trimmed, "[")) && json.Unmarshal([]byte(text), &parsed) == nil {
+					v[key] = parsed
+				}
+				continue
+			}
+			expandJSONText(child)
+		}
+	case []any:
+		for _, child := range v {
+			expandJSONText(child)
+		}
+	}
+}
+
+// parseIgnorePath splits a path such as "result.content[*].text.updated_at" into its
+// segments. A "*" segment matches any key or index, and "**" any number of them.
+func parseIgnorePath(path string) ([]string, error) {
+	normalized := strings.NewReplacer("[", ".", "]", "").Replace(strings.TrimPrefix(path, "$"))
+	var segments []string
+	for _, segment := range strings.Split(normalized, ".") {
+		if segment != "" {
+			segments = append(segments, segment)
+		}
+	}
+	if len(segments) == 0 || segments[len(segments)-1] == "**" {
+		return nil, fmt.Errorf("invalid path to ignore %q, it must end with a key, an index or *", path)
+	}
+	return segments, nil
+}
+
+// removePath removes the values at path from value. Object keys are deleted, and array items
+// are set to null so that the indexes of the items after them don't change.
+func removePath(value any, path []string) {
+	segment, rest := path[0], path[1:]
+	if segment == "**" {
+		removePath(value, rest)
+		forEachChild(value, func(child any) { removePath(child, path) })
+		return
+	}
+
+	switch v := value.(type) {
+	case map[string]any:
+		for key, child := range v {
+			if segment != "*" && segment != key {
+				continue
+			}
+			if len(rest) == 0 {
+				delete(v, key)
+			} else {
+				removePath(child, rest)
+			}
+		}
+	case []any:
+		for i, child := range v {
+			if segment != "*" && segment != strconv.Itoa(i) {
+				continue
+			}
+			if len(rest) == 0 {
+				v[i] = nil
+			} else {
+				removePath(child, rest)
+			}
+		}
+	}
+}
+
+func forEachChild(value any, f func(any)) {
+	switch v := value.(type) {
+	case map[string]any:
+		for _, child := range v {
+			f(child)
+		}
+	case []any:
+		for _, child := range v {
+			f(child)
+		}
+	}
+}
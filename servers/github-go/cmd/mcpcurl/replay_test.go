@@ -0,0 +1,231 @@
+package main
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_parseIgnorePath(t *testing.T) {
+	tests := []struct {
+		name        string
+		path        string
+		expected    []string
+		expectedErr string
+	}{
+		{name: "keys", path: "result.structuredContent.updated_at", expected: []string{"result", "structuredContent", "updated_at"}},
+		{name: "indexes", path: "result.content[0].text", expected: []string{"result", "content", "0", "text"}},
+		{name: "wildcards", path: "result.content[*].text.**.updated_at", expected: []string{"result", "content", "*", "text", "**", "updated_at"}},
+		{name: "root", path: "$.result.id", expected: []string{"result", "id"}},
+		{name: "ends with a wildcard", path: "result.*", expected: []string{"result", "*"}},
+		{name: "empty", path: "", expectedErr: `invalid path to ignore "", it must end with a key, an index or *`},
+		{name: "only the root", path: "$", expectedErr: `invalid path to ignore "$", it must end with a key, an index or *`},
+		{name: "ends with any number of segments", path: "result.**", expectedErr: `invalid path to ignore "result.**", it must end with a key, an index or *`},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			segments, err := parseIgnorePath(tc.path)
+			if tc.expectedErr != "" {
+				require.EqualError(t, err, tc.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, segments)
+		})
+	}
+}
+
+func Test_removePath(t *testing.T) {
+	const value = `{
+		"id": 1,
+		"updated_at": "2025-01-01T00:00:00Z",
+		"items": [
+			{"id": 2, "updated_at": "2025-01-02T00:00:00Z", "user": {"id": 3, "updated_at": "2025-01-03T00:00:00Z"}},
+			{"id": 4, "labels": [{"id": 5, "updated_at": "2025-01-04T00:00:00Z"}]}
+		]
+	}`
+
+	tests := []struct {
+		name     string
+		path     string
+		expected string
+	}{
+		{
+			name:     "key",
+			path:     "updated_at",
+			expected: `{"id": 1, "items": [{"id": 2, "updated_at": "2025-01-02T00:00:00Z", "user": {"id": 3, "updated_at": "2025-01-03T00:00:00Z"}}, {"id": 4, "labels": [{"id": 5, "updated_at": "2025-01-04T00:00:00Z"}]}]}`,
+		},
+		{
+			name:     "index keeps the following items in place",
+			path:     "items[0]",
+			expected: `{"id": 1, "updated_at": "2025-01-01T00:00:00Z", "items": [null, {"id": 4, "labels": [{"id": 5, "updated_at": "2025-01-04T00:00:00Z"}]}]}`,
+		},
+		{
+			name:     "key under an index",
+			path:     "items[1].labels",
+			expected: `{"id": 1, "updated_at": "2025-01-01T00:00:00Z", "items": [{"id": 2, "updated_at": "2025-01-02T00:00:00Z", "user": {"id": 3, "updated_at": "2025-01-03T00:00:00Z"}}, {"id": 4}]}`,
+		},
+		{
+			name:     "* matches a single level",
+			path:     "items[*].updated_at",
+			expected: `{"id": 1, "updated_at": "2025-01-01T00:00:00Z", "items": [{"id": 2, "user": {"id": 3, "updated_at": "2025-01-03T00:00:00Z"}}, {"id": 4, "labels": [{"id": 5, "updated_at": "2025-01-04T00:00:00Z"}]}]}`,
+		},
+		{
+			name:     "* matches keys",
+			path:     "items[0].*",
+			expected: `{"id": 1, "updated_at": "2025-01-01T00:00:00Z", "items": [{}, {"id": 4, "labels": [{"id": 5, "updated_at": "2025-01-04T00:00:00Z"}]}]}`,
+		},
+		{
+			name:     "** matches any number of levels",
+			path:     "**.updated_at",
+			expected: `{"id": 1, "items": [{"id": 2, "user": {"id": 3}}, {"id": 4, "labels": [{"id": 5}]}]}`,
+		},
+		{
+			name:     "** within a path",
+			path:     "items.**.id",
+			expected: `{"id": 1, "updated_at": "2025-01-01T00:00:00Z", "items": [{"updated_at": "2025-01-02T00:00:00Z", "user": {"updated_at": "2025-01-03T00:00:00Z"}}, {"labels": [{"updated_at": "2025-01-04T00:00:00Z"}]}]}`,
+		},
+		{
+			name:     "missing path",
+			path:     "items[2].user.id",
+			expected: value,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var v any
+			require.NoError(t, json.Unmarshal([]byte(value), &v))
+			path, err := parseIgnorePath(tc.path)
+			require.NoError(t, err)
+
+			removePath(v, path)
+			actual, err := json.Marshal(v)
+			require.NoError(t, err)
+			assert.JSONEq(t, tc.expected, string(actual))
+		})
+	}
+}
+
+func Test_expandJSONText(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    string
+		expected string
+	}{
+		{
+			name:     "object",
+			value:    `{"content": [{"type": "text", "text": "{\"number\": 1}"}]}`,
+			expected: `{"content": [{"type": "text", "text": {"number": 1}}]}`,
+		},
+		{
+			name:     "array with whitespace around it",
+			value:    `{"text": "\n [1, 2]\n"}`,
+			expected: `{"text": [1, 2]}`,
+		},
+		{
+			name:     "plain text",
+			value:    `{"text": "successfully merged"}`,
+			expected: `{"text": "successfully merged"}`,
+		},
+		{
+			name:     "JSON scalar",
+			value:    `{"text": "42"}`,
+			expected: `{"text": "42"}`,
+		},
+		{
+			name:     "invalid JSON",
+			value:    `{"text": "{not json"}`,
+			expected: `{"text": "{not json"}`,
+		},
+		{
+			name:     "other fields",
+			value:    `{"body": "{\"number\": 1}"}`,
+			expected: `{"body": "{\"number\": 1}"}`,
+		},
+		{
+			name:     "text within expanded text is left as is",
+			value:    `{"text": "{\"text\": \"{}\"}"}`,
+			expected: `{"text": {"text": "{}"}}`,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var v any
+			require.NoError(t, json.Unmarshal([]byte(tc.value), &v))
+			expandJSONText(v)
+			actual, err := json.Marshal(v)
+			require.NoError(t, err)
+			assert.JSONEq(t, tc.expected, string(actual))
+		})
+	}
+}
+
+func Test_diffResponses(t *testing.T) {
+	const recorded = `{"jsonrpc": "2.0", "id": 2, "result": {"content": [{"type": "text", "text": "{\"number\": 1, \"title\": \"Bug\", \"updated_at\": \"2025-01-01T00:00:00Z\"}"}]}}`
+
+	tests := []struct {
+		name        string
+		replayed    string
+		ignore      []string
+		expected    string
+		expectedErr string
+	}{
+		{
+			name:     "same response",
+			replayed: recorded,
+		},
+		{
+			name:     "differently formatted text",
+			replayed: `{"jsonrpc": "2.0", "id": 2, "result": {"content": [{"type": "text", "text": "{\"updated_at\":\"2025-01-01T00:00:00Z\",\"title\":\"Bug\",\"number\":1}"}]}}`,
+		},
+		{
+			// Arrays are diffed as sets, so the changed item is shown as a whole
+			name:     "changed field in the text",
+			replayed: `{"jsonrpc": "2.0", "id": 2, "result": {"content": [{"type": "text", "text": "{\"number\": 1, \"title\": \"Crash\", \"updated_at\": \"2025-01-01T00:00:00Z\"}"}]}}`,
+			expected: "@ [\"result\",\"content\",{}]\n" +
+				"- {\"text\":{\"number\":1,\"title\":\"Bug\",\"updated_at\":\"2025-01-01T00:00:00Z\"},\"type\":\"text\"}\n" +
+				"+ {\"text\":{\"number\":1,\"title\":\"Crash\",\"updated_at\":\"2025-01-01T00:00:00Z\"},\"type\":\"text\"}\n",
+		},
+		{
+			name:     "ignored field",
+			replayed: `{"jsonrpc": "2.0", "id": 2, "result": {"content": [{"type": "text", "text": "{\"number\": 1, \"title\": \"Bug\", \"updated_at\": \"2025-02-01T00:00:00Z\"}"}]}}`,
+			ignore:   []string{"result.content[*].text.updated_at"},
+		},
+		{
+			name:     "error in place of a result",
+			replayed: `{"jsonrpc": "2.0", "id": 2, "error": {"code": -32603, "message": "failed"}}`,
+			ignore:   []string{"**.updated_at"},
+			expected: "@ [\"result\"]\n- {\"content\":[{\"text\":{\"number\":1,\"title\":\"Bug\"},\"type\":\"text\"}]}\n" +
+				"@ [\"error\"]\n+ {\"code\":-32603,\"message\":\"failed\"}\n",
+		},
+		{
+			name:        "invalid response",
+			replayed:    `{"jsonrpc": "2.0",`,
+			expectedErr: "unexpected end of JSON input",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ignore := make([][]string, 0, len(tc.ignore))
+			for _, path := range tc.ignore {
+				segments, err := parseIgnorePath(path)
+				require.NoError(t, err)
+				ignore = append(ignore, segments)
+			}
+
+			diff, err := diffResponses(json.RawMessage(recorded), json.RawMessage(tc.replayed), ignore)
+			if tc.expectedErr != "" {
+				require.EqualError(t, err, tc.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, diff)
+		})
+	}
+}
@@ -0,0 +1,489 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"text/tabwriter"
+
+	"github.com/mark3labs/mcp-go/client"
+	"github.com/mark3labs/mcp-go/client/transport"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/spf13/cobra"
+)
+
+// shellCommand is a command that can be typed into the shell.
+type shellCommand struct {
+	name        string
+	args        string
+	description string
+}
+
+var shellCommands = []shellCommand{
+	{"tools", "", "List the tools of the server"},
+	{"describe", "<tool>", "Show the description and arguments of a tool"},
+	{"call", "<tool> [name=value ...]", "Call a tool"},
+	{"resources", "", "List the resources of the server"},
+	{"templates", "", "List the resource templates of the server"},
+	{"read", "<uri>", "Read a resource"},
+	{"prompts", "", "List the prompts of the server"},
+	{"prompt", "<name> [name=value ...]", "Get a prompt"},
+	{"help", "", "Show this help"},
+	{"exit", "", "End the session"},
+}
+
+var shellCmd = &cobra.Command{
+	Use:   "shell",
+	Short: "Start an interactive session with an MCP server",
+	Long: `Start an interactive session that stays connected to the MCP server given by --stdio-server-cmd,
+or to the streamable HTTP or SSE server at --url. Type help in the session for its commands.
+Tool names, arguments and their values are completed with tab.`,
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		ctx := cmd.Context()
+		prettyPrint, _ := cmd.Flags().GetBool("pretty")
+		s := &shell{
+			out:    cmd.OutOrStdout(),
+			pretty: prettyPrint,
+			stale:  map[string]bool{"tools": true, "resources": true, "prompts": true},
+		}
+		s.in = newLineReader(os.Stdin, cmd.OutOrStdout(), s.complete)
+
+		// Confirmations are read like commands, so that they get the next line of a script
+		c, err := connect(cmd, confirmer{in: s.in, out: cmd.ErrOrStderr()})
+		if err != nil {
+			return err
+		}
+		defer func() { _ = c.Close() }()
+		s.client = c
+		c.OnNotification(s.handleNotification)
+
+		if err := s.initialize(ctx); err != nil {
+			return err
+		}
+		return s.run(ctx)
+	},
+}
+
+// connect starts a client for the server given on the command line, whose elicitation
+// requests are answered by elicit. Its session is not initialized yet.
+func connect(cmd *cobra.Command, elicit client.ElicitationHandler) (*client.Client, error) {
+	serverCmd, _ := cmd.Flags().GetString("stdio-server-cmd")
+	url, _ := cmd.Flags().GetString("url")
+	if (serverCmd == "") == (url == "") {
+		return nil, fmt.Errorf("either --stdio-server-cmd or --url is required")
+	}
+
+	var (
+		t   transport.Interface
+		err error
+	)
+	switch {
+	case serverCmd != "":
+		parts := strings.Fields(serverCmd)
+		t = transport.NewStdioWithOptions(parts[0], nil, parts[1:], transport.WithCommandLogger(quietLogger{}))
+	default:
+		headers := make(map[string]string)
+		values, _ := cmd.Flags().GetStringArray("header")
+		for _, value := range values {
+			name, value, ok := strings.Cut(value, ":")
+			if !ok {
+				return nil, fmt.Errorf("invalid header %q, expected \"Name: value\"", value)
+			}
+			headers[strings.TrimSpace(name)] = strings.TrimSpace(value)
+		}
+		if sse, _ := cmd.Flags().GetBool("sse"); sse {
+			t, err = transport.NewSSE(url, transport.WithHeaders(headers), transport.WithSSELogger(quietLogger{}))
+		} else {
+			// Listening continuously delivers the notifications that lists changed
+			t, err = transport.NewStreamableHTTP(url, transport.WithHTTPHeaders(headers), transport.WithContinuousListening(), transport.WithHTTPLogger(quietLogger{}))
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to create transport: %w", err)
+		}
+	}
+
+	c := client.NewClient(t, client.WithElicitationHandler(elicit))
+	if err := c.Start(cmd.Context()); err != nil {
+		return nil, fmt.Errorf("failed to connect to server: %w", err)
+	}
+	if stderr, ok := client.GetStderr(c); ok {
+		// The server logs to stderr, which would garble the shell
+		go func() { _, _ = io.Copy(io.Discard, stderr) }()
+	}
+	return c, nil
+}
+
+// quietLogger keeps the transports from logging into the shell, e.g. that the pipes to a
+// stdio server were closed, which they are whenever the shell exits.
+type quietLogger struct{}
+
+func (quietLogger) Infof(string, ...any)  {}
+func (quietLogger) Errorf(string, ...any) {}
+
+// confirmer answers elicitation requests of the server, such as confirming a destructive tool
+// call, by asking the user to accept or decline. It can't fill in requested fields. Answers
+// are read from the shell's own reader, which may already have buffered them.
+type confirmer struct {
+	in  lineReader
+	out io.Writer
+}
+
+func (c confirmer) Elicit(_ context.Context, request mcp.ElicitationRequest) (*mcp.ElicitationResult, error) {
+	_, _ = fmt.Fprintln(c.out, request.Params.Message)
+	answer, err := c.in.ReadLine("Accept? [y/N] ")
+	if err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+	answer = strings.TrimSpace(answer)
+
+	result := &mcp.ElicitationResult{}
+	result.Action = mcp.ElicitationResponseActionDecline
+	if strings.EqualFold(answer, "y") || strings.EqualFold(answer, "yes") {
+		result.Action = mcp.ElicitationResponseActionAccept
+		result.Content = map[string]any{}
+	}
+	return result, nil
+}
+
+// shell runs the commands typed by the user in one session with the server. The lists of
+// tools, resources and prompts are kept for completion, and fetched again when the server
+// reports that they changed.
+type shell struct {
+	client *client.Client
+	in     lineReader
+	out    io.Writer
+	pretty bool
+
+	capabilities mcp.ServerCapabilities
+
+	// staleMu guards stale separately, so that notifications are never held up by a refresh
+	staleMu sync.Mutex
+	stale   map[string]bool
+
+	mu        sync.Mutex
+	tools     []mcp.Tool
+	resources []mcp.Resource
+	templates []mcp.ResourceTemplate
+	prompts   []mcp.Prompt
+}
+
+func (s *shell) initialize(ctx context.Context) error {
+	request := mcp.InitializeRequest{}
+	request.Params.ProtocolVersion = mcp.LATEST_PROTOCOL_VERSION
+	request.Params.ClientInfo = mcp.Implementation{Name: "mcpcurl", Version: "shell"}
+
+	result, err := s.client.Initialize(ctx, request)
+	if err != nil {
+		return fmt.Errorf("failed to initialize session: %w", err)
+	}
+	s.capabilities = result.Capabilities
+
+	_, _ = fmt.Fprintf(s.out, "Connected to %s %s. Type help for the commands.\n", result.ServerInfo.Name, result.ServerInfo.Version)
+	return nil
+}
+
+// handleNotification marks the lists the server reports as changed, so that they are fetched again.
+func (s *shell) handleNotification(notification mcp.JSONRPCNotification) {
+	s.staleMu.Lock()
+	defer s.staleMu.Unlock()
+	switch notification.Method {
+	case mcp.MethodNotificationToolsListChanged:
+		s.stale["tools"] = true
+	case mcp.MethodNotificationResourcesListChanged:
+		s.stale["resources"] = true
+	case mcp.MethodNotificationPromptsListChanged:
+		s.stale["prompts"] = true
+	}
+}
+
+// refresh fetches the lists that are stale. Lists the server doesn't offer are left empty.
+func (s *shell) refresh(ctx context.Context) error {
+	s.staleMu.Lock()
+	stale := s.stale
+	s.stale = make(map[string]bool)
+	s.staleMu.Unlock()
+
+	if stale["tools"] && s.capabilities.Tools != nil {
+		result, err := s.client.ListTools(ctx, mcp.ListToolsRequest{})
+		if err != nil {
+			return fmt.Errorf("failed to list tools: %w", err)
+		}
+		slices.SortFunc(result.Tools, func(a, b mcp.Tool) int { return strings.Compare(a.Name, b.Name) })
+		s.mu.Lock()
+		s.tools = result.Tools
+		s.mu.Unlock()
+	}
+	if stale["resources"] && s.capabilities.Resources != nil {
+		resources, err := s.client.ListResources(ctx, mcp.ListResourcesRequest{})
+		if err != nil {
+			return fmt.Errorf("failed to list resources: %w", err)
+		}
+		templates, err := s.client.ListResourceTemplates(ctx, mcp.ListResourceTemplatesRequest{})
+		if err != nil {
+			return fmt.Errorf("failed to list resource templates: %w", err)
+		}
+		s.mu.Lock()
+		s.resources, s.templates = resources.Resources, templates.ResourceTemplates
+		s.mu.Unlock()
+	}
+	if stale["prompts"] && s.capabilities.Prompts != nil {
+		result, err := s.client.ListPrompts(ctx, mcp.ListPromptsRequest{})
+		if err != nil {
+			return fmt.Errorf("failed to list prompts: %w", err)
+		}
+		s.mu.Lock()
+		s.prompts = result.Prompts
+		s.mu.Unlock()
+	}
+	return nil
+}
+
+func (s *shell) listTools() []mcp.Tool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.tools
+}
+
+func (s *shell) listResources() []mcp.Resource {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.resources
+}
+
+func (s *shell) listTemplates() []mcp.ResourceTemplate {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.templates
+}
+
+func (s *shell) listPrompts() []mcp.Prompt {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.prompts
+}
+
+// run reads and runs commands until the user exits or the input ends.
+func (s *shell) run(ctx context.Context) error {
+	for {
+		if err := s.refresh(ctx); err != nil {
+			return err
+		}
+
+		line, err := s.in.ReadLine("mcp> ")
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		words, _, err := splitWords(line)
+		if err == nil && len(words) > 0 {
+			if words[0] == "exit" || words[0] == "quit" {
+				return nil
+			}
+			err = s.runCommand(ctx, words[0], words[1:])
+		}
+		if err != nil {
+			_, _ = fmt.Fprintf(s.out, "error: %v\n", err)
+		}
+	}
+}
+
+func (s *shell) runCommand(ctx context.Context, name string, args []string) error {
+	switch name {
+	case "help":
+		w := tabwriter.NewWriter(s.out, 0, 0, 2, ' ', 0)
+		for _, command := range shellCommands {
+			_, _ = fmt.Fprintf(w, "%s %s\t%s\n", command.name, command.args, command.description)
+		}
+		return w.Flush()
+	case "tools":
+		w := tabwriter.NewWriter(s.out, 0, 0, 2, ' ', 0)
+		for _, tool := range s.listTools() {
+			_, _ = fmt.Fprintf(w, "%s\t%s\n", tool.Name, firstLine(tool.Description))
+		}
+		return w.Flush()
+	case "describe":
+		tool, err := s.findTool(args)
+		if err != nil {
+			return err
+		}
+		_, _ = fmt.Fprintf(s.out, "%s\n\n", tool.Description)
+		w := tabwriter.NewWriter(s.out, 0, 0, 2, ' ', 0)
+		for _, param := range toolParameters(tool) {
+			typ := param.typ
+			if len(param.enum) > 0 {
+				typ = strings.Join(param.enum, "|")
+			}
+			required := ""
+			if param.required {
+				required = "required"
+			}
+			_, _ = fmt.Fprintf(w, "%s\t%s\t%s\n", param.name, typ, required)
+		}
+		return w.Flush()
+	case "call":
+		tool, err := s.findTool(args)
+		if err != nil {
+			return err
+		}
+		arguments, err := parseArguments(args[1:], toolParameters(tool))
+		if err != nil {
+			return err
+		}
+		request := mcp.CallToolRequest{}
+		request.Params.Name = tool.Name
+		request.Params.Arguments = arguments
+		result, err := s.client.CallTool(ctx, request)
+		if err != nil {
+			return err
+		}
+		return s.printToolResult(result)
+	case "resources":
+		w := tabwriter.NewWriter(s.out, 0, 0, 2, ' ', 0)
+		for _, resource := range s.listResources() {
+			_, _ = fmt.Fprintf(w, "%s\t%s\n", resource.URI, resource.Name)
+		}
+		return w.Flush()
+	case "templates":
+		w := tabwriter.NewWriter(s.out, 0, 0, 2, ' ', 0)
+		for _, template := range s.listTemplates() {
+			if template.URITemplate != nil {
+				_, _ = fmt.Fprintf(w, "%s\t%s\n", template.URITemplate.Raw(), template.Name)
+			}
+		}
+		return w.Flush()
+	case "read":
+		if len(args) != 1 {
+			return fmt.Errorf("usage: read <uri>")
+		}
+		request := mcp.ReadResourceRequest{}
+		request.Params.URI = args[0]
+		result, err := s.client.ReadResource(ctx, request)
+		if err != nil {
+			return err
+		}
+		return s.printResourceContents(result.Contents)
+	case "prompts":
+		w := tabwriter.NewWriter(s.out, 0, 0, 2, ' ', 0)
+		for _, prompt := range s.listPrompts() {
+			_, _ = fmt.Fprintf(w, "%s\t%s\n", prompt.Name, firstLine(prompt.Description))
+		}
+		return w.Flush()
+	case "prompt":
+		if len(args) == 0 {
+			return fmt.Errorf("usage: prompt <name> [name=value ...]")
+		}
+		prompts := s.listPrompts()
+		i := slices.IndexFunc(prompts, func(prompt mcp.Prompt) bool { return prompt.Name == args[0] })
+		if i < 0 {
+			return fmt.Errorf("unknown prompt %s", args[0])
+		}
+		arguments, err := parseArguments(args[1:], promptParameters(prompts[i]))
+		if err != nil {
+			return err
+		}
+		request := mcp.GetPromptRequest{}
+		request.Params.Name = args[0]
+		request.Params.Arguments = make(map[string]string, len(arguments))
+		for name, value := range arguments {
+			request.Params.Arguments[name] = fmt.Sprint(value)
+		}
+		result, err := s.client.GetPrompt(ctx, request)
+		if err != nil {
+			return err
+		}
+		for _, message := range result.Messages {
+			_, _ = fmt.Fprintf(s.out, "[%s]\n", message.Role)
+			if err := s.printContent(message.Content); err != nil {
+				return err
+			}
+		}
+		return nil
+	default:
+		return fmt.Errorf("unknown command %s, type help for the commands", name)
+	}
+}
+
+func (s *shell) findTool(args []string) (mcp.Tool, error) {
+	if len(args) == 0 {
+		return mcp.Tool{}, fmt.Errorf("missing tool name")
+	}
+	tools := s.listTools()
+	i := slices.IndexFunc(tools, func(tool mcp.Tool) bool { return tool.Name == args[0] })
+	if i < 0 {
+		return mcp.Tool{}, fmt.Errorf("unknown tool %s", args[0])
+	}
+	return tools[i], nil
+}
+
+// printToolResult prints the content of a tool result, or the whole result as JSON without --pretty.
+func (s *shell) printToolResult(result *mcp.CallToolResult) error {
+	if !s.pretty {
+		return s.printJSON(result)
+	}
+	if result.IsError {
+		_, _ = fmt.Fprintln(s.out, "The tool returned an error:")
+	}
+	for _, content := range result.Content {
+		if err := s.printContent(content); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// printContent prints text as indented JSON if it is JSON, and as it is otherwise. Other
+// content is printed as JSON.
+func (s *shell) printContent(content mcp.Content) error {
+	text, ok := content.(mcp.TextContent)
+	if !ok {
+		return s.printJSON(content)
+	}
+	var value any
+	if s.pretty && json.Unmarshal([]byte(text.Text), &value) == nil {
+		return s.printJSON(value)
+	}
+	_, err := fmt.Fprintln(s.out, text.Text)
+	return err
+}
+
+func (s *shell) printResourceContents(contents []mcp.ResourceContents) error {
+	if !s.pretty {
+		return s.printJSON(contents)
+	}
+	for _, content := range contents {
+		switch content := content.(type) {
+		case mcp.TextResourceContents:
+			_, _ = fmt.Fprintln(s.out, content.Text)
+		case mcp.BlobResourceContents:
+			_, _ = fmt.Fprintf(s.out, "(%s, %d bytes encoded as base64)\n", content.MIMEType, len(content.Blob))
+		default:
+			if err := s.printJSON(content); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (s *shell) printJSON(value any) error {
+	data, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal output: %w", err)
+	}
+	_, err = fmt.Fprintln(s.out, string(data))
+	return err
+}
+
+func firstLine(s string) string {
+	line, _, _ := strings.Cut(s, "\n")
+	return line
+}
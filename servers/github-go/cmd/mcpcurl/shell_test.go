@@ -0,0 +1,40 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ConfirmerElicit(t *testing.T) {
+	// A script answers the confirmation on the line after the command that asks for it
+	in := &plainReader{scanner: bufio.NewScanner(strings.NewReader("call delete_file path=README.md\ny\nexit\n"))}
+	var out bytes.Buffer
+	c := confirmer{in: in, out: &out}
+
+	line, err := in.ReadLine("> ")
+	require.NoError(t, err)
+	require.Equal(t, "call delete_file path=README.md", line)
+
+	request := mcp.ElicitationRequest{}
+	request.Params.Message = "Delete README.md?"
+	result, err := c.Elicit(context.Background(), request)
+	require.NoError(t, err)
+	assert.Equal(t, mcp.ElicitationResponseActionAccept, result.Action)
+	assert.Equal(t, "Delete README.md?\n", out.String())
+
+	line, err = in.ReadLine("> ")
+	require.NoError(t, err)
+	assert.Equal(t, "exit", line)
+
+	// Without an answer the request is declined
+	result, err = c.Elicit(context.Background(), request)
+	require.NoError(t, err)
+	assert.Equal(t, mcp.ElicitationResponseActionDecline, result.Action)
+}
@@ -0,0 +1,11 @@
+//go:build !linux && !darwin && !freebsd && !netbsd && !openbsd
+
+package main
+
+import "os"
+
+// rawMode reports that terminals are not supported on this platform, so lines are read as
+// they are, without completion.
+func rawMode(_ *os.File) (func() (func(), error), bool) {
+	return nil, false
+}
@@ -0,0 +1,39 @@
+//go:build linux || darwin || freebsd || netbsd || openbsd
+
+package main
+
+import (
+	"os"
+
+	"golang.org/x/sys/unix"
+)
+
+// rawMode returns a function that puts the terminal in into raw mode and returns how to
+// restore it, or false if in is not a terminal.
+func rawMode(in *os.File) (func() (func(), error), bool) {
+	fd := int(in.Fd())
+	if _, err := unix.IoctlGetTermios(fd, ioctlGetTermios); err != nil {
+		return nil, false
+	}
+
+	return func() (func(), error) {
+		old, err := unix.IoctlGetTermios(fd, ioctlGetTermios)
+		if err != nil {
+			return nil, err
+		}
+
+		raw := *old
+		raw.Iflag &^= unix.IGNBRK | unix.BRKINT | unix.PARMRK | unix.ISTRIP | unix.INLCR | unix.IGNCR | unix.ICRNL | unix.IXON
+		raw.Oflag &^= unix.OPOST
+		raw.Lflag &^= unix.ECHO | unix.ECHONL | unix.ICANON | unix.ISIG | unix.IEXTEN
+		raw.Cflag &^= unix.CSIZE | unix.PARENB
+		raw.Cflag |= unix.CS8
+		raw.Cc[unix.VMIN] = 1
+		raw.Cc[unix.VTIME] = 0
+		if err := unix.IoctlSetTermios(fd, ioctlSetTermios, &raw); err != nil {
+			return nil, err
+		}
+
+		return func() { _ = unix.IoctlSetTermios(fd, ioctlSetTermios, old) }, nil
+	}, true
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/github/github-mcp-server/internal/toolsnaps"
+	"github.com/spf13/cobra"
+)
+
+var (
+	rootCmd = &cobra.Command{
+		Use:   "toolsnaps",
+		Short: "Work with tool snapshots",
+		Long:  `Work with the JSON snapshots of tools and resource templates that are kept in __toolsnaps__ directories.`,
+	}
+
+	compareCmd = &cobra.Command{
+		Use:   "compare <old-dir> <new-dir>",
+		Short: "Classify the changes between two sets of snapshots",
+		Long: `Compare the snapshots in two __toolsnaps__ directories, e.g. of the last release and of the
+working tree, and list each change as breaking or non-breaking. Fails if any change is breaking.
+The snapshots of any MCP server in this repository can be compared.`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			oldSnaps, err := toolsnaps.ReadDir(args[0])
+			if err != nil {
+				return err
+			}
+			newSnaps, err := toolsnaps.ReadDir(args[1])
+			if err != nil {
+				return err
+			}
+
+			changes, err := toolsnaps.Compare(oldSnaps, newSnaps)
+			if err != nil {
+				return err
+			}
+			if breaking := printChanges(cmd.OutOrStdout(), changes); breaking > 0 {
+				return fmt.Errorf("found %d breaking changes", breaking)
+			}
+			return nil
+		},
+	}
+)
+
+// printChanges lists the breaking changes and then the others, and returns how many changes
+// are breaking.
+func printChanges(w io.Writer, changes []toolsnaps.Change) int {
+	if len(changes) == 0 {
+		_, _ = fmt.Fprintln(w, "No changes")
+		return 0
+	}
+
+	var breaking, compatible []toolsnaps.Change
+	for _, change := range changes {
+		if change.Breaking {
+			breaking = append(breaking, change)
+		} else {
+			compatible = append(compatible, change)
+		}
+	}
+	for _, section := range []struct {
+		title   string
+		changes []toolsnaps.Change
+	}{
+		{"Breaking changes", breaking},
+		{"Non-breaking changes", compatible},
+	} {
+		if len(section.changes) == 0 {
+			continue
+		}
+		_, _ = fmt.Fprintf(w, "%s:\n", section.title)
+		for _, change := range section.changes {
+			_, _ = fmt.Fprintf(w, "  %s\n", change)
+		}
+	}
+	return len(breaking)
+}
+
+func main() {
+	rootCmd.AddCommand(compareCmd)
+	rootCmd.SilenceUsage = true
+
+	if err := rootCmd.Execute(); err != nil {
+		os.Exit(1)
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"net/http/httptest"
 	"os"
 	"os/exec"
 	"slices"
@@ -16,6 +17,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/github/github-mcp-server/internal/ghfake"
 	"github.com/github/github-mcp-server/internal/ghmcp"
 	"github.com/github/github-mcp-server/pkg/github"
 	"github.com/github/github-mcp-server/pkg/translations"
@@ -35,11 +37,34 @@ var (
 
 	buildOnce  sync.Once
 	buildError error
+
+	startFakeOnce sync.Once
+	fakeURL       string
 )
 
+// useFake reports whether the tests run against an in-memory fake of GitHub rather than a real host.
+func useFake() bool {
+	return os.Getenv("GITHUB_MCP_SERVER_E2E_FAKE") != ""
+}
+
+// getFakeURL starts the fake shared by all tests only once and returns its URL. The fake is
+// left running until the test binary exits.
+func getFakeURL(t *testing.T) string {
+	startFakeOnce.Do(func() {
+		fake, err := ghfake.New(&ghfake.Fixture{Viewer: "e2e-user"})
+		require.NoError(t, err, "expected to start the fake successfully")
+		fakeURL = httptest.NewServer(fake).URL
+	})
+	return fakeURL
+}
+
 // getE2EToken ensures the environment variable is checked only once and returns the token
 func getE2EToken(t *testing.T) string {
 	getTokenOnce.Do(func() {
+		if useFake() {
+			token = "fake-token"
+			return
+		}
 		token = os.Getenv("GITHUB_MCP_SERVER_E2E_TOKEN")
 		if token == "" {
 			t.Fatalf("GITHUB_MCP_SERVER_E2E_TOKEN environment variable is not set")
@@ -49,8 +74,12 @@ func getE2EToken(t *testing.T) string {
 }
 
 // getE2EHost ensures the environment variable is checked only once and returns the host
-func getE2EHost() string {
+func getE2EHost(t *testing.T) string {
 	getHostOnce.Do(func() {
+		if useFake() {
+			host = getFakeURL(t)
+			return
+		}
 		host = os.Getenv("GITHUB_MCP_SERVER_E2E_HOST")
 	})
 	return host
@@ -63,7 +92,7 @@ func getRESTClient(t *testing.T) *gogithub.Client {
 	// Create a new GitHub client with the token
 	ghClient := gogithub.NewClient(nil).WithAuthToken(token)
 
-	if host := getE2EHost(); host != "" && host != "https://github.com" {
+	if host := getE2EHost(t); host != "" && host != "https://github.com" {
 		var err error
 		// Currently this works for GHEC because the API is exposed at the api subdomain and the path prefix
 		// but it would be preferable to extract the host parsing from the main server logic, and use it here.
@@ -121,9 +150,10 @@ func setupMCPClient(t *testing.T, options ...clientOption) *mcpClient.Client {
 	}
 
 	// By default, we run the tests including the Docker image, but with DEBUG
-	// enabled, we run the server in-process, allowing for easier debugging.
+	// enabled, we run the server in-process, allowing for easier debugging. The
+	// fake only listens on localhost, so it is always used in-process too.
 	var client *mcpClient.Client
-	if os.Getenv("GITHUB_MCP_SERVER_E2E_DEBUG") == "" {
+	if os.Getenv("GITHUB_MCP_SERVER_E2E_DEBUG") == "" && !useFake() {
 		ensureDockerImageBuilt(t)
 
 		// Prepare Docker arguments
@@ -136,7 +166,7 @@ func setupMCPClient(t *testing.T, options ...clientOption) *mcpClient.Client {
 			"GITHUB_PERSONAL_ACCESS_TOKEN", // Personal access token is all required
 		}
 
-		host := getE2EHost()
+		host := getE2EHost(t)
 		if host != "" {
 			args = append(args, "-e", "GITHUB_HOST")
 		}
@@ -176,7 +206,7 @@ func setupMCPClient(t *testing.T, options ...clientOption) *mcpClient.Client {
 		ghServer, err := ghmcp.NewMCPServer(ghmcp.MCPServerConfig{
 			Token:           token,
 			EnabledToolsets: enabledToolsets,
-			Host:            getE2EHost(),
+			Host:            getE2EHost(t),
 			Translator:      translations.NullTranslationHelper,
 		})
 		require.NoError(t, err, "expected to construct MCP server successfully")
@@ -798,7 +828,7 @@ func TestDirectoryDeletion(t *testing.T) {
 func TestRequestCopilotReview(t *testing.T) {
 	t.Parallel()
 
-	if getE2EHost() != "" && getE2EHost() != "https://github.com" {
+	if getE2EHost(t) != "" && getE2EHost(t) != "https://github.com" {
 		t.Skip("Skipping test because the host does not support copilot reviews")
 	}
 
@@ -946,7 +976,7 @@ func TestRequestCopilotReview(t *testing.T) {
 func TestAssignCopilotToIssue(t *testing.T) {
 	t.Parallel()
 
-	if getE2EHost() != "" && getE2EHost() != "https://github.com" {
+	if getE2EHost(t) != "" && getE2EHost(t) != "https://github.com" {
 		t.Skip("Skipping test because the host does not support copilot being assigned to issues")
 	}
 
@@ -0,0 +1,209 @@
+package ghfake
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/google/go-github/v72/github"
+)
+
+// contextLines is the number of unchanged lines that patches show around changes.
+const contextLines = 3
+
+type lineOp struct {
+	// kind is ' ' for an unchanged line, '-' for a deleted one and '+' for an added one.
+	kind byte
+	line string
+}
+
+// diffLines returns the edits that turn a into b, from a longest common subsequence of their
+// lines. It is quadratic, which is fine for the small files of fixtures.
+func diffLines(a, b []string) []lineOp {
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var ops []lineOp
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			ops = append(ops, lineOp{' ', a[i]})
+			i++
+			j++
+		// Deletions come before additions, as in git.
+		case i < len(a) && (j == len(b) || lcs[i+1][j] >= lcs[i][j+1]):
+			ops = append(ops, lineOp{'-', a[i]})
+			i++
+		default:
+			ops = append(ops, lineOp{'+', b[j]})
+			j++
+		}
+	}
+	return ops
+}
+
+func splitLines(content string) []string {
+	if content == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(content, "\n"), "\n")
+}
+
+// patch returns the hunks of a unified diff between two versions of a file, without the
+// file headers, as the API shows them for the files of commits and pull requests.
+func patch(before, after string) (string, int, int) {
+	ops := diffLines(splitLines(before), splitLines(after))
+
+	var additions, deletions int
+	var changed []int
+	for i, op := range ops {
+		switch op.kind {
+		case '+':
+			additions++
+		case '-':
+			deletions++
+		default:
+			continue
+		}
+		changed = append(changed, i)
+	}
+
+	var b strings.Builder
+	for k := 0; k < len(changed); {
+		start := max(changed[k]-contextLines, 0)
+		end := changed[k]
+		for k < len(changed) && changed[k] <= end+2*contextLines {
+			end = changed[k]
+			k++
+		}
+		end = min(end+contextLines, len(ops)-1)
+
+		// Count the lines of both sides before and in the hunk.
+		oldStart, newStart := 1, 1
+		for _, op := range ops[:start] {
+			if op.kind != '+' {
+				oldStart++
+			}
+			if op.kind != '-' {
+				newStart++
+			}
+		}
+		var oldLines, newLines int
+		for _, op := range ops[start : end+1] {
+			if op.kind != '+' {
+				oldLines++
+			}
+			if op.kind != '-' {
+				newLines++
+			}
+		}
+		if oldLines == 0 {
+			oldStart--
+		}
+		if newLines == 0 {
+			newStart--
+		}
+
+		if b.Len() > 0 {
+			b.WriteString("\n")
+		}
+		fmt.Fprintf(&b, "@@ -%d,%d +%d,%d @@", oldStart, oldLines, newStart, newLines)
+		for _, op := range ops[start : end+1] {
+			b.WriteString("\n")
+			b.WriteByte(op.kind)
+			b.WriteString(op.line)
+		}
+	}
+	return b.String(), additions, deletions
+}
+
+func isText(content string) bool {
+	return utf8.ValidString(content) && !strings.ContainsRune(content, 0)
+}
+
+// diffFiles lists the files that differ between two trees, given as maps from paths to blob
+// SHAs, in the format of the files of commits and pull requests.
+func diffFiles(o *objectStore, before, after map[string]string) []*github.CommitFile {
+	paths := map[string]bool{}
+	for p := range before {
+		paths[p] = true
+	}
+	for p := range after {
+		paths[p] = true
+	}
+
+	files := []*github.CommitFile{}
+	for _, p := range sortedKeys(paths) {
+		oldSHA, inBefore := before[p]
+		newSHA, inAfter := after[p]
+		if oldSHA == newSHA {
+			continue
+		}
+		status, sha := "modified", newSHA
+		switch {
+		case !inBefore:
+			status = "added"
+		case !inAfter:
+			status, sha = "removed", oldSHA
+		}
+
+		file := &github.CommitFile{
+			SHA:       github.Ptr(sha),
+			Filename:  github.Ptr(p),
+			Status:    github.Ptr(status),
+			Additions: github.Ptr(0),
+			Deletions: github.Ptr(0),
+			Changes:   github.Ptr(0),
+		}
+		oldContent, newContent := o.blobs[oldSHA], o.blobs[newSHA]
+		if isText(oldContent) && isText(newContent) {
+			hunks, additions, deletions := patch(oldContent, newContent)
+			file.Patch = github.Ptr(hunks)
+			file.Additions = github.Ptr(additions)
+			file.Deletions = github.Ptr(deletions)
+			file.Changes = github.Ptr(additions + deletions)
+		}
+		files = append(files, file)
+	}
+	return files
+}
+
+// unifiedDiff renders files as a diff in the format of git diff, which the API returns for
+// the diff media type.
+func unifiedDiff(files []*github.CommitFile) string {
+	var b strings.Builder
+	for _, file := range files {
+		name := file.GetFilename()
+		fmt.Fprintf(&b, "diff --git a/%s b/%s\n", name, name)
+		oldName, newName := "a/"+name, "b/"+name
+		switch file.GetStatus() {
+		case "added":
+			b.WriteString("new file mode 100644\n")
+			oldName = "/dev/null"
+		case "removed":
+			b.WriteString("deleted file mode 100644\n")
+			newName = "/dev/null"
+		}
+		if file.Patch == nil {
+			fmt.Fprintf(&b, "Binary files %s and %s differ\n", oldName, newName)
+			continue
+		}
+		if file.GetPatch() == "" {
+			continue
+		}
+		fmt.Fprintf(&b, "--- %s\n+++ %s\n%s\n", oldName, newName, file.GetPatch())
+	}
+	return b.String()
+}
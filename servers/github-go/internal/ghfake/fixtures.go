@@ -0,0 +1,328 @@
+package ghfake
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Fixture is the initial state of a fake, usually loaded from a JSON file. Users that are
+// referenced but not listed are created as regular users.
+type Fixture struct {
+	// Viewer is the login of the user that tokens authenticate as.
+	Viewer string `json:"viewer,omitempty"`
+	// Tokens maps tokens to the logins of their users. If there are none, any token is
+	// accepted as the viewer's.
+	Tokens        map[string]string     `json:"tokens,omitempty"`
+	Users         []FixtureUser         `json:"users,omitempty"`
+	Repositories  []FixtureRepository   `json:"repositories,omitempty"`
+	Notifications []FixtureNotification `json:"notifications,omitempty"`
+}
+
+type FixtureUser struct {
+	Login string `json:"login"`
+	Name  string `json:"name,omitempty"`
+	Email string `json:"email,omitempty"`
+	// Type is "User", "Bot" or "Organization", and defaults to "User".
+	Type string `json:"type,omitempty"`
+}
+
+type FixtureRepository struct {
+	Owner       string `json:"owner"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Private     bool   `json:"private,omitempty"`
+	// DefaultBranch defaults to "main".
+	DefaultBranch string `json:"default_branch,omitempty"`
+	// ForkOf is the full name of the repository this one is a fork of, which must come
+	// earlier in the fixture. Forks start with the branches and tags of their parents.
+	ForkOf string `json:"fork_of,omitempty"`
+	// Files are the files of the initial commit on the default branch, by path.
+	Files map[string]string `json:"files,omitempty"`
+	// Branches are commits on top of the default branch or other branches, in order.
+	Branches []FixtureBranch `json:"branches,omitempty"`
+	// Tags maps the names of lightweight tags to the branches they point to.
+	Tags map[string]string `json:"tags,omitempty"`
+	// Issues are numbered in order, before the pull requests.
+	Issues       []FixtureIssue       `json:"issues,omitempty"`
+	PullRequests []FixturePullRequest `json:"pull_requests,omitempty"`
+}
+
+// FixtureBranch is a commit on a branch. The branch is created from From, or from the
+// default branch, if it doesn't exist yet.
+type FixtureBranch struct {
+	Name    string `json:"name"`
+	From    string `json:"from,omitempty"`
+	Message string `json:"message,omitempty"`
+	Author  string `json:"author,omitempty"`
+	// Files maps paths to their new contents, or to null to delete them.
+	Files map[string]*string `json:"files"`
+}
+
+type FixtureIssue struct {
+	Title     string           `json:"title"`
+	Body      string           `json:"body,omitempty"`
+	Author    string           `json:"author,omitempty"`
+	State     string           `json:"state,omitempty"`
+	Labels    []string         `json:"labels,omitempty"`
+	Assignees []string         `json:"assignees,omitempty"`
+	Comments  []FixtureComment `json:"comments,omitempty"`
+}
+
+type FixtureComment struct {
+	Author string `json:"author,omitempty"`
+	Body   string `json:"body"`
+}
+
+type FixturePullRequest struct {
+	FixtureIssue
+	// Head is a branch of the repository, or owner:branch for a branch of a fork.
+	Head               string   `json:"head"`
+	Base               string   `json:"base,omitempty"`
+	Draft              bool     `json:"draft,omitempty"`
+	RequestedReviewers []string `json:"requested_reviewers,omitempty"`
+}
+
+// FixtureNotification is a notification thread about an issue or pull request, or about
+// another subject with a title and a type such as "Release".
+type FixtureNotification struct {
+	// User defaults to the viewer.
+	User       string `json:"user,omitempty"`
+	Repository string `json:"repository"`
+	Number     int    `json:"number,omitempty"`
+	Title      string `json:"title,omitempty"`
+	Type       string `json:"type,omitempty"`
+	// Reason defaults to "subscribed".
+	Reason string `json:"reason,omitempty"`
+	// Unread defaults to true.
+	Unread *bool `json:"unread,omitempty"`
+}
+
+// LoadFixture reads a fixture from a JSON file.
+func LoadFixture(path string) (*Fixture, error) {
+	data, err := os.ReadFile(path) //nolint:gosec // fixtures are provided by the operator
+	if err != nil {
+		return nil, fmt.Errorf("failed to read fixture: %w", err)
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	var fixture Fixture
+	if err := decoder.Decode(&fixture); err != nil {
+		return nil, fmt.Errorf("failed to parse fixture %s: %w", path, err)
+	}
+	return &fixture, nil
+}
+
+func (s *Server) seed(f *Fixture) error {
+	if f.Viewer != "" {
+		s.viewer = f.Viewer
+	}
+	for token, login := range f.Tokens {
+		s.tokens[token] = login
+		s.ensureUser(login)
+	}
+	for _, fu := range f.Users {
+		u := s.ensureUser(fu.Login)
+		u.name, u.email = fu.Name, fu.Email
+		if fu.Type != "" {
+			u.typ = fu.Type
+		}
+	}
+	for _, fr := range f.Repositories {
+		if err := s.seedRepository(fr); err != nil {
+			return fmt.Errorf("repository %s/%s: %w", fr.Owner, fr.Name, err)
+		}
+	}
+	for _, fn := range f.Notifications {
+		if err := s.seedNotification(fn); err != nil {
+			return fmt.Errorf("notification in %s: %w", fn.Repository, err)
+		}
+	}
+	return nil
+}
+
+// author returns the user with the login, or the viewer if it is empty.
+func (s *Server) author(login string) *user {
+	if login == "" {
+		login = s.viewer
+	}
+	return s.ensureUser(login)
+}
+
+func (s *Server) seedRepository(fr FixtureRepository) error {
+	if _, ok := s.repository(fr.Owner, fr.Name); ok {
+		return fmt.Errorf("repository already exists")
+	}
+	owner := s.ensureUser(fr.Owner)
+	// Commits are authored by the owner of the repository, unless it is an organization.
+	committer := owner
+	if owner.typ == "Organization" {
+		committer = s.author("")
+	}
+
+	var repo *repository
+	if fr.ForkOf != "" {
+		parentOwner, parentName, _ := strings.Cut(fr.ForkOf, "/")
+		parent, ok := s.repository(parentOwner, parentName)
+		if !ok {
+			return fmt.Errorf("parent %s does not exist", fr.ForkOf)
+		}
+		repo = s.newRepository(owner, fr.Name, parent.objects)
+		repo.parent = parent
+		repo.defaultBranch = parent.defaultBranch
+		for name, sha := range parent.refs {
+			if strings.HasPrefix(name, "refs/heads/") || strings.HasPrefix(name, "refs/tags/") {
+				repo.refs[name] = sha
+			}
+		}
+	} else {
+		repo = s.newRepository(owner, fr.Name, nil)
+	}
+	repo.description = fr.Description
+	repo.private = fr.Private
+	if fr.DefaultBranch != "" {
+		repo.defaultBranch = fr.DefaultBranch
+	}
+
+	if len(fr.Files) > 0 {
+		if _, ok := repo.branchHead(repo.defaultBranch); ok {
+			return fmt.Errorf("files can only be given for new repositories, use branches to change them")
+		}
+		changes := make(map[string]*string, len(fr.Files))
+		for p, content := range fr.Files {
+			changes[p] = &content
+		}
+		c := s.commitChanges(repo.objects, nil, changes, "Initial commit", committer)
+		repo.refs["refs/heads/"+repo.defaultBranch] = c.sha
+	}
+
+	for _, fb := range fr.Branches {
+		parent, ok := repo.branchHead(fb.Name)
+		if !ok {
+			from := fb.From
+			if from == "" {
+				from = repo.defaultBranch
+			}
+			if parent, ok = repo.branchHead(from); !ok && len(repo.refs) > 0 {
+				return fmt.Errorf("branch %s does not exist", from)
+			}
+		}
+		message := fb.Message
+		if message == "" {
+			message = "Update " + strings.Join(sortedKeys(fb.Files), ", ")
+		}
+		author := committer
+		if fb.Author != "" {
+			author = s.ensureUser(fb.Author)
+		}
+		c := s.commitChanges(repo.objects, parent, fb.Files, message, author)
+		repo.refs["refs/heads/"+fb.Name] = c.sha
+	}
+
+	for tag, branch := range fr.Tags {
+		head, ok := repo.branchHead(branch)
+		if !ok {
+			return fmt.Errorf("branch %s of tag %s does not exist", branch, tag)
+		}
+		repo.refs["refs/tags/"+tag] = head.sha
+	}
+
+	for _, fi := range fr.Issues {
+		if _, err := s.seedIssue(repo, fi); err != nil {
+			return err
+		}
+	}
+	for _, fp := range fr.PullRequests {
+		if err := s.seedPullRequest(repo, fp); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *Server) seedIssue(repo *repository, fi FixtureIssue) (*issue, error) {
+	i := s.newIssue(repo, s.author(fi.Author), fi.Title, fi.Body)
+	i.labels = fi.Labels
+	for _, login := range fi.Assignees {
+		i.assignees = append(i.assignees, s.ensureUser(login))
+	}
+	for _, fc := range fi.Comments {
+		i.comments = append(i.comments, &issueComment{
+			id:        s.nextID(),
+			author:    s.author(fc.Author),
+			body:      fc.Body,
+			createdAt: i.createdAt,
+			updatedAt: i.createdAt,
+		})
+	}
+	if fi.State != "" && !i.setState(fi.State, "", i.author, i.createdAt) {
+		return nil, fmt.Errorf("issue %q has the invalid state %q", fi.Title, fi.State)
+	}
+	return i, nil
+}
+
+func (s *Server) seedPullRequest(repo *repository, fp FixturePullRequest) error {
+	base := fp.Base
+	if base == "" {
+		base = repo.defaultBranch
+	}
+	if _, ok := repo.branchHead(base); !ok {
+		return fmt.Errorf("base %s of pull request %q does not exist", base, fp.Title)
+	}
+	headRepo, headRef := s.headRepository(repo, fp.Head)
+	if headRepo == nil {
+		return fmt.Errorf("head %s of pull request %q is not in a fork", fp.Head, fp.Title)
+	}
+	head, ok := headRepo.branchHead(headRef)
+	if !ok {
+		return fmt.Errorf("head %s of pull request %q does not exist", fp.Head, fp.Title)
+	}
+
+	i, err := s.seedIssue(repo, fp.FixtureIssue)
+	if err != nil {
+		return err
+	}
+	i.pull = &pull{
+		issue:               i,
+		head:                headRepo,
+		headRef:             headRef,
+		headSHA:             head.sha,
+		base:                base,
+		draft:               fp.Draft,
+		maintainerCanModify: true,
+	}
+	for _, login := range fp.RequestedReviewers {
+		i.pull.requestedReviewers = append(i.pull.requestedReviewers, s.ensureUser(login))
+	}
+	return nil
+}
+
+func (s *Server) seedNotification(fn FixtureNotification) error {
+	owner, name, _ := strings.Cut(fn.Repository, "/")
+	repo, ok := s.repository(owner, name)
+	if !ok {
+		return fmt.Errorf("repository does not exist")
+	}
+	n := &notification{
+		id:          s.nextID(),
+		user:        s.author(fn.User),
+		repo:        repo,
+		title:       fn.Title,
+		subjectType: fn.Type,
+		reason:      fn.Reason,
+		unread:      fn.Unread == nil || *fn.Unread,
+		updatedAt:   s.now(),
+	}
+	if fn.Number != 0 {
+		if n.issue = repo.issueByNumber(fmt.Sprint(fn.Number)); n.issue == nil {
+			return fmt.Errorf("issue %d does not exist", fn.Number)
+		}
+	}
+	if n.reason == "" {
+		n.reason = "subscribed"
+	}
+	s.notifications = append(s.notifications, n)
+	return nil
+}
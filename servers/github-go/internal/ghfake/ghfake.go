@@ -0,0 +1,232 @@
+// Package ghfake provides a stateful, in-memory stand-in for the GitHub REST and GraphQL APIs.
+// It implements the repository, contents, branch, issue, pull request, review and notification
+// endpoints that the tools of the MCP server use, so that whole workflows can run offline
+// against it, e.g. in demos and end-to-end tests.
+//
+// The server is laid out like GitHub Enterprise Server, with the REST API under /api/v3/ and
+// the GraphQL API at /api/graphql, so that the MCP server can be pointed at it with
+// --gh-host http://localhost:PORT. Its state can be seeded from fixture files.
+package ghfake
+
+import (
+	"context"
+	"crypto/sha1" //nolint:gosec // Git object IDs are SHA-1 hashes
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Server is the fake GitHub. All of its state is kept in memory, and is guarded by a single
+// lock that every request holds while it is handled.
+type Server struct {
+	mu sync.Mutex
+
+	// viewer is the login of the user that tokens authenticate as, unless tokens says otherwise.
+	viewer string
+	// tokens maps the tokens that are accepted to the logins of their users. If it is empty,
+	// any token is accepted as the viewer's.
+	tokens map[string]string
+
+	users         map[string]*user
+	repos         map[string]*repository
+	notifications []*notification
+	lastID        int64
+	seq           int
+
+	now func() time.Time
+	mux *http.ServeMux
+}
+
+// New returns a fake seeded with the fixtures. Without a fixture that names the viewer, tokens
+// authenticate as the user "octocat".
+func New(fixtures ...*Fixture) (*Server, error) {
+	s := &Server{
+		viewer: "octocat",
+		tokens: make(map[string]string),
+		users:  make(map[string]*user),
+		repos:  make(map[string]*repository),
+		now:    func() time.Time { return time.Now().UTC().Truncate(time.Second) },
+	}
+	for _, fixture := range fixtures {
+		if err := s.seed(fixture); err != nil {
+			return nil, err
+		}
+	}
+	s.ensureUser(s.viewer)
+
+	s.mux = http.NewServeMux()
+	s.routes()
+	return s, nil
+}
+
+// ServeHTTP serves the REST API under /api/v3/, the GraphQL API at /api/graphql, and the raw
+// content of files under /raw/.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.mux.ServeHTTP(w, r)
+}
+
+func (s *Server) routes() {
+	rest := http.NewServeMux()
+	s.userRoutes(rest)
+	s.repoRoutes(rest)
+	s.gitRoutes(rest)
+	s.issueRoutes(rest)
+	s.pullRoutes(rest)
+	s.notificationRoutes(rest)
+	s.searchRoutes(rest)
+	rest.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		writeError(w, http.StatusNotImplemented, fmt.Sprintf("%s %s is not implemented by the fake", r.Method, r.URL.Path))
+	})
+
+	s.mux.Handle("/api/v3/", http.StripPrefix("/api/v3", s.authenticated(rest)))
+	s.mux.Handle("POST /api/graphql", s.authenticated(http.HandlerFunc(s.serveGraphQL)))
+	s.mux.HandleFunc("GET /raw/{owner}/{repo}/{sha}/{path...}", s.getRaw)
+}
+
+type loginKey struct{}
+
+// authenticated rejects requests without an accepted token, and passes the others on with
+// the login of their user in the context.
+func (s *Server) authenticated(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		scheme, token, _ := strings.Cut(r.Header.Get("Authorization"), " ")
+		login := s.viewer
+		if len(s.tokens) > 0 {
+			login = s.tokens[token]
+		}
+		if token == "" || (!strings.EqualFold(scheme, "bearer") && !strings.EqualFold(scheme, "token")) || login == "" {
+			writeError(w, http.StatusUnauthorized, "Bad credentials")
+			return
+		}
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), loginKey{}, login)))
+	})
+}
+
+// caller returns the user that made an authenticated request.
+func (s *Server) caller(r *http.Request) *user {
+	login, _ := r.Context().Value(loginKey{}).(string)
+	return s.ensureUser(login)
+}
+
+// nextID returns a new ID for an object, unique across all kinds of objects.
+func (s *Server) nextID() int64 {
+	s.lastID++
+	return s.lastID
+}
+
+// tick returns the current time, and a sequence number that orders objects created at the
+// same second.
+func (s *Server) tick() (time.Time, int) {
+	s.seq++
+	return s.now(), s.seq
+}
+
+// urls builds the URLs of the API and web pages of the fake, from the host a request was
+// sent to.
+type urls struct {
+	base string
+}
+
+func urlsFor(r *http.Request) urls {
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+	return urls{base: scheme + "://" + r.Host}
+}
+
+func (u urls) api(format string, args ...any) string {
+	return u.base + "/api/v3/" + fmt.Sprintf(format, args...)
+}
+
+func (u urls) html(format string, args ...any) string {
+	return u.base + "/" + fmt.Sprintf(format, args...)
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// writeError writes an error in the format of the GitHub API.
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, map[string]string{
+		"message":           message,
+		"documentation_url": "https://docs.github.com/rest",
+	})
+}
+
+func notFound(w http.ResponseWriter) {
+	writeError(w, http.StatusNotFound, "Not Found")
+}
+
+// decodeBody decodes the JSON body of a request into v, and writes an error if it can't.
+func decodeBody(w http.ResponseWriter, r *http.Request, v any) bool {
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		writeError(w, http.StatusBadRequest, "Problems parsing JSON")
+		return false
+	}
+	return true
+}
+
+// paginate returns the page of items that a request asks for with the page and per_page
+// query parameters, and links the next and last pages like GitHub does.
+func paginate[T any](w http.ResponseWriter, r *http.Request, items []T) []T {
+	query := r.URL.Query()
+	page, _ := strconv.Atoi(query.Get("page"))
+	page = max(page, 1)
+	perPage, _ := strconv.Atoi(query.Get("per_page"))
+	if perPage <= 0 {
+		perPage = 30
+	}
+	perPage = min(perPage, 100)
+
+	last := max((len(items)+perPage-1)/perPage, 1)
+	if page < last {
+		link := func(page int, rel string) string {
+			// The request URI still has the path prefix that was stripped from the URL
+			u, _ := url.Parse(r.RequestURI)
+			u.Scheme, u.Host = "http", r.Host
+			q := u.Query()
+			q.Set("page", strconv.Itoa(page))
+			u.RawQuery = q.Encode()
+			return fmt.Sprintf("<%s>; rel=%q", u.String(), rel)
+		}
+		w.Header().Set("Link", link(page+1, "next")+", "+link(last, "last"))
+	}
+
+	start := min((page-1)*perPage, len(items))
+	end := min(start+perPage, len(items))
+	return items[start:end]
+}
+
+// sortByTime sorts items by the times returned by key, newest first unless direction is
+// "asc". Items with the same time keep their order.
+func sortByTime[T any](items []T, direction string, key func(T) time.Time) {
+	sort.SliceStable(items, func(i, j int) bool {
+		if direction == "asc" {
+			return key(items[i]).Before(key(items[j]))
+		}
+		return key(items[i]).After(key(items[j]))
+	})
+}
+
+// hash returns a hex encoded SHA-1 hash of the parts, which the fake uses for the IDs of git
+// objects. They are not the IDs that git would compute for the same objects.
+func hash(parts ...string) string {
+	h := sha1.New() //nolint:gosec // Git object IDs are SHA-1 hashes
+	for _, part := range parts {
+		_, _ = fmt.Fprintf(h, "%d:%s\n", len(part), part)
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
@@ -0,0 +1,250 @@
+package ghfake_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/github/github-mcp-server/internal/ghfake"
+	"github.com/github/github-mcp-server/internal/ghmcp"
+	"github.com/github/github-mcp-server/pkg/github"
+	"github.com/github/github-mcp-server/pkg/translations"
+	gogithub "github.com/google/go-github/v72/github"
+	mcpClient "github.com/mark3labs/mcp-go/client"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var fixture = &ghfake.Fixture{
+	Viewer: "octocat",
+	Tokens: map[string]string{"octocat-token": "octocat", "hubot-token": "hubot"},
+	Users:  []ghfake.FixtureUser{{Login: "octo-org", Type: "Organization"}},
+	Repositories: []ghfake.FixtureRepository{
+		{
+			Owner:       "octo-org",
+			Name:        "hello-world",
+			Description: "My first repository",
+			Files:       map[string]string{"README.md": "# Hello\n\nHello, world!\n"},
+			Branches: []ghfake.FixtureBranch{
+				{
+					Name:    "greeting",
+					Author:  "hubot",
+					Message: "Greet the fake",
+					Files:   map[string]*string{"README.md": gogithub.Ptr("# Hello\n\nHello, fake!\n")},
+				},
+			},
+			Issues: []ghfake.FixtureIssue{
+				{Title: "Greet the fake", Author: "hubot", Labels: []string{"enhancement"}},
+			},
+			PullRequests: []ghfake.FixturePullRequest{
+				{
+					FixtureIssue:       ghfake.FixtureIssue{Title: "Greet the fake", Body: "Fixes #1", Author: "hubot"},
+					Head:               "greeting",
+					RequestedReviewers: []string{"octocat"},
+				},
+			},
+		},
+	},
+	Notifications: []ghfake.FixtureNotification{
+		{Repository: "octo-org/hello-world", Number: 2, Reason: "review_requested"},
+	},
+}
+
+// setupMCPClient starts a fake seeded with the fixture and an MCP server pointed at it.
+func setupMCPClient(t *testing.T) *mcpClient.Client {
+	fake, err := ghfake.New(fixture)
+	require.NoError(t, err)
+	srv := httptest.NewServer(fake)
+	t.Cleanup(srv.Close)
+
+	ghServer, err := ghmcp.NewMCPServer(ghmcp.MCPServerConfig{
+		Token:           "octocat-token",
+		EnabledToolsets: github.DefaultTools,
+		Host:            srv.URL,
+		Translator:      translations.NullTranslationHelper,
+	})
+	require.NoError(t, err)
+
+	client, err := mcpClient.NewInProcessClient(ghServer)
+	require.NoError(t, err)
+	t.Cleanup(func() { require.NoError(t, client.Close()) })
+
+	request := mcp.InitializeRequest{}
+	request.Params.ProtocolVersion = "2025-03-26"
+	request.Params.ClientInfo = mcp.Implementation{Name: "ghfake-test-client", Version: "0.0.1"}
+	_, err = client.Initialize(context.Background(), request)
+	require.NoError(t, err)
+	return client
+}
+
+// callTool calls a tool that is expected to succeed and returns its text.
+func callTool(t *testing.T, client *mcpClient.Client, name string, args map[string]any) string {
+	t.Helper()
+	request := mcp.CallToolRequest{}
+	request.Params.Name = name
+	request.Params.Arguments = args
+	result, err := client.CallTool(context.Background(), request)
+	require.NoError(t, err)
+	require.NotEmpty(t, result.Content)
+	text, ok := result.Content[0].(mcp.TextContent)
+	require.True(t, ok, "expected text content")
+	require.False(t, result.IsError, "expected %s to succeed: %s", name, text.Text)
+	return text.Text
+}
+
+func Test_ReviewAndMergeWorkflow(t *testing.T) {
+	client := setupMCPClient(t)
+	repo := map[string]any{"owner": "octo-org", "repo": "hello-world"}
+	with := func(args map[string]any) map[string]any {
+		merged := map[string]any{}
+		for k, v := range repo {
+			merged[k] = v
+		}
+		for k, v := range args {
+			merged[k] = v
+		}
+		return merged
+	}
+
+	var notifications []gogithub.Notification
+	require.NoError(t, json.Unmarshal([]byte(callTool(t, client, "list_notifications", nil)), &notifications))
+	require.Len(t, notifications, 1)
+	assert.Equal(t, "Greet the fake", notifications[0].GetSubject().GetTitle())
+	assert.Equal(t, "PullRequest", notifications[0].GetSubject().GetType())
+	assert.Equal(t, "review_requested", notifications[0].GetReason())
+
+	diff := callTool(t, client, "get_pull_request_diff", with(map[string]any{"pullNumber": 2}))
+	assert.Contains(t, diff, "diff --git a/README.md b/README.md")
+	assert.Contains(t, diff, "-Hello, world!\n+Hello, fake!")
+
+	callTool(t, client, "create_and_submit_pull_request_review", with(map[string]any{
+		"pullNumber": 2,
+		"body":       "Looks good",
+		"event":      "APPROVE",
+	}))
+	var reviews []gogithub.PullRequestReview
+	require.NoError(t, json.Unmarshal([]byte(callTool(t, client, "get_pull_request_reviews", with(map[string]any{"pullNumber": 2}))), &reviews))
+	require.Len(t, reviews, 1)
+	assert.Equal(t, "APPROVED", reviews[0].GetState())
+	assert.Equal(t, "octocat", reviews[0].GetUser().GetLogin())
+
+	callTool(t, client, "merge_pull_request", with(map[string]any{"pullNumber": 2, "merge_method": "squash"}))
+	var pr gogithub.PullRequest
+	require.NoError(t, json.Unmarshal([]byte(callTool(t, client, "get_pull_request", with(map[string]any{"pullNumber": 2}))), &pr))
+	assert.True(t, pr.GetMerged())
+	assert.Equal(t, "closed", pr.GetState())
+
+	var readme gogithub.RepositoryContent
+	require.NoError(t, json.Unmarshal([]byte(callTool(t, client, "get_file_contents", with(map[string]any{"path": "README.md", "branch": "main"}))), &readme))
+	content, err := readme.GetContent()
+	require.NoError(t, err)
+	assert.Equal(t, "# Hello\n\nHello, fake!\n", content)
+
+	var result gogithub.IssuesSearchResult
+	require.NoError(t, json.Unmarshal([]byte(callTool(t, client, "search_issues", map[string]any{"q": "repo:octo-org/hello-world is:pr is:merged"})), &result))
+	assert.Equal(t, 1, result.GetTotal())
+
+	callTool(t, client, "update_issue", with(map[string]any{"issue_number": 1, "state": "closed"}))
+	var issue gogithub.Issue
+	require.NoError(t, json.Unmarshal([]byte(callTool(t, client, "get_issue", with(map[string]any{"issue_number": 1}))), &issue))
+	assert.Equal(t, "closed", issue.GetState())
+	assert.Equal(t, "octocat", issue.GetClosedBy().GetLogin())
+}
+
+func Test_CommitWorkflow(t *testing.T) {
+	client := setupMCPClient(t)
+
+	callTool(t, client, "create_repository", map[string]any{"name": "scratch", "autoInit": true})
+
+	callTool(t, client, "create_branch", map[string]any{"owner": "octocat", "repo": "scratch", "branch": "docs", "from_branch": "main"})
+	callTool(t, client, "push_files", map[string]any{
+		"owner":   "octocat",
+		"repo":    "scratch",
+		"branch":  "docs",
+		"message": "Add docs",
+		"files": []any{
+			map[string]any{"path": "docs/a.md", "content": "a\n"},
+			map[string]any{"path": "docs/b.md", "content": "b\n"},
+		},
+	})
+
+	var commits []gogithub.RepositoryCommit
+	require.NoError(t, json.Unmarshal([]byte(callTool(t, client, "list_commits", map[string]any{"owner": "octocat", "repo": "scratch", "sha": "docs"})), &commits))
+	require.Len(t, commits, 2)
+	assert.Equal(t, "Add docs", commits[0].GetCommit().GetMessage())
+
+	var commit gogithub.RepositoryCommit
+	require.NoError(t, json.Unmarshal([]byte(callTool(t, client, "get_commit", map[string]any{"owner": "octocat", "repo": "scratch", "sha": commits[0].GetSHA()})), &commit))
+	require.Len(t, commit.Files, 2)
+	assert.Equal(t, "docs/a.md", commit.Files[0].GetFilename())
+	assert.Equal(t, "added", commit.Files[0].GetStatus())
+
+	var pr gogithub.PullRequest
+	require.NoError(t, json.Unmarshal([]byte(callTool(t, client, "create_pull_request", map[string]any{
+		"owner": "octocat",
+		"repo":  "scratch",
+		"title": "Add docs",
+		"head":  "docs",
+		"base":  "main",
+	})), &pr))
+	assert.Equal(t, 1, pr.GetNumber())
+	assert.Equal(t, "octocat", pr.GetUser().GetLogin())
+}
+
+func Test_Tokens(t *testing.T) {
+	fake, err := ghfake.New(fixture)
+	require.NoError(t, err)
+	srv := httptest.NewServer(fake)
+	t.Cleanup(srv.Close)
+
+	tests := []struct {
+		name           string
+		token          string
+		expectedStatus int
+		expectedLogin  string
+	}{
+		{name: "viewer", token: "octocat-token", expectedStatus: http.StatusOK, expectedLogin: "octocat"},
+		{name: "other user", token: "hubot-token", expectedStatus: http.StatusOK, expectedLogin: "hubot"},
+		{name: "unknown token", token: "nope", expectedStatus: http.StatusUnauthorized},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			client, err := gogithub.NewClient(nil).WithAuthToken(tc.token).WithEnterpriseURLs(srv.URL, srv.URL)
+			require.NoError(t, err)
+			user, resp, err := client.Users.Get(context.Background(), "")
+			require.Equal(t, tc.expectedStatus, resp.StatusCode)
+			if tc.expectedStatus != http.StatusOK {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedLogin, user.GetLogin())
+		})
+	}
+}
+
+func Test_LoadFixture(t *testing.T) {
+	dir := t.TempDir()
+	valid := filepath.Join(dir, "valid.json")
+	require.NoError(t, os.WriteFile(valid, []byte(`{"viewer": "octocat", "repositories": [{"owner": "octocat", "name": "hello-world", "files": {"README.md": "hi"}}]}`), 0600))
+	unknown := filepath.Join(dir, "unknown.json")
+	require.NoError(t, os.WriteFile(unknown, []byte(`{"viewer": "octocat", "repos": []}`), 0600))
+
+	loaded, err := ghfake.LoadFixture(valid)
+	require.NoError(t, err)
+	assert.Equal(t, "octocat", loaded.Viewer)
+	require.Len(t, loaded.Repositories, 1)
+	assert.Equal(t, map[string]string{"README.md": "hi"}, loaded.Repositories[0].Files)
+
+	_, err = ghfake.LoadFixture(unknown)
+	require.ErrorContains(t, err, `unknown field "repos"`)
+
+	_, err = ghfake.New(&ghfake.Fixture{Repositories: []ghfake.FixtureRepository{{Owner: "octocat", Name: "fork", ForkOf: "octocat/missing"}}})
+	require.ErrorContains(t, err, "parent octocat/missing does not exist")
+}
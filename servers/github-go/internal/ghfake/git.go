@@ -0,0 +1,693 @@
+package ghfake
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/go-github/v72/github"
+)
+
+// objectStore holds the git objects of a repository. Forks share the store of the repository
+// they were forked from, so that pull requests can be opened across them, as on GitHub.
+type objectStore struct {
+	blobs map[string]string
+	// trees maps the SHA of a tree to the SHAs of the blobs in it by path. Trees are flat,
+	// directories exist only as the prefixes of the paths in them.
+	trees   map[string]map[string]string
+	commits map[string]*commit
+	tags    map[string]*tagObject
+}
+
+func newObjectStore() *objectStore {
+	return &objectStore{
+		blobs:   make(map[string]string),
+		trees:   make(map[string]map[string]string),
+		commits: make(map[string]*commit),
+		tags:    make(map[string]*tagObject),
+	}
+}
+
+type commit struct {
+	sha     string
+	tree    string
+	message string
+	author  *user
+	date    time.Time
+	// seq orders commits made at the same second.
+	seq     int
+	parents []string
+}
+
+// tagObject is an annotated tag.
+type tagObject struct {
+	sha        string
+	name       string
+	message    string
+	objectSHA  string
+	objectType string
+	tagger     *user
+	date       time.Time
+}
+
+func (o *objectStore) putBlob(content string) string {
+	sha := hash("blob", content)
+	o.blobs[sha] = content
+	return sha
+}
+
+func (o *objectStore) putTree(files map[string]string) string {
+	paths := make([]string, 0, len(files))
+	for p := range files {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+	parts := []string{"tree"}
+	for _, p := range paths {
+		parts = append(parts, p, files[p])
+	}
+
+	sha := hash(parts...)
+	o.trees[sha] = files
+	return sha
+}
+
+// files returns the blob SHAs by path of the tree of a commit. The map must not be modified.
+func (o *objectStore) files(c *commit) map[string]string {
+	return o.trees[c.tree]
+}
+
+// file returns the content of the file at path in a commit.
+func (o *objectStore) file(c *commit, p string) (string, bool) {
+	sha, ok := o.files(c)[p]
+	if !ok {
+		return "", false
+	}
+	return o.blobs[sha], true
+}
+
+// isDir reports whether there are files under the directory at p in a commit. The root is
+// always a directory.
+func (o *objectStore) isDir(c *commit, p string) bool {
+	if p == "" {
+		return true
+	}
+	for file := range o.files(c) {
+		if strings.HasPrefix(file, p+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// putCommit stores a commit of the tree, and returns it.
+func (s *Server) putCommit(o *objectStore, tree, message string, author *user, parents []string) *commit {
+	date, seq := s.tick()
+	c := &commit{
+		tree:    tree,
+		message: message,
+		author:  author,
+		date:    date,
+		seq:     seq,
+		parents: parents,
+	}
+	c.sha = hash(append([]string{"commit", tree, message, author.login, date.String(), fmt.Sprint(seq)}, parents...)...)
+	o.commits[c.sha] = c
+	return c
+}
+
+// commitChanges commits changes to the files of parent, which may be nil for a first commit.
+// Changes map paths to new contents, or to nil to delete a file or a directory with all the
+// files under it.
+func (s *Server) commitChanges(o *objectStore, parent *commit, changes map[string]*string, message string, author *user) *commit {
+	files := make(map[string]string)
+	var parents []string
+	if parent != nil {
+		for p, sha := range o.files(parent) {
+			files[p] = sha
+		}
+		parents = []string{parent.sha}
+	}
+	applyChanges(o, files, changes)
+	return s.putCommit(o, o.putTree(files), message, author, parents)
+}
+
+func applyChanges(o *objectStore, files map[string]string, changes map[string]*string) {
+	for p, content := range changes {
+		if content != nil {
+			files[p] = o.putBlob(*content)
+			continue
+		}
+		for file := range files {
+			if file == p || strings.HasPrefix(file, p+"/") {
+				delete(files, file)
+			}
+		}
+	}
+}
+
+// history returns the commits reachable from head, newest first.
+func (o *objectStore) history(head *commit) []*commit {
+	seen := map[string]bool{}
+	var commits []*commit
+	queue := []*commit{head}
+	for len(queue) > 0 {
+		c := queue[0]
+		queue = queue[1:]
+		if seen[c.sha] {
+			continue
+		}
+		seen[c.sha] = true
+		commits = append(commits, c)
+		for _, parent := range c.parents {
+			if p, ok := o.commits[parent]; ok {
+				queue = append(queue, p)
+			}
+		}
+	}
+	sort.SliceStable(commits, func(i, j int) bool { return commits[i].seq > commits[j].seq })
+	return commits
+}
+
+// isAncestor reports whether ancestor is reachable from c, which includes c itself.
+func (o *objectStore) isAncestor(ancestor, c *commit) bool {
+	for _, h := range o.history(c) {
+		if h.sha == ancestor.sha {
+			return true
+		}
+	}
+	return false
+}
+
+// mergeBase returns the newest commit that both a and b descend from, if there is one.
+func (o *objectStore) mergeBase(a, b *commit) *commit {
+	inA := map[string]bool{}
+	for _, c := range o.history(a) {
+		inA[c.sha] = true
+	}
+	for _, c := range o.history(b) {
+		if inA[c.sha] {
+			return c
+		}
+	}
+	return nil
+}
+
+// merge merges the files of head into base, given the commit they both descend from. It
+// fails if a file was changed differently on both sides.
+func (o *objectStore) merge(mergeBase, base, head *commit) (map[string]string, error) {
+	var baseFiles map[string]string
+	if mergeBase != nil {
+		baseFiles = o.files(mergeBase)
+	}
+	ours, theirs := o.files(base), o.files(head)
+
+	merged := make(map[string]string)
+	for _, files := range []map[string]string{baseFiles, ours, theirs} {
+		for p := range files {
+			original, inOriginal := baseFiles[p]
+			our, inOurs := ours[p]
+			their, inTheirs := theirs[p]
+			switch {
+			case inOurs == inTheirs && our == their:
+				if inOurs {
+					merged[p] = our
+				}
+			case inOriginal == inTheirs && original == their:
+				if inOurs {
+					merged[p] = our
+				}
+			case inOriginal == inOurs && original == our:
+				if inTheirs {
+					merged[p] = their
+				}
+			default:
+				return nil, fmt.Errorf("merge conflict in %s", p)
+			}
+		}
+	}
+	return merged, nil
+}
+
+// resolve finds the commit a ref names: a SHA or a prefix of one, a branch or tag name, or
+// a full ref such as refs/heads/main, refs/tags/v1 or refs/pull/1/head.
+func (repo *repository) resolve(ref string) (*commit, bool) {
+	candidates := []string{ref, "refs/heads/" + ref, "refs/tags/" + ref}
+	for _, candidate := range candidates {
+		if sha, ok := repo.refs[candidate]; ok {
+			return repo.peel(sha)
+		}
+	}
+	if number, ok := strings.CutPrefix(ref, "refs/pull/"); ok {
+		if number, ok := strings.CutSuffix(number, "/head"); ok {
+			if pr := repo.pullByNumber(number); pr != nil {
+				return repo.objects.commits[pr.headSHA], true
+			}
+		}
+	}
+	if c, ok := repo.objects.commits[ref]; ok {
+		return c, true
+	}
+	if len(ref) >= 7 {
+		for sha, c := range repo.objects.commits {
+			if strings.HasPrefix(sha, ref) {
+				return c, true
+			}
+		}
+	}
+	return nil, false
+}
+
+// peel returns the commit an object is or tags.
+func (repo *repository) peel(sha string) (*commit, bool) {
+	for {
+		if c, ok := repo.objects.commits[sha]; ok {
+			return c, true
+		}
+		tag, ok := repo.objects.tags[sha]
+		if !ok {
+			return nil, false
+		}
+		sha = tag.objectSHA
+	}
+}
+
+// branchHead returns the commit a branch points to.
+func (repo *repository) branchHead(branch string) (*commit, bool) {
+	sha, ok := repo.refs["refs/heads/"+branch]
+	if !ok {
+		return nil, false
+	}
+	return repo.objects.commits[sha], true
+}
+
+func (repo *repository) renderCommit(c *commit, urls urls) *github.Commit {
+	parents := make([]*github.Commit, 0, len(c.parents))
+	for _, parent := range c.parents {
+		parents = append(parents, &github.Commit{
+			SHA: github.Ptr(parent),
+			URL: github.Ptr(urls.api("repos/%s/git/commits/%s", repo.fullName(), parent)),
+		})
+	}
+	signature := &github.CommitAuthor{
+		Name:  github.Ptr(c.author.login),
+		Email: github.Ptr(c.author.login + "@users.noreply.github.com"),
+		Login: github.Ptr(c.author.login),
+		Date:  &github.Timestamp{Time: c.date},
+	}
+	return &github.Commit{
+		SHA:       github.Ptr(c.sha),
+		Message:   github.Ptr(c.message),
+		Author:    signature,
+		Committer: signature,
+		Tree: &github.Tree{
+			SHA: github.Ptr(c.tree),
+		},
+		Parents: parents,
+		URL:     github.Ptr(urls.api("repos/%s/git/commits/%s", repo.fullName(), c.sha)),
+		HTMLURL: github.Ptr(urls.html("%s/commit/%s", repo.fullName(), c.sha)),
+	}
+}
+
+// renderRepositoryCommit renders a commit as the commits API does. Its files are only listed
+// if withFiles is true, as the API only lists them for single commits.
+func (repo *repository) renderRepositoryCommit(c *commit, withFiles bool, urls urls) *github.RepositoryCommit {
+	parents := make([]*github.Commit, 0, len(c.parents))
+	for _, parent := range c.parents {
+		parents = append(parents, &github.Commit{
+			SHA: github.Ptr(parent),
+			URL: github.Ptr(urls.api("repos/%s/commits/%s", repo.fullName(), parent)),
+		})
+	}
+	rc := &github.RepositoryCommit{
+		SHA:       github.Ptr(c.sha),
+		NodeID:    github.Ptr("C_" + c.sha),
+		Commit:    repo.renderCommit(c, urls),
+		Author:    c.author.render(urls),
+		Committer: c.author.render(urls),
+		Parents:   parents,
+		URL:       github.Ptr(urls.api("repos/%s/commits/%s", repo.fullName(), c.sha)),
+		HTMLURL:   github.Ptr(urls.html("%s/commit/%s", repo.fullName(), c.sha)),
+	}
+	if withFiles {
+		var before map[string]string
+		if len(c.parents) > 0 {
+			if parent, ok := repo.objects.commits[c.parents[0]]; ok {
+				before = repo.objects.files(parent)
+			}
+		}
+		rc.Files = diffFiles(repo.objects, before, repo.objects.files(c))
+		stats := &github.CommitStats{Additions: github.Ptr(0), Deletions: github.Ptr(0), Total: github.Ptr(0)}
+		for _, file := range rc.Files {
+			*stats.Additions += file.GetAdditions()
+			*stats.Deletions += file.GetDeletions()
+			*stats.Total += file.GetChanges()
+		}
+		rc.Stats = stats
+	}
+	return rc
+}
+
+func (repo *repository) renderRef(name, sha string, urls urls) *github.Reference {
+	objectType := "commit"
+	if _, ok := repo.objects.tags[sha]; ok {
+		objectType = "tag"
+	}
+	return &github.Reference{
+		Ref:    github.Ptr(name),
+		URL:    github.Ptr(urls.api("repos/%s/git/%s", repo.fullName(), name)),
+		NodeID: github.Ptr("REF_" + hash(repo.fullName(), name)),
+		Object: &github.GitObject{
+			Type: github.Ptr(objectType),
+			SHA:  github.Ptr(sha),
+			URL:  github.Ptr(urls.api("repos/%s/git/%ss/%s", repo.fullName(), objectType, sha)),
+		},
+	}
+}
+
+func (repo *repository) renderTag(tag *tagObject, urls urls) *github.Tag {
+	return &github.Tag{
+		Tag:     github.Ptr(tag.name),
+		SHA:     github.Ptr(tag.sha),
+		Message: github.Ptr(tag.message),
+		URL:     github.Ptr(urls.api("repos/%s/git/tags/%s", repo.fullName(), tag.sha)),
+		NodeID:  github.Ptr("TAG_" + tag.sha),
+		Tagger: &github.CommitAuthor{
+			Name:  github.Ptr(tag.tagger.login),
+			Email: github.Ptr(tag.tagger.login + "@users.noreply.github.com"),
+			Date:  &github.Timestamp{Time: tag.date},
+		},
+		Object: &github.GitObject{
+			Type: github.Ptr(tag.objectType),
+			SHA:  github.Ptr(tag.objectSHA),
+			URL:  github.Ptr(urls.api("repos/%s/git/%ss/%s", repo.fullName(), tag.objectType, tag.objectSHA)),
+		},
+	}
+}
+
+func (s *Server) gitRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("GET /repos/{owner}/{repo}/git/ref/{ref...}", s.withRepo(func(w http.ResponseWriter, r *http.Request, repo *repository) {
+		name := "refs/" + r.PathValue("ref")
+		sha, ok := repo.refs[name]
+		if !ok {
+			notFound(w)
+			return
+		}
+		writeJSON(w, http.StatusOK, repo.renderRef(name, sha, urlsFor(r)))
+	}))
+
+	mux.HandleFunc("GET /repos/{owner}/{repo}/git/matching-refs/{ref...}", s.withRepo(func(w http.ResponseWriter, r *http.Request, repo *repository) {
+		prefix := "refs/" + r.PathValue("ref")
+		refs := []*github.Reference{}
+		for _, name := range repo.refNames() {
+			if strings.HasPrefix(name, prefix) {
+				refs = append(refs, repo.renderRef(name, repo.refs[name], urlsFor(r)))
+			}
+		}
+		writeJSON(w, http.StatusOK, refs)
+	}))
+
+	mux.HandleFunc("POST /repos/{owner}/{repo}/git/refs", s.withRepo(func(w http.ResponseWriter, r *http.Request, repo *repository) {
+		var body struct {
+			Ref string `json:"ref"`
+			SHA string `json:"sha"`
+		}
+		if !decodeBody(w, r, &body) {
+			return
+		}
+		if !strings.HasPrefix(body.Ref, "refs/") || strings.Count(body.Ref, "/") < 2 {
+			writeError(w, http.StatusUnprocessableEntity, "Reference name must start with 'refs/' and have at least two slashes.")
+			return
+		}
+		if _, ok := repo.refs[body.Ref]; ok {
+			writeError(w, http.StatusUnprocessableEntity, "Reference already exists")
+			return
+		}
+		if _, ok := repo.objects.commits[body.SHA]; !ok {
+			if _, ok := repo.objects.tags[body.SHA]; !ok {
+				writeError(w, http.StatusUnprocessableEntity, "Object does not exist")
+				return
+			}
+		}
+		repo.refs[body.Ref] = body.SHA
+		repo.pushed(s.now())
+		writeJSON(w, http.StatusCreated, repo.renderRef(body.Ref, body.SHA, urlsFor(r)))
+	}))
+
+	mux.HandleFunc("PATCH /repos/{owner}/{repo}/git/refs/{ref...}", s.withRepo(func(w http.ResponseWriter, r *http.Request, repo *repository) {
+		var body struct {
+			SHA   string `json:"sha"`
+			Force bool   `json:"force"`
+		}
+		if !decodeBody(w, r, &body) {
+			return
+		}
+		name := "refs/" + r.PathValue("ref")
+		current, ok := repo.refs[name]
+		if !ok {
+			writeError(w, http.StatusUnprocessableEntity, "Reference does not exist")
+			return
+		}
+		c, ok := repo.objects.commits[body.SHA]
+		if !ok {
+			writeError(w, http.StatusUnprocessableEntity, "Object does not exist")
+			return
+		}
+		if old, ok := repo.objects.commits[current]; ok && !body.Force && !repo.objects.isAncestor(old, c) {
+			writeError(w, http.StatusUnprocessableEntity, "Update is not a fast forward")
+			return
+		}
+		repo.refs[name] = body.SHA
+		repo.pushed(s.now())
+		writeJSON(w, http.StatusOK, repo.renderRef(name, body.SHA, urlsFor(r)))
+	}))
+
+	mux.HandleFunc("DELETE /repos/{owner}/{repo}/git/refs/{ref...}", s.withRepo(func(w http.ResponseWriter, r *http.Request, repo *repository) {
+		name := "refs/" + r.PathValue("ref")
+		if _, ok := repo.refs[name]; !ok {
+			writeError(w, http.StatusUnprocessableEntity, "Reference does not exist")
+			return
+		}
+		delete(repo.refs, name)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	mux.HandleFunc("GET /repos/{owner}/{repo}/git/commits/{sha}", s.withRepo(func(w http.ResponseWriter, r *http.Request, repo *repository) {
+		c, ok := repo.objects.commits[r.PathValue("sha")]
+		if !ok {
+			notFound(w)
+			return
+		}
+		writeJSON(w, http.StatusOK, repo.renderCommit(c, urlsFor(r)))
+	}))
+
+	mux.HandleFunc("POST /repos/{owner}/{repo}/git/commits", s.withRepo(func(w http.ResponseWriter, r *http.Request, repo *repository) {
+		var body struct {
+			Message string   `json:"message"`
+			Tree    string   `json:"tree"`
+			Parents []string `json:"parents"`
+		}
+		if !decodeBody(w, r, &body) {
+			return
+		}
+		if _, ok := repo.objects.trees[body.Tree]; !ok {
+			writeError(w, http.StatusUnprocessableEntity, "Tree SHA does not exist")
+			return
+		}
+		for _, parent := range body.Parents {
+			if _, ok := repo.objects.commits[parent]; !ok {
+				writeError(w, http.StatusUnprocessableEntity, "Parent SHA does not exist or is not a commit object")
+				return
+			}
+		}
+		c := s.putCommit(repo.objects, body.Tree, body.Message, s.caller(r), body.Parents)
+		writeJSON(w, http.StatusCreated, repo.renderCommit(c, urlsFor(r)))
+	}))
+
+	mux.HandleFunc("GET /repos/{owner}/{repo}/git/trees/{sha}", s.withRepo(func(w http.ResponseWriter, r *http.Request, repo *repository) {
+		sha := r.PathValue("sha")
+		if c, ok := repo.resolve(sha); ok {
+			sha = c.tree
+		}
+		files, ok := repo.objects.trees[sha]
+		if !ok {
+			notFound(w)
+			return
+		}
+		writeJSON(w, http.StatusOK, renderTree(repo, sha, files, r.URL.Query().Get("recursive") != "", urlsFor(r)))
+	}))
+
+	mux.HandleFunc("POST /repos/{owner}/{repo}/git/trees", s.withRepo(func(w http.ResponseWriter, r *http.Request, repo *repository) {
+		var body struct {
+			BaseTree string `json:"base_tree"`
+			Tree     []struct {
+				Path    string  `json:"path"`
+				Mode    string  `json:"mode"`
+				Type    string  `json:"type"`
+				SHA     *string `json:"sha"`
+				Content *string `json:"content"`
+			} `json:"tree"`
+		}
+		if !decodeBody(w, r, &body) {
+			return
+		}
+		files := make(map[string]string)
+		if body.BaseTree != "" {
+			base, ok := repo.objects.trees[body.BaseTree]
+			if !ok {
+				writeError(w, http.StatusUnprocessableEntity, "base_tree is not a valid tree oid")
+				return
+			}
+			for p, sha := range base {
+				files[p] = sha
+			}
+		}
+		changes := make(map[string]*string)
+		for _, entry := range body.Tree {
+			switch {
+			case entry.Content != nil:
+				changes[entry.Path] = entry.Content
+			case entry.SHA != nil:
+				content, ok := repo.objects.blobs[*entry.SHA]
+				if !ok {
+					writeError(w, http.StatusUnprocessableEntity, fmt.Sprintf("tree.sha %s is not a valid blob", *entry.SHA))
+					return
+				}
+				changes[entry.Path] = &content
+			default:
+				changes[entry.Path] = nil
+			}
+		}
+		applyChanges(repo.objects, files, changes)
+		sha := repo.objects.putTree(files)
+		writeJSON(w, http.StatusCreated, renderTree(repo, sha, files, true, urlsFor(r)))
+	}))
+
+	mux.HandleFunc("POST /repos/{owner}/{repo}/git/blobs", s.withRepo(func(w http.ResponseWriter, r *http.Request, repo *repository) {
+		var body struct {
+			Content  string `json:"content"`
+			Encoding string `json:"encoding"`
+		}
+		if !decodeBody(w, r, &body) {
+			return
+		}
+		content := body.Content
+		if body.Encoding == "base64" {
+			decoded, err := base64.StdEncoding.DecodeString(body.Content)
+			if err != nil {
+				writeError(w, http.StatusUnprocessableEntity, "content is not valid Base64")
+				return
+			}
+			content = string(decoded)
+		}
+		sha := repo.objects.putBlob(content)
+		writeJSON(w, http.StatusCreated, &github.Blob{
+			SHA: github.Ptr(sha),
+			URL: github.Ptr(urlsFor(r).api("repos/%s/git/blobs/%s", repo.fullName(), sha)),
+		})
+	}))
+
+	mux.HandleFunc("GET /repos/{owner}/{repo}/git/blobs/{sha}", s.withRepo(func(w http.ResponseWriter, r *http.Request, repo *repository) {
+		sha := r.PathValue("sha")
+		content, ok := repo.objects.blobs[sha]
+		if !ok {
+			notFound(w)
+			return
+		}
+		writeJSON(w, http.StatusOK, &github.Blob{
+			SHA:      github.Ptr(sha),
+			Content:  github.Ptr(base64.StdEncoding.EncodeToString([]byte(content))),
+			Encoding: github.Ptr("base64"),
+			Size:     github.Ptr(len(content)),
+			URL:      github.Ptr(urlsFor(r).api("repos/%s/git/blobs/%s", repo.fullName(), sha)),
+		})
+	}))
+
+	mux.HandleFunc("GET /repos/{owner}/{repo}/git/tags/{sha}", s.withRepo(func(w http.ResponseWriter, r *http.Request, repo *repository) {
+		tag, ok := repo.objects.tags[r.PathValue("sha")]
+		if !ok {
+			notFound(w)
+			return
+		}
+		writeJSON(w, http.StatusOK, repo.renderTag(tag, urlsFor(r)))
+	}))
+
+	mux.HandleFunc("POST /repos/{owner}/{repo}/git/tags", s.withRepo(func(w http.ResponseWriter, r *http.Request, repo *repository) {
+		var body struct {
+			Tag     string `json:"tag"`
+			Message string `json:"message"`
+			Object  string `json:"object"`
+			Type    string `json:"type"`
+		}
+		if !decodeBody(w, r, &body) {
+			return
+		}
+		if _, ok := repo.peel(body.Object); !ok {
+			writeError(w, http.StatusUnprocessableEntity, "Object does not exist")
+			return
+		}
+		if body.Type == "" {
+			body.Type = "commit"
+		}
+		date, seq := s.tick()
+		tag := &tagObject{
+			name:       body.Tag,
+			message:    body.Message,
+			objectSHA:  body.Object,
+			objectType: body.Type,
+			tagger:     s.caller(r),
+			date:       date,
+		}
+		tag.sha = hash("tag", body.Tag, body.Message, body.Object, date.String(), fmt.Sprint(seq))
+		repo.objects.tags[tag.sha] = tag
+		writeJSON(w, http.StatusCreated, repo.renderTag(tag, urlsFor(r)))
+	}))
+}
+
+func renderTree(repo *repository, sha string, files map[string]string, recursive bool, urls urls) *github.Tree {
+	entries := []*github.TreeEntry{}
+	dirs := map[string]bool{}
+	for _, p := range sortedKeys(files) {
+		if !recursive {
+			if dir, _, nested := strings.Cut(p, "/"); nested {
+				if !dirs[dir] {
+					dirs[dir] = true
+					entries = append(entries, &github.TreeEntry{Path: github.Ptr(dir), Mode: github.Ptr("040000"), Type: github.Ptr("tree")})
+				}
+				continue
+			}
+		} else {
+			for dir := path.Dir(p); dir != "."; dir = path.Dir(dir) {
+				dirs[dir] = true
+			}
+		}
+		content := repo.objects.blobs[files[p]]
+		entries = append(entries, &github.TreeEntry{
+			Path: github.Ptr(p),
+			Mode: github.Ptr("100644"),
+			Type: github.Ptr("blob"),
+			SHA:  github.Ptr(files[p]),
+			Size: github.Ptr(len(content)),
+			URL:  github.Ptr(urls.api("repos/%s/git/blobs/%s", repo.fullName(), files[p])),
+		})
+	}
+	if recursive {
+		for _, dir := range sortedKeys(dirs) {
+			entries = append(entries, &github.TreeEntry{Path: github.Ptr(dir), Mode: github.Ptr("040000"), Type: github.Ptr("tree")})
+		}
+	}
+	return &github.Tree{SHA: github.Ptr(sha), Entries: entries, Truncated: github.Ptr(false)}
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,768 @@
+package ghfake
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// The GraphQL API of the fake covers the queries and mutations that the tools send. Requests
+// are parsed by a small parser for the subset of GraphQL that clients generate: a single
+// operation with variables, fields with aliases and arguments, and inline fragments. They are
+// executed over gqlObjects, whose fields are values or resolvers of arguments.
+
+type gqlOperation struct {
+	kind       string
+	variables  map[string]any
+	selections []gqlSelection
+}
+
+type gqlSelection struct {
+	alias string
+	name  string
+	args  map[string]any
+	// typeCondition is the type of an inline fragment, whose selections apply only to objects
+	// of that type.
+	typeCondition string
+	selections    []gqlSelection
+}
+
+// gqlVariable is a reference to a variable in an argument.
+type gqlVariable string
+
+type gqlParser struct {
+	src string
+	pos int
+}
+
+func parseGraphQL(src string) (*gqlOperation, error) {
+	p := &gqlParser{src: src}
+	op := &gqlOperation{kind: "query", variables: make(map[string]any)}
+	if p.peek() != '{' {
+		kind, err := p.name()
+		if err != nil {
+			return nil, err
+		}
+		if kind != "query" && kind != "mutation" {
+			return nil, fmt.Errorf("unsupported operation type %q", kind)
+		}
+		op.kind = kind
+		if isNameStart(p.peek()) {
+			if _, err := p.name(); err != nil {
+				return nil, err
+			}
+		}
+		if p.consume("(") {
+			for !p.consume(")") {
+				if err := p.variableDefinition(op); err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+
+	var err error
+	if op.selections, err = p.selectionSet(); err != nil {
+		return nil, err
+	}
+	if p.peek() != 0 {
+		return nil, fmt.Errorf("unexpected %q at offset %d, only a single operation is supported", p.src[p.pos:], p.pos)
+	}
+	return op, nil
+}
+
+func (p *gqlParser) skip() {
+	for p.pos < len(p.src) {
+		switch p.src[p.pos] {
+		case ' ', '\t', '\n', '\r', ',':
+			p.pos++
+		case '#':
+			for p.pos < len(p.src) && p.src[p.pos] != '\n' {
+				p.pos++
+			}
+		default:
+			return
+		}
+	}
+}
+
+func (p *gqlParser) peek() byte {
+	p.skip()
+	if p.pos == len(p.src) {
+		return 0
+	}
+	return p.src[p.pos]
+}
+
+func (p *gqlParser) consume(token string) bool {
+	p.skip()
+	if strings.HasPrefix(p.src[p.pos:], token) {
+		p.pos += len(token)
+		return true
+	}
+	return false
+}
+
+func (p *gqlParser) expect(token string) error {
+	if !p.consume(token) {
+		return p.unexpected(token)
+	}
+	return nil
+}
+
+func (p *gqlParser) unexpected(expected string) error {
+	if p.pos == len(p.src) {
+		return fmt.Errorf("expected %s at end of query", expected)
+	}
+	return fmt.Errorf("expected %s at offset %d, found %q", expected, p.pos, p.src[p.pos])
+}
+
+func isNameStart(c byte) bool {
+	return c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
+
+func (p *gqlParser) name() (string, error) {
+	p.skip()
+	start := p.pos
+	for p.pos < len(p.src) && (isNameStart(p.src[p.pos]) || p.pos > start && p.src[p.pos] >= '0' && p.src[p.pos] <= '9') {
+		p.pos++
+	}
+	if p.pos == start {
+		return "", p.unexpected("a name")
+	}
+	return p.src[start:p.pos], nil
+}
+
+// variableDefinition parses the definition of a variable, and records its default value.
+// Types aren't checked.
+func (p *gqlParser) variableDefinition(op *gqlOperation) error {
+	if err := p.expect("$"); err != nil {
+		return err
+	}
+	name, err := p.name()
+	if err != nil {
+		return err
+	}
+	if err := p.expect(":"); err != nil {
+		return err
+	}
+	for p.consume("[") {
+	}
+	if _, err := p.name(); err != nil {
+		return err
+	}
+	for p.consume("!") || p.consume("]") {
+	}
+	op.variables[name] = nil
+	if p.consume("=") {
+		if op.variables[name], err = p.value(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p *gqlParser) selectionSet() ([]gqlSelection, error) {
+	if err := p.expect("{"); err != nil {
+		return nil, err
+	}
+	var selections []gqlSelection
+	for !p.consume("}") {
+		if p.peek() == 0 {
+			return nil, p.unexpected("}")
+		}
+		if p.consume("...") {
+			if on, err := p.name(); err != nil || on != "on" {
+				return nil, errors.New("only inline fragments are supported")
+			}
+			typ, err := p.name()
+			if err != nil {
+				return nil, err
+			}
+			nested, err := p.selectionSet()
+			if err != nil {
+				return nil, err
+			}
+			selections = append(selections, gqlSelection{typeCondition: typ, selections: nested})
+			continue
+		}
+
+		var sel gqlSelection
+		var err error
+		if sel.name, err = p.name(); err != nil {
+			return nil, err
+		}
+		sel.alias = sel.name
+		if p.consume(":") {
+			if sel.name, err = p.name(); err != nil {
+				return nil, err
+			}
+		}
+		if p.consume("(") {
+			sel.args = make(map[string]any)
+			for !p.consume(")") {
+				name, err := p.name()
+				if err != nil {
+					return nil, err
+				}
+				if err := p.expect(":"); err != nil {
+					return nil, err
+				}
+				if sel.args[name], err = p.value(); err != nil {
+					return nil, err
+				}
+			}
+		}
+		if p.peek() == '{' {
+			if sel.selections, err = p.selectionSet(); err != nil {
+				return nil, err
+			}
+		}
+		selections = append(selections, sel)
+	}
+	return selections, nil
+}
+
+func (p *gqlParser) value() (any, error) {
+	switch c := p.peek(); {
+	case c == '$':
+		p.pos++
+		name, err := p.name()
+		return gqlVariable(name), err
+	case c == '"':
+		start := p.pos
+		for p.pos++; p.pos < len(p.src) && p.src[p.pos] != '"'; p.pos++ {
+			if p.src[p.pos] == '\\' {
+				p.pos++
+			}
+		}
+		if p.pos == len(p.src) {
+			return nil, errors.New("unterminated string")
+		}
+		p.pos++
+		return strconv.Unquote(p.src[start:p.pos])
+	case c == '[':
+		p.pos++
+		list := []any{}
+		for !p.consume("]") {
+			item, err := p.value()
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, item)
+		}
+		return list, nil
+	case c == '{':
+		p.pos++
+		object := map[string]any{}
+		for !p.consume("}") {
+			name, err := p.name()
+			if err != nil {
+				return nil, err
+			}
+			if err := p.expect(":"); err != nil {
+				return nil, err
+			}
+			if object[name], err = p.value(); err != nil {
+				return nil, err
+			}
+		}
+		return object, nil
+	case c == '-' || c >= '0' && c <= '9':
+		start := p.pos
+		for p.pos < len(p.src) && strings.IndexByte("-+.eE0123456789", p.src[p.pos]) >= 0 {
+			p.pos++
+		}
+		return strconv.ParseFloat(p.src[start:p.pos], 64)
+	default:
+		name, err := p.name()
+		switch name {
+		case "true":
+			return true, nil
+		case "false":
+			return false, nil
+		case "null":
+			return nil, nil
+		}
+		// Enum values are passed on as strings, like in variables.
+		return name, err
+	}
+}
+
+// gqlObject is an object of a GraphQL type. Its fields are values, other objects, lists of
+// objects or gqlResolvers. The name of its type is in the __typename field.
+type gqlObject map[string]any
+
+// gqlResolver resolves a field from its arguments.
+type gqlResolver func(args map[string]any) (any, error)
+
+// execute resolves the selections on an object.
+func execute(obj gqlObject, selections []gqlSelection, variables map[string]any) (map[string]any, error) {
+	result := make(map[string]any)
+	for _, sel := range selections {
+		if sel.typeCondition != "" {
+			if obj["__typename"] != sel.typeCondition {
+				continue
+			}
+			nested, err := execute(obj, sel.selections, variables)
+			if err != nil {
+				return nil, err
+			}
+			for key, value := range nested {
+				result[key] = value
+			}
+			continue
+		}
+
+		value, ok := obj[sel.name]
+		if !ok {
+			return nil, fmt.Errorf("field '%s' doesn't exist on type '%s'", sel.name, obj["__typename"])
+		}
+		if resolve, ok := value.(gqlResolver); ok {
+			args := make(map[string]any, len(sel.args))
+			for name, arg := range sel.args {
+				args[name] = substitute(arg, variables)
+			}
+			var err error
+			if value, err = resolve(args); err != nil {
+				return nil, err
+			}
+		}
+
+		var err error
+		if result[sel.alias], err = executeValue(value, sel, variables); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+func executeValue(value any, sel gqlSelection, variables map[string]any) (any, error) {
+	switch value := value.(type) {
+	case nil:
+		return nil, nil
+	case gqlObject:
+		if len(sel.selections) == 0 {
+			return nil, fmt.Errorf("field '%s' returns %s and must have selections", sel.name, value["__typename"])
+		}
+		return execute(value, sel.selections, variables)
+	case []gqlObject:
+		list := make([]any, 0, len(value))
+		for _, item := range value {
+			result, err := executeValue(item, sel, variables)
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, result)
+		}
+		return list, nil
+	}
+	if len(sel.selections) > 0 {
+		return nil, fmt.Errorf("selections can't be made on the scalar field '%s'", sel.name)
+	}
+	return value, nil
+}
+
+// substitute replaces the variables in an argument with their values.
+func substitute(arg any, variables map[string]any) any {
+	switch arg := arg.(type) {
+	case gqlVariable:
+		return variables[string(arg)]
+	case []any:
+		list := make([]any, 0, len(arg))
+		for _, item := range arg {
+			list = append(list, substitute(item, variables))
+		}
+		return list
+	case map[string]any:
+		object := make(map[string]any, len(arg))
+		for name, value := range arg {
+			object[name] = substitute(value, variables)
+		}
+		return object
+	}
+	return arg
+}
+
+// notResolved returns the error that GitHub answers references to missing objects with.
+func notResolved(format string, args ...any) error {
+	return errors.New("Could not resolve to " + fmt.Sprintf(format, args...))
+}
+
+func argString(args map[string]any, name string) string {
+	s, _ := args[name].(string)
+	return s
+}
+
+func argInt(args map[string]any, name string) int {
+	f, _ := args[name].(float64)
+	return int(f)
+}
+
+func argInput(args map[string]any) map[string]any {
+	input, _ := args["input"].(map[string]any)
+	return input
+}
+
+// serveGraphQL executes a GraphQL request as the caller.
+func (s *Server) serveGraphQL(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		Query     string         `json:"query"`
+		Variables map[string]any `json:"variables"`
+	}
+	if !decodeBody(w, r, &body) {
+		return
+	}
+
+	data, err := func() (map[string]any, error) {
+		op, err := parseGraphQL(body.Query)
+		if err != nil {
+			return nil, fmt.Errorf("parse error: %w", err)
+		}
+		variables := op.variables
+		for name, value := range body.Variables {
+			variables[name] = value
+		}
+		g := &graphQL{s: s, caller: s.caller(r), urls: urlsFor(r)}
+		if op.kind == "mutation" {
+			return execute(g.mutation(), op.selections, variables)
+		}
+		return execute(g.query(), op.selections, variables)
+	}()
+	if err != nil {
+		writeJSON(w, http.StatusOK, map[string]any{
+			"data":   nil,
+			"errors": []map[string]string{{"message": err.Error()}},
+		})
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"data": data})
+}
+
+// graphQL builds the objects of the GraphQL API for a request.
+type graphQL struct {
+	s      *Server
+	caller *user
+	urls   urls
+}
+
+func (g *graphQL) query() gqlObject {
+	return gqlObject{
+		"__typename": "Query",
+		"viewer":     g.user(g.caller),
+		"repository": gqlResolver(func(args map[string]any) (any, error) {
+			owner, name := argString(args, "owner"), argString(args, "name")
+			repo, ok := g.s.repository(owner, name)
+			if !ok {
+				return nil, notResolved("a Repository with the name '%s/%s'.", owner, name)
+			}
+			return g.repository(repo), nil
+		}),
+	}
+}
+
+func (g *graphQL) mutation() gqlObject {
+	return gqlObject{
+		"__typename":                 "Mutation",
+		"addPullRequestReview":       gqlResolver(g.addPullRequestReview),
+		"addPullRequestReviewThread": gqlResolver(g.addPullRequestReviewThread),
+		"submitPullRequestReview":    gqlResolver(g.submitPullRequestReview),
+		"deletePullRequestReview":    gqlResolver(g.deletePullRequestReview),
+		"replaceActorsForAssignable": gqlResolver(g.replaceActorsForAssignable),
+	}
+}
+
+// connection pages through the nodes of a connection with the first and after arguments.
+func connection(nodes []gqlObject, args map[string]any) gqlObject {
+	start := 0
+	if after := argString(args, "after"); after != "" {
+		if decoded, err := base64.StdEncoding.DecodeString(after); err == nil {
+			if n, err := strconv.Atoi(strings.TrimPrefix(string(decoded), "cursor:")); err == nil {
+				start = min(n+1, len(nodes))
+			}
+		}
+	}
+	end := len(nodes)
+	if first := argInt(args, "first"); first > 0 {
+		end = min(start+first, len(nodes))
+	}
+	cursor := func(n int) any {
+		if n < 0 || n >= len(nodes) || start == end {
+			return nil
+		}
+		return base64.StdEncoding.EncodeToString([]byte("cursor:" + strconv.Itoa(n)))
+	}
+	return gqlObject{
+		"__typename": "Connection",
+		"nodes":      nodes[start:end],
+		"totalCount": len(nodes),
+		"pageInfo": gqlObject{
+			"__typename":      "PageInfo",
+			"hasNextPage":     end < len(nodes),
+			"hasPreviousPage": start > 0,
+			"startCursor":     cursor(start),
+			"endCursor":       cursor(end - 1),
+		},
+	}
+}
+
+func (g *graphQL) user(u *user) gqlObject {
+	return gqlObject{
+		"__typename": u.typ,
+		"id":         u.nodeID(),
+		"databaseId": u.id,
+		"login":      u.login,
+		"name":       u.name,
+		"url":        g.urls.html("%s", u.login),
+	}
+}
+
+func (g *graphQL) repository(repo *repository) gqlObject {
+	return gqlObject{
+		"__typename":    "Repository",
+		"id":            repo.nodeID(),
+		"databaseId":    repo.id,
+		"name":          repo.name,
+		"nameWithOwner": repo.fullName(),
+		"owner":         g.user(repo.owner),
+		"url":           g.urls.html("%s", repo.fullName()),
+		"pullRequest": gqlResolver(func(args map[string]any) (any, error) {
+			number := strconv.Itoa(argInt(args, "number"))
+			p := repo.pullByNumber(number)
+			if p == nil {
+				return nil, notResolved("a PullRequest with the number of %s.", number)
+			}
+			return g.pull(p), nil
+		}),
+		"issue": gqlResolver(func(args map[string]any) (any, error) {
+			number := strconv.Itoa(argInt(args, "number"))
+			i := repo.issueByNumber(number)
+			if i == nil || i.pull != nil {
+				return nil, notResolved("an Issue with the number of %s.", number)
+			}
+			return g.issue(i), nil
+		}),
+		// Every user and bot can be assigned, bots first.
+		"suggestedActors": gqlResolver(func(args map[string]any) (any, error) {
+			var bots, users []gqlObject
+			for _, key := range sortedKeys(g.s.users) {
+				switch u := g.s.users[key]; u.typ {
+				case "Bot":
+					bots = append(bots, g.user(u))
+				case "User":
+					users = append(users, g.user(u))
+				}
+			}
+			return connection(append(bots, users...), args), nil
+		}),
+	}
+}
+
+func (g *graphQL) issue(i *issue) gqlObject {
+	return gqlObject{
+		"__typename": "Issue",
+		"id":         i.nodeID(),
+		"databaseId": i.id,
+		"number":     i.number,
+		"title":      i.title,
+		"body":       i.body,
+		"state":      strings.ToUpper(i.state),
+		"url":        g.urls.html("%s/issues/%d", i.repo.fullName(), i.number),
+		"assignees": gqlResolver(func(args map[string]any) (any, error) {
+			nodes := make([]gqlObject, 0, len(i.assignees))
+			for _, u := range i.assignees {
+				nodes = append(nodes, g.user(u))
+			}
+			return connection(nodes, args), nil
+		}),
+	}
+}
+
+func (g *graphQL) pull(p *pull) gqlObject {
+	state := strings.ToUpper(p.issue.state)
+	if p.merged {
+		state = "MERGED"
+	}
+	return gqlObject{
+		"__typename": "PullRequest",
+		"id":         p.issue.nodeID(),
+		"databaseId": p.issue.id,
+		"number":     p.issue.number,
+		"title":      p.issue.title,
+		"body":       p.issue.body,
+		"state":      state,
+		"isDraft":    p.draft,
+		"url":        g.urls.html("%s/pull/%d", p.repo().fullName(), p.issue.number),
+		"author":     g.user(p.issue.author),
+		// Pending reviews are only visible to their authors. They are listed first, because
+		// the tools look for the pending review of the viewer in the first of its reviews.
+		"reviews": gqlResolver(func(args map[string]any) (any, error) {
+			var pending, submitted []gqlObject
+			for _, rv := range p.reviews {
+				if author := argString(args, "author"); author != "" && !strings.EqualFold(rv.author.login, author) {
+					continue
+				}
+				if states, ok := args["states"].([]any); ok && !slices.Contains(states, any(rv.state)) {
+					continue
+				}
+				switch {
+				case rv.state != "PENDING":
+					submitted = append(submitted, g.review(rv))
+				case rv.author == g.caller:
+					pending = append(pending, g.review(rv))
+				}
+			}
+			return connection(append(pending, submitted...), args), nil
+		}),
+	}
+}
+
+func (g *graphQL) review(rv *review) gqlObject {
+	return gqlObject{
+		"__typename": "PullRequestReview",
+		"id":         rv.nodeID(),
+		"databaseId": rv.id,
+		"state":      rv.state,
+		"body":       rv.body,
+		"url":        rv.htmlURL(g.urls),
+		"author":     g.user(rv.author),
+	}
+}
+
+// pendingReview finds a pending review of the caller by its node ID. Pending reviews of other
+// users can't be resolved, as on GitHub.
+func (g *graphQL) pendingReview(id string) (*review, error) {
+	rv := g.s.reviewByNodeID(id)
+	if rv == nil || rv.state == "PENDING" && rv.author != g.caller {
+		return nil, notResolved("a node with the global id of '%s'", id)
+	}
+	if rv.author != g.caller {
+		return nil, errors.New("only the author of a review can change it")
+	}
+	return rv, nil
+}
+
+func (g *graphQL) addPullRequestReview(args map[string]any) (any, error) {
+	input := argInput(args)
+	id := argString(input, "pullRequestId")
+	p := g.s.pullByNodeID(id)
+	if p == nil {
+		return nil, notResolved("a node with the global id of '%s'", id)
+	}
+
+	// The review stays pending while its threads are added, and is submitted afterwards.
+	rv, err := g.s.addReview(p, g.caller, argString(input, "body"), "", argString(input, "commitOID"))
+	if err != nil {
+		return nil, err
+	}
+	threads, _ := input["threads"].([]any)
+	for _, thread := range threads {
+		thread, _ := thread.(map[string]any)
+		if err := g.s.addReviewComment(rv, reviewThread(thread)); err != nil {
+			_ = g.s.deleteReview(rv)
+			return nil, err
+		}
+	}
+	if event := argString(input, "event"); event != "" {
+		if err := g.s.submitReview(rv, event, ""); err != nil {
+			_ = g.s.deleteReview(rv)
+			return nil, err
+		}
+	}
+	return gqlObject{"__typename": "AddPullRequestReviewPayload", "pullRequestReview": g.review(rv)}, nil
+}
+
+func reviewThread(input map[string]any) *reviewComment {
+	return &reviewComment{
+		body:        argString(input, "body"),
+		path:        argString(input, "path"),
+		line:        argInt(input, "line"),
+		side:        argString(input, "side"),
+		startLine:   argInt(input, "startLine"),
+		startSide:   argString(input, "startSide"),
+		subjectType: argString(input, "subjectType"),
+	}
+}
+
+func (g *graphQL) addPullRequestReviewThread(args map[string]any) (any, error) {
+	input := argInput(args)
+	rv, err := g.pendingReview(argString(input, "pullRequestReviewId"))
+	if err != nil {
+		return nil, err
+	}
+	c := reviewThread(input)
+	if err := g.s.addReviewComment(rv, c); err != nil {
+		return nil, err
+	}
+	return gqlObject{
+		"__typename": "AddPullRequestReviewThreadPayload",
+		"thread": gqlObject{
+			"__typename": "PullRequestReviewThread",
+			"id":         fmt.Sprintf("PRRT_%d", c.id),
+			"path":       c.path,
+			"line":       c.line,
+		},
+	}, nil
+}
+
+func (g *graphQL) submitPullRequestReview(args map[string]any) (any, error) {
+	input := argInput(args)
+	rv, err := g.pendingReview(argString(input, "pullRequestReviewId"))
+	if err != nil {
+		return nil, err
+	}
+	if err := g.s.submitReview(rv, argString(input, "event"), argString(input, "body")); err != nil {
+		return nil, err
+	}
+	return gqlObject{"__typename": "SubmitPullRequestReviewPayload", "pullRequestReview": g.review(rv)}, nil
+}
+
+func (g *graphQL) deletePullRequestReview(args map[string]any) (any, error) {
+	rv, err := g.pendingReview(argString(argInput(args), "pullRequestReviewId"))
+	if err != nil {
+		return nil, err
+	}
+	if err := g.s.deleteReview(rv); err != nil {
+		return nil, err
+	}
+	return gqlObject{"__typename": "DeletePullRequestReviewPayload", "pullRequestReview": g.review(rv)}, nil
+}
+
+func (g *graphQL) replaceActorsForAssignable(args map[string]any) (any, error) {
+	input := argInput(args)
+	id := argString(input, "assignableId")
+	i := g.s.issueByNodeID(id)
+	if i == nil {
+		return nil, notResolved("a node with the global id of '%s'", id)
+	}
+
+	actorIDs, _ := input["actorIds"].([]any)
+	assignees := make([]*user, 0, len(actorIDs))
+	for _, actorID := range actorIDs {
+		id, _ := actorID.(string)
+		u := g.s.userByNodeID(id)
+		if u == nil {
+			return nil, notResolved("a node with the global id of '%s'", id)
+		}
+		if !slices.Contains(assignees, u) {
+			assignees = append(assignees, u)
+		}
+	}
+	for _, u := range assignees {
+		if !slices.Contains(i.assignees, u) {
+			g.s.notify(i, u, "assign", g.caller)
+		}
+	}
+	i.assignees = assignees
+	i.updatedAt = g.s.now()
+
+	assignable := g.issue(i)
+	if i.pull != nil {
+		assignable = g.pull(i.pull)
+	}
+	return gqlObject{"__typename": "ReplaceActorsForAssignablePayload", "assignable": assignable}, nil
+}
@@ -0,0 +1,129 @@
+package ghfake
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_parseGraphQL(t *testing.T) {
+	tests := []struct {
+		name        string
+		src         string
+		expected    *gqlOperation
+		expectedErr string
+	}{
+		{
+			name: "shorthand query",
+			src:  `{viewer{login}}`,
+			expected: &gqlOperation{
+				kind:      "query",
+				variables: map[string]any{},
+				selections: []gqlSelection{
+					{alias: "viewer", name: "viewer", selections: []gqlSelection{{alias: "login", name: "login"}}},
+				},
+			},
+		},
+		{
+			name: "query with variables, aliases and fragments",
+			src:  `query($owner:String!$name:String!$first:Int=100){repo: repository(owner: $owner, name: $name){suggestedActors(first: $first, capabilities: [CAN_BE_ASSIGNED]){nodes{... on Bot{id,login,__typename}}}}}`,
+			expected: &gqlOperation{
+				kind:      "query",
+				variables: map[string]any{"owner": nil, "name": nil, "first": float64(100)},
+				selections: []gqlSelection{
+					{
+						alias: "repo",
+						name:  "repository",
+						args:  map[string]any{"owner": gqlVariable("owner"), "name": gqlVariable("name")},
+						selections: []gqlSelection{
+							{
+								alias: "suggestedActors",
+								name:  "suggestedActors",
+								args:  map[string]any{"first": gqlVariable("first"), "capabilities": []any{"CAN_BE_ASSIGNED"}},
+								selections: []gqlSelection{
+									{alias: "nodes", name: "nodes", selections: []gqlSelection{
+										{typeCondition: "Bot", selections: []gqlSelection{{alias: "id", name: "id"}, {alias: "login", name: "login"}, {alias: "__typename", name: "__typename"}}},
+									}},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "mutation with an input object",
+			src:  `mutation($input:SubmitPullRequestReviewInput!){submitPullRequestReview(input: {pullRequestReviewId: "PRR_1", event: APPROVE, body: $input}){pullRequestReview{id}}}`,
+			expected: &gqlOperation{
+				kind:      "mutation",
+				variables: map[string]any{"input": nil},
+				selections: []gqlSelection{
+					{
+						alias: "submitPullRequestReview",
+						name:  "submitPullRequestReview",
+						args: map[string]any{"input": map[string]any{
+							"pullRequestReviewId": "PRR_1",
+							"event":               "APPROVE",
+							"body":                gqlVariable("input"),
+						}},
+						selections: []gqlSelection{{alias: "pullRequestReview", name: "pullRequestReview", selections: []gqlSelection{{alias: "id", name: "id"}}}},
+					},
+				},
+			},
+		},
+		{
+			name:        "unterminated selection set",
+			src:         `{viewer{login}`,
+			expectedErr: "expected",
+		},
+		{
+			name:        "subscription",
+			src:         `subscription{viewer{login}}`,
+			expectedErr: "subscription",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			op, err := parseGraphQL(tc.src)
+			if tc.expectedErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tc.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, op)
+		})
+	}
+}
+
+func Test_execute(t *testing.T) {
+	obj := gqlObject{
+		"viewer": gqlObject{"login": "octocat", "name": nil},
+		"repository": gqlResolver(func(args map[string]any) (any, error) {
+			if argString(args, "name") != "hello-world" {
+				return nil, notResolved("a Repository with the name '%s'.", argString(args, "name"))
+			}
+			return gqlObject{
+				"__typename": "Repository",
+				"issues":     []gqlObject{{"number": 1}, {"number": 2}},
+			}, nil
+		}),
+	}
+
+	op, err := parseGraphQL(`query($name:String!){viewer{login,name},r: repository(name: $name){__typename,issues{number}}}`)
+	require.NoError(t, err)
+	data, err := execute(obj, op.selections, map[string]any{"name": "hello-world"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{
+		"viewer": map[string]any{"login": "octocat", "name": nil},
+		"r": map[string]any{
+			"__typename": "Repository",
+			"issues":     []any{map[string]any{"number": 1}, map[string]any{"number": 2}},
+		},
+	}, data)
+
+	_, err = execute(obj, op.selections, map[string]any{"name": "missing"})
+	require.EqualError(t, err, "Could not resolve to a Repository with the name 'missing'.")
+}
@@ -0,0 +1,425 @@
+package ghfake
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/go-github/v72/github"
+)
+
+// issue is an issue or, if pull is set, a pull request.
+type issue struct {
+	id          int64
+	number      int
+	repo        *repository
+	title       string
+	body        string
+	state       string
+	stateReason string
+	author      *user
+	labels      []string
+	assignees   []*user
+	milestone   int
+	comments    []*issueComment
+
+	createdAt time.Time
+	updatedAt time.Time
+	closedAt  time.Time
+	closedBy  *user
+
+	pull *pull
+}
+
+type issueComment struct {
+	id        int64
+	author    *user
+	body      string
+	createdAt time.Time
+	updatedAt time.Time
+}
+
+// newIssue adds an open issue to a repository with the next number.
+func (s *Server) newIssue(repo *repository, author *user, title, body string) *issue {
+	now := s.now()
+	i := &issue{
+		id:        s.nextID(),
+		number:    len(repo.issues) + 1,
+		repo:      repo,
+		title:     title,
+		body:      body,
+		state:     "open",
+		author:    author,
+		createdAt: now,
+		updatedAt: now,
+	}
+	repo.issues = append(repo.issues, i)
+	return i
+}
+
+func (repo *repository) issueByNumber(number string) *issue {
+	n, err := strconv.Atoi(number)
+	if err != nil || n < 1 || n > len(repo.issues) {
+		return nil
+	}
+	return repo.issues[n-1]
+}
+
+// withIssue looks up the issue or pull request that a request is about, from the number path
+// value, and answers 404 if there is none.
+func (s *Server) withIssue(handler func(http.ResponseWriter, *http.Request, *issue)) http.HandlerFunc {
+	return s.withRepo(func(w http.ResponseWriter, r *http.Request, repo *repository) {
+		i := repo.issueByNumber(r.PathValue("number"))
+		if i == nil {
+			notFound(w)
+			return
+		}
+		handler(w, r, i)
+	})
+}
+
+func (i *issue) nodeID() string {
+	if i.pull != nil {
+		return fmt.Sprintf("PR_%d", i.id)
+	}
+	return fmt.Sprintf("I_%d", i.id)
+}
+
+// setState opens or closes an issue, and returns whether the state was valid.
+func (i *issue) setState(state, reason string, by *user, now time.Time) bool {
+	switch state {
+	case "open":
+		if i.state == "closed" {
+			i.stateReason = "reopened"
+		}
+		i.closedAt, i.closedBy = time.Time{}, nil
+	case "closed":
+		if reason == "" {
+			reason = "completed"
+		}
+		if i.state == "open" {
+			i.closedAt, i.closedBy = now, by
+		}
+		i.stateReason = reason
+	default:
+		return false
+	}
+	i.state = state
+	return true
+}
+
+func (i *issue) render(urls urls) *github.Issue {
+	path := fmt.Sprintf("repos/%s/issues/%d", i.repo.fullName(), i.number)
+	rendered := &github.Issue{
+		ID:            github.Ptr(i.id),
+		NodeID:        github.Ptr(i.nodeID()),
+		Number:        github.Ptr(i.number),
+		State:         github.Ptr(i.state),
+		Title:         github.Ptr(i.title),
+		Body:          github.Ptr(i.body),
+		User:          i.author.render(urls),
+		Labels:        renderLabels(i.labels),
+		Assignees:     renderUsers(i.assignees, urls),
+		Comments:      github.Ptr(len(i.comments)),
+		Locked:        github.Ptr(false),
+		CreatedAt:     &github.Timestamp{Time: i.createdAt},
+		UpdatedAt:     &github.Timestamp{Time: i.updatedAt},
+		URL:           github.Ptr(urls.api("%s", path)),
+		CommentsURL:   github.Ptr(urls.api("%s/comments", path)),
+		RepositoryURL: github.Ptr(urls.api("repos/%s", i.repo.fullName())),
+		HTMLURL:       github.Ptr(urls.html("%s/issues/%d", i.repo.fullName(), i.number)),
+	}
+	if i.stateReason != "" {
+		rendered.StateReason = github.Ptr(i.stateReason)
+	}
+	if len(i.assignees) > 0 {
+		rendered.Assignee = i.assignees[0].render(urls)
+	}
+	if i.milestone != 0 {
+		rendered.Milestone = &github.Milestone{Number: github.Ptr(i.milestone)}
+	}
+	if i.state == "closed" {
+		rendered.ClosedAt = &github.Timestamp{Time: i.closedAt}
+		if i.closedBy != nil {
+			rendered.ClosedBy = i.closedBy.render(urls)
+		}
+	}
+	if i.pull != nil {
+		rendered.HTMLURL = github.Ptr(urls.html("%s/pull/%d", i.repo.fullName(), i.number))
+		rendered.PullRequestLinks = &github.PullRequestLinks{
+			URL:      github.Ptr(urls.api("repos/%s/pulls/%d", i.repo.fullName(), i.number)),
+			HTMLURL:  github.Ptr(urls.html("%s/pull/%d", i.repo.fullName(), i.number)),
+			DiffURL:  github.Ptr(urls.html("%s/pull/%d.diff", i.repo.fullName(), i.number)),
+			PatchURL: github.Ptr(urls.html("%s/pull/%d.patch", i.repo.fullName(), i.number)),
+		}
+		if i.pull.merged {
+			rendered.PullRequestLinks.MergedAt = &github.Timestamp{Time: i.pull.mergedAt}
+		}
+	}
+	return rendered
+}
+
+func renderLabels(names []string) []*github.Label {
+	labels := make([]*github.Label, 0, len(names))
+	for _, name := range names {
+		labels = append(labels, &github.Label{
+			Name:  github.Ptr(name),
+			Color: github.Ptr("ededed"),
+		})
+	}
+	return labels
+}
+
+func (c *issueComment) render(i *issue, urls urls) *github.IssueComment {
+	return &github.IssueComment{
+		ID:        github.Ptr(c.id),
+		NodeID:    github.Ptr(fmt.Sprintf("IC_%d", c.id)),
+		Body:      github.Ptr(c.body),
+		User:      c.author.render(urls),
+		CreatedAt: &github.Timestamp{Time: c.createdAt},
+		UpdatedAt: &github.Timestamp{Time: c.updatedAt},
+		URL:       github.Ptr(urls.api("repos/%s/issues/comments/%d", i.repo.fullName(), c.id)),
+		HTMLURL:   github.Ptr(urls.html("%s/issues/%d#issuecomment-%d", i.repo.fullName(), i.number, c.id)),
+		IssueURL:  github.Ptr(urls.api("repos/%s/issues/%d", i.repo.fullName(), i.number)),
+	}
+}
+
+// usersByLogin looks up the users with the logins, and writes a validation error if one of them
+// doesn't exist.
+func (s *Server) usersByLogin(w http.ResponseWriter, field string, logins []string) ([]*user, bool) {
+	users := make([]*user, 0, len(logins))
+	for _, login := range logins {
+		u, ok := s.users[strings.ToLower(login)]
+		if !ok {
+			writeValidationError(w, fmt.Sprintf("%s %q does not exist", field, login))
+			return nil, false
+		}
+		users = append(users, u)
+	}
+	return users, true
+}
+
+// writeValidationError writes a validation error like the ones the API answers invalid
+// requests with.
+func writeValidationError(w http.ResponseWriter, message string) {
+	writeJSON(w, http.StatusUnprocessableEntity, map[string]any{
+		"message":           "Validation Failed",
+		"errors":            []map[string]string{{"code": "custom", "message": message}},
+		"documentation_url": "https://docs.github.com/rest",
+	})
+}
+
+// issueRequest is the body of requests that create and edit issues.
+type issueRequest struct {
+	Title       *string   `json:"title"`
+	Body        *string   `json:"body"`
+	State       *string   `json:"state"`
+	StateReason *string   `json:"state_reason"`
+	Labels      *[]string `json:"labels"`
+	Assignees   *[]string `json:"assignees"`
+	Milestone   *int      `json:"milestone"`
+}
+
+// apply applies the changes of the request to an issue, and returns false after writing an
+// error if they are invalid.
+func (s *Server) apply(w http.ResponseWriter, r *http.Request, i *issue, req issueRequest) bool {
+	var assignees []*user
+	if req.Assignees != nil {
+		var ok bool
+		if assignees, ok = s.usersByLogin(w, "assignee", *req.Assignees); !ok {
+			return false
+		}
+	}
+	if req.Title != nil {
+		if *req.Title == "" {
+			writeValidationError(w, "title cannot be blank")
+			return false
+		}
+		i.title = *req.Title
+	}
+	if req.State != nil {
+		var reason string
+		if req.StateReason != nil {
+			reason = *req.StateReason
+		}
+		if !i.setState(*req.State, reason, s.caller(r), s.now()) {
+			writeValidationError(w, fmt.Sprintf("state %q is not one of open or closed", *req.State))
+			return false
+		}
+	}
+	if req.Body != nil {
+		i.body = *req.Body
+	}
+	if req.Labels != nil {
+		i.labels = slices.Clone(*req.Labels)
+	}
+	if req.Assignees != nil {
+		for _, assignee := range assignees {
+			if !slices.Contains(i.assignees, assignee) {
+				s.notify(i, assignee, "assign", s.caller(r))
+			}
+		}
+		i.assignees = assignees
+	}
+	if req.Milestone != nil {
+		i.milestone = *req.Milestone
+	}
+	i.updatedAt = s.now()
+	return true
+}
+
+func (s *Server) issueRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("GET /repos/{owner}/{repo}/issues", s.withRepo(func(w http.ResponseWriter, r *http.Request, repo *repository) {
+		query := r.URL.Query()
+		var since time.Time
+		if value := query.Get("since"); value != "" {
+			var err error
+			if since, err = time.Parse(time.RFC3339, value); err != nil {
+				writeValidationError(w, "since is not a valid ISO 8601 timestamp")
+				return
+			}
+		}
+		state := query.Get("state")
+		if state == "" {
+			state = "open"
+		}
+		var labels []string
+		if value := query.Get("labels"); value != "" {
+			labels = strings.Split(value, ",")
+		}
+
+		var matches []*issue
+		for _, i := range repo.issues {
+			if state != "all" && i.state != state || i.updatedAt.Before(since) || !hasLabels(i, labels) {
+				continue
+			}
+			if creator := query.Get("creator"); creator != "" && !strings.EqualFold(i.author.login, creator) {
+				continue
+			}
+			if assignee := query.Get("assignee"); assignee != "" && !isAssigned(i, assignee) {
+				continue
+			}
+			matches = append(matches, i)
+		}
+		sortIssues(matches, query.Get("sort"), query.Get("direction"))
+
+		issues := make([]*github.Issue, 0, len(matches))
+		for _, i := range matches {
+			issues = append(issues, i.render(urlsFor(r)))
+		}
+		writeJSON(w, http.StatusOK, paginate(w, r, issues))
+	}))
+
+	mux.HandleFunc("POST /repos/{owner}/{repo}/issues", s.withRepo(func(w http.ResponseWriter, r *http.Request, repo *repository) {
+		var req issueRequest
+		if !decodeBody(w, r, &req) {
+			return
+		}
+		if req.Title == nil || *req.Title == "" {
+			writeValidationError(w, "title cannot be blank")
+			return
+		}
+		if req.Assignees != nil {
+			if _, ok := s.usersByLogin(w, "assignee", *req.Assignees); !ok {
+				return
+			}
+		}
+		req.State = nil
+
+		i := s.newIssue(repo, s.caller(r), "", "")
+		s.apply(w, r, i, req)
+		writeJSON(w, http.StatusCreated, i.render(urlsFor(r)))
+	}))
+
+	mux.HandleFunc("GET /repos/{owner}/{repo}/issues/{number}", s.withIssue(func(w http.ResponseWriter, r *http.Request, i *issue) {
+		writeJSON(w, http.StatusOK, i.render(urlsFor(r)))
+	}))
+
+	mux.HandleFunc("PATCH /repos/{owner}/{repo}/issues/{number}", s.withIssue(func(w http.ResponseWriter, r *http.Request, i *issue) {
+		var req issueRequest
+		if !decodeBody(w, r, &req) {
+			return
+		}
+		if i.pull != nil && i.pull.merged && req.State != nil && *req.State == "open" {
+			writeValidationError(w, "merged pull requests cannot be reopened")
+			return
+		}
+		if !s.apply(w, r, i, req) {
+			return
+		}
+		writeJSON(w, http.StatusOK, i.render(urlsFor(r)))
+	}))
+
+	mux.HandleFunc("GET /repos/{owner}/{repo}/issues/{number}/comments", s.withIssue(func(w http.ResponseWriter, r *http.Request, i *issue) {
+		comments := make([]*github.IssueComment, 0, len(i.comments))
+		for _, c := range i.comments {
+			comments = append(comments, c.render(i, urlsFor(r)))
+		}
+		writeJSON(w, http.StatusOK, paginate(w, r, comments))
+	}))
+
+	mux.HandleFunc("POST /repos/{owner}/{repo}/issues/{number}/comments", s.withIssue(func(w http.ResponseWriter, r *http.Request, i *issue) {
+		var body struct {
+			Body string `json:"body"`
+		}
+		if !decodeBody(w, r, &body) {
+			return
+		}
+		if body.Body == "" {
+			writeValidationError(w, "body cannot be blank")
+			return
+		}
+		now := s.now()
+		c := &issueComment{id: s.nextID(), author: s.caller(r), body: body.Body, createdAt: now, updatedAt: now}
+		i.comments = append(i.comments, c)
+		i.updatedAt = now
+		writeJSON(w, http.StatusCreated, c.render(i, urlsFor(r)))
+	}))
+}
+
+func hasLabels(i *issue, labels []string) bool {
+	for _, label := range labels {
+		if !slices.ContainsFunc(i.labels, func(l string) bool { return strings.EqualFold(l, label) }) {
+			return false
+		}
+	}
+	return true
+}
+
+func isAssigned(i *issue, login string) bool {
+	switch login {
+	case "none":
+		return len(i.assignees) == 0
+	case "*":
+		return len(i.assignees) > 0
+	}
+	return slices.ContainsFunc(i.assignees, func(u *user) bool { return strings.EqualFold(u.login, login) })
+}
+
+// sortIssues sorts issues by the time they were created or updated, or by their number of
+// comments, newest or most first unless direction is "asc".
+func sortIssues(issues []*issue, by, direction string) {
+	switch by {
+	case "updated":
+		sortByTime(issues, direction, func(i *issue) time.Time { return i.updatedAt })
+	case "comments":
+		slices.SortStableFunc(issues, func(a, b *issue) int {
+			if direction == "asc" {
+				return len(a.comments) - len(b.comments)
+			}
+			return len(b.comments) - len(a.comments)
+		})
+	default:
+		// Issues are created in the order of their numbers, which also orders issues that
+		// were created at the same second.
+		slices.SortStableFunc(issues, func(a, b *issue) int {
+			if direction == "asc" {
+				return a.number - b.number
+			}
+			return b.number - a.number
+		})
+	}
+}
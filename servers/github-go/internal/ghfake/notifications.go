@@ -0,0 +1,251 @@
+package ghfake
+
+import (
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/go-github/v72/github"
+)
+
+// notification is a notification thread of a user.
+type notification struct {
+	id   int64
+	user *user
+	repo *repository
+	// issue is the issue or pull request the thread is about. Threads about other subjects,
+	// which can only be seeded from fixtures, have a title and a type instead.
+	issue       *issue
+	title       string
+	subjectType string
+	reason      string
+	unread      bool
+	// done threads are no longer listed.
+	done         bool
+	updatedAt    time.Time
+	lastReadAt   time.Time
+	subscription *subscription
+}
+
+type subscription struct {
+	subscribed bool
+	ignored    bool
+	createdAt  time.Time
+}
+
+// notify notifies a user of activity on an issue or pull request by another user, unless
+// the user ignores the thread or the repository.
+func (s *Server) notify(i *issue, to *user, reason string, by *user) {
+	if to == nil || to == by {
+		return
+	}
+	if sub, ok := i.repo.subscriptions[strings.ToLower(to.login)]; ok && sub.ignored {
+		return
+	}
+
+	now := s.now()
+	for _, n := range s.notifications {
+		if n.user == to && n.issue == i && !n.done {
+			if n.subscription != nil && n.subscription.ignored {
+				return
+			}
+			n.reason, n.unread, n.updatedAt = reason, true, now
+			return
+		}
+	}
+	s.notifications = append(s.notifications, &notification{
+		id:        s.nextID(),
+		user:      to,
+		repo:      i.repo,
+		issue:     i,
+		reason:    reason,
+		unread:    true,
+		updatedAt: now,
+	})
+}
+
+func (n *notification) render(urls urls) *github.Notification {
+	subject := &github.NotificationSubject{
+		Title: github.Ptr(n.title),
+		Type:  github.Ptr(n.subjectType),
+	}
+	if n.issue != nil {
+		subject.Title = github.Ptr(n.issue.title)
+		subject.Type = github.Ptr("Issue")
+		subject.URL = github.Ptr(urls.api("repos/%s/issues/%d", n.repo.fullName(), n.issue.number))
+		if n.issue.pull != nil {
+			subject.Type = github.Ptr("PullRequest")
+			subject.URL = github.Ptr(urls.api("repos/%s/pulls/%d", n.repo.fullName(), n.issue.number))
+		}
+	}
+
+	rendered := &github.Notification{
+		ID:         github.Ptr(strconv.FormatInt(n.id, 10)),
+		Repository: n.repo.render(urls),
+		Subject:    subject,
+		Reason:     github.Ptr(n.reason),
+		Unread:     github.Ptr(n.unread),
+		UpdatedAt:  &github.Timestamp{Time: n.updatedAt},
+		URL:        github.Ptr(urls.api("notifications/threads/%d", n.id)),
+	}
+	if !n.lastReadAt.IsZero() {
+		rendered.LastReadAt = &github.Timestamp{Time: n.lastReadAt}
+	}
+	return rendered
+}
+
+// threads returns the notification threads of a user that are not done, newest first,
+// optionally only those of a repository.
+func (s *Server) threads(u *user, repo *repository) []*notification {
+	var threads []*notification
+	for _, n := range s.notifications {
+		if n.user == u && !n.done && (repo == nil || n.repo == repo) {
+			threads = append(threads, n)
+		}
+	}
+	slices.Reverse(threads)
+	sortByTime(threads, "desc", func(n *notification) time.Time { return n.updatedAt })
+	return threads
+}
+
+// withThread looks up the notification thread of the caller that a request is about, from the
+// thread_id path value, and answers 404 if there is none.
+func (s *Server) withThread(handler func(http.ResponseWriter, *http.Request, *notification)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, _ := strconv.ParseInt(r.PathValue("thread_id"), 10, 64)
+		for _, n := range s.notifications {
+			if n.id == id && n.user == s.caller(r) && !n.done {
+				handler(w, r, n)
+				return
+			}
+		}
+		notFound(w)
+	}
+}
+
+func (s *Server) notificationRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("GET /notifications", func(w http.ResponseWriter, r *http.Request) {
+		s.listNotifications(w, r, nil)
+	})
+	mux.HandleFunc("GET /repos/{owner}/{repo}/notifications", s.withRepo(s.listNotifications))
+
+	mux.HandleFunc("PUT /notifications", func(w http.ResponseWriter, r *http.Request) {
+		s.markRead(w, r, nil)
+	})
+	mux.HandleFunc("PUT /repos/{owner}/{repo}/notifications", s.withRepo(s.markRead))
+
+	mux.HandleFunc("GET /notifications/threads/{thread_id}", s.withThread(func(w http.ResponseWriter, r *http.Request, n *notification) {
+		writeJSON(w, http.StatusOK, n.render(urlsFor(r)))
+	}))
+
+	mux.HandleFunc("PATCH /notifications/threads/{thread_id}", s.withThread(func(w http.ResponseWriter, _ *http.Request, n *notification) {
+		n.unread = false
+		n.lastReadAt = s.now()
+		w.WriteHeader(http.StatusResetContent)
+	}))
+
+	mux.HandleFunc("DELETE /notifications/threads/{thread_id}", s.withThread(func(w http.ResponseWriter, _ *http.Request, n *notification) {
+		n.done = true
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	mux.HandleFunc("PUT /notifications/threads/{thread_id}/subscription", s.withThread(func(w http.ResponseWriter, r *http.Request, n *notification) {
+		var body struct {
+			Ignored bool `json:"ignored"`
+		}
+		if !decodeBody(w, r, &body) {
+			return
+		}
+		n.subscription = &subscription{subscribed: !body.Ignored, ignored: body.Ignored, createdAt: s.now()}
+		writeJSON(w, http.StatusOK, &github.Subscription{
+			Subscribed: github.Ptr(n.subscription.subscribed),
+			Ignored:    github.Ptr(n.subscription.ignored),
+			CreatedAt:  &github.Timestamp{Time: n.subscription.createdAt},
+			URL:        github.Ptr(urlsFor(r).api("notifications/threads/%d/subscription", n.id)),
+			ThreadURL:  github.Ptr(urlsFor(r).api("notifications/threads/%d", n.id)),
+		})
+	}))
+
+	mux.HandleFunc("DELETE /notifications/threads/{thread_id}/subscription", s.withThread(func(w http.ResponseWriter, _ *http.Request, n *notification) {
+		n.subscription = nil
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	mux.HandleFunc("PUT /repos/{owner}/{repo}/subscription", s.withRepo(func(w http.ResponseWriter, r *http.Request, repo *repository) {
+		var body struct {
+			Subscribed bool `json:"subscribed"`
+			Ignored    bool `json:"ignored"`
+		}
+		if !decodeBody(w, r, &body) {
+			return
+		}
+		sub := &subscription{subscribed: body.Subscribed, ignored: body.Ignored, createdAt: s.now()}
+		repo.subscriptions[strings.ToLower(s.caller(r).login)] = sub
+		writeJSON(w, http.StatusOK, &github.Subscription{
+			Subscribed:    github.Ptr(sub.subscribed),
+			Ignored:       github.Ptr(sub.ignored),
+			CreatedAt:     &github.Timestamp{Time: sub.createdAt},
+			URL:           github.Ptr(urlsFor(r).api("repos/%s/subscription", repo.fullName())),
+			RepositoryURL: github.Ptr(urlsFor(r).api("repos/%s", repo.fullName())),
+		})
+	}))
+
+	mux.HandleFunc("DELETE /repos/{owner}/{repo}/subscription", s.withRepo(func(w http.ResponseWriter, r *http.Request, repo *repository) {
+		delete(repo.subscriptions, strings.ToLower(s.caller(r).login))
+		w.WriteHeader(http.StatusNoContent)
+	}))
+}
+
+// listNotifications lists the notification threads of the caller, or only those of a
+// repository if repo is not nil.
+func (s *Server) listNotifications(w http.ResponseWriter, r *http.Request, repo *repository) {
+	query := r.URL.Query()
+	var since, before time.Time
+	for name, t := range map[string]*time.Time{"since": &since, "before": &before} {
+		if value := query.Get(name); value != "" {
+			parsed, err := time.Parse(time.RFC3339, value)
+			if err != nil {
+				writeValidationError(w, name+" is not a valid ISO 8601 timestamp")
+				return
+			}
+			*t = parsed
+		}
+	}
+
+	notifications := []*github.Notification{}
+	for _, n := range s.threads(s.caller(r), repo) {
+		switch {
+		case query.Get("all") != "true" && !n.unread,
+			query.Get("participating") == "true" && n.reason == "subscribed",
+			!since.IsZero() && n.updatedAt.Before(since),
+			!before.IsZero() && !n.updatedAt.Before(before):
+			continue
+		}
+		notifications = append(notifications, n.render(urlsFor(r)))
+	}
+	writeJSON(w, http.StatusOK, paginate(w, r, notifications))
+}
+
+// markRead marks the notification threads of the caller that were updated before the
+// last_read_at time as read, or only those of a repository if repo is not nil.
+func (s *Server) markRead(w http.ResponseWriter, r *http.Request, repo *repository) {
+	var body struct {
+		LastReadAt *time.Time `json:"last_read_at"`
+	}
+	if r.ContentLength != 0 && !decodeBody(w, r, &body) {
+		return
+	}
+	lastReadAt := s.now()
+	if body.LastReadAt != nil && !body.LastReadAt.IsZero() {
+		lastReadAt = *body.LastReadAt
+	}
+	for _, n := range s.threads(s.caller(r), repo) {
+		if n.unread && !n.updatedAt.After(lastReadAt) {
+			n.unread = false
+			n.lastReadAt = lastReadAt
+		}
+	}
+	w.WriteHeader(http.StatusResetContent)
+}
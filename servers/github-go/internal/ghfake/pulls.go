@@ -0,0 +1,911 @@
+package ghfake
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/go-github/v72/github"
+)
+
+// pull holds what a pull request has in addition to its issue.
+type pull struct {
+	issue *issue
+	// head is the repository of the head branch, which is the repository of the pull request
+	// or a fork of it.
+	head    *repository
+	headRef string
+	// headSHA is the commit the head branch pointed to when the pull request was last looked
+	// at. It is kept when the branch is deleted, as on GitHub.
+	headSHA string
+	base    string
+	draft   bool
+
+	maintainerCanModify bool
+	requestedReviewers  []*user
+	reviews             []*review
+
+	merged         bool
+	mergedAt       time.Time
+	mergedBy       *user
+	mergeCommitSHA string
+	// mergeBaseSHA is the commit the changes of a merged pull request are compared to.
+	mergeBaseSHA string
+}
+
+// review is a pull request review, which is pending until it is submitted.
+type review struct {
+	id          int64
+	pull        *pull
+	author      *user
+	body        string
+	state       string
+	commitID    string
+	submittedAt time.Time
+	comments    []*reviewComment
+}
+
+type reviewComment struct {
+	id          int64
+	review      *review
+	body        string
+	path        string
+	line        int
+	side        string
+	startLine   int
+	startSide   string
+	subjectType string
+	createdAt   time.Time
+}
+
+// pullByNumber returns the pull request with the number, if there is one.
+func (repo *repository) pullByNumber(number string) *pull {
+	i := repo.issueByNumber(number)
+	if i == nil || i.pull == nil {
+		return nil
+	}
+	i.pull.refresh()
+	return i.pull
+}
+
+// withPull looks up the pull request that a request is about, from the number path value,
+// and answers 404 if there is none.
+func (s *Server) withPull(handler func(http.ResponseWriter, *http.Request, *pull)) http.HandlerFunc {
+	return s.withRepo(func(w http.ResponseWriter, r *http.Request, repo *repository) {
+		p := repo.pullByNumber(r.PathValue("number"))
+		if p == nil {
+			notFound(w)
+			return
+		}
+		handler(w, r, p)
+	})
+}
+
+// refresh follows the head branch of an open pull request.
+func (p *pull) refresh() {
+	if p.issue.state != "open" {
+		return
+	}
+	if sha, ok := p.head.refs["refs/heads/"+p.headRef]; ok {
+		p.headSHA = sha
+	}
+}
+
+func (p *pull) repo() *repository {
+	return p.issue.repo
+}
+
+func (p *pull) headCommit() *commit {
+	return p.repo().objects.commits[p.headSHA]
+}
+
+// baseCommit returns the commit the base branch points to, or nil if it was deleted.
+func (p *pull) baseCommit() *commit {
+	c, _ := p.repo().branchHead(p.base)
+	return c
+}
+
+// mergeBase returns the commit that the changes of the pull request are compared to.
+func (p *pull) mergeBase() *commit {
+	if p.merged {
+		return p.repo().objects.commits[p.mergeBaseSHA]
+	}
+	base := p.baseCommit()
+	if base == nil {
+		return nil
+	}
+	return p.repo().objects.mergeBase(base, p.headCommit())
+}
+
+// commits returns the commits of the pull request, oldest first.
+func (p *pull) commits() []*commit {
+	objects := p.repo().objects
+	inBase := map[string]bool{}
+	if mergeBase := p.mergeBase(); mergeBase != nil {
+		for _, c := range objects.history(mergeBase) {
+			inBase[c.sha] = true
+		}
+	}
+	var commits []*commit
+	for _, c := range objects.history(p.headCommit()) {
+		if !inBase[c.sha] {
+			commits = append(commits, c)
+		}
+	}
+	slices.Reverse(commits)
+	return commits
+}
+
+func (p *pull) files() []*github.CommitFile {
+	objects := p.repo().objects
+	var before map[string]string
+	if mergeBase := p.mergeBase(); mergeBase != nil {
+		before = objects.files(mergeBase)
+	}
+	return diffFiles(objects, before, objects.files(p.headCommit()))
+}
+
+// mergedFiles merges the pull request into its base branch, without committing the result.
+func (p *pull) mergedFiles() (map[string]string, error) {
+	base := p.baseCommit()
+	if base == nil {
+		return nil, errors.New("base branch was deleted")
+	}
+	return p.repo().objects.merge(p.mergeBase(), base, p.headCommit())
+}
+
+// submittedComments returns the comments of the reviews that were submitted.
+func (p *pull) submittedComments() []*reviewComment {
+	var comments []*reviewComment
+	for _, rv := range p.reviews {
+		if rv.state != "PENDING" {
+			comments = append(comments, rv.comments...)
+		}
+	}
+	return comments
+}
+
+func (p *pull) render(urls urls) *github.PullRequest {
+	i, repo := p.issue, p.repo()
+	rendered := &github.PullRequest{
+		ID:                  github.Ptr(i.id),
+		NodeID:              github.Ptr(i.nodeID()),
+		Number:              github.Ptr(i.number),
+		State:               github.Ptr(i.state),
+		Locked:              github.Ptr(false),
+		Title:               github.Ptr(i.title),
+		Body:                github.Ptr(i.body),
+		User:                i.author.render(urls),
+		Labels:              renderLabels(i.labels),
+		Assignees:           renderUsers(i.assignees, urls),
+		RequestedReviewers:  renderUsers(p.requestedReviewers, urls),
+		Draft:               github.Ptr(p.draft),
+		Merged:              github.Ptr(p.merged),
+		MaintainerCanModify: github.Ptr(p.maintainerCanModify),
+		Comments:            github.Ptr(len(i.comments)),
+		ReviewComments:      github.Ptr(len(p.submittedComments())),
+		CreatedAt:           &github.Timestamp{Time: i.createdAt},
+		UpdatedAt:           &github.Timestamp{Time: i.updatedAt},
+		URL:                 github.Ptr(urls.api("repos/%s/pulls/%d", repo.fullName(), i.number)),
+		HTMLURL:             github.Ptr(urls.html("%s/pull/%d", repo.fullName(), i.number)),
+		DiffURL:             github.Ptr(urls.html("%s/pull/%d.diff", repo.fullName(), i.number)),
+		PatchURL:            github.Ptr(urls.html("%s/pull/%d.patch", repo.fullName(), i.number)),
+		IssueURL:            github.Ptr(urls.api("repos/%s/issues/%d", repo.fullName(), i.number)),
+		Head: &github.PullRequestBranch{
+			Label: github.Ptr(p.head.owner.login + ":" + p.headRef),
+			Ref:   github.Ptr(p.headRef),
+			SHA:   github.Ptr(p.headSHA),
+			Repo:  p.head.render(urls),
+			User:  p.head.owner.render(urls),
+		},
+		Base: &github.PullRequestBranch{
+			Label: github.Ptr(repo.owner.login + ":" + p.base),
+			Ref:   github.Ptr(p.base),
+			Repo:  repo.render(urls),
+			User:  repo.owner.render(urls),
+		},
+	}
+	if base := p.baseCommit(); base != nil {
+		rendered.Base.SHA = github.Ptr(base.sha)
+	}
+	if len(i.assignees) > 0 {
+		rendered.Assignee = i.assignees[0].render(urls)
+	}
+	if i.state == "closed" {
+		rendered.ClosedAt = &github.Timestamp{Time: i.closedAt}
+	}
+	if p.merged {
+		rendered.MergedAt = &github.Timestamp{Time: p.mergedAt}
+		rendered.MergedBy = p.mergedBy.render(urls)
+		rendered.MergeCommitSHA = github.Ptr(p.mergeCommitSHA)
+	} else if i.state == "open" {
+		_, err := p.mergedFiles()
+		rendered.Mergeable = github.Ptr(err == nil)
+		switch {
+		case err != nil:
+			rendered.MergeableState = github.Ptr("dirty")
+		case p.draft:
+			rendered.MergeableState = github.Ptr("draft")
+		default:
+			rendered.MergeableState = github.Ptr("clean")
+		}
+	}
+
+	files := p.files()
+	var additions, deletions int
+	for _, file := range files {
+		additions += file.GetAdditions()
+		deletions += file.GetDeletions()
+	}
+	rendered.Commits = github.Ptr(len(p.commits()))
+	rendered.Additions = github.Ptr(additions)
+	rendered.Deletions = github.Ptr(deletions)
+	rendered.ChangedFiles = github.Ptr(len(files))
+	return rendered
+}
+
+func (rv *review) nodeID() string {
+	return fmt.Sprintf("PRR_%d", rv.id)
+}
+
+func (rv *review) htmlURL(urls urls) string {
+	return urls.html("%s/pull/%d#pullrequestreview-%d", rv.pull.repo().fullName(), rv.pull.issue.number, rv.id)
+}
+
+func (rv *review) render(urls urls) *github.PullRequestReview {
+	rendered := &github.PullRequestReview{
+		ID:             github.Ptr(rv.id),
+		NodeID:         github.Ptr(rv.nodeID()),
+		User:           rv.author.render(urls),
+		Body:           github.Ptr(rv.body),
+		State:          github.Ptr(rv.state),
+		CommitID:       github.Ptr(rv.commitID),
+		HTMLURL:        github.Ptr(rv.htmlURL(urls)),
+		PullRequestURL: github.Ptr(urls.api("repos/%s/pulls/%d", rv.pull.repo().fullName(), rv.pull.issue.number)),
+	}
+	if rv.state != "PENDING" {
+		rendered.SubmittedAt = &github.Timestamp{Time: rv.submittedAt}
+	}
+	return rendered
+}
+
+func (c *reviewComment) render(urls urls) *github.PullRequestComment {
+	rv := c.review
+	repo, number := rv.pull.repo(), rv.pull.issue.number
+	rendered := &github.PullRequestComment{
+		ID:                  github.Ptr(c.id),
+		NodeID:              github.Ptr(fmt.Sprintf("PRRC_%d", c.id)),
+		PullRequestReviewID: github.Ptr(rv.id),
+		Path:                github.Ptr(c.path),
+		CommitID:            github.Ptr(rv.commitID),
+		OriginalCommitID:    github.Ptr(rv.commitID),
+		User:                rv.author.render(urls),
+		Body:                github.Ptr(c.body),
+		SubjectType:         github.Ptr(strings.ToLower(c.subjectType)),
+		CreatedAt:           &github.Timestamp{Time: c.createdAt},
+		UpdatedAt:           &github.Timestamp{Time: c.createdAt},
+		URL:                 github.Ptr(urls.api("repos/%s/pulls/comments/%d", repo.fullName(), c.id)),
+		HTMLURL:             github.Ptr(urls.html("%s/pull/%d#discussion_r%d", repo.fullName(), number, c.id)),
+		PullRequestURL:      github.Ptr(urls.api("repos/%s/pulls/%d", repo.fullName(), number)),
+	}
+	if c.line != 0 {
+		rendered.Line = github.Ptr(c.line)
+		rendered.OriginalLine = github.Ptr(c.line)
+		rendered.Side = github.Ptr(c.side)
+	}
+	if c.startLine != 0 {
+		rendered.StartLine = github.Ptr(c.startLine)
+		rendered.OriginalStartLine = github.Ptr(c.startLine)
+		rendered.StartSide = github.Ptr(c.startSide)
+	}
+	return rendered
+}
+
+// reviewState maps the events that submit reviews to the states of the submitted reviews.
+var reviewState = map[string]string{
+	"APPROVE":         "APPROVED",
+	"REQUEST_CHANGES": "CHANGES_REQUESTED",
+	"COMMENT":         "COMMENTED",
+}
+
+// addReview adds a review to a pull request. It is pending if event is empty, and submitted
+// with the event otherwise. The review is of the head of the pull request unless commitID
+// names another commit.
+func (s *Server) addReview(p *pull, author *user, body, event, commitID string) (*review, error) {
+	if event == "" {
+		for _, rv := range p.reviews {
+			if rv.author == author && rv.state == "PENDING" {
+				return nil, errors.New("user can only have one pending review per pull request")
+			}
+		}
+	}
+	if commitID == "" {
+		commitID = p.headSHA
+	} else if _, ok := p.repo().objects.commits[commitID]; !ok {
+		return nil, fmt.Errorf("could not resolve to a commit with the oid %s", commitID)
+	}
+
+	rv := &review{
+		id:       s.nextID(),
+		pull:     p,
+		author:   author,
+		state:    "PENDING",
+		commitID: commitID,
+	}
+	if event != "" {
+		if err := s.submitReview(rv, event, body); err != nil {
+			return nil, err
+		}
+	}
+	rv.body = body
+	p.reviews = append(p.reviews, rv)
+	return rv, nil
+}
+
+// submitReview submits a pending review with an event.
+func (s *Server) submitReview(rv *review, event, body string) error {
+	state, ok := reviewState[event]
+	switch {
+	case rv.state != "PENDING":
+		return errors.New("review has already been submitted")
+	case !ok:
+		return fmt.Errorf("%s is not a valid review event", event)
+	case event == "APPROVE" && rv.author == rv.pull.issue.author:
+		return errors.New("can not approve your own pull request")
+	case event == "REQUEST_CHANGES" && rv.author == rv.pull.issue.author:
+		return errors.New("can not request changes on your own pull request")
+	case event != "APPROVE" && body == "" && rv.body == "" && len(rv.comments) == 0:
+		return errors.New("review body is required")
+	}
+
+	if body != "" {
+		rv.body = body
+	}
+	rv.state = state
+	rv.submittedAt = s.now()
+	p := rv.pull
+	p.requestedReviewers = slices.DeleteFunc(p.requestedReviewers, func(u *user) bool { return u == rv.author })
+	p.issue.updatedAt = rv.submittedAt
+	s.notify(p.issue, p.issue.author, "author", rv.author)
+	return nil
+}
+
+// deleteReview deletes a pending review.
+func (s *Server) deleteReview(rv *review) error {
+	if rv.state != "PENDING" {
+		return errors.New("can not delete a review that has been submitted")
+	}
+	p := rv.pull
+	p.reviews = slices.DeleteFunc(p.reviews, func(other *review) bool { return other == rv })
+	return nil
+}
+
+// addReviewComment adds a comment on a file of the pull request to a pending review.
+func (s *Server) addReviewComment(rv *review, c *reviewComment) error {
+	if rv.state != "PENDING" {
+		return errors.New("comments can only be added to pending reviews")
+	}
+	if !slices.ContainsFunc(rv.pull.files(), func(file *github.CommitFile) bool { return file.GetFilename() == c.path }) {
+		return fmt.Errorf("path %s is not part of the pull request", c.path)
+	}
+	if c.subjectType == "" {
+		c.subjectType = "LINE"
+	}
+	if c.subjectType == "LINE" && c.line == 0 {
+		return errors.New("line is required for comments on lines")
+	}
+	if c.side == "" && c.line != 0 {
+		c.side = "RIGHT"
+	}
+	if c.startSide == "" && c.startLine != 0 {
+		c.startSide = c.side
+	}
+	c.id = s.nextID()
+	c.review = rv
+	c.createdAt = s.now()
+	rv.comments = append(rv.comments, c)
+	return nil
+}
+
+// reviewByNodeID finds a review by its node ID across all repositories.
+func (s *Server) reviewByNodeID(id string) *review {
+	for _, repo := range s.repos {
+		for _, i := range repo.issues {
+			if i.pull == nil {
+				continue
+			}
+			for _, rv := range i.pull.reviews {
+				if rv.nodeID() == id {
+					return rv
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// pullByNodeID finds a pull request by its node ID across all repositories.
+func (s *Server) pullByNodeID(id string) *pull {
+	if i := s.issueByNodeID(id); i != nil && i.pull != nil {
+		i.pull.refresh()
+		return i.pull
+	}
+	return nil
+}
+
+// issueByNodeID finds an issue or pull request by its node ID across all repositories.
+func (s *Server) issueByNodeID(id string) *issue {
+	for _, repo := range s.repos {
+		for _, i := range repo.issues {
+			if i.nodeID() == id {
+				return i
+			}
+		}
+	}
+	return nil
+}
+
+// headRepository finds the repository of the head of a new pull request, which may name its
+// branch as owner:branch to open it from a fork.
+func (s *Server) headRepository(repo *repository, head string) (*repository, string) {
+	owner, branch, ok := strings.Cut(head, ":")
+	if !ok || strings.EqualFold(owner, repo.owner.login) {
+		return repo, strings.TrimPrefix(head, owner+":")
+	}
+	for _, candidate := range s.repos {
+		if candidate.objects == repo.objects && strings.EqualFold(candidate.owner.login, owner) {
+			return candidate, branch
+		}
+	}
+	return nil, branch
+}
+
+func (s *Server) pullRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("GET /repos/{owner}/{repo}/pulls", s.withRepo(func(w http.ResponseWriter, r *http.Request, repo *repository) {
+		query := r.URL.Query()
+		state := query.Get("state")
+		if state == "" {
+			state = "open"
+		}
+
+		var matches []*issue
+		for _, i := range repo.issues {
+			if i.pull == nil || state != "all" && i.state != state {
+				continue
+			}
+			p := i.pull
+			p.refresh()
+			if base := query.Get("base"); base != "" && p.base != base {
+				continue
+			}
+			if head := query.Get("head"); head != "" && !strings.EqualFold(head, p.head.owner.login+":"+p.headRef) {
+				continue
+			}
+			matches = append(matches, i)
+		}
+		if query.Get("sort") == "updated" {
+			sortByTime(matches, query.Get("direction"), func(i *issue) time.Time { return i.updatedAt })
+		} else {
+			sortIssues(matches, "created", query.Get("direction"))
+		}
+
+		pulls := make([]*github.PullRequest, 0, len(matches))
+		for _, i := range matches {
+			pulls = append(pulls, i.pull.render(urlsFor(r)))
+		}
+		writeJSON(w, http.StatusOK, paginate(w, r, pulls))
+	}))
+
+	mux.HandleFunc("POST /repos/{owner}/{repo}/pulls", s.withRepo(func(w http.ResponseWriter, r *http.Request, repo *repository) {
+		var body struct {
+			Title               string `json:"title"`
+			Body                string `json:"body"`
+			Head                string `json:"head"`
+			Base                string `json:"base"`
+			Draft               bool   `json:"draft"`
+			MaintainerCanModify *bool  `json:"maintainer_can_modify"`
+		}
+		if !decodeBody(w, r, &body) {
+			return
+		}
+		if body.Title == "" {
+			writeValidationError(w, "title cannot be blank")
+			return
+		}
+		base, ok := repo.branchHead(body.Base)
+		if !ok {
+			writeValidationError(w, "base is invalid")
+			return
+		}
+		headRepo, headRef := s.headRepository(repo, body.Head)
+		if headRepo == nil {
+			writeValidationError(w, "head is invalid")
+			return
+		}
+		head, ok := headRepo.branchHead(headRef)
+		if !ok {
+			writeValidationError(w, "head is invalid")
+			return
+		}
+		if repo.objects.isAncestor(head, base) {
+			writeValidationError(w, fmt.Sprintf("No commits between %s and %s", body.Base, body.Head))
+			return
+		}
+		for _, i := range repo.issues {
+			if p := i.pull; p != nil && i.state == "open" && p.head == headRepo && p.headRef == headRef && p.base == body.Base {
+				writeValidationError(w, fmt.Sprintf("A pull request already exists for %s:%s.", headRepo.owner.login, headRef))
+				return
+			}
+		}
+
+		i := s.newIssue(repo, s.caller(r), body.Title, body.Body)
+		i.pull = &pull{
+			issue:               i,
+			head:                headRepo,
+			headRef:             headRef,
+			headSHA:             head.sha,
+			base:                body.Base,
+			draft:               body.Draft,
+			maintainerCanModify: body.MaintainerCanModify == nil || *body.MaintainerCanModify,
+		}
+		writeJSON(w, http.StatusCreated, i.pull.render(urlsFor(r)))
+	}))
+
+	mux.HandleFunc("GET /repos/{owner}/{repo}/pulls/{number}", s.withPull(func(w http.ResponseWriter, r *http.Request, p *pull) {
+		switch r.Header.Get("Accept") {
+		case "application/vnd.github.v3.diff":
+			w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+			_, _ = w.Write([]byte(unifiedDiff(p.files())))
+		case "application/vnd.github.v3.patch":
+			w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+			_, _ = w.Write([]byte(formatPatch(p)))
+		default:
+			writeJSON(w, http.StatusOK, p.render(urlsFor(r)))
+		}
+	}))
+
+	mux.HandleFunc("PATCH /repos/{owner}/{repo}/pulls/{number}", s.withPull(func(w http.ResponseWriter, r *http.Request, p *pull) {
+		var body struct {
+			Title               *string `json:"title"`
+			Body                *string `json:"body"`
+			State               *string `json:"state"`
+			Base                *string `json:"base"`
+			MaintainerCanModify *bool   `json:"maintainer_can_modify"`
+		}
+		if !decodeBody(w, r, &body) {
+			return
+		}
+		if body.Base != nil {
+			if _, ok := p.repo().branchHead(*body.Base); !ok {
+				writeValidationError(w, "base is invalid")
+				return
+			}
+		}
+		if p.merged && body.State != nil && *body.State == "open" {
+			writeValidationError(w, "merged pull requests cannot be reopened")
+			return
+		}
+		if !s.apply(w, r, p.issue, issueRequest{Title: body.Title, Body: body.Body, State: body.State}) {
+			return
+		}
+		if body.Base != nil {
+			p.base = *body.Base
+		}
+		if body.MaintainerCanModify != nil {
+			p.maintainerCanModify = *body.MaintainerCanModify
+		}
+		p.refresh()
+		writeJSON(w, http.StatusOK, p.render(urlsFor(r)))
+	}))
+
+	mux.HandleFunc("GET /repos/{owner}/{repo}/pulls/{number}/files", s.withPull(func(w http.ResponseWriter, r *http.Request, p *pull) {
+		writeJSON(w, http.StatusOK, paginate(w, r, p.files()))
+	}))
+
+	mux.HandleFunc("GET /repos/{owner}/{repo}/pulls/{number}/commits", s.withPull(func(w http.ResponseWriter, r *http.Request, p *pull) {
+		commits := []*github.RepositoryCommit{}
+		for _, c := range p.commits() {
+			commits = append(commits, p.repo().renderRepositoryCommit(c, false, urlsFor(r)))
+		}
+		writeJSON(w, http.StatusOK, paginate(w, r, commits))
+	}))
+
+	mux.HandleFunc("GET /repos/{owner}/{repo}/pulls/{number}/merge", s.withPull(func(w http.ResponseWriter, _ *http.Request, p *pull) {
+		if !p.merged {
+			notFound(w)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	mux.HandleFunc("PUT /repos/{owner}/{repo}/pulls/{number}/merge", s.withPull(s.mergePull))
+
+	mux.HandleFunc("PUT /repos/{owner}/{repo}/pulls/{number}/update-branch", s.withPull(func(w http.ResponseWriter, r *http.Request, p *pull) {
+		var body struct {
+			ExpectedHeadSHA string `json:"expected_head_sha"`
+		}
+		if r.ContentLength != 0 && !decodeBody(w, r, &body) {
+			return
+		}
+		if body.ExpectedHeadSHA != "" && body.ExpectedHeadSHA != p.headSHA {
+			writeValidationError(w, "expected head sha didn't match current head ref.")
+			return
+		}
+		base, head := p.baseCommit(), p.headCommit()
+		if p.issue.state != "open" || base == nil {
+			writeValidationError(w, "pull request is not open")
+			return
+		}
+		if p.repo().objects.isAncestor(base, head) {
+			writeValidationError(w, "There are no new commits on the base branch.")
+			return
+		}
+		files, err := p.repo().objects.merge(p.mergeBase(), head, base)
+		if err != nil {
+			writeValidationError(w, "merge conflict between base and head")
+			return
+		}
+
+		objects := p.repo().objects
+		message := fmt.Sprintf("Merge branch '%s' into %s", p.base, p.headRef)
+		c := s.putCommit(objects, objects.putTree(files), message, s.caller(r), []string{head.sha, base.sha})
+		p.head.refs["refs/heads/"+p.headRef] = c.sha
+		p.head.pushed(s.now())
+		p.refresh()
+		writeJSON(w, http.StatusAccepted, map[string]string{
+			"message": "Updating pull request branch.",
+			"url":     urlsFor(r).html("%s/pull/%d", p.repo().fullName(), p.issue.number),
+		})
+	}))
+
+	mux.HandleFunc("GET /repos/{owner}/{repo}/pulls/{number}/comments", s.withPull(func(w http.ResponseWriter, r *http.Request, p *pull) {
+		comments := []*github.PullRequestComment{}
+		for _, c := range p.submittedComments() {
+			comments = append(comments, c.render(urlsFor(r)))
+		}
+		writeJSON(w, http.StatusOK, paginate(w, r, comments))
+	}))
+
+	// Pending reviews are only listed for their authors, as on GitHub.
+	mux.HandleFunc("GET /repos/{owner}/{repo}/pulls/{number}/reviews", s.withPull(func(w http.ResponseWriter, r *http.Request, p *pull) {
+		reviews := []*github.PullRequestReview{}
+		for _, rv := range p.reviews {
+			if rv.state != "PENDING" || rv.author == s.caller(r) {
+				reviews = append(reviews, rv.render(urlsFor(r)))
+			}
+		}
+		writeJSON(w, http.StatusOK, paginate(w, r, reviews))
+	}))
+
+	mux.HandleFunc("GET /repos/{owner}/{repo}/pulls/{number}/reviews/{review_id}", s.withPull(func(w http.ResponseWriter, r *http.Request, p *pull) {
+		rv := s.visibleReview(r, p)
+		if rv == nil {
+			notFound(w)
+			return
+		}
+		writeJSON(w, http.StatusOK, rv.render(urlsFor(r)))
+	}))
+
+	mux.HandleFunc("GET /repos/{owner}/{repo}/pulls/{number}/reviews/{review_id}/comments", s.withPull(func(w http.ResponseWriter, r *http.Request, p *pull) {
+		rv := s.visibleReview(r, p)
+		if rv == nil {
+			notFound(w)
+			return
+		}
+		comments := []*github.PullRequestComment{}
+		for _, c := range rv.comments {
+			comments = append(comments, c.render(urlsFor(r)))
+		}
+		writeJSON(w, http.StatusOK, paginate(w, r, comments))
+	}))
+
+	mux.HandleFunc("POST /repos/{owner}/{repo}/pulls/{number}/reviews", s.withPull(func(w http.ResponseWriter, r *http.Request, p *pull) {
+		var body struct {
+			Body     string `json:"body"`
+			Event    string `json:"event"`
+			CommitID string `json:"commit_id"`
+			Comments []struct {
+				Path      string `json:"path"`
+				Body      string `json:"body"`
+				Line      int    `json:"line"`
+				Side      string `json:"side"`
+				StartLine int    `json:"start_line"`
+				StartSide string `json:"start_side"`
+			} `json:"comments"`
+		}
+		if !decodeBody(w, r, &body) {
+			return
+		}
+		rv, err := s.addReview(p, s.caller(r), body.Body, "", body.CommitID)
+		if err != nil {
+			writeValidationError(w, err.Error())
+			return
+		}
+		for _, c := range body.Comments {
+			err := s.addReviewComment(rv, &reviewComment{
+				body:      c.Body,
+				path:      c.Path,
+				line:      c.Line,
+				side:      c.Side,
+				startLine: c.StartLine,
+				startSide: c.StartSide,
+			})
+			if err != nil {
+				_ = s.deleteReview(rv)
+				writeValidationError(w, err.Error())
+				return
+			}
+		}
+		if body.Event != "" {
+			if err := s.submitReview(rv, body.Event, body.Body); err != nil {
+				_ = s.deleteReview(rv)
+				writeValidationError(w, err.Error())
+				return
+			}
+		}
+		writeJSON(w, http.StatusOK, rv.render(urlsFor(r)))
+	}))
+
+	mux.HandleFunc("POST /repos/{owner}/{repo}/pulls/{number}/requested_reviewers", s.withPull(func(w http.ResponseWriter, r *http.Request, p *pull) {
+		var body struct {
+			Reviewers []string `json:"reviewers"`
+		}
+		if !decodeBody(w, r, &body) {
+			return
+		}
+		reviewers, ok := s.usersByLogin(w, "reviewer", body.Reviewers)
+		if !ok {
+			return
+		}
+		for _, reviewer := range reviewers {
+			if reviewer == p.issue.author {
+				writeValidationError(w, "Review cannot be requested from pull request author.")
+				return
+			}
+		}
+		for _, reviewer := range reviewers {
+			if !slices.Contains(p.requestedReviewers, reviewer) {
+				p.requestedReviewers = append(p.requestedReviewers, reviewer)
+				s.notify(p.issue, reviewer, "review_requested", s.caller(r))
+			}
+		}
+		p.issue.updatedAt = s.now()
+		writeJSON(w, http.StatusCreated, p.render(urlsFor(r)))
+	}))
+}
+
+// visibleReview returns the review of the pull request with the review_id path value, unless
+// there is none or it is pending and the caller isn't its author.
+func (s *Server) visibleReview(r *http.Request, p *pull) *review {
+	id, _ := strconv.ParseInt(r.PathValue("review_id"), 10, 64)
+	for _, rv := range p.reviews {
+		if rv.id == id && (rv.state != "PENDING" || rv.author == s.caller(r)) {
+			return rv
+		}
+	}
+	return nil
+}
+
+func (s *Server) mergePull(w http.ResponseWriter, r *http.Request, p *pull) {
+	var body struct {
+		CommitTitle   string `json:"commit_title"`
+		CommitMessage string `json:"commit_message"`
+		SHA           string `json:"sha"`
+		MergeMethod   string `json:"merge_method"`
+	}
+	if r.ContentLength != 0 && !decodeBody(w, r, &body) {
+		return
+	}
+	switch {
+	case p.merged || p.issue.state != "open":
+		writeError(w, http.StatusMethodNotAllowed, "Pull Request is not mergeable")
+		return
+	case p.draft:
+		writeError(w, http.StatusMethodNotAllowed, "Pull Request is still a draft")
+		return
+	case body.SHA != "" && body.SHA != p.headSHA:
+		writeError(w, http.StatusConflict, "Head branch was modified. Review and try the merge again.")
+		return
+	}
+	files, err := p.mergedFiles()
+	if err != nil {
+		writeError(w, http.StatusMethodNotAllowed, "Pull Request is not mergeable")
+		return
+	}
+
+	objects := p.repo().objects
+	base, head, mergeBase := p.baseCommit(), p.headCommit(), p.mergeBase()
+	caller := s.caller(r)
+	title := body.CommitTitle
+	var merged *commit
+	switch body.MergeMethod {
+	case "", "merge":
+		if title == "" {
+			title = fmt.Sprintf("Merge pull request #%d from %s:%s", p.issue.number, p.head.owner.login, p.headRef)
+		}
+		message := joinMessage(title, body.CommitMessage, p.issue.title)
+		merged = s.putCommit(objects, objects.putTree(files), message, caller, []string{base.sha, head.sha})
+	case "squash":
+		if title == "" {
+			title = fmt.Sprintf("%s (#%d)", p.issue.title, p.issue.number)
+		}
+		message := joinMessage(title, body.CommitMessage, "")
+		merged = s.putCommit(objects, objects.putTree(files), message, caller, []string{base.sha})
+	case "rebase":
+		// Replay the commits of the pull request on the base branch one by one.
+		merged = base
+		for _, c := range p.commits() {
+			var parent *commit
+			if len(c.parents) > 0 {
+				parent = objects.commits[c.parents[0]]
+			}
+			replayed, err := objects.merge(parent, merged, c)
+			if err != nil {
+				writeError(w, http.StatusMethodNotAllowed, "This branch can't be rebased")
+				return
+			}
+			merged = s.putCommit(objects, objects.putTree(replayed), c.message, c.author, []string{merged.sha})
+		}
+	default:
+		writeValidationError(w, fmt.Sprintf("merge_method %q is not one of merge, squash or rebase", body.MergeMethod))
+		return
+	}
+
+	repo := p.repo()
+	repo.refs["refs/heads/"+p.base] = merged.sha
+	repo.pushed(s.now())
+	p.merged = true
+	p.mergedAt = s.now()
+	p.mergedBy = caller
+	p.mergeCommitSHA = merged.sha
+	if mergeBase != nil {
+		p.mergeBaseSHA = mergeBase.sha
+	}
+	p.issue.setState("closed", "completed", caller, p.mergedAt)
+	p.issue.updatedAt = p.mergedAt
+	writeJSON(w, http.StatusOK, &github.PullRequestMergeResult{
+		SHA:     github.Ptr(merged.sha),
+		Merged:  github.Ptr(true),
+		Message: github.Ptr("Pull Request successfully merged"),
+	})
+}
+
+// joinMessage joins the title and the message of a commit, with fallback as the message if
+// there is none.
+func joinMessage(title, message, fallback string) string {
+	if message == "" {
+		message = fallback
+	}
+	if message == "" {
+		return title
+	}
+	return title + "\n\n" + message
+}
+
+// formatPatch renders the commits of a pull request in the format of git format-patch, which
+// the API returns for the patch media type.
+func formatPatch(p *pull) string {
+	objects := p.repo().objects
+	var b strings.Builder
+	commits := p.commits()
+	for n, c := range commits {
+		var before map[string]string
+		if len(c.parents) > 0 {
+			before = objects.files(objects.commits[c.parents[0]])
+		}
+		subject, message, _ := strings.Cut(c.message, "\n")
+		fmt.Fprintf(&b, "From %s Mon Sep 17 00:00:00 2001\n", c.sha)
+		fmt.Fprintf(&b, "From: %s <%s@users.noreply.github.com>\n", c.author.login, c.author.login)
+		fmt.Fprintf(&b, "Date: %s\n", c.date.Format(time.RFC1123Z))
+		fmt.Fprintf(&b, "Subject: [PATCH %d/%d] %s\n\n", n+1, len(commits), subject)
+		if message = strings.TrimSpace(message); message != "" {
+			b.WriteString(message + "\n")
+		}
+		fmt.Fprintf(&b, "---\n%s\n", unifiedDiff(diffFiles(objects, before, objects.files(c))))
+	}
+	return b.String()
+}
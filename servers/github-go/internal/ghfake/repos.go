@@ -0,0 +1,662 @@
+package ghfake
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/go-github/v72/github"
+)
+
+type repository struct {
+	id            int64
+	owner         *user
+	name          string
+	description   string
+	private       bool
+	defaultBranch string
+	// parent is the repository this one is a fork of, if any.
+	parent *repository
+
+	createdAt time.Time
+	updatedAt time.Time
+	pushedAt  time.Time
+
+	objects *objectStore
+	// refs maps the full names of refs, such as refs/heads/main, to the SHAs of the objects
+	// they point to.
+	refs map[string]string
+	// issues holds the issues and pull requests of the repository, which share their numbers,
+	// in the order of their numbers.
+	issues []*issue
+	// statuses holds the commit statuses by commit SHA, oldest first.
+	statuses map[string][]*commitStatus
+	// subscriptions holds the subscriptions of users to the repository by login.
+	subscriptions map[string]*subscription
+}
+
+type commitStatus struct {
+	id          int64
+	state       string
+	context     string
+	description string
+	targetURL   string
+	creator     *user
+	createdAt   time.Time
+}
+
+func (repo *repository) fullName() string {
+	return repo.owner.login + "/" + repo.name
+}
+
+func (repo *repository) nodeID() string {
+	return fmt.Sprintf("R_%d", repo.id)
+}
+
+// pushed records that the commits of the repository changed.
+func (repo *repository) pushed(now time.Time) {
+	repo.pushedAt = now
+	repo.updatedAt = now
+}
+
+func (repo *repository) refNames() []string {
+	return sortedKeys(repo.refs)
+}
+
+// newRepository creates an empty repository. If objects is nil, the repository gets a store of
+// its own.
+func (s *Server) newRepository(owner *user, name string, objects *objectStore) *repository {
+	if objects == nil {
+		objects = newObjectStore()
+	}
+	now := s.now()
+	repo := &repository{
+		id:            s.nextID(),
+		owner:         owner,
+		name:          name,
+		defaultBranch: "main",
+		createdAt:     now,
+		updatedAt:     now,
+		pushedAt:      now,
+		objects:       objects,
+		refs:          make(map[string]string),
+		statuses:      make(map[string][]*commitStatus),
+		subscriptions: make(map[string]*subscription),
+	}
+	s.repos[strings.ToLower(repo.fullName())] = repo
+	return repo
+}
+
+func (s *Server) repository(owner, name string) (*repository, bool) {
+	repo, ok := s.repos[strings.ToLower(owner+"/"+name)]
+	return repo, ok
+}
+
+// withRepo looks up the repository that a request is about, from the owner and repo path
+// values, and answers 404 if there is none.
+func (s *Server) withRepo(handler func(http.ResponseWriter, *http.Request, *repository)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		repo, ok := s.repository(r.PathValue("owner"), r.PathValue("repo"))
+		if !ok {
+			notFound(w)
+			return
+		}
+		handler(w, r, repo)
+	}
+}
+
+func (repo *repository) render(urls urls) *github.Repository {
+	visibility := "public"
+	if repo.private {
+		visibility = "private"
+	}
+	rendered := &github.Repository{
+		ID:            github.Ptr(repo.id),
+		NodeID:        github.Ptr(repo.nodeID()),
+		Owner:         repo.owner.render(urls),
+		Name:          github.Ptr(repo.name),
+		FullName:      github.Ptr(repo.fullName()),
+		Description:   github.Ptr(repo.description),
+		Private:       github.Ptr(repo.private),
+		Visibility:    github.Ptr(visibility),
+		Fork:          github.Ptr(repo.parent != nil),
+		DefaultBranch: github.Ptr(repo.defaultBranch),
+		HTMLURL:       github.Ptr(urls.html("%s", repo.fullName())),
+		URL:           github.Ptr(urls.api("repos/%s", repo.fullName())),
+		CloneURL:      github.Ptr(urls.html("%s.git", repo.fullName())),
+		CreatedAt:     &github.Timestamp{Time: repo.createdAt},
+		UpdatedAt:     &github.Timestamp{Time: repo.updatedAt},
+		PushedAt:      &github.Timestamp{Time: repo.pushedAt},
+	}
+	return rendered
+}
+
+// renderFull renders a repository as the API does for a single one, with the repositories it
+// was forked from.
+func (repo *repository) renderFull(urls urls) *github.Repository {
+	rendered := repo.render(urls)
+	if repo.parent != nil {
+		rendered.Parent = repo.parent.render(urls)
+		source := repo.parent
+		for source.parent != nil {
+			source = source.parent
+		}
+		rendered.Source = source.render(urls)
+	}
+	return rendered
+}
+
+func (s *Server) repoRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("GET /repos/{owner}/{repo}", s.withRepo(func(w http.ResponseWriter, r *http.Request, repo *repository) {
+		writeJSON(w, http.StatusOK, repo.renderFull(urlsFor(r)))
+	}))
+
+	mux.HandleFunc("DELETE /repos/{owner}/{repo}", s.withRepo(func(w http.ResponseWriter, r *http.Request, repo *repository) {
+		delete(s.repos, strings.ToLower(repo.fullName()))
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	mux.HandleFunc("POST /user/repos", func(w http.ResponseWriter, r *http.Request) {
+		s.createRepository(w, r, s.caller(r))
+	})
+
+	mux.HandleFunc("POST /orgs/{org}/repos", func(w http.ResponseWriter, r *http.Request) {
+		org, ok := s.users[strings.ToLower(r.PathValue("org"))]
+		if !ok || org.typ != "Organization" {
+			notFound(w)
+			return
+		}
+		s.createRepository(w, r, org)
+	})
+
+	mux.HandleFunc("POST /repos/{owner}/{repo}/forks", s.withRepo(func(w http.ResponseWriter, r *http.Request, repo *repository) {
+		var body struct {
+			Organization string `json:"organization"`
+			Name         string `json:"name"`
+		}
+		if r.ContentLength != 0 && !decodeBody(w, r, &body) {
+			return
+		}
+		owner := s.caller(r)
+		if body.Organization != "" {
+			org, ok := s.users[strings.ToLower(body.Organization)]
+			if !ok || org.typ != "Organization" {
+				writeError(w, http.StatusUnprocessableEntity, "Invalid organization")
+				return
+			}
+			owner = org
+		}
+		if body.Name == "" {
+			body.Name = repo.name
+		}
+
+		// Forking a repository again returns the existing fork, as on GitHub.
+		fork, ok := s.repository(owner.login, body.Name)
+		if !ok {
+			fork = s.newRepository(owner, body.Name, repo.objects)
+			fork.description = repo.description
+			fork.private = repo.private
+			fork.defaultBranch = repo.defaultBranch
+			fork.parent = repo
+			for name, sha := range repo.refs {
+				if strings.HasPrefix(name, "refs/heads/") || strings.HasPrefix(name, "refs/tags/") {
+					fork.refs[name] = sha
+				}
+			}
+		} else if fork.parent != repo {
+			writeError(w, http.StatusUnprocessableEntity, "name already exists on this account")
+			return
+		}
+		writeJSON(w, http.StatusAccepted, fork.renderFull(urlsFor(r)))
+	}))
+
+	mux.HandleFunc("GET /repos/{owner}/{repo}/branches", s.withRepo(func(w http.ResponseWriter, r *http.Request, repo *repository) {
+		branches := []*github.Branch{}
+		for _, name := range repo.refNames() {
+			if branch, ok := strings.CutPrefix(name, "refs/heads/"); ok {
+				branches = append(branches, repo.renderBranch(branch, urlsFor(r)))
+			}
+		}
+		writeJSON(w, http.StatusOK, paginate(w, r, branches))
+	}))
+
+	mux.HandleFunc("GET /repos/{owner}/{repo}/branches/{branch...}", s.withRepo(func(w http.ResponseWriter, r *http.Request, repo *repository) {
+		branch := r.PathValue("branch")
+		if _, ok := repo.refs["refs/heads/"+branch]; !ok {
+			writeError(w, http.StatusNotFound, "Branch not found")
+			return
+		}
+		writeJSON(w, http.StatusOK, repo.renderBranch(branch, urlsFor(r)))
+	}))
+
+	mux.HandleFunc("GET /repos/{owner}/{repo}/tags", s.withRepo(func(w http.ResponseWriter, r *http.Request, repo *repository) {
+		tags := []*github.RepositoryTag{}
+		for _, name := range repo.refNames() {
+			tag, ok := strings.CutPrefix(name, "refs/tags/")
+			if !ok {
+				continue
+			}
+			c, _ := repo.peel(repo.refs[name])
+			tags = append(tags, &github.RepositoryTag{
+				Name: github.Ptr(tag),
+				Commit: &github.Commit{
+					SHA: github.Ptr(c.sha),
+					URL: github.Ptr(urlsFor(r).api("repos/%s/commits/%s", repo.fullName(), c.sha)),
+				},
+				ZipballURL: github.Ptr(urlsFor(r).api("repos/%s/zipball/refs/tags/%s", repo.fullName(), tag)),
+				TarballURL: github.Ptr(urlsFor(r).api("repos/%s/tarball/refs/tags/%s", repo.fullName(), tag)),
+			})
+		}
+		writeJSON(w, http.StatusOK, paginate(w, r, tags))
+	}))
+
+	mux.HandleFunc("GET /repos/{owner}/{repo}/commits", s.withRepo(s.listCommits))
+
+	// The ref of a commit may contain slashes, so the combined status of a commit is served
+	// by the same route as the commit.
+	mux.HandleFunc("GET /repos/{owner}/{repo}/commits/{ref...}", s.withRepo(func(w http.ResponseWriter, r *http.Request, repo *repository) {
+		ref, status := strings.CutSuffix(r.PathValue("ref"), "/status")
+		c, ok := repo.resolve(ref)
+		if !ok {
+			writeError(w, http.StatusUnprocessableEntity, fmt.Sprintf("No commit found for SHA: %s", ref))
+			return
+		}
+		if status {
+			writeJSON(w, http.StatusOK, repo.renderCombinedStatus(c, urlsFor(r)))
+			return
+		}
+		writeJSON(w, http.StatusOK, repo.renderRepositoryCommit(c, true, urlsFor(r)))
+	}))
+
+	mux.HandleFunc("POST /repos/{owner}/{repo}/statuses/{sha}", s.withRepo(func(w http.ResponseWriter, r *http.Request, repo *repository) {
+		var body struct {
+			State       string `json:"state"`
+			TargetURL   string `json:"target_url"`
+			Description string `json:"description"`
+			Context     string `json:"context"`
+		}
+		if !decodeBody(w, r, &body) {
+			return
+		}
+		c, ok := repo.resolve(r.PathValue("sha"))
+		if !ok {
+			writeError(w, http.StatusUnprocessableEntity, "No commit found for SHA: "+r.PathValue("sha"))
+			return
+		}
+		switch body.State {
+		case "error", "failure", "pending", "success":
+		default:
+			writeError(w, http.StatusUnprocessableEntity, "state is not included in the list")
+			return
+		}
+		if body.Context == "" {
+			body.Context = "default"
+		}
+		status := &commitStatus{
+			id:          s.nextID(),
+			state:       body.State,
+			context:     body.Context,
+			description: body.Description,
+			targetURL:   body.TargetURL,
+			creator:     s.caller(r),
+			createdAt:   s.now(),
+		}
+		repo.statuses[c.sha] = append(repo.statuses[c.sha], status)
+		writeJSON(w, http.StatusCreated, status.render(urlsFor(r)))
+	}))
+
+	mux.HandleFunc("GET /repos/{owner}/{repo}/contents/{path...}", s.withRepo(s.getContents))
+	mux.HandleFunc("PUT /repos/{owner}/{repo}/contents/{path...}", s.withRepo(s.putContents))
+	mux.HandleFunc("DELETE /repos/{owner}/{repo}/contents/{path...}", s.withRepo(s.deleteContents))
+}
+
+func (s *Server) createRepository(w http.ResponseWriter, r *http.Request, owner *user) {
+	var body struct {
+		Name        string `json:"name"`
+		Description string `json:"description"`
+		Private     bool   `json:"private"`
+		AutoInit    bool   `json:"auto_init"`
+	}
+	if !decodeBody(w, r, &body) {
+		return
+	}
+	if body.Name == "" {
+		writeError(w, http.StatusUnprocessableEntity, "name is missing")
+		return
+	}
+	if _, ok := s.repository(owner.login, body.Name); ok {
+		writeError(w, http.StatusUnprocessableEntity, "name already exists on this account")
+		return
+	}
+
+	repo := s.newRepository(owner, body.Name, nil)
+	repo.description = body.Description
+	repo.private = body.Private
+	if body.AutoInit {
+		readme := "# " + body.Name + "\n"
+		if body.Description != "" {
+			readme += "\n" + body.Description + "\n"
+		}
+		c := s.commitChanges(repo.objects, nil, map[string]*string{"README.md": &readme}, "Initial commit", s.caller(r))
+		repo.refs["refs/heads/"+repo.defaultBranch] = c.sha
+	}
+	writeJSON(w, http.StatusCreated, repo.renderFull(urlsFor(r)))
+}
+
+func (repo *repository) renderBranch(branch string, urls urls) *github.Branch {
+	c := repo.objects.commits[repo.refs["refs/heads/"+branch]]
+	return &github.Branch{
+		Name:      github.Ptr(branch),
+		Commit:    repo.renderRepositoryCommit(c, false, urls),
+		Protected: github.Ptr(false),
+	}
+}
+
+func (s *Server) listCommits(w http.ResponseWriter, r *http.Request, repo *repository) {
+	query := r.URL.Query()
+	ref := query.Get("sha")
+	if ref == "" {
+		ref = repo.defaultBranch
+	}
+	head, ok := repo.resolve(ref)
+	if !ok {
+		if len(repo.refs) == 0 {
+			writeError(w, http.StatusConflict, "Git Repository is empty.")
+			return
+		}
+		writeError(w, http.StatusNotFound, "No commit found for SHA: "+ref)
+		return
+	}
+
+	var since, until time.Time
+	for name, t := range map[string]*time.Time{"since": &since, "until": &until} {
+		if value := query.Get(name); value != "" {
+			parsed, err := time.Parse(time.RFC3339, value)
+			if err != nil {
+				writeError(w, http.StatusUnprocessableEntity, fmt.Sprintf("Invalid value for %s", name))
+				return
+			}
+			*t = parsed
+		}
+	}
+
+	commits := []*github.RepositoryCommit{}
+	for _, c := range repo.objects.history(head) {
+		if author := query.Get("author"); author != "" && !strings.EqualFold(c.author.login, author) {
+			continue
+		}
+		if !since.IsZero() && c.date.Before(since) || !until.IsZero() && c.date.After(until) {
+			continue
+		}
+		if p := strings.Trim(query.Get("path"), "/"); p != "" && !repo.touches(c, p) {
+			continue
+		}
+		commits = append(commits, repo.renderRepositoryCommit(c, false, urlsFor(r)))
+	}
+	writeJSON(w, http.StatusOK, paginate(w, r, commits))
+}
+
+// touches reports whether a commit changed the file or the files under the directory at p,
+// compared to its first parent.
+func (repo *repository) touches(c *commit, p string) bool {
+	var before map[string]string
+	if len(c.parents) > 0 {
+		before = repo.objects.files(repo.objects.commits[c.parents[0]])
+	}
+	for _, file := range diffFiles(repo.objects, before, repo.objects.files(c)) {
+		if name := file.GetFilename(); name == p || strings.HasPrefix(name, p+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+func (status *commitStatus) render(urls urls) *github.RepoStatus {
+	return &github.RepoStatus{
+		ID:          github.Ptr(status.id),
+		NodeID:      github.Ptr(fmt.Sprintf("SC_%d", status.id)),
+		State:       github.Ptr(status.state),
+		Context:     github.Ptr(status.context),
+		Description: github.Ptr(status.description),
+		TargetURL:   github.Ptr(status.targetURL),
+		Creator:     status.creator.render(urls),
+		CreatedAt:   &github.Timestamp{Time: status.createdAt},
+		UpdatedAt:   &github.Timestamp{Time: status.createdAt},
+	}
+}
+
+// renderCombinedStatus combines the latest status of every context of a commit. It is
+// pending if there are none, like on GitHub.
+func (repo *repository) renderCombinedStatus(c *commit, urls urls) *github.CombinedStatus {
+	latest := map[string]*commitStatus{}
+	for _, status := range repo.statuses[c.sha] {
+		latest[status.context] = status
+	}
+
+	statuses := []*github.RepoStatus{}
+	state := "success"
+	if len(latest) == 0 {
+		state = "pending"
+	}
+	for _, context := range sortedKeys(latest) {
+		status := latest[context]
+		statuses = append(statuses, status.render(urls))
+		switch {
+		case status.state == "error" || status.state == "failure":
+			state = "failure"
+		case status.state == "pending" && state != "failure":
+			state = "pending"
+		}
+	}
+	return &github.CombinedStatus{
+		State:         github.Ptr(state),
+		SHA:           github.Ptr(c.sha),
+		TotalCount:    github.Ptr(len(statuses)),
+		Statuses:      statuses,
+		CommitURL:     github.Ptr(urls.api("repos/%s/commits/%s", repo.fullName(), c.sha)),
+		RepositoryURL: github.Ptr(urls.api("repos/%s", repo.fullName())),
+	}
+}
+
+// contentsRef resolves the ref query parameter of the contents endpoints, which defaults to
+// the default branch.
+func (repo *repository) contentsRef(w http.ResponseWriter, ref string) (*commit, bool) {
+	if ref == "" {
+		ref = repo.defaultBranch
+	}
+	c, ok := repo.resolve(ref)
+	if !ok {
+		if len(repo.refs) == 0 {
+			writeError(w, http.StatusNotFound, "This repository is empty.")
+		} else {
+			writeError(w, http.StatusNotFound, "No commit found for the ref "+ref)
+		}
+	}
+	return c, ok
+}
+
+func (s *Server) getContents(w http.ResponseWriter, r *http.Request, repo *repository) {
+	c, ok := repo.contentsRef(w, r.URL.Query().Get("ref"))
+	if !ok {
+		return
+	}
+	p := strings.Trim(r.PathValue("path"), "/")
+	if _, ok := repo.objects.file(c, p); ok {
+		writeJSON(w, http.StatusOK, repo.renderContent(c, p, true, urlsFor(r)))
+		return
+	}
+	if !repo.objects.isDir(c, p) {
+		notFound(w)
+		return
+	}
+
+	seen := map[string]bool{}
+	entries := []*github.RepositoryContent{}
+	for _, file := range sortedKeys(repo.objects.files(c)) {
+		rest, ok := strings.CutPrefix(file, p+"/")
+		if p == "" {
+			rest, ok = file, true
+		}
+		if !ok {
+			continue
+		}
+		name, _, _ := strings.Cut(rest, "/")
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
+		entries = append(entries, repo.renderContent(c, path.Join(p, name), false, urlsFor(r)))
+	}
+	sort.SliceStable(entries, func(i, j int) bool { return entries[i].GetType() == "dir" && entries[j].GetType() == "file" })
+	writeJSON(w, http.StatusOK, entries)
+}
+
+// renderContent renders the file or directory at p in a commit. The content of files is only
+// included if withContent is true, as the API leaves it out of directory listings.
+func (repo *repository) renderContent(c *commit, p string, withContent bool, urls urls) *github.RepositoryContent {
+	ref := c.sha
+	rendered := &github.RepositoryContent{
+		Name:    github.Ptr(path.Base(p)),
+		Path:    github.Ptr(p),
+		URL:     github.Ptr(urls.api("repos/%s/contents/%s?ref=%s", repo.fullName(), p, ref)),
+		HTMLURL: github.Ptr(urls.html("%s/tree/%s/%s", repo.fullName(), ref, p)),
+	}
+	content, ok := repo.objects.file(c, p)
+	if !ok {
+		rendered.Type = github.Ptr("dir")
+		rendered.SHA = github.Ptr(hash("dir", c.tree, p))
+		rendered.Size = github.Ptr(0)
+		return rendered
+	}
+
+	sha := repo.objects.files(c)[p]
+	rendered.Type = github.Ptr("file")
+	rendered.SHA = github.Ptr(sha)
+	rendered.Size = github.Ptr(len(content))
+	rendered.HTMLURL = github.Ptr(urls.html("%s/blob/%s/%s", repo.fullName(), ref, p))
+	rendered.GitURL = github.Ptr(urls.api("repos/%s/git/blobs/%s", repo.fullName(), sha))
+	rendered.DownloadURL = github.Ptr(urls.base + "/raw/" + repo.fullName() + "/" + ref + "/" + p)
+	if withContent {
+		rendered.Encoding = github.Ptr("base64")
+		rendered.Content = github.Ptr(base64.StdEncoding.EncodeToString([]byte(content)))
+	}
+	return rendered
+}
+
+// changeFile commits a change to a single file on a branch for the contents endpoints, after
+// checking the SHA of the file that the change is based on.
+func (s *Server) changeFile(w http.ResponseWriter, r *http.Request, repo *repository, branch, p, sha, message string, content *string) (*commit, bool) {
+	if branch == "" {
+		branch = repo.defaultBranch
+	}
+	head, ok := repo.branchHead(branch)
+	if !ok && len(repo.refs) > 0 {
+		writeError(w, http.StatusNotFound, "Branch "+branch+" not found")
+		return nil, false
+	}
+
+	var current string
+	if head != nil {
+		current = repo.objects.files(head)[p]
+	}
+	switch {
+	case content == nil && current == "":
+		notFound(w)
+		return nil, false
+	case current != "" && sha == "":
+		writeError(w, http.StatusUnprocessableEntity, "Invalid request.\n\n\"sha\" wasn't supplied.")
+		return nil, false
+	case current != "" && sha != current:
+		writeError(w, http.StatusConflict, fmt.Sprintf("%s does not match %s", p, sha))
+		return nil, false
+	}
+
+	c := s.commitChanges(repo.objects, head, map[string]*string{p: content}, message, s.caller(r))
+	repo.refs["refs/heads/"+branch] = c.sha
+	repo.pushed(s.now())
+	return c, true
+}
+
+func (s *Server) putContents(w http.ResponseWriter, r *http.Request, repo *repository) {
+	var body struct {
+		Message string `json:"message"`
+		Content string `json:"content"`
+		SHA     string `json:"sha"`
+		Branch  string `json:"branch"`
+	}
+	if !decodeBody(w, r, &body) {
+		return
+	}
+	content, err := base64.StdEncoding.DecodeString(body.Content)
+	if err != nil {
+		writeError(w, http.StatusUnprocessableEntity, "content is not valid Base64")
+		return
+	}
+	p := strings.Trim(r.PathValue("path"), "/")
+	text := string(content)
+	c, ok := s.changeFile(w, r, repo, body.Branch, p, body.SHA, body.Message, &text)
+	if !ok {
+		return
+	}
+
+	status := http.StatusOK
+	if body.SHA == "" {
+		status = http.StatusCreated
+	}
+	writeJSON(w, status, &github.RepositoryContentResponse{
+		Content: repo.renderContent(c, p, false, urlsFor(r)),
+		Commit:  *repo.renderCommit(c, urlsFor(r)),
+	})
+}
+
+func (s *Server) deleteContents(w http.ResponseWriter, r *http.Request, repo *repository) {
+	var body struct {
+		Message string `json:"message"`
+		SHA     string `json:"sha"`
+		Branch  string `json:"branch"`
+	}
+	if !decodeBody(w, r, &body) {
+		return
+	}
+	c, ok := s.changeFile(w, r, repo, body.Branch, strings.Trim(r.PathValue("path"), "/"), body.SHA, body.Message, nil)
+	if !ok {
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{
+		"content": nil,
+		"commit":  repo.renderCommit(c, urlsFor(r)),
+	})
+}
+
+// getRaw serves the content of a file, like raw.githubusercontent.com does for the download
+// URLs of files.
+func (s *Server) getRaw(w http.ResponseWriter, r *http.Request) {
+	repo, ok := s.repository(r.PathValue("owner"), r.PathValue("repo"))
+	if !ok {
+		notFound(w)
+		return
+	}
+	c, ok := repo.resolve(r.PathValue("sha"))
+	if !ok {
+		notFound(w)
+		return
+	}
+	content, ok := repo.objects.file(c, r.PathValue("path"))
+	if !ok {
+		notFound(w)
+		return
+	}
+
+	contentType := "application/octet-stream"
+	if isText(content) {
+		contentType = "text/plain; charset=utf-8"
+	}
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Length", strconv.Itoa(len(content)))
+	_, _ = w.Write([]byte(content))
+}
@@ -0,0 +1,225 @@
+package ghfake
+
+import (
+	"net/http"
+	"path"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/go-github/v72/github"
+)
+
+// searchQuery is a parsed search query, made of free text terms and qualifiers such as
+// repo:owner/name or label:"good first issue".
+type searchQuery struct {
+	terms      []string
+	qualifiers map[string][]string
+}
+
+func parseSearchQuery(q string) searchQuery {
+	query := searchQuery{qualifiers: make(map[string][]string)}
+	var tokens []string
+	var token strings.Builder
+	quoted := false
+	for _, r := range q {
+		switch {
+		case r == '"':
+			quoted = !quoted
+		case r == ' ' && !quoted:
+			tokens = append(tokens, token.String())
+			token.Reset()
+		default:
+			token.WriteRune(r)
+		}
+	}
+	tokens = append(tokens, token.String())
+
+	for _, token := range tokens {
+		if token == "" {
+			continue
+		}
+		if key, value, ok := strings.Cut(token, ":"); ok && key != "" && !strings.ContainsAny(key, " ") {
+			key = strings.ToLower(key)
+			query.qualifiers[key] = append(query.qualifiers[key], value)
+			continue
+		}
+		query.terms = append(query.terms, strings.ToLower(token))
+	}
+	return query
+}
+
+// matches reports whether every value of the qualifier satisfies match. A qualifier that
+// isn't in the query always matches.
+func (q searchQuery) matches(qualifier string, match func(value string) bool) bool {
+	for _, value := range q.qualifiers[qualifier] {
+		if !match(value) {
+			return false
+		}
+	}
+	return true
+}
+
+// matchesText reports whether every free text term of the query occurs in one of the texts.
+func (q searchQuery) matchesText(texts ...string) bool {
+	for _, term := range q.terms {
+		if !slices.ContainsFunc(texts, func(text string) bool { return strings.Contains(strings.ToLower(text), term) }) {
+			return false
+		}
+	}
+	return true
+}
+
+// matchesRepository applies the repo, user and org qualifiers.
+func (q searchQuery) matchesRepository(repo *repository) bool {
+	return q.matches("repo", func(value string) bool { return strings.EqualFold(value, repo.fullName()) }) &&
+		q.matches("user", func(value string) bool { return strings.EqualFold(value, repo.owner.login) }) &&
+		q.matches("org", func(value string) bool { return strings.EqualFold(value, repo.owner.login) })
+}
+
+// sortedRepos returns the repositories by full name, so that searches are deterministic.
+func (s *Server) sortedRepos() []*repository {
+	repos := make([]*repository, 0, len(s.repos))
+	for _, key := range sortedKeys(s.repos) {
+		repos = append(repos, s.repos[key])
+	}
+	return repos
+}
+
+func (s *Server) searchRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("GET /search/issues", func(w http.ResponseWriter, r *http.Request) {
+		query := parseSearchQuery(r.URL.Query().Get("q"))
+		var matches []*issue
+		for _, repo := range s.sortedRepos() {
+			if !query.matchesRepository(repo) {
+				continue
+			}
+			for _, i := range repo.issues {
+				if matchesIssue(query, i) {
+					matches = append(matches, i)
+				}
+			}
+		}
+		switch by := r.URL.Query().Get("sort"); by {
+		case "updated", "comments":
+			sortIssues(matches, by, r.URL.Query().Get("order"))
+		default:
+			sortByTime(matches, r.URL.Query().Get("order"), func(i *issue) time.Time { return i.createdAt })
+		}
+
+		issues := make([]*github.Issue, 0, len(matches))
+		for _, i := range matches {
+			issues = append(issues, i.render(urlsFor(r)))
+		}
+		writeJSON(w, http.StatusOK, &github.IssuesSearchResult{
+			Total:             github.Ptr(len(issues)),
+			IncompleteResults: github.Ptr(false),
+			Issues:            paginate(w, r, issues),
+		})
+	})
+
+	mux.HandleFunc("GET /search/repositories", func(w http.ResponseWriter, r *http.Request) {
+		query := parseSearchQuery(r.URL.Query().Get("q"))
+		repos := []*github.Repository{}
+		for _, repo := range s.sortedRepos() {
+			matches := query.matchesRepository(repo) &&
+				query.matchesText(repo.name, repo.description) &&
+				query.matches("is", func(value string) bool {
+					return value == "public" && !repo.private || value == "private" && repo.private
+				}) &&
+				query.matches("fork", func(value string) bool { return value != "false" || repo.parent == nil })
+			if matches {
+				repos = append(repos, repo.render(urlsFor(r)))
+			}
+		}
+		writeJSON(w, http.StatusOK, &github.RepositoriesSearchResult{
+			Total:             github.Ptr(len(repos)),
+			IncompleteResults: github.Ptr(false),
+			Repositories:      paginate(w, r, repos),
+		})
+	})
+
+	// Code is searched on the default branches of repositories, as on GitHub.
+	mux.HandleFunc("GET /search/code", func(w http.ResponseWriter, r *http.Request) {
+		query := parseSearchQuery(r.URL.Query().Get("q"))
+		results := []*github.CodeResult{}
+		for _, repo := range s.sortedRepos() {
+			head, ok := repo.branchHead(repo.defaultBranch)
+			if !ok || !query.matchesRepository(repo) {
+				continue
+			}
+			for _, p := range sortedKeys(repo.objects.files(head)) {
+				content, _ := repo.objects.file(head, p)
+				matches := query.matchesText(content) &&
+					query.matches("path", func(value string) bool { return strings.HasPrefix(p, strings.Trim(value, "/")) }) &&
+					query.matches("filename", func(value string) bool { return path.Base(p) == value }) &&
+					query.matches("extension", func(value string) bool { return path.Ext(p) == "."+value })
+				if !matches {
+					continue
+				}
+				results = append(results, &github.CodeResult{
+					Name:       github.Ptr(path.Base(p)),
+					Path:       github.Ptr(p),
+					SHA:        github.Ptr(repo.objects.files(head)[p]),
+					HTMLURL:    github.Ptr(urlsFor(r).html("%s/blob/%s/%s", repo.fullName(), head.sha, p)),
+					Repository: repo.render(urlsFor(r)),
+				})
+			}
+		}
+		writeJSON(w, http.StatusOK, &github.CodeSearchResult{
+			Total:             github.Ptr(len(results)),
+			IncompleteResults: github.Ptr(false),
+			CodeResults:       paginate(w, r, results),
+		})
+	})
+
+	mux.HandleFunc("GET /search/users", func(w http.ResponseWriter, r *http.Request) {
+		query := parseSearchQuery(r.URL.Query().Get("q"))
+		users := []*github.User{}
+		for _, key := range sortedKeys(s.users) {
+			u := s.users[key]
+			isType := func(value string) bool {
+				return strings.EqualFold(value, u.typ) || strings.EqualFold(value, "org") && u.typ == "Organization"
+			}
+			if query.matchesText(u.login, u.name) && query.matches("type", isType) {
+				users = append(users, u.render(urlsFor(r)))
+			}
+		}
+		writeJSON(w, http.StatusOK, &github.UsersSearchResult{
+			Total:             github.Ptr(len(users)),
+			IncompleteResults: github.Ptr(false),
+			Users:             paginate(w, r, users),
+		})
+	})
+}
+
+func matchesIssue(query searchQuery, i *issue) bool {
+	isPull := i.pull != nil
+	is := func(value string) bool {
+		switch value {
+		case "issue":
+			return !isPull
+		case "pr":
+			return isPull
+		case "open", "closed":
+			return i.state == value
+		case "merged":
+			return isPull && i.pull.merged
+		case "unmerged":
+			return isPull && !i.pull.merged
+		case "draft":
+			return isPull && i.pull.draft
+		}
+		return true
+	}
+	return query.matchesText(i.title, i.body) &&
+		query.matches("is", is) &&
+		query.matches("type", is) &&
+		query.matches("state", func(value string) bool { return i.state == value }) &&
+		query.matches("author", func(value string) bool { return strings.EqualFold(i.author.login, value) }) &&
+		query.matches("assignee", func(value string) bool { return isAssigned(i, value) }) &&
+		query.matches("label", func(value string) bool { return hasLabels(i, []string{value}) }) &&
+		query.matches("review-requested", func(value string) bool {
+			return isPull && slices.ContainsFunc(i.pull.requestedReviewers, func(u *user) bool { return strings.EqualFold(u.login, value) })
+		})
+}
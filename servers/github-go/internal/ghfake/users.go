@@ -0,0 +1,85 @@
+package ghfake
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/google/go-github/v72/github"
+)
+
+// user is a user, bot or organization.
+type user struct {
+	id    int64
+	login string
+	name  string
+	email string
+	// typ is "User", "Bot" or "Organization".
+	typ string
+}
+
+// ensureUser returns the user with the login, and creates it if there is none.
+func (s *Server) ensureUser(login string) *user {
+	if u, ok := s.users[strings.ToLower(login)]; ok {
+		return u
+	}
+	u := &user{id: s.nextID(), login: login, typ: "User"}
+	s.users[strings.ToLower(login)] = u
+	return u
+}
+
+func (s *Server) userByNodeID(id string) *user {
+	for _, u := range s.users {
+		if u.nodeID() == id {
+			return u
+		}
+	}
+	return nil
+}
+
+func (u *user) nodeID() string {
+	if u.typ == "Bot" {
+		return fmt.Sprintf("BOT_%d", u.id)
+	}
+	return fmt.Sprintf("U_%d", u.id)
+}
+
+func (u *user) render(urls urls) *github.User {
+	gu := &github.User{
+		Login:   github.Ptr(u.login),
+		ID:      github.Ptr(u.id),
+		NodeID:  github.Ptr(u.nodeID()),
+		Type:    github.Ptr(u.typ),
+		URL:     github.Ptr(urls.api("users/%s", u.login)),
+		HTMLURL: github.Ptr(urls.html("%s", u.login)),
+	}
+	if u.name != "" {
+		gu.Name = github.Ptr(u.name)
+	}
+	if u.email != "" {
+		gu.Email = github.Ptr(u.email)
+	}
+	return gu
+}
+
+func renderUsers(users []*user, urls urls) []*github.User {
+	rendered := make([]*github.User, 0, len(users))
+	for _, u := range users {
+		rendered = append(rendered, u.render(urls))
+	}
+	return rendered
+}
+
+func (s *Server) userRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("GET /user", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, s.caller(r).render(urlsFor(r)))
+	})
+	mux.HandleFunc("GET /users/{login}", func(w http.ResponseWriter, r *http.Request) {
+		u, ok := s.users[strings.ToLower(r.PathValue("login"))]
+		if !ok {
+			notFound(w)
+			return
+		}
+		writeJSON(w, http.StatusOK, u.render(urlsFor(r)))
+	})
+}
@@ -0,0 +1,135 @@
+package ghmcp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/github/github-mcp-server/pkg/github"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// AccountConfig describes a named GitHub account that tools can act as by passing
+// its name in the "account" argument.
+type AccountConfig struct {
+	// Name is what the account is selected by, e.g. "personal" or "work"
+	Name string
+
+	// Token to authenticate with the GitHub API as this account
+	Token string
+
+	// Host is the GitHub host for this account, defaulting to the server's host if empty
+	Host string
+}
+
+// account is a configured named account together with the clients for its host.
+type account struct {
+	token   string
+	clients *clientCache
+}
+
+// clientResolver decides which clients serve a request: those of the named account
+// selected by the tool call if there is one, otherwise those for the session's token
+// or the server-wide token source. Sessions that bring their own token can't select
+// named accounts.
+type clientResolver struct {
+	tokens   tokenSource
+	clients  *clientCache
+	accounts map[string]*account
+}
+
+func newClientResolver(cfg MCPServerConfig, host apiHost, tokens tokenSource) (*clientResolver, error) {
+	r := &clientResolver{
+		tokens:   tokens,
+		clients:  newClientCache(cfg, github.DefaultAccountName, host),
+		accounts: make(map[string]*account, len(cfg.Accounts)),
+	}
+
+	for _, acct := range cfg.Accounts {
+		if acct.Name == "" || strings.EqualFold(acct.Name, github.DefaultAccountName) {
+			return nil, fmt.Errorf("invalid account name %q", acct.Name)
+		}
+		if _, exists := r.accounts[acct.Name]; exists {
+			return nil, fmt.Errorf("account %s is configured more than once", acct.Name)
+		}
+		if acct.Token == "" {
+			return nil, fmt.Errorf("no token configured for account %s", acct.Name)
+		}
+
+		accountHost := host
+		if acct.Host != "" {
+			var err error
+			accountHost, err = parseAPIHost(acct.Host)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse API host for account %s: %w", acct.Name, err)
+			}
+		}
+
+		r.accounts[acct.Name] = &account{
+			token:   acct.Token,
+			clients: newClientCache(cfg, acct.Name, accountHost),
+		}
+	}
+
+	return r, nil
+}
+
+// accountNames returns the names of the configured accounts in a stable order.
+func (r *clientResolver) accountNames() []string {
+	names := make([]string, 0, len(r.accounts))
+	for name := range r.accounts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (r *clientResolver) resolve(ctx context.Context) (*githubClients, error) {
+	if name, ok := github.AccountFromContext(ctx); ok && name != github.DefaultAccountName {
+		// Named accounts belong to the operator, not to whoever a session's own token belongs to
+		if _, ok := TokenFromContext(ctx); ok {
+			return nil, fmt.Errorf("account %s can't be used by sessions that authenticate with their own token", name)
+		}
+		acct, ok := r.accounts[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown account %q, configured accounts are: %s", name, strings.Join(r.accountNames(), ", "))
+		}
+		return acct.clients.get(acct.token), nil
+	}
+
+	if token, ok := TokenFromContext(ctx); ok {
+		return r.clients.getSession(token), nil
+	}
+
+	// Clients of the app installation outlive its tokens
+	if app, ok := r.tokens.(*appTokenSource); ok {
+		installationID, err := app.installationID(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return r.clients.getInstallation(app, installationID), nil
+	}
+
+	token, err := r.tokens.Token(ctx)
+	if errors.Is(err, errNoToken) && len(r.accounts) > 0 {
+		// The server may only act as named accounts
+		return nil, fmt.Errorf("no token is configured for the default account, select one of the accounts with the account argument: %s", strings.Join(r.accountNames(), ", "))
+	}
+	if err != nil {
+		return nil, err
+	}
+	return r.clients.get(token), nil
+}
+
+// accountMiddleware selects the account named by the "account" argument of a tool call.
+func accountMiddleware(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		if name, ok := request.GetArguments()["account"].(string); ok && name != "" {
+			ctx = github.ContextWithAccount(ctx, name)
+		}
+		return next(ctx, request)
+	}
+}
@@ -0,0 +1,170 @@
+package ghmcp
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/github/github-mcp-server/internal/ghfake"
+	"github.com/github/github-mcp-server/pkg/github"
+	"github.com/github/github-mcp-server/pkg/translations"
+	mcpClient "github.com/mark3labs/mcp-go/client"
+	"github.com/mark3labs/mcp-go/client/transport"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ClientResolver(t *testing.T) {
+	host, err := newDotcomHost()
+	require.NoError(t, err)
+
+	cfg := MCPServerConfig{
+		Version: "test",
+		Accounts: []AccountConfig{
+			{Name: "work", Token: "work-token", Host: "https://github.example.com"},
+			{Name: "personal", Token: "personal-token"},
+		},
+	}
+	resolver, err := newClientResolver(cfg, host, staticTokenSource("default-token"))
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"personal", "work"}, resolver.accountNames())
+
+	ctx := context.Background()
+	defaultClients, err := resolver.resolve(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "https://api.github.com/", defaultClients.rest.BaseURL.String())
+
+	explicitDefault, err := resolver.resolve(github.ContextWithAccount(ctx, github.DefaultAccountName))
+	require.NoError(t, err)
+	assert.Same(t, defaultClients, explicitDefault)
+
+	workClients, err := resolver.resolve(github.ContextWithAccount(ctx, "work"))
+	require.NoError(t, err)
+	assert.Equal(t, "https://github.example.com/api/v3/", workClients.rest.BaseURL.String())
+
+	personalClients, err := resolver.resolve(github.ContextWithAccount(ctx, "personal"))
+	require.NoError(t, err)
+	assert.Equal(t, "https://api.github.com/", personalClients.rest.BaseURL.String())
+	assert.NotSame(t, defaultClients, personalClients)
+
+	_, err = resolver.resolve(github.ContextWithAccount(ctx, "unknown"))
+	require.ErrorContains(t, err, `unknown account "unknown"`)
+}
+
+func Test_ClientResolverWithoutDefaultToken(t *testing.T) {
+	host, err := newDotcomHost()
+	require.NoError(t, err)
+
+	cfg := MCPServerConfig{
+		Version:  "test",
+		Accounts: []AccountConfig{{Name: "work", Token: "work-token"}},
+	}
+	resolver, err := newClientResolver(cfg, host, staticTokenSource(""))
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	_, err = resolver.resolve(ctx)
+	require.EqualError(t, err, "no token is configured for the default account, select one of the accounts with the account argument: work")
+
+	_, err = resolver.resolve(github.ContextWithAccount(ctx, "work"))
+	require.NoError(t, err)
+
+	// A token brought by the session is used for the default account
+	_, err = resolver.resolve(ContextWithToken(ctx, "session-token"))
+	require.NoError(t, err)
+
+	// but doesn't entitle the session to the named accounts
+	_, err = resolver.resolve(github.ContextWithAccount(ContextWithToken(ctx, "session-token"), "work"))
+	require.EqualError(t, err, "account work can't be used by sessions that authenticate with their own token")
+}
+
+func Test_AccountsOverHTTP(t *testing.T) {
+	fake, err := ghfake.New(&ghfake.Fixture{
+		Tokens: map[string]string{"token": "octocat", "work-token": "octo-work", "foreign-token": "mallory"},
+	})
+	require.NoError(t, err)
+	ts := httptest.NewServer(fake)
+	defer ts.Close()
+
+	ghServer, err := NewMCPServer(MCPServerConfig{
+		Token:           "token",
+		Host:            ts.URL,
+		Accounts:        []AccountConfig{{Name: "work", Token: "work-token"}},
+		EnabledToolsets: []string{"repos"},
+		Translator:      translations.NullTranslationHelper,
+	})
+	require.NoError(t, err)
+	mcpServer := httptest.NewServer(withRequestToken(server.NewStreamableHTTPServer(ghServer), true))
+	defer mcpServer.Close()
+
+	client, err := mcpClient.NewStreamableHttpClient(mcpServer.URL, transport.WithHTTPHeaders(map[string]string{
+		"Authorization": "Bearer foreign-token",
+	}))
+	require.NoError(t, err)
+	defer func() { _ = client.Close() }()
+	ctx := context.Background()
+	require.NoError(t, client.Start(ctx))
+	initRequest := mcp.InitializeRequest{}
+	initRequest.Params.ProtocolVersion = "2025-03-26"
+	initRequest.Params.ClientInfo = mcp.Implementation{Name: "accounts-test", Version: "0.0.1"}
+	_, err = client.Initialize(ctx, initRequest)
+	require.NoError(t, err)
+
+	request := mcp.CallToolRequest{}
+	request.Params.Name = "get_me"
+	request.Params.Arguments = map[string]any{"account": "work"}
+	result, err := client.CallTool(ctx, request)
+	require.NoError(t, err)
+	text := result.Content[0].(mcp.TextContent).Text
+	assert.True(t, result.IsError, "a foreign token must not select a named account")
+	assert.Contains(t, text, "account work can't be used by sessions that authenticate with their own token")
+	assert.NotContains(t, text, "octo-work")
+
+	request.Params.Arguments = map[string]any{"account": github.DefaultAccountName}
+	result, err = client.CallTool(ctx, request)
+	require.NoError(t, err)
+	text = result.Content[0].(mcp.TextContent).Text
+	require.False(t, result.IsError, text)
+	assert.Contains(t, text, `"login":"mallory"`, "the default account uses the session's own token")
+}
+
+func Test_ClientResolverRejectsInvalidAccounts(t *testing.T) {
+	host, err := newDotcomHost()
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		accounts []AccountConfig
+	}{
+		{name: "reserved name", accounts: []AccountConfig{{Name: "default", Token: "x"}}},
+		{name: "missing token", accounts: []AccountConfig{{Name: "work"}}},
+		{name: "duplicate name", accounts: []AccountConfig{{Name: "work", Token: "x"}, {Name: "work", Token: "y"}}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := newClientResolver(MCPServerConfig{Accounts: tc.accounts}, host, staticTokenSource(""))
+			require.Error(t, err)
+		})
+	}
+}
+
+func Test_AccountMiddleware(t *testing.T) {
+	var selected string
+	handler := accountMiddleware(func(ctx context.Context, _ mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		selected, _ = github.AccountFromContext(ctx)
+		return nil, nil
+	})
+
+	request := mcp.CallToolRequest{}
+	request.Params.Arguments = map[string]any{"account": "work"}
+	_, _ = handler(context.Background(), request)
+	assert.Equal(t, "work", selected)
+
+	request.Params.Arguments = map[string]any{}
+	_, _ = handler(context.Background(), request)
+	assert.Empty(t, selected)
+}
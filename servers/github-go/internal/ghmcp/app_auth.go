@@ -0,0 +1,351 @@
+package ghmcp
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	gogithub "github.com/google/go-github/v72/github"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+const (
+	// appJWTLifetime is how long the JWTs we sign as the app are valid for. GitHub caps this at 10 minutes.
+	appJWTLifetime = 9 * time.Minute
+
+	// appJWTClockSkew backdates the JWT issue time to allow for clock drift between us and GitHub.
+	appJWTClockSkew = 60 * time.Second
+
+	// installationTokenRefreshMargin is how long before expiry an installation token is replaced.
+	installationTokenRefreshMargin = 5 * time.Minute
+
+	// appFetchTimeout bounds a shared lookup of an installation or a token, which runs on
+	// its own so that the caller who started it can't cancel it for the others.
+	appFetchTimeout = 30 * time.Second
+)
+
+// AppAuthConfig configures authenticating as a GitHub App rather than with a personal access token.
+type AppAuthConfig struct {
+	// AppID is the numeric ID of the GitHub App
+	AppID int64
+
+	// PrivateKey is the PEM encoded private key of the GitHub App
+	PrivateKey []byte
+
+	// InstallationID is used for tool calls that do not have an owner argument. If zero,
+	// the app must have exactly one installation, which is then used instead.
+	InstallationID int64
+}
+
+// tokenSource supplies the token that API requests made for ctx authenticate with.
+type tokenSource interface {
+	Token(ctx context.Context) (string, error)
+}
+
+// staticTokenSource always returns the same token, e.g. a personal access token.
+type staticTokenSource string
+
+func (s staticTokenSource) Token(_ context.Context) (string, error) {
+	if s == "" {
+		return "", errNoToken
+	}
+	return string(s), nil
+}
+
+// installationTokenSource always returns a current token of one app installation, which
+// changes whenever the previous one is about to expire.
+type installationTokenSource struct {
+	app *appTokenSource
+	id  int64
+}
+
+func (s installationTokenSource) Token(ctx context.Context) (string, error) {
+	return s.app.installationToken(ctx, s.id)
+}
+
+type ownerCtxKey struct{}
+
+// contextWithOwner records the repository owner a tool call operates on, so that the
+// matching app installation can be chosen when the GitHub clients are resolved.
+func contextWithOwner(ctx context.Context, owner string) context.Context {
+	return context.WithValue(ctx, ownerCtxKey{}, owner)
+}
+
+func ownerFromContext(ctx context.Context) (string, bool) {
+	owner, ok := ctx.Value(ownerCtxKey{}).(string)
+	return owner, ok && owner != ""
+}
+
+// ownerMiddleware stores the "owner" argument of every tool call in its context.
+func ownerMiddleware(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		if owner, ok := request.GetArguments()["owner"].(string); ok && owner != "" {
+			ctx = contextWithOwner(ctx, owner)
+		}
+		return next(ctx, request)
+	}
+}
+
+// appTokenSource authenticates as a GitHub App. It exchanges a JWT signed with the app's
+// private key for installation access tokens, picks the installation from the owner in
+// the request context, and refreshes tokens shortly before they expire.
+type appTokenSource struct {
+	appID                 int64
+	key                   *rsa.PrivateKey
+	defaultInstallationID int64
+
+	// client is authenticated as the app itself, using a JWT
+	client *gogithub.Client
+
+	// mu guards the maps and the default installation ID, but is not held during requests
+	mu                  sync.Mutex
+	installations       map[string]int64 // lowercased owner login -> installation ID
+	tokens              map[int64]*gogithub.InstallationToken
+	installationFlights map[string]*flight[int64]
+	tokenFlights        map[int64]*flight[*gogithub.InstallationToken]
+
+	now func() time.Time
+}
+
+func newAppTokenSource(cfg AppAuthConfig, version string, host apiHost) (*appTokenSource, error) {
+	key, err := parseRSAPrivateKey(cfg.PrivateKey)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &appTokenSource{
+		appID:                 cfg.AppID,
+		key:                   key,
+		defaultInstallationID: cfg.InstallationID,
+		installations:         make(map[string]int64),
+		tokens:                make(map[int64]*gogithub.InstallationToken),
+		installationFlights:   make(map[string]*flight[int64]),
+		tokenFlights:          make(map[int64]*flight[*gogithub.InstallationToken]),
+		now:                   time.Now,
+	}
+
+	client := gogithub.NewClient(&http.Client{
+		Transport: &appJWTTransport{transport: http.DefaultTransport, source: s},
+	})
+	client.UserAgent = defaultUserAgent(version)
+	client.BaseURL = host.baseRESTURL
+	client.UploadURL = host.uploadURL
+	s.client = client
+
+	return s, nil
+}
+
+// Token returns an installation token for the owner the request operates on, or for the
+// default installation if it does not name one.
+func (s *appTokenSource) Token(ctx context.Context) (string, error) {
+	installationID, err := s.installationID(ctx)
+	if err != nil {
+		return "", err
+	}
+	return s.installationToken(ctx, installationID)
+}
+
+// installationToken returns a token for the installation, creating a new one when the
+// last one is about to expire.
+func (s *appTokenSource) installationToken(ctx context.Context, installationID int64) (string, error) {
+	s.mu.Lock()
+	token, ok := s.tokens[installationID]
+	s.mu.Unlock()
+	if ok && s.now().Add(installationTokenRefreshMargin).Before(token.GetExpiresAt().Time) {
+		return token.GetToken(), nil
+	}
+
+	token, err := fetchOnce(ctx, &s.mu, s.tokenFlights, installationID, func(ctx context.Context) (*gogithub.InstallationToken, error) {
+		token, _, err := s.client.Apps.CreateInstallationToken(ctx, installationID, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create installation token for installation %d: %w", installationID, err)
+		}
+		s.mu.Lock()
+		s.tokens[installationID] = token
+		s.mu.Unlock()
+		return token, nil
+	})
+	if err != nil {
+		return "", err
+	}
+	return token.GetToken(), nil
+}
+
+// installationID resolves which installation a request should use.
+func (s *appTokenSource) installationID(ctx context.Context) (int64, error) {
+	owner, ok := ownerFromContext(ctx)
+	if !ok {
+		return s.defaultInstallation(ctx)
+	}
+
+	key := strings.ToLower(owner)
+	s.mu.Lock()
+	id, ok := s.installations[key]
+	s.mu.Unlock()
+	if ok {
+		return id, nil
+	}
+
+	return fetchOnce(ctx, &s.mu, s.installationFlights, key, func(ctx context.Context) (int64, error) {
+		// Owners can be organizations or users, and each has their own lookup endpoint.
+		installation, _, err := s.client.Apps.FindOrganizationInstallation(ctx, owner)
+		if err != nil {
+			var errResp *gogithub.ErrorResponse
+			if !errors.As(err, &errResp) || errResp.Response.StatusCode != http.StatusNotFound {
+				return 0, fmt.Errorf("failed to find app installation for %s: %w", owner, err)
+			}
+			installation, _, err = s.client.Apps.FindUserInstallation(ctx, owner)
+			if err != nil {
+				return 0, fmt.Errorf("the GitHub App is not installed for %s: %w", owner, err)
+			}
+		}
+
+		s.mu.Lock()
+		s.installations[key] = installation.GetID()
+		s.mu.Unlock()
+		return installation.GetID(), nil
+	})
+}
+
+func (s *appTokenSource) defaultInstallation(ctx context.Context) (int64, error) {
+	s.mu.Lock()
+	id := s.defaultInstallationID
+	s.mu.Unlock()
+	if id != 0 {
+		return id, nil
+	}
+
+	// No owner login is empty, so the default installation is looked up under ""
+	return fetchOnce(ctx, &s.mu, s.installationFlights, "", func(ctx context.Context) (int64, error) {
+		installations, _, err := s.client.Apps.ListInstallations(ctx, &gogithub.ListOptions{PerPage: 2})
+		if err != nil {
+			return 0, fmt.Errorf("failed to list app installations: %w", err)
+		}
+		if len(installations) != 1 {
+			return 0, fmt.Errorf("the GitHub App has %d installations, so an installation ID must be configured for tools without an owner argument", len(installations))
+		}
+
+		s.mu.Lock()
+		s.defaultInstallationID = installations[0].GetID()
+		s.mu.Unlock()
+		return installations[0].GetID(), nil
+	})
+}
+
+// flight is a lookup in progress, whose result concurrent callers for the same key share.
+type flight[T any] struct {
+	done  chan struct{}
+	value T
+	err   error
+}
+
+// fetchOnce runs fetch for key, unless a fetch for the same key is already running, in which
+// case it waits for that one's result instead. mu guards flights, and is not held while
+// fetching, so that a slow request doesn't hold up requests for other keys. The fetch runs
+// on a context that keeps the values of ctx but not its cancellation, so that callers who
+// give up, including the one who started it, don't fail it for the others.
+func fetchOnce[K comparable, T any](ctx context.Context, mu *sync.Mutex, flights map[K]*flight[T], key K, fetch func(ctx context.Context) (T, error)) (T, error) {
+	mu.Lock()
+	f, running := flights[key]
+	if !running {
+		f = &flight[T]{done: make(chan struct{})}
+		flights[key] = f
+		go func() {
+			fetchCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), appFetchTimeout)
+			defer cancel()
+			f.value, f.err = fetch(fetchCtx)
+
+			mu.Lock()
+			delete(flights, key)
+			mu.Unlock()
+			close(f.done)
+		}()
+	}
+	mu.Unlock()
+
+	select {
+	case <-f.done:
+		return f.value, f.err
+	case <-ctx.Done():
+		var zero T
+		return zero, ctx.Err()
+	}
+}
+
+// signJWT creates the short-lived JWT that authenticates requests as the app itself.
+// See: https://docs.github.com/en/apps/creating-github-apps/authenticating-with-a-github-app/generating-a-json-web-token-jwt-for-a-github-app
+func (s *appTokenSource) signJWT() (string, error) {
+	now := s.now()
+	header, err := json.Marshal(map[string]string{"alg": "RS256", "typ": "JWT"})
+	if err != nil {
+		return "", err
+	}
+	claims, err := json.Marshal(map[string]any{
+		"iat": now.Add(-appJWTClockSkew).Unix(),
+		"exp": now.Add(appJWTLifetime).Unix(),
+		"iss": fmt.Sprintf("%d", s.appID),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	unsigned := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(claims)
+	digest := sha256.Sum256([]byte(unsigned))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, s.key, crypto.SHA256, digest[:])
+	if err != nil {
+		return "", fmt.Errorf("failed to sign app JWT: %w", err)
+	}
+
+	return unsigned + "." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
+
+// appJWTTransport authenticates requests as the GitHub App with a freshly signed JWT.
+type appJWTTransport struct {
+	transport http.RoundTripper
+	source    *appTokenSource
+}
+
+func (t *appJWTTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	jwt, err := t.source.signJWT()
+	if err != nil {
+		return nil, err
+	}
+	req = req.Clone(req.Context())
+	req.Header.Set("Authorization", "Bearer "+jwt)
+	return t.transport.RoundTrip(req)
+}
+
+// parseRSAPrivateKey accepts both PKCS#1 keys, as downloaded from GitHub, and PKCS#8 keys.
+func parseRSAPrivateKey(data []byte) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("failed to decode GitHub App private key: no PEM data found")
+	}
+
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse GitHub App private key: %w", err)
+	}
+	key, ok := parsed.(*rsa.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("GitHub App private key must be an RSA key, got %T", parsed)
+	}
+	return key, nil
+}
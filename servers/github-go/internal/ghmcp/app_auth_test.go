@@ -0,0 +1,243 @@
+package ghmcp
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func generateTestKey(t *testing.T) (*rsa.PrivateKey, []byte) {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	return key, pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+}
+
+func Test_ParseRSAPrivateKey(t *testing.T) {
+	key, pkcs1 := generateTestKey(t)
+
+	parsed, err := parseRSAPrivateKey(pkcs1)
+	require.NoError(t, err)
+	assert.True(t, key.Equal(parsed))
+
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	require.NoError(t, err)
+	parsed, err = parseRSAPrivateKey(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}))
+	require.NoError(t, err)
+	assert.True(t, key.Equal(parsed))
+
+	_, err = parseRSAPrivateKey([]byte("not a key"))
+	require.Error(t, err)
+}
+
+func Test_AppTokenSource(t *testing.T) {
+	key, keyPEM := generateTestKey(t)
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	var tokensIssued int
+	mux := http.NewServeMux()
+	mux.HandleFunc("/orgs/octo-org/installation", func(w http.ResponseWriter, r *http.Request) {
+		verifyAppJWT(t, r, &key.PublicKey)
+		_, _ = w.Write([]byte(`{"id": 1}`))
+	})
+	mux.HandleFunc("/orgs/octocat/installation", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"message": "Not Found"}`))
+	})
+	mux.HandleFunc("/users/octocat/installation", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`{"id": 2}`))
+	})
+	mux.HandleFunc("/app/installations", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`[{"id": 3}]`))
+	})
+	mux.HandleFunc("/app/installations/{id}/access_tokens", func(w http.ResponseWriter, r *http.Request) {
+		verifyAppJWT(t, r, &key.PublicKey)
+		tokensIssued++
+		_, _ = fmt.Fprintf(w, `{"token": "installation-%s-%d", "expires_at": %q}`,
+			r.PathValue("id"), tokensIssued, now.Add(time.Hour).Format(time.RFC3339))
+	})
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+
+	baseURL, err := url.Parse(ts.URL + "/")
+	require.NoError(t, err)
+
+	source, err := newAppTokenSource(AppAuthConfig{AppID: 1234, PrivateKey: keyPEM}, "test", apiHost{baseRESTURL: baseURL, uploadURL: baseURL})
+	require.NoError(t, err)
+	source.now = func() time.Time { return now }
+
+	ctx := context.Background()
+
+	// Organization owner
+	token, err := source.Token(contextWithOwner(ctx, "octo-org"))
+	require.NoError(t, err)
+	assert.Equal(t, "installation-1-1", token)
+
+	// Tokens are reused until they are about to expire
+	token, err = source.Token(contextWithOwner(ctx, "Octo-Org"))
+	require.NoError(t, err)
+	assert.Equal(t, "installation-1-1", token)
+
+	// User owners fall back to the user installation endpoint
+	token, err = source.Token(contextWithOwner(ctx, "octocat"))
+	require.NoError(t, err)
+	assert.Equal(t, "installation-2-2", token)
+
+	// Without an owner, the only installation is used
+	token, err = source.Token(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "installation-3-3", token)
+
+	// Close to expiry, the token is refreshed
+	source.now = func() time.Time { return now.Add(time.Hour - installationTokenRefreshMargin) }
+	token, err = source.Token(contextWithOwner(ctx, "octo-org"))
+	require.NoError(t, err)
+	assert.Equal(t, "installation-1-4", token)
+}
+
+func Test_AppTokenSourceConcurrentLookups(t *testing.T) {
+	_, keyPEM := generateTestKey(t)
+
+	var slowLookups, tokensIssued atomic.Int32
+	slowLookupStarted := make(chan struct{})
+	releaseSlowLookup := make(chan struct{})
+	mux := http.NewServeMux()
+	mux.HandleFunc("/orgs/slow-org/installation", func(w http.ResponseWriter, _ *http.Request) {
+		if slowLookups.Add(1) == 1 {
+			close(slowLookupStarted)
+		}
+		<-releaseSlowLookup
+		_, _ = w.Write([]byte(`{"id": 1}`))
+	})
+	mux.HandleFunc("/orgs/octo-org/installation", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`{"id": 2}`))
+	})
+	mux.HandleFunc("/app/installations/{id}/access_tokens", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprintf(w, `{"token": "installation-%s-%d", "expires_at": %q}`,
+			r.PathValue("id"), tokensIssued.Add(1), time.Now().Add(time.Hour).Format(time.RFC3339))
+	})
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+
+	baseURL, err := url.Parse(ts.URL + "/")
+	require.NoError(t, err)
+	source, err := newAppTokenSource(AppAuthConfig{AppID: 1234, PrivateKey: keyPEM}, "test", apiHost{baseRESTURL: baseURL, uploadURL: baseURL})
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	const callers = 5
+	tokens := make(chan string, callers)
+	for range callers {
+		go func() {
+			token, err := source.Token(contextWithOwner(ctx, "slow-org"))
+			assert.NoError(t, err)
+			tokens <- token
+		}()
+	}
+	<-slowLookupStarted
+
+	// Other owners are served while the lookup for slow-org is stuck
+	token, err := source.Token(contextWithOwner(ctx, "octo-org"))
+	require.NoError(t, err)
+	assert.Equal(t, "installation-2-1", token)
+
+	close(releaseSlowLookup)
+	for range callers {
+		assert.Equal(t, "installation-1-2", <-tokens, "concurrent callers share one token")
+	}
+	assert.Equal(t, int32(1), slowLookups.Load(), "concurrent callers share one lookup")
+}
+
+func Test_AppTokenSourceCanceledCaller(t *testing.T) {
+	_, keyPEM := generateTestKey(t)
+
+	var tokensIssued atomic.Int32
+	var requestCanceled atomic.Bool
+	tokenRequested := make(chan struct{}, 1)
+	releaseToken := make(chan struct{})
+	mux := http.NewServeMux()
+	mux.HandleFunc("/orgs/octo-org/installation", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`{"id": 1}`))
+	})
+	mux.HandleFunc("/app/installations/{id}/access_tokens", func(w http.ResponseWriter, r *http.Request) {
+		tokenRequested <- struct{}{}
+		select {
+		case <-releaseToken:
+		case <-r.Context().Done():
+			requestCanceled.Store(true)
+			return
+		}
+		_, _ = fmt.Fprintf(w, `{"token": "installation-%s-%d", "expires_at": %q}`,
+			r.PathValue("id"), tokensIssued.Add(1), time.Now().Add(time.Hour).Format(time.RFC3339))
+	})
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+
+	baseURL, err := url.Parse(ts.URL + "/")
+	require.NoError(t, err)
+	source, err := newAppTokenSource(AppAuthConfig{AppID: 1234, PrivateKey: keyPEM}, "test", apiHost{baseRESTURL: baseURL, uploadURL: baseURL})
+	require.NoError(t, err)
+
+	// The first caller gives up while the token it asked for is being created
+	firstCtx, cancel := context.WithCancel(contextWithOwner(context.Background(), "octo-org"))
+	firstErr := make(chan error, 1)
+	go func() {
+		_, err := source.Token(firstCtx)
+		firstErr <- err
+	}()
+	<-tokenRequested
+	cancel()
+	require.ErrorIs(t, <-firstErr, context.Canceled)
+
+	// A caller that is still waiting gets the token
+	secondToken := make(chan string, 1)
+	go func() {
+		token, err := source.Token(contextWithOwner(context.Background(), "octo-org"))
+		assert.NoError(t, err)
+		secondToken <- token
+	}()
+	close(releaseToken)
+	assert.Equal(t, "installation-1-1", <-secondToken)
+	assert.False(t, requestCanceled.Load(), "the token request is not canceled with the first caller")
+	assert.Equal(t, int32(1), tokensIssued.Load())
+}
+
+// verifyAppJWT checks that the request is authenticated with a JWT signed by the app's key.
+func verifyAppJWT(t *testing.T, r *http.Request, key *rsa.PublicKey) {
+	t.Helper()
+	jwt, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	require.True(t, ok, "expected a bearer token")
+
+	parts := strings.Split(jwt, ".")
+	require.Len(t, parts, 3)
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	require.NoError(t, err)
+	digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	require.NoError(t, rsa.VerifyPKCS1v15(key, crypto.SHA256, digest[:], signature))
+
+	claimsJSON, err := base64.RawURLEncoding.DecodeString(parts[1])
+	require.NoError(t, err)
+	var claims struct {
+		Issuer string `json:"iss"`
+	}
+	require.NoError(t, json.Unmarshal(claimsJSON, &claims))
+	assert.Equal(t, "1234", claims.Issuer)
+}
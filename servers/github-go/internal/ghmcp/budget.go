@@ -0,0 +1,387 @@
+package ghmcp
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/github/github-mcp-server/pkg/toolsets"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+const (
+	// DefaultResponseBudgetTTL is how long the remaining parts of a split result are kept by default.
+	DefaultResponseBudgetTTL = 10 * time.Minute
+
+	// minResponseBudget is the smallest budget that still leaves room for useful parts.
+	minResponseBudget = 1024
+
+	// maxSplitResults bounds the number of split results kept at a time. The ones that
+	// expire first are dropped when there are more.
+	maxSplitResults = 256
+
+	continueResultTool = "continue_result"
+)
+
+var errUnknownContinuation = errors.New("continuation token is unknown or has expired, call the original tool again")
+
+// ResponseBudgetConfig limits the size of tool results.
+type ResponseBudgetConfig struct {
+	// MaxBytes is the size of the largest text result returned in one piece. Larger results
+	// are split into parts that are fetched one at a time with the continue_result tool
+	MaxBytes int
+
+	// TTL is how long the remaining parts of a split result can be fetched
+	TTL time.Duration
+}
+
+// responseBudget splits tool results that exceed the budget, and keeps the parts that
+// haven't been returned yet for continue_result.
+type responseBudget struct {
+	maxBytes int
+	ttl      time.Duration
+	now      func() time.Time
+
+	mu      sync.Mutex
+	results map[string]*splitResult
+}
+
+// splitResult holds the parts of a tool result for the session that made the call.
+type splitResult struct {
+	sessionID string
+	parts     []string
+	expires   time.Time
+}
+
+func newResponseBudget(cfg ResponseBudgetConfig) (*responseBudget, error) {
+	if cfg.MaxBytes < minResponseBudget {
+		return nil, fmt.Errorf("the response budget must be at least %d bytes", minResponseBudget)
+	}
+	ttl := cfg.TTL
+	if ttl <= 0 {
+		ttl = DefaultResponseBudgetTTL
+	}
+	return &responseBudget{
+		maxBytes: cfg.MaxBytes,
+		ttl:      ttl,
+		now:      time.Now,
+		results:  make(map[string]*splitResult),
+	}, nil
+}
+
+// middleware splits successful results that consist of a single text larger than the budget,
+// and returns their first part along with a continuation token for the next one.
+func (b *responseBudget) middleware(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		result, err := next(ctx, request)
+		if err != nil || result == nil || result.IsError || request.Params.Name == continueResultTool {
+			return result, err
+		}
+		if len(result.Content) != 1 {
+			return result, nil
+		}
+		text, ok := result.Content[0].(mcp.TextContent)
+		if !ok || len(text.Text) <= b.maxBytes {
+			return result, nil
+		}
+
+		parts := splitText(text.Text, b.maxBytes)
+		if len(parts) < 2 {
+			return result, nil
+		}
+		id, err := b.store(ctx, parts)
+		if err != nil {
+			return nil, err
+		}
+		return partResult(id, parts, 0), nil
+	}
+}
+
+// store keeps the parts of a split result and returns the ID they can be fetched with.
+func (b *responseBudget) store(ctx context.Context, parts []string) (string, error) {
+	random := make([]byte, 16)
+	if _, err := rand.Read(random); err != nil {
+		return "", fmt.Errorf("failed to generate continuation token: %w", err)
+	}
+	id := hex.EncodeToString(random)
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := b.now()
+	for key, result := range b.results {
+		if !now.Before(result.expires) {
+			delete(b.results, key)
+		}
+	}
+	for len(b.results) >= maxSplitResults {
+		var oldest string
+		for key, result := range b.results {
+			if oldest == "" || result.expires.Before(b.results[oldest].expires) {
+				oldest = key
+			}
+		}
+		delete(b.results, oldest)
+	}
+
+	b.results[id] = &splitResult{
+		sessionID: toolsets.SessionIDFromContext(ctx),
+		parts:     parts,
+		expires:   now.Add(b.ttl),
+	}
+	return id, nil
+}
+
+// chunk returns the part of a split result that token refers to. Tokens can only be used
+// by the session that made the original call.
+func (b *responseBudget) chunk(ctx context.Context, token string) (*mcp.CallToolResult, error) {
+	id, index, ok := strings.Cut(token, ".")
+	if !ok {
+		return nil, errUnknownContinuation
+	}
+	i, err := strconv.Atoi(index)
+	if err != nil {
+		return nil, errUnknownContinuation
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	result, ok := b.results[id]
+	if !ok || !b.now().Before(result.expires) || result.sessionID != toolsets.SessionIDFromContext(ctx) || i < 1 || i >= len(result.parts) {
+		return nil, errUnknownContinuation
+	}
+	return partResult(id, result.parts, i), nil
+}
+
+// partResult returns part i of a split result, followed by a note on how to get the next part.
+func partResult(id string, parts []string, i int) *mcp.CallToolResult {
+	result := mcp.NewToolResultText(parts[i])
+	note := fmt.Sprintf("This is the last part, %d of %d, of a result that was too large to return at once.", i+1, len(parts))
+	if i+1 < len(parts) {
+		note = fmt.Sprintf("This is part %d of %d of a result that was too large to return at once. "+
+			"Call continue_result with the token %q to get the next part.", i+1, len(parts), id+"."+strconv.Itoa(i+1))
+	}
+	result.Content = append(result.Content, mcp.NewTextContent(note))
+	return result
+}
+
+// splitText splits a tool result into parts of at most maxBytes at safe boundaries: files
+// from get_file_contents into objects holding a part of the content each, JSON arrays
+// between items, so that every part is an array of its own, diffs between files, and any
+// other text between lines. Items, files or lines that are larger than the budget on their
+// own are split further, into fragments of text that are only valid once joined.
+func splitText(text string, maxBytes int) []string {
+	if parts, ok := fileContentParts(text, maxBytes); ok {
+		return parts
+	}
+	if items, ok := jsonArrayItems(text); ok {
+		return packPieces(items, maxBytes, "[", ",", "]")
+	}
+	if strings.HasPrefix(text, "diff --git ") {
+		return packPieces(splitLines(text, "diff --git "), maxBytes, "", "", "")
+	}
+	return packPieces(splitLines(text, ""), maxBytes, "", "", "")
+}
+
+// fileContentParts splits a file as returned by get_file_contents, a JSON object with the
+// base64 encoded content of the file, into copies of the object that each hold a part of
+// the content. Text is decoded, with the encoding set to "utf-8", and split between lines.
+// Binary content stays base64 encoded, split so that every part decodes on its own.
+func fileContentParts(text string, maxBytes int) ([]string, bool) {
+	if !strings.HasPrefix(text, "{") {
+		return nil, false
+	}
+	var file map[string]any
+	if err := json.Unmarshal([]byte(text), &file); err != nil {
+		return nil, false
+	}
+	content, ok := file["content"].(string)
+	if !ok || file["type"] != "file" || file["encoding"] != "base64" {
+		return nil, false
+	}
+	decoded, err := base64.StdEncoding.DecodeString(strings.ReplaceAll(content, "\n", ""))
+	if err != nil {
+		return nil, false
+	}
+
+	isText := utf8.Valid(decoded)
+	if isText {
+		file["encoding"] = "utf-8"
+	}
+	file["content"] = ""
+	empty, err := json.Marshal(file)
+	if err != nil {
+		return nil, false
+	}
+	// Leave files whose other fields take up nearly all of the budget to the other splits
+	room := maxBytes - len(empty)
+	if room < minResponseBudget/4 {
+		return nil, false
+	}
+
+	var pieces []string
+	if isText {
+		pieces = packEscaped(splitLines(string(decoded), ""), room)
+	} else {
+		// Every 3 bytes take 4 characters, none of which are escaped
+		size := room / 4 * 3
+		for len(decoded) > 0 {
+			n := min(size, len(decoded))
+			pieces = append(pieces, base64.StdEncoding.EncodeToString(decoded[:n]))
+			decoded = decoded[n:]
+		}
+	}
+
+	parts := make([]string, 0, len(pieces))
+	for _, piece := range pieces {
+		file["content"] = piece
+		part, err := json.Marshal(file)
+		if err != nil {
+			return nil, false
+		}
+		parts = append(parts, string(part))
+	}
+	return parts, true
+}
+
+// packEscaped joins consecutive lines into pieces that take at most room bytes once encoded
+// as a JSON string, and splits lines that don't fit on their own between characters.
+func packEscaped(lines []string, room int) []string {
+	var (
+		pieces  []string
+		current strings.Builder
+		size    int
+	)
+	add := func(s string, n int) {
+		if current.Len() > 0 && size+n > room {
+			pieces = append(pieces, current.String())
+			current.Reset()
+			size = 0
+		}
+		current.WriteString(s)
+		size += n
+	}
+
+	for _, line := range lines {
+		if n := escapedLen(line); n <= room {
+			add(line, n)
+			continue
+		}
+		for _, r := range line {
+			add(string(r), escapedLen(string(r)))
+		}
+	}
+	if current.Len() > 0 {
+		pieces = append(pieces, current.String())
+	}
+	return pieces
+}
+
+// escapedLen returns the length of s encoded as a JSON string, without the quotes.
+func escapedLen(s string) int {
+	encoded, _ := json.Marshal(s)
+	return len(encoded) - 2
+}
+
+// jsonArrayItems returns the items of text if it is a JSON array.
+func jsonArrayItems(text string) ([]string, bool) {
+	if !strings.HasPrefix(strings.TrimSpace(text), "[") {
+		return nil, false
+	}
+	var raw []json.RawMessage
+	if err := json.Unmarshal([]byte(text), &raw); err != nil {
+		return nil, false
+	}
+	items := make([]string, len(raw))
+	for i, item := range raw {
+		items[i] = string(item)
+	}
+	return items, true
+}
+
+// splitLines splits text after every line break that is followed by prefix, or after every
+// line break if prefix is empty. Pieces keep their line break.
+func splitLines(text, prefix string) []string {
+	var pieces []string
+	start := 0
+	for i := 0; i < len(text)-1; i++ {
+		if text[i] == '\n' && strings.HasPrefix(text[i+1:], prefix) {
+			pieces = append(pieces, text[start:i+1])
+			start = i + 1
+		}
+	}
+	if start < len(text) {
+		pieces = append(pieces, text[start:])
+	}
+	return pieces
+}
+
+// packPieces joins consecutive pieces into parts of at most maxBytes, each wrapped in
+// open and closing, with sep between pieces.
+func packPieces(pieces []string, maxBytes int, open, sep, closing string) []string {
+	var (
+		parts   []string
+		current []string
+		size    int
+	)
+	overhead := len(open) + len(closing)
+	flush := func() {
+		if len(current) > 0 {
+			parts = append(parts, open+strings.Join(current, sep)+closing)
+			current, size = nil, 0
+		}
+	}
+
+	for _, piece := range pieces {
+		if overhead+len(piece) > maxBytes {
+			flush()
+			parts = append(parts, splitOversized(piece, maxBytes)...)
+			continue
+		}
+		added := len(piece)
+		if len(current) > 0 {
+			added += len(sep)
+		}
+		if overhead+size+added > maxBytes {
+			flush()
+			added = len(piece)
+		}
+		current = append(current, piece)
+		size += added
+	}
+	flush()
+	return parts
+}
+
+// splitOversized splits a piece that doesn't fit the budget on its own between lines if it
+// has several, and otherwise between characters.
+func splitOversized(piece string, maxBytes int) []string {
+	if lines := splitLines(piece, ""); len(lines) > 1 {
+		return packPieces(lines, maxBytes, "", "", "")
+	}
+
+	var parts []string
+	for len(piece) > maxBytes {
+		cut := maxBytes
+		for cut > 0 && !utf8.RuneStart(piece[cut]) {
+			cut--
+		}
+		parts = append(parts, piece[:cut])
+		piece = piece[cut:]
+	}
+	if piece != "" {
+		parts = append(parts, piece)
+	}
+	return parts
+}
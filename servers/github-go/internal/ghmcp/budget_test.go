@@ -0,0 +1,246 @@
+package ghmcp
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/github/github-mcp-server/internal/ghfake"
+	"github.com/github/github-mcp-server/pkg/github"
+	"github.com/github/github-mcp-server/pkg/translations"
+	gogithub "github.com/google/go-github/v72/github"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_SplitText(t *testing.T) {
+	diffFile := func(name string) string {
+		return fmt.Sprintf("diff --git a/%[1]s b/%[1]s\n--- a/%[1]s\n+++ b/%[1]s\n@@ -1 +1 @@\n-old\n+new\n", name)
+	}
+
+	tests := []struct {
+		name     string
+		text     string
+		maxBytes int
+		expected []string
+	}{
+		{
+			name:     "JSON array between items",
+			text:     `[{"sha":"aaaa"},{"sha":"bbbb"},{"sha":"cccc"}]`,
+			maxBytes: 35,
+			expected: []string{`[{"sha":"aaaa"},{"sha":"bbbb"}]`, `[{"sha":"cccc"}]`},
+		},
+		{
+			name:     "JSON array with an oversized item",
+			text:     `[{"sha":"aaaa"},{"sha":"bbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb"},{"sha":"cccc"}]`,
+			maxBytes: 35,
+			expected: []string{`[{"sha":"aaaa"}]`, `{"sha":"bbbbbbbbbbbbbbbbbbbbbbbbbbb`, `bbbbbbbbbbbbb"}`, `[{"sha":"cccc"}]`},
+		},
+		{
+			name:     "diff between files",
+			text:     diffFile("a.go") + diffFile("b.go") + diffFile("c.go"),
+			maxBytes: len(diffFile("a.go")) * 2,
+			expected: []string{diffFile("a.go") + diffFile("b.go"), diffFile("c.go")},
+		},
+		{
+			name:     "text between lines",
+			text:     "first line\nsecond line\nthird line\n",
+			maxBytes: 24,
+			expected: []string{"first line\nsecond line\n", "third line\n"},
+		},
+		{
+			name:     "oversized line between characters",
+			text:     "short\n" + strings.Repeat("é", 5),
+			maxBytes: 6,
+			expected: []string{"short\n", "ééé", "éé"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			parts := splitText(tc.text, tc.maxBytes)
+			assert.Equal(t, tc.expected, parts)
+			for _, part := range parts {
+				assert.LessOrEqual(t, len(part), tc.maxBytes)
+			}
+		})
+	}
+}
+
+// sessionWithID is a client session that only has an ID.
+type sessionWithID string
+
+func (s sessionWithID) SessionID() string                                   { return string(s) }
+func (s sessionWithID) NotificationChannel() chan<- mcp.JSONRPCNotification { return nil }
+func (s sessionWithID) Initialize()                                         {}
+func (s sessionWithID) Initialized() bool                                   { return true }
+
+func Test_ResponseBudget(t *testing.T) {
+	budget, err := newResponseBudget(ResponseBudgetConfig{MaxBytes: 1024, TTL: time.Minute})
+	require.NoError(t, err)
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	budget.now = func() time.Time { return now }
+
+	commits := make([]map[string]string, 100)
+	for i := range commits {
+		commits[i] = map[string]string{"sha": fmt.Sprintf("%040d", i), "message": "Fix the build"}
+	}
+	data, err := json.Marshal(commits)
+	require.NoError(t, err)
+
+	handler := budget.middleware(func(_ context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		if request.Params.Name == "get_me" {
+			return mcp.NewToolResultText(`{"login":"octocat"}`), nil
+		}
+		return mcp.NewToolResultText(string(data)), nil
+	})
+
+	mcpServer := server.NewMCPServer("test", "1.0.0")
+	ctx := mcpServer.WithContext(context.Background(), sessionWithID("session-1"))
+	call := func(name string) *mcp.CallToolResult {
+		request := mcp.CallToolRequest{}
+		request.Params.Name = name
+		result, err := handler(ctx, request)
+		require.NoError(t, err)
+		return result
+	}
+
+	small := call("get_me")
+	require.Len(t, small.Content, 1, "small results are returned as they are")
+
+	tokenPattern := regexp.MustCompile(`continue_result with the token "([^"]+)"`)
+	var (
+		collected []map[string]string
+		result    = call("list_commits")
+		tokens    []string
+	)
+	for {
+		require.Len(t, result.Content, 2)
+		text := result.Content[0].(mcp.TextContent).Text
+		assert.LessOrEqual(t, len(text), 1024)
+
+		var part []map[string]string
+		require.NoError(t, json.Unmarshal([]byte(text), &part), "every part is a JSON array")
+		collected = append(collected, part...)
+
+		match := tokenPattern.FindStringSubmatch(result.Content[1].(mcp.TextContent).Text)
+		if match == nil {
+			assert.Contains(t, result.Content[1].(mcp.TextContent).Text, "This is the last part")
+			break
+		}
+		tokens = append(tokens, match[1])
+		result, err = budget.chunk(ctx, match[1])
+		require.NoError(t, err)
+	}
+	assert.Equal(t, commits, collected)
+	require.NotEmpty(t, tokens)
+
+	// Parts can be fetched again, but only by the same session and until they expire
+	_, err = budget.chunk(ctx, tokens[0])
+	require.NoError(t, err)
+
+	otherSession := mcpServer.WithContext(context.Background(), sessionWithID("session-2"))
+	_, err = budget.chunk(otherSession, tokens[0])
+	assert.ErrorIs(t, err, errUnknownContinuation)
+
+	for _, token := range []string{"", "unknown.1", tokens[0] + "0", strings.Split(tokens[0], ".")[0] + ".0"} {
+		_, err = budget.chunk(ctx, token)
+		assert.ErrorIs(t, err, errUnknownContinuation, "token %q", token)
+	}
+
+	now = now.Add(time.Minute)
+	_, err = budget.chunk(ctx, tokens[0])
+	assert.ErrorIs(t, err, errUnknownContinuation)
+
+	_, err = newResponseBudget(ResponseBudgetConfig{MaxBytes: 100})
+	assert.EqualError(t, err, "the response budget must be at least 1024 bytes")
+}
+
+func Test_ResponseBudgetFileContents(t *testing.T) {
+	var text strings.Builder
+	for i := range 200 {
+		fmt.Fprintf(&text, "line %d: \"quoted\" <html> & tabs\t\n", i)
+	}
+	binary := make([]byte, 3000)
+	for i := range binary {
+		binary[i] = byte(i * 7)
+	}
+	fake, err := ghfake.New(&ghfake.Fixture{
+		Repositories: []ghfake.FixtureRepository{
+			{
+				Owner: "octocat",
+				Name:  "hello-world",
+				Files: map[string]string{"notes.txt": text.String(), "logo.bin": string(binary)},
+			},
+		},
+	})
+	require.NoError(t, err)
+	ts := httptest.NewServer(fake)
+	defer ts.Close()
+
+	host, err := parseAPIHost(ts.URL)
+	require.NoError(t, err)
+	client := gogithub.NewClient(nil).WithAuthToken("token")
+	client.BaseURL = host.baseRESTURL
+	_, getFileContents := github.GetFileContents(func(context.Context) (*gogithub.Client, error) { return client, nil }, translations.NullTranslationHelper)
+
+	budget, err := newResponseBudget(ResponseBudgetConfig{MaxBytes: 1024})
+	require.NoError(t, err)
+	handler := budget.middleware(getFileContents)
+	ctx := server.NewMCPServer("test", "1.0.0").WithContext(context.Background(), sessionWithID("session-1"))
+
+	tokenPattern := regexp.MustCompile(`continue_result with the token "([^"]+)"`)
+	// fetch returns the parts of the file at path, which must be objects with the file's
+	// metadata and a part of its content each
+	fetch := func(path string) []map[string]any {
+		request := mcp.CallToolRequest{}
+		request.Params.Name = "get_file_contents"
+		request.Params.Arguments = map[string]any{"owner": "octocat", "repo": "hello-world", "path": path}
+		result, err := handler(ctx, request)
+		require.NoError(t, err)
+
+		var parts []map[string]any
+		for {
+			require.Len(t, result.Content, 2, "the file is split")
+			part := result.Content[0].(mcp.TextContent).Text
+			assert.LessOrEqual(t, len(part), 1024)
+			var file map[string]any
+			require.NoError(t, json.Unmarshal([]byte(part), &file), "every part is a JSON object")
+			assert.Equal(t, path, file["path"])
+			parts = append(parts, file)
+
+			match := tokenPattern.FindStringSubmatch(result.Content[1].(mcp.TextContent).Text)
+			if match == nil {
+				return parts
+			}
+			result, err = budget.chunk(ctx, match[1])
+			require.NoError(t, err)
+		}
+	}
+
+	var joined strings.Builder
+	for _, part := range fetch("notes.txt") {
+		assert.Equal(t, "utf-8", part["encoding"])
+		content := part["content"].(string)
+		assert.True(t, strings.HasSuffix(content, "\n"), "text is split between lines")
+		joined.WriteString(content)
+	}
+	assert.Equal(t, text.String(), joined.String())
+
+	var decoded []byte
+	for _, part := range fetch("logo.bin") {
+		assert.Equal(t, "base64", part["encoding"])
+		chunk, err := base64.StdEncoding.DecodeString(part["content"].(string))
+		require.NoError(t, err, "every part decodes on its own")
+		decoded = append(decoded, chunk...)
+	}
+	assert.Equal(t, binary, decoded)
+}
@@ -0,0 +1,400 @@
+package ghmcp
+
+import (
+	"bytes"
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+const (
+	// DefaultCacheMaxBytes is the default size limit of the response cache.
+	DefaultCacheMaxBytes = 64 << 20
+
+	// DefaultCacheTTL is how long cached responses are kept by default.
+	DefaultCacheTTL = time.Hour
+
+	// maxCacheEntryFraction caps single responses at this fraction of the cache size,
+	// so that one large file can't push out everything else.
+	maxCacheEntryFraction = 8
+
+	// noRepository is the bucket for entries that don't belong to a repository.
+	noRepository = "_"
+)
+
+// CacheConfig configures the conditional-request cache for GitHub REST API responses.
+type CacheConfig struct {
+	// MaxBytes bounds the total size of cached response bodies
+	MaxBytes int64
+
+	// TTL is how long a response is kept before it is dropped, 0 keeps it until evicted
+	TTL time.Duration
+
+	// Dir persists the cache on disk across restarts, if set
+	Dir string
+}
+
+// CacheStats counts how the response cache has been used.
+type CacheStats struct {
+	// Hits are requests answered from the cache after a 304 Not Modified
+	Hits uint64 `json:"hits"`
+	// Misses are cacheable requests that needed a full response
+	Misses uint64 `json:"misses"`
+	// Evictions are entries dropped for space or because they expired
+	Evictions uint64 `json:"evictions"`
+	// Invalidations are entries dropped because a write tool changed their repository
+	Invalidations uint64 `json:"invalidations"`
+	Entries       int    `json:"entries"`
+	Bytes         int64  `json:"bytes"`
+}
+
+// cacheEntry is a cached response along with the validators used to revalidate it.
+type cacheEntry struct {
+	Key        string      `json:"key"`
+	Repository string      `json:"repository"`
+	StatusCode int         `json:"status_code"`
+	Header     http.Header `json:"header"`
+	Body       []byte      `json:"body"`
+	StoredAt   time.Time   `json:"stored_at"`
+}
+
+// ResponseCache keeps GitHub REST API responses keyed by token, URL and media type,
+// and revalidates them with If-None-Match and If-Modified-Since. GitHub doesn't count
+// 304 Not Modified responses against the rate limit, so repeated reads of unchanged
+// objects are free. It is safe for concurrent use by all clients of a server.
+type ResponseCache struct {
+	config   CacheConfig
+	maxEntry int64
+
+	mu      sync.Mutex
+	entries map[string]*list.Element
+	lru     *list.List
+	bytes   int64
+	stats   CacheStats
+
+	now func() time.Time
+}
+
+// NewResponseCache creates a response cache, loading any entries persisted in config.Dir.
+func NewResponseCache(config CacheConfig) (*ResponseCache, error) {
+	if config.MaxBytes <= 0 {
+		return nil, fmt.Errorf("cache size must be positive, got %d", config.MaxBytes)
+	}
+
+	c := &ResponseCache{
+		config:   config,
+		maxEntry: config.MaxBytes / maxCacheEntryFraction,
+		entries:  make(map[string]*list.Element),
+		lru:      list.New(),
+		now:      time.Now,
+	}
+
+	if config.Dir != "" {
+		if err := os.MkdirAll(config.Dir, 0700); err != nil {
+			return nil, fmt.Errorf("failed to create cache directory: %w", err)
+		}
+		if err := c.load(); err != nil {
+			return nil, fmt.Errorf("failed to load cache: %w", err)
+		}
+	}
+
+	return c, nil
+}
+
+// Stats returns a snapshot of the cache statistics.
+func (c *ResponseCache) Stats() CacheStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	stats := c.stats
+	stats.Entries = len(c.entries)
+	stats.Bytes = c.bytes
+	return stats
+}
+
+// InvalidateRepository drops every cached response for owner/repo, for all tokens.
+func (c *ResponseCache) InvalidateRepository(owner, repo string) {
+	repository := repositoryKey(owner, repo)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, elem := range c.entries {
+		entry := elem.Value.(*cacheEntry)
+		if entry.Repository == repository {
+			c.removeLocked(elem)
+			c.stats.Invalidations++
+		}
+	}
+	if c.config.Dir != "" {
+		_ = os.RemoveAll(filepath.Join(c.config.Dir, hashKey(repository)))
+	}
+}
+
+func (c *ResponseCache) get(key string) *cacheEntry {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.entries[key]
+	if !ok {
+		return nil
+	}
+	entry := elem.Value.(*cacheEntry)
+	if c.expired(entry) {
+		c.removeLocked(elem)
+		c.stats.Evictions++
+		return nil
+	}
+	c.lru.MoveToFront(elem)
+	return entry
+}
+
+func (c *ResponseCache) put(entry *cacheEntry) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if elem, ok := c.entries[entry.Key]; ok {
+		c.removeLocked(elem)
+	}
+	c.addLocked(entry)
+	c.persist(entry)
+}
+
+func (c *ResponseCache) recordHit() {
+	c.mu.Lock()
+	c.stats.Hits++
+	c.mu.Unlock()
+}
+
+func (c *ResponseCache) recordMiss() {
+	c.mu.Lock()
+	c.stats.Misses++
+	c.mu.Unlock()
+}
+
+func (c *ResponseCache) expired(entry *cacheEntry) bool {
+	return c.config.TTL > 0 && c.now().Sub(entry.StoredAt) > c.config.TTL
+}
+
+// addLocked inserts entry as the most recently used, evicting the least recently used
+// entries to make room. The caller must hold c.mu.
+func (c *ResponseCache) addLocked(entry *cacheEntry) {
+	c.entries[entry.Key] = c.lru.PushFront(entry)
+	c.bytes += int64(len(entry.Body))
+
+	for c.bytes > c.config.MaxBytes {
+		oldest := c.lru.Back()
+		if oldest == nil {
+			break
+		}
+		c.removeLocked(oldest)
+		c.stats.Evictions++
+	}
+}
+
+// removeLocked drops elem from memory and disk. The caller must hold c.mu.
+func (c *ResponseCache) removeLocked(elem *list.Element) {
+	entry := elem.Value.(*cacheEntry)
+	c.lru.Remove(elem)
+	delete(c.entries, entry.Key)
+	c.bytes -= int64(len(entry.Body))
+	if c.config.Dir != "" {
+		_ = os.Remove(c.entryPath(entry))
+	}
+}
+
+// entryPath groups entries on disk by repository, so that invalidating a repository
+// is a single directory removal.
+func (c *ResponseCache) entryPath(entry *cacheEntry) string {
+	return filepath.Join(c.config.Dir, hashKey(entry.Repository), hashKey(entry.Key)+".json")
+}
+
+// persist writes entry to disk, if the cache is persistent. The caller must hold c.mu.
+// Failing to persist only costs a future cache miss, so errors are ignored.
+func (c *ResponseCache) persist(entry *cacheEntry) {
+	if c.config.Dir == "" {
+		return
+	}
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return
+	}
+	path := c.entryPath(entry)
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return
+	}
+	_ = os.WriteFile(path, data, 0600)
+}
+
+// load reads the entries persisted on disk, dropping those that have expired.
+func (c *ResponseCache) load() error {
+	return filepath.WalkDir(c.config.Dir, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || filepath.Ext(path) != ".json" {
+			return nil
+		}
+
+		data, err := os.ReadFile(path) //nolint:gosec // the path is within the configured cache directory
+		if err != nil {
+			return err
+		}
+		var entry cacheEntry
+		if err := json.Unmarshal(data, &entry); err != nil || c.expired(&entry) {
+			_ = os.Remove(path)
+			return nil
+		}
+
+		c.mu.Lock()
+		c.addLocked(&entry)
+		c.mu.Unlock()
+		return nil
+	})
+}
+
+// cachingTransport serves GET requests from a ResponseCache when GitHub confirms
+// with 304 Not Modified that the cached response is still current.
+type cachingTransport struct {
+	transport http.RoundTripper
+	cache     *ResponseCache
+
+	// tokenKey separates the entries of clients using different tokens or app installations,
+	// which may see different data
+	tokenKey string
+}
+
+func (t *cachingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method != http.MethodGet || req.Header.Get("Range") != "" {
+		return t.transport.RoundTrip(req)
+	}
+
+	key := t.tokenKey + " " + req.Header.Get("Accept") + " " + req.URL.String()
+	cached := t.cache.get(key)
+	if cached != nil {
+		req = req.Clone(req.Context())
+		if etag := cached.Header.Get("ETag"); etag != "" {
+			req.Header.Set("If-None-Match", etag)
+		}
+		if lastModified := cached.Header.Get("Last-Modified"); lastModified != "" {
+			req.Header.Set("If-Modified-Since", lastModified)
+		}
+	}
+
+	resp, err := t.transport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if cached != nil && resp.StatusCode == http.StatusNotModified {
+		t.cache.recordHit()
+		_, _ = io.Copy(io.Discard, resp.Body)
+		_ = resp.Body.Close()
+		return cached.response(req, resp.Header), nil
+	}
+
+	t.cache.recordMiss()
+	if resp.StatusCode != http.StatusOK || (resp.Header.Get("ETag") == "" && resp.Header.Get("Last-Modified") == "") {
+		return resp, nil
+	}
+
+	// Only buffer as much as we are willing to cache, passing larger bodies through untouched
+	body, err := io.ReadAll(io.LimitReader(resp.Body, t.cache.maxEntry+1))
+	if err != nil {
+		_ = resp.Body.Close()
+		return nil, err
+	}
+	if int64(len(body)) > t.cache.maxEntry {
+		resp.Body = struct {
+			io.Reader
+			io.Closer
+		}{io.MultiReader(bytes.NewReader(body), resp.Body), resp.Body}
+		return resp, nil
+	}
+	_ = resp.Body.Close()
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	t.cache.put(&cacheEntry{
+		Key:        key,
+		Repository: repositoryFromPath(req.URL.Path),
+		StatusCode: resp.StatusCode,
+		Header:     resp.Header.Clone(),
+		Body:       body,
+		StoredAt:   t.cache.now(),
+	})
+	return resp, nil
+}
+
+// response rebuilds the cached response for req, taking the headers of the 304 response
+// that revalidated it, such as the current rate limit, over the cached ones.
+func (e *cacheEntry) response(req *http.Request, revalidated http.Header) *http.Response {
+	header := e.Header.Clone()
+	for name, values := range revalidated {
+		header[name] = values
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", e.StatusCode, http.StatusText(e.StatusCode)),
+		StatusCode:    e.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(e.Body)),
+		ContentLength: int64(len(e.Body)),
+		Request:       req,
+	}
+}
+
+// repositoryFromPath returns the owner/repo a REST API path refers to, e.g. for
+// /repos/octocat/hello-world/pulls/1 or /api/v3/repos/octocat/hello-world on GHES.
+func repositoryFromPath(path string) string {
+	_, rest, ok := strings.Cut(path, "/repos/")
+	if !ok {
+		return noRepository
+	}
+	parts := strings.SplitN(rest, "/", 3)
+	if len(parts) < 2 || parts[0] == "" || parts[1] == "" {
+		return noRepository
+	}
+	return repositoryKey(parts[0], parts[1])
+}
+
+func repositoryKey(owner, repo string) string {
+	return strings.ToLower(owner + "/" + repo)
+}
+
+func hashKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// cacheInvalidationMiddleware drops the cached responses for the repository a write tool
+// acted on, identified by its owner and repo arguments, so that later reads don't depend
+// on GitHub having already updated the ETags.
+func cacheInvalidationMiddleware(cache *ResponseCache) server.ToolHandlerMiddleware {
+	return func(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+		return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			result, err := next(ctx, request)
+
+			owner, _ := request.GetArguments()["owner"].(string)
+			repo, _ := request.GetArguments()["repo"].(string)
+			if owner != "" && repo != "" {
+				cache.InvalidateRepository(owner, repo)
+			}
+			return result, err
+		}
+	}
+}
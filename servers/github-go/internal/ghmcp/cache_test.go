@@ -0,0 +1,178 @@
+package ghmcp
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// etagServer serves a fixed body per path with an ETag derived from its version,
+// answering 304 Not Modified when the client already has the current version.
+type etagServer struct {
+	versions    map[string]int
+	full        int
+	notModified int
+}
+
+func (s *etagServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	etag := fmt.Sprintf(`"%s-%d"`, r.URL.Path, s.versions[r.URL.Path])
+	w.Header().Set("X-RateLimit-Remaining", fmt.Sprint(5000-s.full))
+	if r.Header.Get("If-None-Match") == etag {
+		s.notModified++
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	s.full++
+	w.Header().Set("ETag", etag)
+	_, _ = fmt.Fprintf(w, "body of %s version %d", r.URL.Path, s.versions[r.URL.Path])
+}
+
+func fetch(t *testing.T, client *http.Client, url string) string {
+	t.Helper()
+	resp, err := client.Get(url)
+	require.NoError(t, err)
+	defer func() { _ = resp.Body.Close() }()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	return string(body)
+}
+
+func cachingClient(cache *ResponseCache, token string) *http.Client {
+	return &http.Client{Transport: &cachingTransport{transport: http.DefaultTransport, cache: cache, tokenKey: hashKey(token)}}
+}
+
+func Test_CachingTransport(t *testing.T) {
+	upstream := &etagServer{versions: map[string]int{}}
+	ts := httptest.NewServer(upstream)
+	defer ts.Close()
+
+	cache, err := NewResponseCache(CacheConfig{MaxBytes: 1 << 20})
+	require.NoError(t, err)
+	client := cachingClient(cache, "token-a")
+
+	url := ts.URL + "/repos/octocat/hello-world/pulls/1"
+	assert.Equal(t, "body of /repos/octocat/hello-world/pulls/1 version 0", fetch(t, client, url))
+	assert.Equal(t, "body of /repos/octocat/hello-world/pulls/1 version 0", fetch(t, client, url))
+	assert.Equal(t, 1, upstream.full)
+	assert.Equal(t, 1, upstream.notModified)
+
+	// Changed objects are fetched again
+	upstream.versions["/repos/octocat/hello-world/pulls/1"] = 1
+	assert.Equal(t, "body of /repos/octocat/hello-world/pulls/1 version 1", fetch(t, client, url))
+	assert.Equal(t, 2, upstream.full)
+
+	// Other tokens don't share entries
+	fetch(t, cachingClient(cache, "token-b"), url)
+	assert.Equal(t, 3, upstream.full)
+
+	stats := cache.Stats()
+	assert.Equal(t, uint64(1), stats.Hits)
+	assert.Equal(t, uint64(3), stats.Misses)
+	assert.Equal(t, 2, stats.Entries)
+
+	// Writes to the repository drop its entries for every token
+	cache.InvalidateRepository("OctoCat", "Hello-World")
+	fetch(t, client, url)
+	assert.Equal(t, 4, upstream.full)
+	assert.Equal(t, uint64(2), cache.Stats().Invalidations)
+}
+
+func Test_ResponseCacheLimits(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	cache, err := NewResponseCache(CacheConfig{MaxBytes: 80, TTL: time.Hour})
+	require.NoError(t, err)
+	cache.now = func() time.Time { return now }
+
+	entry := func(key string, size int) *cacheEntry {
+		return &cacheEntry{Key: key, Repository: noRepository, StatusCode: http.StatusOK, Body: []byte(strings.Repeat("x", size)), StoredAt: cache.now()}
+	}
+
+	cache.put(entry("a", 10))
+	cache.put(entry("b", 10))
+	require.NotNil(t, cache.get("a"), "a is now the most recently used")
+
+	// Going over the size limit evicts the least recently used entries
+	for i := 0; i < 7; i++ {
+		cache.put(entry(fmt.Sprintf("c%d", i), 10))
+	}
+	assert.NotNil(t, cache.get("a"))
+	assert.Nil(t, cache.get("b"))
+	assert.LessOrEqual(t, cache.Stats().Bytes, int64(80))
+
+	// Entries expire after the TTL
+	now = now.Add(2 * time.Hour)
+	assert.Nil(t, cache.get("a"))
+	assert.Equal(t, uint64(2), cache.Stats().Evictions)
+}
+
+func Test_ResponseCachePersistence(t *testing.T) {
+	upstream := &etagServer{versions: map[string]int{}}
+	ts := httptest.NewServer(upstream)
+	defer ts.Close()
+
+	dir := t.TempDir()
+	cache, err := NewResponseCache(CacheConfig{MaxBytes: 1 << 20, Dir: dir})
+	require.NoError(t, err)
+
+	first := ts.URL + "/repos/octocat/hello-world/contents/README.md"
+	second := ts.URL + "/repos/octocat/spoon-knife/contents/README.md"
+	fetch(t, cachingClient(cache, "token"), first)
+	fetch(t, cachingClient(cache, "token"), second)
+	cache.InvalidateRepository("octocat", "spoon-knife")
+
+	// A new cache on the same directory picks up where the previous one left off
+	restarted, err := NewResponseCache(CacheConfig{MaxBytes: 1 << 20, Dir: dir})
+	require.NoError(t, err)
+	assert.Equal(t, 1, restarted.Stats().Entries)
+
+	assert.Equal(t, "body of /repos/octocat/hello-world/contents/README.md version 0", fetch(t, cachingClient(restarted, "token"), first))
+	assert.Equal(t, 2, upstream.full)
+	assert.Equal(t, 1, upstream.notModified)
+}
+
+func Test_RepositoryFromPath(t *testing.T) {
+	tests := []struct {
+		path     string
+		expected string
+	}{
+		{path: "/repos/octocat/Hello-World/pulls/1", expected: "octocat/hello-world"},
+		{path: "/api/v3/repos/octocat/hello-world", expected: "octocat/hello-world"},
+		{path: "/repos/octocat", expected: noRepository},
+		{path: "/user", expected: noRepository},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.path, func(t *testing.T) {
+			assert.Equal(t, tc.expected, repositoryFromPath(tc.path))
+		})
+	}
+}
+
+func Test_CacheInvalidationMiddleware(t *testing.T) {
+	cache, err := NewResponseCache(CacheConfig{MaxBytes: 1 << 20})
+	require.NoError(t, err)
+	cache.put(&cacheEntry{Key: "k", Repository: "octocat/hello-world", StatusCode: http.StatusOK})
+
+	handler := cacheInvalidationMiddleware(cache)(func(_ context.Context, _ mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultText("ok"), nil
+	})
+
+	request := mcp.CallToolRequest{}
+	request.Params.Arguments = map[string]any{"owner": "octocat", "repo": "spoon-knife"}
+	_, _ = handler(context.Background(), request)
+	assert.NotNil(t, cache.get("k"))
+
+	request.Params.Arguments = map[string]any{"owner": "octocat", "repo": "hello-world"}
+	_, _ = handler(context.Background(), request)
+	assert.Nil(t, cache.get("k"))
+}
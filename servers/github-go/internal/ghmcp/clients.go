@@ -0,0 +1,230 @@
+package ghmcp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	gogithub "github.com/google/go-github/v72/github"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/shurcooL/githubv4"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// maxCachedClients bounds how many distinct tokens and installations we keep clients around for.
+const maxCachedClients = 1024
+
+// errNoToken is returned when neither the request nor the server configuration provide a token.
+var errNoToken = errors.New("no GitHub token provided for this session, set the Authorization header")
+
+type tokenCtxKey struct{}
+
+// ContextWithToken returns a copy of ctx carrying the GitHub token that API requests
+// made on its behalf should authenticate with. It takes precedence over the token
+// the server was configured with.
+func ContextWithToken(ctx context.Context, token string) context.Context {
+	return context.WithValue(ctx, tokenCtxKey{}, token)
+}
+
+// TokenFromContext returns the GitHub token stored in ctx by ContextWithToken, if any.
+func TokenFromContext(ctx context.Context) (string, bool) {
+	token, ok := ctx.Value(tokenCtxKey{}).(string)
+	return token, ok && token != ""
+}
+
+// tokenFromRequest extracts a token from an "Authorization: Bearer <token>" or
+// "Authorization: token <token>" header. It returns an empty string if there is none.
+func tokenFromRequest(r *http.Request) string {
+	scheme, token, ok := strings.Cut(r.Header.Get("Authorization"), " ")
+	if !ok {
+		return ""
+	}
+	if !strings.EqualFold(scheme, "bearer") && !strings.EqualFold(scheme, "token") {
+		return ""
+	}
+	return strings.TrimSpace(token)
+}
+
+// githubClients is the pair of API clients that authenticate with a single token,
+// along with the rate limits the API has reported for that token.
+type githubClients struct {
+	rest       *gogithub.Client
+	gql        *githubv4.Client
+	rateLimits *rateLimitTracker
+	lastUsed   time.Time
+}
+
+// clientCache hands out REST and GraphQL clients per token, so that sessions using
+// the same token share connections while sessions using different tokens never
+// share a client. Tokens are only kept as SHA-256 digests. Clients of GitHub App
+// installations are kept per installation instead, since their tokens rotate hourly.
+type clientCache struct {
+	account   string
+	version   string
+	apiHost   apiHost
+	rateLimit RateLimitConfig
+	responses *ResponseCache
+	metrics   *Metrics
+	tracer    trace.Tracer
+	dryRun    bool
+
+	mu      sync.Mutex
+	clients map[string]*githubClients
+}
+
+// newClientCache creates a client cache for the named account on host, whose clients are
+// set up as cfg describes.
+func newClientCache(cfg MCPServerConfig, account string, host apiHost) *clientCache {
+	c := &clientCache{
+		account:   account,
+		version:   cfg.Version,
+		apiHost:   host,
+		rateLimit: cfg.RateLimit,
+		responses: cfg.Cache,
+		metrics:   cfg.Metrics,
+		dryRun:    cfg.DryRun,
+		clients:   make(map[string]*githubClients),
+	}
+	if cfg.Tracing != nil {
+		c.tracer = cfg.Tracing.Tracer(tracerName)
+	}
+	return c
+}
+
+// get returns the clients for the account's token, constructing them on first use.
+func (c *clientCache) get(token string) *githubClients {
+	return c.getKeyed("token "+hashKey(token), staticTokenSource(token), c.account, "")
+}
+
+// getSession returns the clients for a token that a session brought, whose rate limit
+// isn't recorded in the metrics, since sessions may bring any number of tokens.
+func (c *clientCache) getSession(token string) *githubClients {
+	return c.getKeyed("session "+hashKey(token), staticTokenSource(token), "", "")
+}
+
+// getInstallation returns the clients for an installation of app, which authenticate
+// with whichever token of the installation is current when a request is sent.
+func (c *clientCache) getInstallation(app *appTokenSource, installationID int64) *githubClients {
+	return c.getKeyed(fmt.Sprintf("installation %d", installationID), installationTokenSource{app: app, id: installationID},
+		c.account, strconv.FormatInt(installationID, 10))
+}
+
+// getKeyed returns the clients stored under key, constructing them with tokens on first
+// use. The rate limits they see are recorded for account and installation, if account is set.
+func (c *clientCache) getKeyed(key string, tokens tokenSource, account, installation string) *githubClients {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if clients, ok := c.clients[key]; ok {
+		clients.lastUsed = time.Now()
+		return clients
+	}
+
+	if len(c.clients) >= maxCachedClients {
+		c.evictOldestLocked()
+	}
+
+	clients := c.newClients(key, tokens, account, installation)
+	c.clients[key] = clients
+	return clients
+}
+
+// evictOldestLocked drops the least recently used clients. The caller must hold c.mu.
+func (c *clientCache) evictOldestLocked() {
+	var (
+		oldestKey string
+		oldest    time.Time
+		found     bool
+	)
+	for key, clients := range c.clients {
+		if !found || clients.lastUsed.Before(oldest) {
+			oldestKey, oldest, found = key, clients.lastUsed, true
+		}
+	}
+	if found {
+		delete(c.clients, oldestKey)
+	}
+}
+
+// newClients constructs clients that authenticate with tokens. key identifies the
+// credentials in the response cache, which must not serve one's responses to another.
+func (c *clientCache) newClients(key string, tokens tokenSource, account, installation string) *githubClients {
+	rateLimits := newRateLimitTracker()
+	var transport http.RoundTripper = &userAgentTransport{
+		transport: &bearerAuthTransport{
+			transport: http.DefaultTransport,
+			tokens:    tokens,
+		},
+		version: c.version,
+	}
+
+	// Traced and counted below the rate limit handling, so that every retry is visible
+	if c.tracer != nil {
+		transport = &tracingTransport{transport: transport, tracer: c.tracer}
+	}
+	if c.metrics != nil {
+		transport = &metricsTransport{
+			transport:    transport,
+			metrics:      c.metrics,
+			account:      account,
+			installation: installation,
+		}
+	}
+	transport = newRateLimitTransport(transport, c.rateLimit, rateLimits)
+
+	// Writes held back in dry-run mode never reach the rate limit handling
+	if c.dryRun {
+		transport = &dryRunTransport{transport: transport}
+	}
+
+	// Revalidations go through the rate limit handling like any other request
+	if c.responses != nil {
+		transport = &cachingTransport{
+			transport: transport,
+			cache:     c.responses,
+			tokenKey:  key,
+		}
+	}
+	httpClient := &http.Client{Transport: transport}
+
+	// Construct our REST client
+	restClient := gogithub.NewClient(httpClient)
+	restClient.UserAgent = defaultUserAgent(c.version)
+	restClient.BaseURL = c.apiHost.baseRESTURL
+	restClient.UploadURL = c.apiHost.uploadURL
+
+	// Construct our GraphQL client
+	// We're using NewEnterpriseClient here unconditionally as opposed to NewClient because we already
+	// did the necessary API host parsing so that github.com will return the correct URL anyway.
+	gqlClient := githubv4.NewEnterpriseClient(c.apiHost.graphqlURL.String(), httpClient)
+
+	return &githubClients{
+		rest:       restClient,
+		gql:        gqlClient,
+		rateLimits: rateLimits,
+		lastUsed:   time.Now(),
+	}
+}
+
+func defaultUserAgent(version string) string {
+	return fmt.Sprintf("github-mcp-server/%s", version)
+}
+
+// userAgentFromContext includes the MCP client's name and version in the user agent
+// when the session it is serving has reported them during initialization.
+func userAgentFromContext(ctx context.Context, version string) string {
+	session, ok := server.ClientSessionFromContext(ctx).(server.SessionWithClientInfo)
+	if !ok {
+		return defaultUserAgent(version)
+	}
+	info := session.GetClientInfo()
+	if info.Name == "" {
+		return defaultUserAgent(version)
+	}
+	return fmt.Sprintf("github-mcp-server/%s (%s/%s)", version, info.Name, info.Version)
+}
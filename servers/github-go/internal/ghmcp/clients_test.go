@@ -0,0 +1,181 @@
+package ghmcp
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/github/github-mcp-server/pkg/github"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_TokenFromRequest(t *testing.T) {
+	tests := []struct {
+		name     string
+		header   string
+		expected string
+	}{
+		{name: "bearer scheme", header: "Bearer abc", expected: "abc"},
+		{name: "token scheme", header: "token abc", expected: "abc"},
+		{name: "scheme is case insensitive", header: "BEARER abc", expected: "abc"},
+		{name: "no header", header: "", expected: ""},
+		{name: "basic scheme is ignored", header: "Basic dXNlcjpwYXNz", expected: ""},
+		{name: "missing token", header: "Bearer", expected: ""},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPost, "/mcp", nil)
+			if tc.header != "" {
+				r.Header.Set("Authorization", tc.header)
+			}
+			assert.Equal(t, tc.expected, tokenFromRequest(r))
+		})
+	}
+}
+
+func Test_ClientResolverPrefersSessionToken(t *testing.T) {
+	host, err := newDotcomHost()
+	require.NoError(t, err)
+	resolver, err := newClientResolver(MCPServerConfig{Version: "test"}, host, staticTokenSource("fallback"))
+	require.NoError(t, err)
+
+	clients, err := resolver.resolve(ContextWithToken(context.Background(), "session"))
+	require.NoError(t, err)
+	assert.Same(t, resolver.clients.getSession("session"), clients)
+
+	clients, err = resolver.resolve(context.Background())
+	require.NoError(t, err)
+	assert.Same(t, resolver.clients.get("fallback"), clients)
+
+	resolver, err = newClientResolver(MCPServerConfig{Version: "test"}, host, staticTokenSource(""))
+	require.NoError(t, err)
+	_, err = resolver.resolve(context.Background())
+	require.ErrorIs(t, err, errNoToken)
+}
+
+func Test_ClientCacheIsolatesTokens(t *testing.T) {
+	var gotAuth []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotAuth = append(gotAuth, r.Header.Get("Authorization"))
+		_, _ = w.Write([]byte(`{"login":"octocat"}`))
+	}))
+	defer ts.Close()
+
+	baseURL, err := url.Parse(ts.URL + "/")
+	require.NoError(t, err)
+	cache := newClientCache(MCPServerConfig{Version: "test"}, github.DefaultAccountName, apiHost{baseRESTURL: baseURL, graphqlURL: baseURL, uploadURL: baseURL})
+
+	a1 := cache.get("token-a")
+	a2 := cache.get("token-a")
+	b := cache.get("token-b")
+
+	assert.Same(t, a1, a2, "expected clients to be reused for the same token")
+	assert.NotSame(t, a1, b, "expected distinct clients for distinct tokens")
+
+	_, _, err = a1.rest.Users.Get(context.Background(), "")
+	require.NoError(t, err)
+	_, _, err = b.rest.Users.Get(context.Background(), "")
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"Bearer token-a", "Bearer token-b"}, gotAuth)
+}
+
+func Test_ClientResolverKeysAppInstallations(t *testing.T) {
+	_, keyPEM := generateTestKey(t)
+
+	var tokensIssued, notModified int
+	var gotAuth []string
+	mux := http.NewServeMux()
+	mux.HandleFunc("/app/installations/{id}/access_tokens", func(w http.ResponseWriter, r *http.Request) {
+		tokensIssued++
+		_, _ = fmt.Fprintf(w, `{"token": "installation-%s-%d", "expires_at": %q}`,
+			r.PathValue("id"), tokensIssued, time.Now().Add(time.Hour).Format(time.RFC3339))
+	})
+	mux.HandleFunc("/user", func(w http.ResponseWriter, r *http.Request) {
+		gotAuth = append(gotAuth, r.Header.Get("Authorization"))
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			notModified++
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		_, _ = w.Write([]byte(`{"login":"octo-app[bot]"}`))
+	})
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+
+	baseURL, err := url.Parse(ts.URL + "/")
+	require.NoError(t, err)
+	host := apiHost{baseRESTURL: baseURL, graphqlURL: baseURL, uploadURL: baseURL}
+	source, err := newAppTokenSource(AppAuthConfig{AppID: 1234, PrivateKey: keyPEM, InstallationID: 1}, "test", host)
+	require.NoError(t, err)
+	cache, err := NewResponseCache(CacheConfig{MaxBytes: 1 << 20})
+	require.NoError(t, err)
+	resolver, err := newClientResolver(MCPServerConfig{Version: "test", Cache: cache}, host, source)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	before, err := resolver.resolve(ctx)
+	require.NoError(t, err)
+	_, _, err = before.rest.Users.Get(ctx, "")
+	require.NoError(t, err)
+
+	// The installation token rotates
+	source.now = func() time.Time { return time.Now().Add(time.Hour) }
+
+	after, err := resolver.resolve(ctx)
+	require.NoError(t, err)
+	assert.Same(t, before, after, "expected the installation to keep its clients across token rotations")
+	_, _, err = after.rest.Users.Get(ctx, "")
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"Bearer installation-1-1", "Bearer installation-1-2"}, gotAuth)
+	assert.Equal(t, 1, notModified, "expected the cached response to be revalidated with the new token")
+
+	// Sessions that bring their own token don't use the installation's clients
+	own, err := resolver.resolve(ContextWithToken(ctx, "user-token"))
+	require.NoError(t, err)
+	assert.NotSame(t, before, own)
+}
+
+func Test_WithRequestToken(t *testing.T) {
+	var seen string
+	next := http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		seen, _ = TokenFromContext(r.Context())
+	})
+
+	t.Run("rejects requests without a token by default", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		withRequestToken(next, false).ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/mcp", nil))
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+		assert.NotEmpty(t, rec.Header().Get("WWW-Authenticate"))
+	})
+
+	t.Run("passes requests without a token through when allowed", func(t *testing.T) {
+		seen = "unset"
+		rec := httptest.NewRecorder()
+		withRequestToken(next, true).ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/mcp", nil))
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Empty(t, seen)
+	})
+
+	t.Run("stores the request token in the context", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodPost, "/mcp", nil)
+		r.Header.Set("Authorization", "Bearer abc")
+		rec := httptest.NewRecorder()
+		withRequestToken(next, false).ServeHTTP(rec, r)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "abc", seen)
+	})
+}
+
+func Test_RunHTTPServerRequiresTokenForUnauthenticatedRequests(t *testing.T) {
+	err := RunHTTPServer(HTTPServerConfig{Version: "test", AllowUnauthenticated: true})
+	require.EqualError(t, err, "serving requests without an Authorization header requires a GitHub token or app to serve them with")
+}
@@ -0,0 +1,113 @@
+package ghmcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/github/github-mcp-server/pkg/audit"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+const (
+	// ConfirmationFallbackDeny refuses calls of destructive tools from clients that can't
+	// ask the user for confirmation.
+	ConfirmationFallbackDeny = "deny"
+	// ConfirmationFallbackAllow runs calls of destructive tools from clients that can't ask
+	// the user for confirmation, and marks them as unconfirmed in the audit log.
+	ConfirmationFallbackAllow = "allow"
+)
+
+// ConfirmationConfig configures how the user is asked to confirm calls of destructive tools.
+type ConfirmationConfig struct {
+	// Fallback is ConfirmationFallbackDeny or ConfirmationFallbackAllow, and applies to clients
+	// that don't support elicitation
+	Fallback string
+}
+
+// validate checks that the fallback is known.
+func (cfg ConfirmationConfig) validate() error {
+	switch cfg.Fallback {
+	case ConfirmationFallbackDeny, ConfirmationFallbackAllow:
+		return nil
+	default:
+		return fmt.Errorf("unknown confirmation fallback %q, expected %s or %s", cfg.Fallback, ConfirmationFallbackDeny, ConfirmationFallbackAllow)
+	}
+}
+
+// confirmationMiddleware asks the user to confirm every call of the tools it wraps, which are
+// those annotated as destructive, with an MCP elicitation that summarizes the call. Calls only
+// run once the user accepts. Clients that don't support elicitation get the configured fallback.
+func confirmationMiddleware(cfg ConfirmationConfig) server.ToolHandlerMiddleware {
+	return func(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+		return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			session := server.ClientSessionFromContext(ctx)
+			elicitor, ok := session.(server.SessionWithElicitation)
+			if !ok || !supportsElicitation(session) {
+				if cfg.Fallback == ConfirmationFallbackAllow {
+					audit.SetConfirmation(ctx, audit.ConfirmationUnconfirmed)
+					return next(ctx, request)
+				}
+				return mcp.NewToolResultError(fmt.Sprintf("%s must be confirmed by the user, but the client can't ask for confirmation", request.Params.Name)), nil
+			}
+
+			response, err := elicitor.RequestElicitation(ctx, mcp.ElicitationRequest{
+				Params: mcp.ElicitationParams{
+					Message: confirmationMessage(request),
+					RequestedSchema: map[string]any{
+						"type":       "object",
+						"properties": map[string]any{},
+					},
+				},
+			})
+			if err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("failed to ask the user for confirmation: %v", err)), nil
+			}
+			if response.Action != mcp.ElicitationResponseActionAccept {
+				audit.SetConfirmation(ctx, audit.ConfirmationDeclined)
+				return mcp.NewToolResultError(fmt.Sprintf("the user did not confirm %s, so it was not run", request.Params.Name)), nil
+			}
+
+			audit.SetConfirmation(ctx, audit.ConfirmationConfirmed)
+			return next(ctx, request)
+		}
+	}
+}
+
+// supportsElicitation reports whether the client of session declared the elicitation capability.
+func supportsElicitation(session server.ClientSession) bool {
+	withInfo, ok := session.(server.SessionWithClientInfo)
+	return ok && withInfo.GetClientCapabilities().Elicitation != nil
+}
+
+// confirmationMessage summarizes a tool call for the user: the tool and the arguments it was
+// called with, redacted like in the audit log so that file contents don't flood the prompt.
+func confirmationMessage(request mcp.CallToolRequest) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Allow %s to run? It can make changes on GitHub that are hard to undo.", request.Params.Name)
+
+	args := audit.RedactArguments(request.GetArguments())
+	if len(args) == 0 {
+		return b.String()
+	}
+
+	keys := make([]string, 0, len(args))
+	for key := range args {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	b.WriteString("\n")
+	for _, key := range keys {
+		value, ok := args[key].(string)
+		if !ok {
+			data, _ := json.Marshal(args[key])
+			value = string(data)
+		}
+		fmt.Fprintf(&b, "\n%s: %s", key, value)
+	}
+	return b.String()
+}
@@ -0,0 +1,156 @@
+package ghmcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/github/github-mcp-server/pkg/audit"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// elicitationHandler answers elicitation requests like a user would, and remembers what was asked.
+type elicitationHandler struct {
+	action   mcp.ElicitationResponseAction
+	err      error
+	messages []string
+}
+
+func (h *elicitationHandler) Elicit(_ context.Context, request mcp.ElicitationRequest) (*mcp.ElicitationResult, error) {
+	h.messages = append(h.messages, request.Params.Message)
+	if h.err != nil {
+		return nil, h.err
+	}
+	return &mcp.ElicitationResult{ElicitationResponse: mcp.ElicitationResponse{Action: h.action}}, nil
+}
+
+func Test_ConfirmationMiddleware(t *testing.T) {
+	tests := []struct {
+		name                 string
+		fallback             string
+		supportsElicitation  bool
+		handler              *elicitationHandler
+		expectCalled         bool
+		expectError          string
+		expectConfirmation   audit.Confirmation
+		expectElicitedPrompt bool
+	}{
+		{
+			name:                 "user accepts",
+			fallback:             ConfirmationFallbackDeny,
+			supportsElicitation:  true,
+			handler:              &elicitationHandler{action: mcp.ElicitationResponseActionAccept},
+			expectCalled:         true,
+			expectConfirmation:   audit.ConfirmationConfirmed,
+			expectElicitedPrompt: true,
+		},
+		{
+			name:                 "user declines",
+			fallback:             ConfirmationFallbackAllow,
+			supportsElicitation:  true,
+			handler:              &elicitationHandler{action: mcp.ElicitationResponseActionDecline},
+			expectError:          "the user did not confirm merge_pull_request, so it was not run",
+			expectConfirmation:   audit.ConfirmationDeclined,
+			expectElicitedPrompt: true,
+		},
+		{
+			name:                 "user cancels",
+			fallback:             ConfirmationFallbackAllow,
+			supportsElicitation:  true,
+			handler:              &elicitationHandler{action: mcp.ElicitationResponseActionCancel},
+			expectError:          "the user did not confirm merge_pull_request, so it was not run",
+			expectConfirmation:   audit.ConfirmationDeclined,
+			expectElicitedPrompt: true,
+		},
+		{
+			name:                 "elicitation fails",
+			fallback:             ConfirmationFallbackAllow,
+			supportsElicitation:  true,
+			handler:              &elicitationHandler{err: errors.New("client went away")},
+			expectError:          "failed to ask the user for confirmation: client went away",
+			expectElicitedPrompt: true,
+		},
+		{
+			name:        "client without elicitation, deny",
+			fallback:    ConfirmationFallbackDeny,
+			handler:     &elicitationHandler{action: mcp.ElicitationResponseActionAccept},
+			expectError: "merge_pull_request must be confirmed by the user, but the client can't ask for confirmation",
+		},
+		{
+			name:               "client without elicitation, allow",
+			fallback:           ConfirmationFallbackAllow,
+			handler:            &elicitationHandler{action: mcp.ElicitationResponseActionAccept},
+			expectCalled:       true,
+			expectConfirmation: audit.ConfirmationUnconfirmed,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "audit.jsonl")
+			auditLog, err := audit.Open(path, false)
+			require.NoError(t, err)
+			defer func() { _ = auditLog.Close() }()
+
+			called := false
+			handler := auditLog.Middleware(confirmationMiddleware(ConfirmationConfig{Fallback: tc.fallback})(
+				func(_ context.Context, _ mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+					called = true
+					return mcp.NewToolResultText("merged"), nil
+				},
+			))
+
+			session := server.NewInProcessSessionWithHandlers("session-1", nil, tc.handler, nil)
+			if tc.supportsElicitation {
+				session.SetClientCapabilities(mcp.ClientCapabilities{Elicitation: &struct{}{}})
+			}
+			ctx := server.NewMCPServer("test", "1.0.0").WithContext(context.Background(), session)
+
+			request := mcp.CallToolRequest{}
+			request.Params.Name = "merge_pull_request"
+			request.Params.Arguments = map[string]any{
+				"owner":        "octocat",
+				"repo":         "hello-world",
+				"pullNumber":   float64(42),
+				"merge_method": "squash",
+			}
+
+			result, err := handler(ctx, request)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectCalled, called)
+			if tc.expectError != "" {
+				require.True(t, result.IsError)
+				assert.Equal(t, tc.expectError, result.Content[0].(mcp.TextContent).Text)
+			} else {
+				assert.False(t, result.IsError)
+			}
+
+			if tc.expectElicitedPrompt {
+				require.Len(t, tc.handler.messages, 1)
+				assert.Equal(t, "Allow merge_pull_request to run? It can make changes on GitHub that are hard to undo.\n\n"+
+					"merge_method: squash\nowner: octocat\npullNumber: 42\nrepo: hello-world", tc.handler.messages[0])
+			} else {
+				assert.Empty(t, tc.handler.messages)
+			}
+
+			data, err := os.ReadFile(path)
+			require.NoError(t, err)
+			var record audit.Record
+			require.NoError(t, json.Unmarshal([]byte(strings.TrimSpace(string(data))), &record))
+			assert.Equal(t, tc.expectConfirmation, record.Confirmation)
+		})
+	}
+}
+
+func Test_ConfirmationConfigValidate(t *testing.T) {
+	assert.NoError(t, ConfirmationConfig{Fallback: ConfirmationFallbackDeny}.validate())
+	assert.NoError(t, ConfirmationConfig{Fallback: ConfirmationFallbackAllow}.validate())
+	assert.EqualError(t, ConfirmationConfig{Fallback: "ask"}.validate(), `unknown confirmation fallback "ask", expected deny or allow`)
+}
@@ -0,0 +1,219 @@
+package ghmcp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/github/github-mcp-server/pkg/audit"
+	"github.com/github/github-mcp-server/pkg/github"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// dryRunRequest is a GitHub API request that was not sent because of dry-run mode.
+type dryRunRequest struct {
+	Method string `json:"method"`
+	URL    string `json:"url"`
+	Body   any    `json:"body,omitempty"`
+}
+
+// dryRunRecorder collects the requests a single tool call would have sent.
+type dryRunRecorder struct {
+	mu       sync.Mutex
+	requests []dryRunRequest
+}
+
+func (r *dryRunRecorder) record(req dryRunRequest) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.requests = append(r.requests, req)
+	return len(r.requests)
+}
+
+type dryRunCtxKey struct{}
+
+// dryRunMiddleware collects the requests that the dry-run transport holds back during a
+// tool call, and returns them instead of the tool's result, which would only reflect the
+// placeholder responses. A tool may also fail on a placeholder that lacks what it needs
+// from a real response; the requests it would have sent until then are returned with the
+// error. Tool calls that fail before holding anything back, e.g. on invalid arguments, are
+// returned as they are, as are those of tools that didn't try to change anything.
+func dryRunMiddleware(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		recorder := &dryRunRecorder{}
+		result, err := next(context.WithValue(ctx, dryRunCtxKey{}, recorder), request)
+
+		recorder.mu.Lock()
+		requests := recorder.requests
+		recorder.mu.Unlock()
+		if len(requests) == 0 {
+			return result, err
+		}
+
+		output := map[string]any{
+			"dry_run":  true,
+			"tool":     request.Params.Name,
+			"requests": requests,
+		}
+		if err != nil {
+			output["error"] = err.Error()
+		} else if result != nil && result.IsError {
+			output["error"] = toolResultText(result)
+		}
+		return github.MarshalledTextResult(output), nil
+	}
+}
+
+// toolResultText joins the text content of a tool result.
+func toolResultText(result *mcp.CallToolResult) string {
+	var texts []string
+	for _, content := range result.Content {
+		if text, ok := content.(mcp.TextContent); ok {
+			texts = append(texts, text.Text)
+		}
+	}
+	return strings.Join(texts, "\n")
+}
+
+// dryRunTransport sends requests that only read through, and holds back those that would
+// change anything on GitHub:
+//
+//   - REST requests other than GET, HEAD and OPTIONS, except POST .../releases/generate-notes
+//   - GraphQL mutations
+//
+// Held back requests are recorded for the tool call they belong to and answered with a
+// placeholder response, so that the tool can carry on:
+//
+//   - REST placeholders only hold the SHA "dry-run-sha-<n>", which is all that push_files
+//     and delete_file, the only tools that build a write on the result of another, read.
+//   - GraphQL mutations are answered with {"data":{}}.
+//
+// Any other field, such as the number, id or html_url of a created issue, or the ID of a
+// created review, decodes as its zero value. Tools only put those in their own result,
+// which dryRunMiddleware replaces with the held back requests.
+type dryRunTransport struct {
+	transport http.RoundTripper
+}
+
+func (t *dryRunTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, err := readRequestBody(req)
+	if err != nil {
+		return nil, err
+	}
+
+	graphQL := strings.HasSuffix(req.URL.Path, "/graphql")
+	if !isWriteRequest(req.Method, req.URL.Path, graphQL, body) {
+		return t.transport.RoundTrip(req)
+	}
+
+	recorded := dryRunRequest{Method: req.Method, URL: req.URL.String()}
+	if len(body) > 0 {
+		var decoded any
+		contentType := req.Header.Get("Content-Type")
+		switch {
+		case json.Unmarshal(body, &decoded) == nil:
+			recorded.Body = decoded
+		case contentType != "" && !strings.HasPrefix(contentType, "text/"):
+			// Such as release assets, which are binary and may be large
+			recorded.Body = fmt.Sprintf("%d bytes of %s", len(body), contentType)
+		default:
+			recorded.Body = string(body)
+		}
+	}
+
+	recorder, ok := req.Context().Value(dryRunCtxKey{}).(*dryRunRecorder)
+	if !ok {
+		recorder = &dryRunRecorder{}
+	}
+	n := recorder.record(recorded)
+	audit.SetDryRun(req.Context())
+
+	return placeholderResponse(req, graphQL, n), nil
+}
+
+// readRequestBody reads the body of req and replaces it, so that it can still be sent.
+func readRequestBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, nil
+	}
+	body, err := io.ReadAll(req.Body)
+	_ = req.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read request body: %w", err)
+	}
+	req.Body = io.NopCloser(bytes.NewReader(body))
+	return body, nil
+}
+
+// isWriteRequest reports whether a request would change anything on GitHub.
+func isWriteRequest(method, path string, graphQL bool, body []byte) bool {
+	if graphQL {
+		var query struct {
+			Query string `json:"query"`
+		}
+		if err := json.Unmarshal(body, &query); err != nil {
+			return true
+		}
+		return strings.HasPrefix(strings.TrimSpace(query.Query), "mutation")
+	}
+
+	switch {
+	case method == http.MethodGet, method == http.MethodHead, method == http.MethodOptions:
+		return false
+	case method == http.MethodPost && strings.HasSuffix(path, "/releases/generate-notes"):
+		return false
+	default:
+		return true
+	}
+}
+
+// placeholderStatus returns the status GitHub answers a REST write request with, which the
+// tools check: 201 for most POSTs, 202 for those that only start something, and 204 for
+// deletions and workflow dispatches.
+func placeholderStatus(method, path string) int {
+	switch {
+	case method == http.MethodDelete, strings.HasSuffix(path, "/dispatches"):
+		return http.StatusNoContent
+	case strings.HasSuffix(path, "/cancel"), strings.HasSuffix(path, "/forks"), strings.HasSuffix(path, "/update-branch"):
+		return http.StatusAccepted
+	case method == http.MethodPost:
+		return http.StatusCreated
+	default:
+		return http.StatusOK
+	}
+}
+
+func placeholderResponse(req *http.Request, graphQL bool, n int) *http.Response {
+	status := http.StatusOK
+	var body any
+	if graphQL {
+		body = map[string]any{"data": map[string]any{}}
+	} else {
+		status = placeholderStatus(req.Method, req.URL.Path)
+		if status != http.StatusNoContent {
+			body = map[string]any{"sha": fmt.Sprintf("dry-run-sha-%d", n)}
+		}
+	}
+
+	var data []byte
+	if body != nil {
+		data, _ = json.Marshal(body)
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", status, http.StatusText(status)),
+		StatusCode:    status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        http.Header{"Content-Type": []string{"application/json; charset=utf-8"}},
+		Body:          io.NopCloser(bytes.NewReader(data)),
+		ContentLength: int64(len(data)),
+		Request:       req,
+	}
+}
@@ -0,0 +1,368 @@
+package ghmcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/github/github-mcp-server/internal/ghfake"
+	"github.com/github/github-mcp-server/pkg/audit"
+	"github.com/github/github-mcp-server/pkg/translations"
+	mcpClient "github.com/mark3labs/mcp-go/client"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type dryRunOutput struct {
+	DryRun   bool            `json:"dry_run"`
+	Tool     string          `json:"tool"`
+	Requests []dryRunRequest `json:"requests"`
+	Error    string          `json:"error"`
+}
+
+func Test_DryRunTools(t *testing.T) {
+	polished := "# Hello, world\n"
+	fake, err := ghfake.New(&ghfake.Fixture{
+		Repositories: []ghfake.FixtureRepository{
+			{
+				Owner:  "octocat",
+				Name:   "hello-world",
+				Files:  map[string]string{"README.md": "# Hello\n"},
+				Issues: []ghfake.FixtureIssue{{Title: "Greeting is missing", Labels: []string{"bug"}}},
+				Branches: []ghfake.FixtureBranch{
+					{Name: "polish", Files: map[string]*string{"README.md": &polished}},
+				},
+				PullRequests: []ghfake.FixturePullRequest{
+					{FixtureIssue: ghfake.FixtureIssue{Title: "Polish the greeting"}, Head: "polish"},
+				},
+			},
+		},
+	})
+	require.NoError(t, err)
+	ts := httptest.NewServer(fake)
+	defer ts.Close()
+
+	ghServer, err := NewMCPServer(MCPServerConfig{
+		Token:           "token",
+		Host:            ts.URL,
+		EnabledToolsets: []string{"repos", "issues", "pull_requests"},
+		DryRun:          true,
+		Translator:      translations.NullTranslationHelper,
+	})
+	require.NoError(t, err)
+	client, err := mcpClient.NewInProcessClient(ghServer)
+	require.NoError(t, err)
+	defer func() { _ = client.Close() }()
+	initRequest := mcp.InitializeRequest{}
+	initRequest.Params.ProtocolVersion = "2025-03-26"
+	initRequest.Params.ClientInfo = mcp.Implementation{Name: "dry-run-test", Version: "0.0.1"}
+	_, err = client.Initialize(context.Background(), initRequest)
+	require.NoError(t, err)
+
+	callTool := func(name string, args map[string]any) dryRunOutput {
+		t.Helper()
+		request := mcp.CallToolRequest{}
+		request.Params.Name = name
+		request.Params.Arguments = args
+		result, err := client.CallTool(context.Background(), request)
+		require.NoError(t, err)
+		text := result.Content[0].(mcp.TextContent).Text
+		require.False(t, result.IsError, text)
+
+		var output dryRunOutput
+		require.NoError(t, json.Unmarshal([]byte(text), &output), text)
+		assert.True(t, output.DryRun)
+		assert.Equal(t, name, output.Tool)
+		assert.Empty(t, output.Error)
+		return output
+	}
+	get := func(path string, v any) {
+		t.Helper()
+		req, err := http.NewRequest(http.MethodGet, ts.URL+"/api/v3"+path, nil)
+		require.NoError(t, err)
+		req.Header.Set("Authorization", "Bearer token")
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		defer func() { _ = resp.Body.Close() }()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(v))
+	}
+	var ref struct {
+		Object struct {
+			SHA string `json:"sha"`
+		} `json:"object"`
+	}
+	get("/repos/octocat/hello-world/git/ref/heads/main", &ref)
+	head := ref.Object.SHA
+
+	t.Run("push_files", func(t *testing.T) {
+		output := callTool("push_files", map[string]any{
+			"owner":   "octocat",
+			"repo":    "hello-world",
+			"branch":  "main",
+			"message": "Add a greeting",
+			"files":   []any{map[string]any{"path": "hello.txt", "content": "Hello, world!\n"}},
+		})
+
+		require.Len(t, output.Requests, 3)
+		assert.Equal(t, http.MethodPost, output.Requests[0].Method)
+		assert.Equal(t, ts.URL+"/api/v3/repos/octocat/hello-world/git/trees", output.Requests[0].URL)
+		assert.Equal(t, http.MethodPost, output.Requests[1].Method)
+		assert.Equal(t, ts.URL+"/api/v3/repos/octocat/hello-world/git/commits", output.Requests[1].URL)
+		commit := output.Requests[1].Body.(map[string]any)
+		assert.Equal(t, "dry-run-sha-1", commit["tree"], "the commit is built on the placeholder tree")
+		assert.Equal(t, []any{head}, commit["parents"])
+		assert.Equal(t, http.MethodPatch, output.Requests[2].Method)
+		assert.Equal(t, ts.URL+"/api/v3/repos/octocat/hello-world/git/refs/heads/main", output.Requests[2].URL)
+		assert.Equal(t, "dry-run-sha-2", output.Requests[2].Body.(map[string]any)["sha"])
+
+		get("/repos/octocat/hello-world/git/ref/heads/main", &ref)
+		assert.Equal(t, head, ref.Object.SHA, "the branch is not moved")
+	})
+
+	t.Run("create_or_update_file", func(t *testing.T) {
+		output := callTool("create_or_update_file", map[string]any{
+			"owner":   "octocat",
+			"repo":    "hello-world",
+			"path":    "docs/guide.md",
+			"content": "# Guide\n",
+			"message": "Add a guide",
+			"branch":  "main",
+		})
+
+		require.Len(t, output.Requests, 1)
+		assert.Equal(t, http.MethodPut, output.Requests[0].Method)
+		assert.Equal(t, ts.URL+"/api/v3/repos/octocat/hello-world/contents/docs/guide.md", output.Requests[0].URL)
+		assert.Equal(t, "IyBHdWlkZQo=", output.Requests[0].Body.(map[string]any)["content"])
+
+		get("/repos/octocat/hello-world/git/ref/heads/main", &ref)
+		assert.Equal(t, head, ref.Object.SHA, "no commit is made")
+	})
+
+	t.Run("create_issue", func(t *testing.T) {
+		output := callTool("create_issue", map[string]any{
+			"owner":  "octocat",
+			"repo":   "hello-world",
+			"title":  "Greeting is rude",
+			"labels": []any{"bug", "good first issue"},
+		})
+
+		require.Len(t, output.Requests, 1)
+		assert.Equal(t, http.MethodPost, output.Requests[0].Method)
+		assert.Equal(t, ts.URL+"/api/v3/repos/octocat/hello-world/issues", output.Requests[0].URL)
+		assert.Equal(t, []any{"bug", "good first issue"}, output.Requests[0].Body.(map[string]any)["labels"])
+
+		var issues []any
+		get("/repos/octocat/hello-world/issues", &issues)
+		assert.Len(t, issues, 2, "no issue is created besides the issue and pull request of the fixture")
+	})
+
+	t.Run("update_issue", func(t *testing.T) {
+		output := callTool("update_issue", map[string]any{
+			"owner":        "octocat",
+			"repo":         "hello-world",
+			"issue_number": 1,
+			"labels":       []any{"enhancement"},
+		})
+
+		require.Len(t, output.Requests, 1)
+		assert.Equal(t, http.MethodPatch, output.Requests[0].Method)
+		assert.Equal(t, ts.URL+"/api/v3/repos/octocat/hello-world/issues/1", output.Requests[0].URL)
+
+		var issue struct {
+			Labels []struct {
+				Name string `json:"name"`
+			} `json:"labels"`
+		}
+		get("/repos/octocat/hello-world/issues/1", &issue)
+		require.Len(t, issue.Labels, 1)
+		assert.Equal(t, "bug", issue.Labels[0].Name, "the labels are not changed")
+	})
+
+	t.Run("create_pull_request", func(t *testing.T) {
+		// The tool returns the number and html_url of the created pull request, which the
+		// placeholder lacks, but its result is replaced with the held back request
+		output := callTool("create_pull_request", map[string]any{
+			"owner": "octocat",
+			"repo":  "hello-world",
+			"title": "Polish the greeting again",
+			"head":  "polish",
+			"base":  "main",
+		})
+
+		require.Len(t, output.Requests, 1)
+		assert.Equal(t, http.MethodPost, output.Requests[0].Method)
+		assert.Equal(t, ts.URL+"/api/v3/repos/octocat/hello-world/pulls", output.Requests[0].URL)
+		assert.Equal(t, "polish", output.Requests[0].Body.(map[string]any)["head"])
+
+		var pulls []any
+		get("/repos/octocat/hello-world/pulls", &pulls)
+		assert.Len(t, pulls, 1, "no pull request is created")
+	})
+
+	t.Run("create_pending_pull_request_review", func(t *testing.T) {
+		// The pull request is looked up for real, and the mutation answered with empty data
+		output := callTool("create_pending_pull_request_review", map[string]any{
+			"owner":      "octocat",
+			"repo":       "hello-world",
+			"pullNumber": 2,
+		})
+
+		require.Len(t, output.Requests, 1)
+		assert.Equal(t, http.MethodPost, output.Requests[0].Method)
+		assert.Equal(t, ts.URL+"/api/graphql", output.Requests[0].URL)
+		body := output.Requests[0].Body.(map[string]any)
+		assert.True(t, strings.HasPrefix(body["query"].(string), "mutation"))
+		assert.NotEmpty(t, body["variables"].(map[string]any)["input"].(map[string]any)["pullRequestId"])
+
+		var reviews []any
+		get("/repos/octocat/hello-world/pulls/2/reviews", &reviews)
+		assert.Empty(t, reviews, "no review is created")
+	})
+}
+
+func Test_DryRunAudit(t *testing.T) {
+	fake, err := ghfake.New(&ghfake.Fixture{
+		Repositories: []ghfake.FixtureRepository{{Owner: "octocat", Name: "hello-world"}},
+	})
+	require.NoError(t, err)
+	ts := httptest.NewServer(fake)
+	defer ts.Close()
+
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	auditLog, err := audit.Open(path, false)
+	require.NoError(t, err)
+
+	ghServer, err := NewMCPServer(MCPServerConfig{
+		Token:           "token",
+		Host:            ts.URL,
+		EnabledToolsets: []string{"issues"},
+		DryRun:          true,
+		Audit:           auditLog,
+		Translator:      translations.NullTranslationHelper,
+	})
+	require.NoError(t, err)
+
+	session := newTestSession("session-1")
+	require.NoError(t, ghServer.RegisterSession(context.Background(), session))
+	text, isError := callTool(t, ghServer, session, "create_issue", map[string]any{"owner": "octocat", "repo": "hello-world", "title": "Bug"})
+	require.False(t, isError, text)
+	_, isError = callTool(t, ghServer, session, "create_issue", map[string]any{"owner": "octocat"})
+	require.True(t, isError)
+	require.NoError(t, auditLog.Close())
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	require.Len(t, lines, 2)
+	records := make([]audit.Record, len(lines))
+	for i, line := range lines {
+		require.NoError(t, json.Unmarshal([]byte(line), &records[i]))
+	}
+
+	// Only the call that held a request back is a dry run, the other one failed before
+	assert.Equal(t, audit.OutcomeDryRun, records[0].Outcome)
+	assert.Empty(t, records[0].Resources)
+	assert.Equal(t, audit.OutcomeToolError, records[1].Outcome)
+}
+
+func Test_isWriteRequest(t *testing.T) {
+	tests := []struct {
+		name    string
+		method  string
+		path    string
+		graphQL bool
+		body    string
+		write   bool
+	}{
+		{name: "GET", method: http.MethodGet, path: "/repos/octocat/hello-world"},
+		{name: "HEAD", method: http.MethodHead, path: "/repos/octocat/hello-world"},
+		{name: "POST", method: http.MethodPost, path: "/repos/octocat/hello-world/issues", write: true},
+		{name: "DELETE", method: http.MethodDelete, path: "/repos/octocat/hello-world/git/refs/heads/old", write: true},
+		{name: "release notes", method: http.MethodPost, path: "/repos/octocat/hello-world/releases/generate-notes"},
+		{name: "GraphQL query", method: http.MethodPost, path: "/graphql", graphQL: true, body: `{"query":"query { viewer { login } }"}`},
+		{name: "GraphQL mutation", method: http.MethodPost, path: "/graphql", graphQL: true, body: `{"query":"mutation { addStar { clientMutationId } }"}`, write: true},
+		{name: "unreadable GraphQL", method: http.MethodPost, path: "/graphql", graphQL: true, body: `mutation`, write: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.write, isWriteRequest(tc.method, tc.path, tc.graphQL, []byte(tc.body)))
+		})
+	}
+}
+
+func Test_DryRunMiddleware(t *testing.T) {
+	heldBack := dryRunRequest{Method: http.MethodPost, URL: "https://api.github.com/repos/octocat/hello-world/labels"}
+	tests := []struct {
+		name          string
+		record        bool
+		result        *mcp.CallToolResult
+		err           error
+		passesThrough bool
+		expectedError string
+	}{
+		{
+			name:          "tool error",
+			result:        mcp.NewToolResultError("missing required parameter: owner"),
+			passesThrough: true,
+		},
+		{
+			name:          "no requests held back",
+			result:        mcp.NewToolResultText("read only"),
+			passesThrough: true,
+		},
+		{
+			name:   "requests held back",
+			record: true,
+			result: mcp.NewToolResultText("created"),
+		},
+		{
+			name:          "error on a placeholder response",
+			record:        true,
+			err:           errors.New("failed to add labels: json: cannot unmarshal object into Go value of type []*github.Label"),
+			expectedError: "failed to add labels: json: cannot unmarshal object into Go value of type []*github.Label",
+		},
+		{
+			name:          "tool error on a placeholder response",
+			record:        true,
+			result:        mcp.NewToolResultError("unexpected response"),
+			expectedError: "unexpected response",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			handler := dryRunMiddleware(func(ctx context.Context, _ mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+				if tc.record {
+					ctx.Value(dryRunCtxKey{}).(*dryRunRecorder).record(heldBack)
+				}
+				return tc.result, tc.err
+			})
+
+			request := mcp.CallToolRequest{}
+			request.Params.Name = "add_labels"
+			result, err := handler(context.Background(), request)
+			if tc.passesThrough {
+				require.NoError(t, err)
+				assert.Same(t, tc.result, result)
+				return
+			}
+			require.NoError(t, err)
+			require.False(t, result.IsError)
+
+			var output dryRunOutput
+			require.NoError(t, json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &output))
+			assert.Equal(t, "add_labels", output.Tool)
+			assert.Equal(t, []dryRunRequest{heldBack}, output.Requests)
+			assert.Equal(t, tc.expectedError, output.Error)
+		})
+	}
+}
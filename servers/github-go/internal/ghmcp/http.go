@@ -0,0 +1,300 @@
+package ghmcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	mcplog "github.com/github/github-mcp-server/pkg/log"
+	"github.com/github/github-mcp-server/pkg/toolsets"
+	"github.com/github/github-mcp-server/pkg/translations"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+const (
+	// DefaultHTTPAddress is the address the HTTP server listens on when none is configured.
+	// It only accepts local connections, other interfaces have to be listened on explicitly.
+	DefaultHTTPAddress = "127.0.0.1:8080"
+
+	// DefaultShutdownTimeout is how long in-flight requests are given to complete on shutdown.
+	DefaultShutdownTimeout = 10 * time.Second
+)
+
+type HTTPServerConfig struct {
+	// Version of the server
+	Version string
+
+	// GitHub Host to target for API requests (e.g. github.com or github.enterprise.com)
+	Host string
+
+	// GitHub Token to authenticate with the GitHub API when a request does not
+	// provide its own in the Authorization header, if AllowUnauthenticated is set.
+	Token string
+
+	// App authenticates as a GitHub App instead of with Token, if set
+	App *AppAuthConfig
+
+	// AllowUnauthenticated serves requests without an Authorization header with Token or App,
+	// rather than rejecting them. Anyone who can reach the server then acts with that token.
+	AllowUnauthenticated bool
+
+	// Accounts are additional named accounts that tools can act as via their "account" argument
+	Accounts []AccountConfig
+
+	// RateLimit controls how requests that hit a GitHub API rate limit are retried
+	RateLimit RateLimitConfig
+
+	// Cache configures the REST API response cache, which is disabled if nil
+	Cache *CacheConfig
+
+	// Audit configures the audit log of write tool calls, which is disabled if nil
+	Audit *AuditConfig
+
+	// MetricsAddress is the TCP address to serve Prometheus metrics on, which are disabled if empty
+	MetricsAddress string
+
+	// Tracing configures how traces of tool calls are exported, which are disabled if nil
+	Tracing *TracingConfig
+
+	// EnabledToolsets is a list of toolsets to enable
+	// See: https://github.com/github/github-mcp-server?tab=readme-ov-file#tool-configuration
+	EnabledToolsets []string
+
+	// Whether to enable dynamic toolsets
+	// See: https://github.com/github/github-mcp-server?tab=readme-ov-file#dynamic-tool-discovery
+	DynamicToolsets bool
+
+	// ReadOnly indicates if we should only register read-only tools
+	ReadOnly bool
+
+	// DryRun has write tools return the GitHub API requests they would send instead of sending them
+	DryRun bool
+
+	// Confirmation has the user confirm every call of a destructive tool, which is not required if nil
+	Confirmation *ConfirmationConfig
+
+	// Scope restricts the repositories tools and resources can touch, which is not restricted if nil
+	Scope *RepositoryScope
+
+	// ResponseBudget splits tool results that are too large into parts, which is disabled if nil
+	ResponseBudget *ResponseBudgetConfig
+
+	// Tools selects individual tools within the enabled toolsets
+	Tools toolsets.ToolFilter
+
+	// ToolDescriptions overrides the descriptions of tools by tool name
+	ToolDescriptions map[string]string
+
+	// Locales describes tools in the locale of each session, which only uses the built-in texts if nil
+	Locales *LocaleConfig
+
+	// ExportTranslations indicates if we should export translations
+	// See: https://github.com/github/github-mcp-server?tab=readme-ov-file#i18n--overriding-descriptions
+	ExportTranslations bool
+
+	// Path to the log file if not stderr
+	LogFilePath string
+
+	// Redaction configures how secrets are removed from log output
+	Redaction mcplog.RedactionConfig
+
+	// Address is the TCP address to listen on, e.g. "127.0.0.1:8080" or ":8080" for all interfaces
+	Address string
+
+	// ShutdownTimeout bounds how long in-flight requests get to complete once a shutdown signal is received
+	ShutdownTimeout time.Duration
+}
+
+// RunHTTPServer serves the MCP server over streamable HTTP at /mcp, with an SSE
+// fallback at /sse and /message for older clients, and a health check at /health.
+// It blocks until the process receives SIGINT or SIGTERM, then shuts down gracefully.
+func RunHTTPServer(cfg HTTPServerConfig) error {
+	// Create app context
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	hasFallbackToken := cfg.Token != "" || cfg.App != nil
+	if cfg.AllowUnauthenticated && !hasFallbackToken {
+		return errors.New("serving requests without an Authorization header requires a GitHub token or app to serve them with")
+	}
+
+	t, dumpTranslations := translations.TranslationHelper()
+
+	logrusLogger, _, err := newLogger(cfg.LogFilePath, cfg.Redaction)
+	if err != nil {
+		return err
+	}
+
+	// The cache is shared by all sessions, entries are kept apart by token
+	cache, err := newResponseCache(cfg.Cache)
+	if err != nil {
+		return err
+	}
+	defer logCacheStats(logrusLogger, cache)
+
+	auditLog, err := openAuditLog(cfg.Audit)
+	if err != nil {
+		return err
+	}
+	defer closeAuditLog(logrusLogger, auditLog)
+
+	metrics, err := startMetrics(ctx, cfg.MetricsAddress, logrusLogger)
+	if err != nil {
+		return err
+	}
+
+	tracing, err := newTracerProvider(ctx, cfg.Tracing, cfg.Version)
+	if err != nil {
+		return err
+	}
+	defer shutdownTracerProvider(logrusLogger, tracing)
+
+	ghServer, err := NewMCPServer(MCPServerConfig{
+		Version:          cfg.Version,
+		Host:             cfg.Host,
+		Token:            cfg.Token,
+		App:              cfg.App,
+		Accounts:         cfg.Accounts,
+		RateLimit:        cfg.RateLimit,
+		Cache:            cache,
+		Audit:            auditLog,
+		Metrics:          metrics,
+		Tracing:          tracing,
+		EnabledToolsets:  cfg.EnabledToolsets,
+		DynamicToolsets:  cfg.DynamicToolsets,
+		ReadOnly:         cfg.ReadOnly,
+		DryRun:           cfg.DryRun,
+		Confirmation:     cfg.Confirmation,
+		Scope:            cfg.Scope,
+		ResponseBudget:   cfg.ResponseBudget,
+		Tools:            cfg.Tools,
+		ToolDescriptions: cfg.ToolDescriptions,
+		Locales:          cfg.Locales,
+		Translator:       t,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create MCP server: %w", err)
+	}
+
+	if cfg.ExportTranslations {
+		// Once server is initialized, all translations are loaded
+		dumpTranslations()
+	}
+
+	address := cfg.Address
+	if address == "" {
+		address = DefaultHTTPAddress
+	}
+	shutdownTimeout := cfg.ShutdownTimeout
+	if shutdownTimeout <= 0 {
+		shutdownTimeout = DefaultShutdownTimeout
+	}
+
+	// Requests derive their context from baseCtx, so cancelling it after the shutdown
+	// grace period also ends long-lived streams that would otherwise hold the server open.
+	baseCtx, cancelBase := context.WithCancel(context.Background())
+	defer cancelBase()
+
+	mux := http.NewServeMux()
+	httpServer := &http.Server{
+		Addr:              address,
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+		ErrorLog:          log.New(logrusLogger.Writer(), "httpserver", 0),
+		BaseContext:       func(net.Listener) context.Context { return baseCtx },
+	}
+
+	streamableServer := server.NewStreamableHTTPServer(ghServer,
+		server.WithStreamableHTTPServer(httpServer),
+		server.WithLogger(logrusLogger),
+	)
+	sseServer := server.NewSSEServer(ghServer,
+		server.WithHTTPServer(httpServer),
+		server.WithKeepAlive(true),
+	)
+
+	// Requests without a token of their own only get the server's token when the operator opted in
+	mux.Handle("/mcp", withRequestToken(streamableServer, cfg.AllowUnauthenticated))
+	mux.Handle(sseServer.CompleteSsePath(), withRequestToken(sseServer.SSEHandler(), cfg.AllowUnauthenticated))
+	mux.Handle(sseServer.CompleteMessagePath(), withRequestToken(sseServer.MessageHandler(), cfg.AllowUnauthenticated))
+	mux.HandleFunc("/health", healthHandler(cfg.Version))
+
+	errC := make(chan error, 1)
+	go func() {
+		logrusLogger.Infof("GitHub MCP server listening on %s", address)
+		errC <- httpServer.ListenAndServe()
+	}()
+
+	// Wait for shutdown signal
+	select {
+	case <-ctx.Done():
+		logrusLogger.Infof("shutting down server...")
+	case err := <-errC:
+		if err != nil && !errors.Is(err, http.ErrServerClosed) {
+			return fmt.Errorf("error running server: %w", err)
+		}
+		return nil
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+
+	// Shutting down the SSE server closes its open event streams before shutting down
+	// the shared HTTP server, which then waits for in-flight requests to drain.
+	if err := sseServer.Shutdown(shutdownCtx); err != nil {
+		cancelBase()
+		_ = httpServer.Close()
+		if errors.Is(err, context.DeadlineExceeded) {
+			logrusLogger.Warnf("graceful shutdown timed out after %s, closed remaining connections", shutdownTimeout)
+			return nil
+		}
+		return fmt.Errorf("error shutting down server: %w", err)
+	}
+
+	return nil
+}
+
+// withRequestToken makes the token from the request's Authorization header available to
+// the GitHub clients used while serving it. Unless requests may fall back on the server-wide
+// token, requests without one are rejected before they reach the MCP server.
+func withRequestToken(next http.Handler, allowUnauthenticated bool) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token := tokenFromRequest(r)
+		if token == "" {
+			if !allowUnauthenticated {
+				w.Header().Set("WWW-Authenticate", `Bearer realm="github-mcp-server"`)
+				http.Error(w, "missing GitHub token in Authorization header", http.StatusUnauthorized)
+				return
+			}
+			next.ServeHTTP(w, r)
+			return
+		}
+		next.ServeHTTP(w, r.WithContext(ContextWithToken(r.Context(), token)))
+	})
+}
+
+// healthHandler reports that the server is up and able to accept MCP connections.
+func healthHandler(version string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			w.Header().Set("Allow", "GET, HEAD")
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		_ = json.NewEncoder(w).Encode(map[string]string{
+			"status":  "ok",
+			"version": version,
+		})
+	}
+}
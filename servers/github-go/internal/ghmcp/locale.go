@@ -0,0 +1,175 @@
+package ghmcp
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/github/github-mcp-server/pkg/github"
+	"github.com/github/github-mcp-server/pkg/toolsets"
+	"github.com/github/github-mcp-server/pkg/translations"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// LocaleConfig selects the locale that tools are described in for each session.
+type LocaleConfig struct {
+	// Dir is the locale directory, with one JSON file of translations per locale
+	Dir string
+
+	// Default is the locale of sessions whose client has none of its own. The built-in texts
+	// are used if empty
+	Default string
+
+	// Clients maps the names that clients send in their client info to the locale of their
+	// sessions. Names are matched case-insensitively
+	Clients map[string]string
+}
+
+// load reads the locale directory and checks that every configured locale can be shown.
+func (c LocaleConfig) load() (*translations.Locales, error) {
+	locales, err := translations.LoadLocales(c.Dir)
+	if err != nil {
+		return nil, err
+	}
+	if c.Default != "" && !locales.Has(c.Default) {
+		return nil, fmt.Errorf("locale %q not found in %s", c.Default, c.Dir)
+	}
+	for client, locale := range c.Clients {
+		if !locales.Has(locale) {
+			return nil, fmt.Errorf("locale %q of client %q not found in %s", locale, client, c.Dir)
+		}
+	}
+	return locales, nil
+}
+
+// defaultLocale returns the locale of sessions whose client has none of its own.
+func (c LocaleConfig) defaultLocale() string {
+	if c.Default == "" {
+		return translations.DefaultLocale
+	}
+	return c.Default
+}
+
+// toolText is the part of a tool that is translated.
+type toolText struct {
+	description string
+	title       string
+}
+
+// sessionLocales describes tools in the locale of the client of each session. Sessions in the
+// default locale see the tools as they were registered.
+type sessionLocales struct {
+	defaultLocale string
+
+	// clients maps lowercased client names to their locale
+	clients map[string]string
+
+	// texts holds the translated tool texts by locale and tool name, for every locale that
+	// isn't the default
+	texts map[string]map[string]toolText
+
+	// overridden names the tools whose description is configured, which is never translated
+	overridden map[string]string
+}
+
+// newSessionLocales translates the tool texts for every locale of a client that differs from
+// the default. fallback provides the texts that no locale file translates.
+func newSessionLocales(cfg LocaleConfig, locales *translations.Locales, fallback translations.TranslationHelperFunc, overridden map[string]string) (*sessionLocales, error) {
+	s := &sessionLocales{
+		defaultLocale: translations.NormalizeLocale(cfg.defaultLocale()),
+		clients:       make(map[string]string, len(cfg.Clients)),
+		texts:         make(map[string]map[string]toolText),
+		overridden:    overridden,
+	}
+	for client, locale := range cfg.Clients {
+		locale = translations.NormalizeLocale(locale)
+		s.clients[strings.ToLower(client)] = locale
+		if _, ok := s.texts[locale]; ok || locale == s.defaultLocale {
+			continue
+		}
+		texts, err := toolTexts(locales.Helper(locale, fallback))
+		if err != nil {
+			return nil, fmt.Errorf("failed to translate tools to %s: %w", locale, err)
+		}
+		s.texts[locale] = texts
+	}
+	return s, nil
+}
+
+// toolTexts returns the texts of every tool the server can offer as translated by t, by tool name.
+func toolTexts(t translations.TranslationHelperFunc) (map[string]toolText, error) {
+	tsg, err := github.InitToolsets(nil, false, nil, nil, nil, t)
+	if err != nil {
+		return nil, err
+	}
+
+	all := []*toolsets.Toolset{
+		github.InitContextToolset(nil, nil, nil, t),
+		github.InitDynamicToolset(nil, toolsets.NewSessionToolsets(tsg), t),
+	}
+	for _, toolset := range tsg.Toolsets {
+		all = append(all, toolset)
+	}
+
+	texts := make(map[string]toolText)
+	for _, toolset := range all {
+		for _, tool := range toolset.GetAvailableTools() {
+			texts[tool.Tool.Name] = toolText{description: tool.Tool.Description, title: tool.Tool.Annotations.Title}
+		}
+	}
+	continueResult, _ := github.ContinueResult(nil, t)
+	texts[continueResult.Name] = toolText{description: continueResult.Description, title: continueResult.Annotations.Title}
+	return texts, nil
+}
+
+// localeOf returns the locale of the session in ctx, by the name of its client.
+func (s *sessionLocales) localeOf(ctx context.Context) string {
+	session, ok := server.ClientSessionFromContext(ctx).(server.SessionWithClientInfo)
+	if !ok {
+		return s.defaultLocale
+	}
+	if locale, ok := s.clients[strings.ToLower(session.GetClientInfo().Name)]; ok {
+		return locale
+	}
+	return s.defaultLocale
+}
+
+// filterTools describes the listed tools in the locale of the session.
+func (s *sessionLocales) filterTools(ctx context.Context, tools []mcp.Tool) []mcp.Tool {
+	texts, ok := s.texts[s.localeOf(ctx)]
+	if !ok {
+		return tools
+	}
+
+	localized := make([]mcp.Tool, len(tools))
+	for i, tool := range tools {
+		if text, ok := texts[tool.Name]; ok {
+			if _, overridden := s.overridden[tool.Name]; !overridden {
+				tool.Description = text.description
+			}
+			tool.Annotations.Title = text.title
+		}
+		localized[i] = tool
+	}
+	return localized
+}
+
+// TranslationKeys returns the built-in text of every translation key the server uses, with all
+// of its tools and resources.
+func TranslationKeys() (map[string]string, error) {
+	keys := make(map[string]string)
+	_, err := NewMCPServer(MCPServerConfig{
+		EnabledToolsets: []string{"all"},
+		DynamicToolsets: true,
+		ResponseBudget:  &ResponseBudgetConfig{MaxBytes: minResponseBudget},
+		Translator: func(key string, defaultValue string) string {
+			keys[strings.ToUpper(key)] = defaultValue
+			return defaultValue
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return keys, nil
+}
@@ -0,0 +1,76 @@
+package ghmcp
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/github/github-mcp-server/pkg/translations"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_NewMCPServerLocales(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "fr.json"), []byte(`{
+		"TOOL_GET_ME_DESCRIPTION": "Obtenir les détails de l'utilisateur authentifié",
+		"TOOL_GET_ME_USER_TITLE": "Mon profil",
+		"TOOL_GET_PULL_REQUEST_DESCRIPTION": "Obtenir une pull request"
+	}`), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "de.json"), []byte(`{
+		"TOOL_GET_ME_DESCRIPTION": "Details des angemeldeten Benutzers abrufen"
+	}`), 0600))
+
+	cfg := MCPServerConfig{
+		Version:         "test",
+		Token:           "token",
+		EnabledToolsets: []string{"pull_requests"},
+		ToolDescriptions: map[string]string{
+			"get_pull_request": "Look up a pull request",
+		},
+		Locales: &LocaleConfig{
+			Dir:     dir,
+			Default: "de",
+			Clients: map[string]string{"French Client": "fr_FR"},
+		},
+		Translator: translations.NullTranslationHelper,
+	}
+	ghServer, err := NewMCPServer(cfg)
+	require.NoError(t, err)
+
+	session := func(name string) server.ClientSession {
+		session := server.NewInProcessSession(name, nil)
+		session.SetClientInfo(mcp.Implementation{Name: name, Version: "1.0.0"})
+		return session
+	}
+
+	tools := listTools(t, ghServer, session("Other Client"))
+	assert.Equal(t, "Details des angemeldeten Benutzers abrufen", tools["get_me"].Description, "the default locale is used")
+	assert.Equal(t, "Get my user profile", tools["get_me"].Annotations.Title, "texts the locale lacks are built in")
+
+	tools = listTools(t, ghServer, session("french client"))
+	assert.Equal(t, "Obtenir les détails de l'utilisateur authentifié", tools["get_me"].Description, "the base language file is used for fr-FR")
+	assert.Equal(t, "Mon profil", tools["get_me"].Annotations.Title)
+	assert.Equal(t, "Look up a pull request", tools["get_pull_request"].Description, "configured descriptions are not translated")
+	assert.NotEmpty(t, tools["merge_pull_request"].Description)
+
+	t.Run("unknown locale", func(t *testing.T) {
+		cfg := cfg
+		cfg.Locales = &LocaleConfig{Dir: dir, Clients: map[string]string{"client": "ja"}}
+		_, err := NewMCPServer(cfg)
+		assert.ErrorContains(t, err, `locale "ja" of client "client" not found`)
+	})
+}
+
+func Test_TranslationKeys(t *testing.T) {
+	keys, err := TranslationKeys()
+	require.NoError(t, err)
+
+	assert.Equal(t, "Get my user profile", keys["TOOL_GET_ME_USER_TITLE"])
+	assert.Contains(t, keys, "TOOL_CONTINUE_RESULT_DESCRIPTION")
+	assert.Contains(t, keys, "TOOL_ENABLE_TOOLSET_DESCRIPTION")
+	assert.Contains(t, keys, "TOOL_MERGE_PULL_REQUEST_DESCRIPTION")
+	assert.Contains(t, keys, "RESOURCE_REPOSITORY_CONTENT_DESCRIPTION")
+}
@@ -0,0 +1,151 @@
+package ghmcp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/sirupsen/logrus"
+)
+
+// Metrics collects Prometheus metrics about tool calls and the GitHub API requests they make.
+type Metrics struct {
+	registry *prometheus.Registry
+
+	toolCalls        *prometheus.CounterVec
+	toolCallDuration *prometheus.HistogramVec
+	upstreamRequests *prometheus.CounterVec
+	rateLimit        *prometheus.GaugeVec
+}
+
+// NewMetrics creates the metrics in a registry of their own, along with the Go runtime
+// and process metrics.
+func NewMetrics() *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		toolCalls: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "github_mcp_tool_calls_total",
+			Help: "Tool calls by tool and outcome, which is success, tool_error or failure.",
+		}, []string{"tool", "outcome"}),
+		toolCallDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "github_mcp_tool_call_duration_seconds",
+			Help:    "How long tool calls took, by tool.",
+			Buckets: []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60},
+		}, []string{"tool"}),
+		upstreamRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "github_mcp_upstream_requests_total",
+			Help: "Requests made to the GitHub API by API, rest or graphql, and HTTP status code, which is 0 for requests that got no response.",
+		}, []string{"api", "code"}),
+		rateLimit: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "github_mcp_rate_limit_remaining",
+			Help: "Requests remaining in the current GitHub API rate limit window by account, app installation and resource, as last reported by the API.",
+		}, []string{"account", "installation", "resource"}),
+	}
+
+	m.registry.MustRegister(
+		m.toolCalls,
+		m.toolCallDuration,
+		m.upstreamRequests,
+		m.rateLimit,
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+	return m
+}
+
+// Handler serves the metrics in the Prometheus text format, or in OpenMetrics to
+// scrapers that ask for it.
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{EnableOpenMetrics: true})
+}
+
+// ToolMiddleware counts and times the calls of the tools it wraps.
+func (m *Metrics) ToolMiddleware(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		start := time.Now()
+		result, err := next(ctx, request)
+
+		outcome := "success"
+		switch {
+		case err != nil:
+			outcome = "failure"
+		case result != nil && result.IsError:
+			outcome = "tool_error"
+		}
+
+		tool := request.Params.Name
+		m.toolCalls.WithLabelValues(tool, outcome).Inc()
+		m.toolCallDuration.WithLabelValues(tool).Observe(time.Since(start).Seconds())
+		return result, err
+	}
+}
+
+// metricsTransport counts the requests made to the GitHub API and records the remaining
+// rate limit it reports for the account and app installation the requests are made as.
+// Rate limits of tokens that sessions bring are not recorded, leaving account empty.
+type metricsTransport struct {
+	transport    http.RoundTripper
+	metrics      *Metrics
+	account      string
+	installation string
+}
+
+func (t *metricsTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	api := "rest"
+	if strings.HasSuffix(req.URL.Path, "/graphql") {
+		api = "graphql"
+	}
+
+	resp, err := t.transport.RoundTrip(req)
+	if err != nil {
+		t.metrics.upstreamRequests.WithLabelValues(api, "0").Inc()
+		return nil, err
+	}
+	t.metrics.upstreamRequests.WithLabelValues(api, strconv.Itoa(resp.StatusCode)).Inc()
+
+	if remaining, err := strconv.Atoi(resp.Header.Get("X-RateLimit-Remaining")); err == nil && t.account != "" {
+		resource := resp.Header.Get("X-RateLimit-Resource")
+		if resource == "" {
+			resource = "core"
+		}
+		t.metrics.rateLimit.WithLabelValues(t.account, t.installation, resource).Set(float64(remaining))
+	}
+	return resp, nil
+}
+
+// serveMetrics serves the metrics at /metrics on address until ctx is done.
+func serveMetrics(ctx context.Context, address string, metrics *Metrics, logger *logrus.Logger) error {
+	listener, err := net.Listen("tcp", address)
+	if err != nil {
+		return fmt.Errorf("failed to listen for metrics on %s: %w", address, err)
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", metrics.Handler())
+	httpServer := &http.Server{
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	go func() {
+		<-ctx.Done()
+		_ = httpServer.Close()
+	}()
+	go func() {
+		logger.Infof("serving metrics on %s/metrics", listener.Addr())
+		if err := httpServer.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			logger.WithError(err).Error("metrics server failed")
+		}
+	}()
+	return nil
+}
@@ -0,0 +1,99 @@
+package ghmcp
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_MetricsToolMiddleware(t *testing.T) {
+	metrics := NewMetrics()
+	handler := metrics.ToolMiddleware(func(_ context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		switch request.Params.Name {
+		case "get_me":
+			return mcp.NewToolResultText("{}"), nil
+		case "get_issue":
+			return mcp.NewToolResultError("not found"), nil
+		default:
+			return nil, errors.New("boom")
+		}
+	})
+
+	for _, name := range []string{"get_me", "get_me", "get_issue", "create_issue"} {
+		request := mcp.CallToolRequest{}
+		request.Params.Name = name
+		_, _ = handler(context.Background(), request)
+	}
+
+	assert.Equal(t, float64(2), testutil.ToFloat64(metrics.toolCalls.WithLabelValues("get_me", "success")))
+	assert.Equal(t, float64(1), testutil.ToFloat64(metrics.toolCalls.WithLabelValues("get_issue", "tool_error")))
+	assert.Equal(t, float64(1), testutil.ToFloat64(metrics.toolCalls.WithLabelValues("create_issue", "failure")))
+	assert.Equal(t, 3, testutil.CollectAndCount(metrics.toolCallDuration))
+}
+
+func Test_MetricsTransport(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/graphql" {
+			w.Header().Set("X-RateLimit-Resource", "graphql")
+			w.Header().Set("X-RateLimit-Remaining", "4000")
+			return
+		}
+		w.Header().Set("X-RateLimit-Remaining", "4999")
+		if remaining := r.URL.Query().Get("remaining"); remaining != "" {
+			w.Header().Set("X-RateLimit-Remaining", remaining)
+		}
+		if r.URL.Path == "/missing" {
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer ts.Close()
+
+	metrics := NewMetrics()
+	get := func(transport *metricsTransport, path string) {
+		t.Helper()
+		resp, err := (&http.Client{Transport: transport}).Get(ts.URL + path)
+		require.NoError(t, err)
+		_ = resp.Body.Close()
+	}
+	account := &metricsTransport{transport: http.DefaultTransport, metrics: metrics, account: "default"}
+	for _, path := range []string{"/user", "/user", "/missing", "/graphql"} {
+		get(account, path)
+	}
+
+	assert.Equal(t, float64(2), testutil.ToFloat64(metrics.upstreamRequests.WithLabelValues("rest", "200")))
+	assert.Equal(t, float64(1), testutil.ToFloat64(metrics.upstreamRequests.WithLabelValues("rest", "404")))
+	assert.Equal(t, float64(1), testutil.ToFloat64(metrics.upstreamRequests.WithLabelValues("graphql", "200")))
+	assert.Equal(t, float64(4999), testutil.ToFloat64(metrics.rateLimit.WithLabelValues("default", "", "core")))
+	assert.Equal(t, float64(4000), testutil.ToFloat64(metrics.rateLimit.WithLabelValues("default", "", "graphql")))
+
+	// Each installation and account has a rate limit of its own, and those of the
+	// tokens sessions bring aren't recorded
+	get(&metricsTransport{transport: http.DefaultTransport, metrics: metrics, account: "default", installation: "42"}, "/user?remaining=12")
+	get(&metricsTransport{transport: http.DefaultTransport, metrics: metrics, account: "work"}, "/user?remaining=34")
+	get(&metricsTransport{transport: http.DefaultTransport, metrics: metrics}, "/user?remaining=56")
+	assert.Equal(t, float64(12), testutil.ToFloat64(metrics.rateLimit.WithLabelValues("default", "42", "core")))
+	assert.Equal(t, float64(34), testutil.ToFloat64(metrics.rateLimit.WithLabelValues("work", "", "core")))
+	assert.Equal(t, float64(4999), testutil.ToFloat64(metrics.rateLimit.WithLabelValues("default", "", "core")))
+	assert.Equal(t, 4, testutil.CollectAndCount(metrics.rateLimit))
+
+	// The handler speaks OpenMetrics to scrapers that ask for it
+	recorder := httptest.NewRecorder()
+	request := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+	request.Header.Set("Accept", "application/openmetrics-text; version=1.0.0")
+	metrics.Handler().ServeHTTP(recorder, request)
+
+	body, err := io.ReadAll(recorder.Body)
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(recorder.Header().Get("Content-Type"), "application/openmetrics-text"))
+	assert.Contains(t, string(body), `github_mcp_upstream_requests_total{api="rest",code="404"} 1`)
+	assert.True(t, strings.HasSuffix(string(body), "# EOF\n"))
+}
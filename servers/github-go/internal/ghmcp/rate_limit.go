@@ -0,0 +1,328 @@
+package ghmcp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/github/github-mcp-server/pkg/github"
+	gogithub "github.com/google/go-github/v72/github"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+const (
+	// DefaultRateLimitMaxRetries is how many times a rate limited request is retried by default.
+	DefaultRateLimitMaxRetries = 3
+
+	// DefaultRateLimitMaxWait is how long a single request may spend waiting on rate limits by default.
+	DefaultRateLimitMaxWait = time.Minute
+
+	// secondaryRateLimitBackoff is how long to wait on a secondary rate limit that doesn't
+	// say when to retry. GitHub asks for at least a minute.
+	secondaryRateLimitBackoff = time.Minute
+
+	// maxRateLimitBodyPeek bounds how much of an error response is read to recognize a secondary rate limit.
+	maxRateLimitBodyPeek = 64 << 10
+)
+
+// RateLimitConfig controls how requests that hit a GitHub API rate limit are retried.
+type RateLimitConfig struct {
+	// MaxRetries is how many times a rate limited request is retried, 0 disables retries
+	MaxRetries int
+
+	// MaxWait is the total time a single request may spend waiting before it is retried
+	MaxWait time.Duration
+}
+
+// rateLimitTracker keeps the latest rate limit reported by the API for each resource.
+type rateLimitTracker struct {
+	mu     sync.Mutex
+	limits map[string]github.RateLimitStatus
+}
+
+func newRateLimitTracker() *rateLimitTracker {
+	return &rateLimitTracker{limits: make(map[string]github.RateLimitStatus)}
+}
+
+// observe records the X-RateLimit-* headers of resp, if it has any.
+func (t *rateLimitTracker) observe(resp *http.Response, now time.Time) {
+	limit, err := strconv.Atoi(resp.Header.Get("X-RateLimit-Limit"))
+	if err != nil {
+		return
+	}
+	remaining, _ := strconv.Atoi(resp.Header.Get("X-RateLimit-Remaining"))
+	used, _ := strconv.Atoi(resp.Header.Get("X-RateLimit-Used"))
+	reset, _ := strconv.ParseInt(resp.Header.Get("X-RateLimit-Reset"), 10, 64)
+
+	resource := resp.Header.Get("X-RateLimit-Resource")
+	if resource == "" {
+		resource = "core"
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.limits[resource] = github.RateLimitStatus{
+		Resource:   resource,
+		Limit:      limit,
+		Remaining:  remaining,
+		Used:       used,
+		Reset:      time.Unix(reset, 0).UTC(),
+		ObservedAt: now,
+	}
+}
+
+// snapshot returns the observed rate limits ordered by resource.
+func (t *rateLimitTracker) snapshot() []github.RateLimitStatus {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	limits := make([]github.RateLimitStatus, 0, len(t.limits))
+	for _, limit := range t.limits {
+		limits = append(limits, limit)
+	}
+	sort.Slice(limits, func(i, j int) bool { return limits[i].Resource < limits[j].Resource })
+	return limits
+}
+
+// rateLimitHit describes a rate limit that a request ran into and could not wait out.
+type rateLimitHit struct {
+	// Secondary is true for secondary (abuse) rate limits as opposed to the hourly quota
+	Secondary bool
+	RetryAt   time.Time
+}
+
+// rateLimitTransport retries requests that hit a rate limit, as long as the wait fits
+// in the configured budget, and records the rate limits reported on every response.
+type rateLimitTransport struct {
+	transport http.RoundTripper
+	config    RateLimitConfig
+	tracker   *rateLimitTracker
+
+	now   func() time.Time
+	sleep func(context.Context, time.Duration) error
+}
+
+func newRateLimitTransport(transport http.RoundTripper, config RateLimitConfig, tracker *rateLimitTracker) *rateLimitTransport {
+	return &rateLimitTransport{
+		transport: transport,
+		config:    config,
+		tracker:   tracker,
+		now:       time.Now,
+		sleep:     sleepContext,
+	}
+}
+
+func (t *rateLimitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	budget := t.config.MaxWait
+	for attempt := 0; ; attempt++ {
+		resp, err := t.transport.RoundTrip(req)
+		if err != nil {
+			return nil, err
+		}
+
+		now := t.now()
+		t.tracker.observe(resp, now)
+
+		hit, ok := rateLimited(resp, now, attempt)
+		if !ok {
+			return resp, nil
+		}
+
+		wait := hit.RetryAt.Sub(now)
+		replayable := req.Body == nil || req.Body == http.NoBody || req.GetBody != nil
+		if attempt >= t.config.MaxRetries || wait > budget || !replayable {
+			recordRateLimitHit(req.Context(), hit)
+			return resp, nil
+		}
+
+		_, _ = io.Copy(io.Discard, resp.Body)
+		_ = resp.Body.Close()
+
+		if err := t.sleep(req.Context(), wait); err != nil {
+			return nil, err
+		}
+		budget -= wait
+
+		req, err = rewindRequest(req)
+		if err != nil {
+			return nil, err
+		}
+	}
+}
+
+// parseRetryAfter returns when a Retry-After header value allows a retry. The value is
+// either a number of seconds or an HTTP date, which is not waited for if it has passed.
+func parseRetryAfter(value string, now time.Time) (time.Time, bool) {
+	if value == "" {
+		return time.Time{}, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return now.Add(time.Duration(seconds) * time.Second), true
+	}
+	date, err := http.ParseTime(value)
+	if err != nil {
+		return time.Time{}, false
+	}
+	if date.Before(now) {
+		return now, true
+	}
+	return date, true
+}
+
+// rateLimited reports whether resp is a rate limit response and, if so, when the
+// request may be retried. attempt is used to back off exponentially when the
+// response doesn't say.
+func rateLimited(resp *http.Response, now time.Time, attempt int) (rateLimitHit, bool) {
+	if resp.StatusCode != http.StatusForbidden && resp.StatusCode != http.StatusTooManyRequests {
+		return rateLimitHit{}, false
+	}
+
+	if retryAt, ok := parseRetryAfter(resp.Header.Get("Retry-After"), now); ok {
+		return rateLimitHit{Secondary: true, RetryAt: retryAt}, true
+	}
+
+	if resp.Header.Get("X-RateLimit-Remaining") == "0" {
+		if reset, err := strconv.ParseInt(resp.Header.Get("X-RateLimit-Reset"), 10, 64); err == nil {
+			// Allow a second of clock skew before the quota is back
+			return rateLimitHit{RetryAt: time.Unix(reset, 0).UTC().Add(time.Second)}, true
+		}
+	}
+
+	if mentionsSecondaryRateLimit(resp) {
+		backoff := secondaryRateLimitBackoff * time.Duration(math.Pow(2, float64(attempt)))
+		return rateLimitHit{Secondary: true, RetryAt: now.Add(backoff)}, true
+	}
+
+	return rateLimitHit{}, false
+}
+
+// mentionsSecondaryRateLimit checks the error message of resp, leaving the body readable.
+func mentionsSecondaryRateLimit(resp *http.Response) bool {
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxRateLimitBodyPeek))
+	resp.Body = struct {
+		io.Reader
+		io.Closer
+	}{io.MultiReader(bytes.NewReader(body), resp.Body), resp.Body}
+	if err != nil {
+		return false
+	}
+	return strings.Contains(strings.ToLower(string(body)), "secondary rate limit")
+}
+
+// rewindRequest returns a copy of req with a fresh body, so that it can be sent again.
+func rewindRequest(req *http.Request) (*http.Request, error) {
+	if req.GetBody == nil {
+		return req, nil
+	}
+	body, err := req.GetBody()
+	if err != nil {
+		return nil, err
+	}
+	retry := req.Clone(req.Context())
+	retry.Body = body
+	return retry, nil
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+type rateLimitHitCtxKey struct{}
+
+// rateLimitHits collects the rate limits hit by requests made during one tool call.
+type rateLimitHits struct {
+	mu   sync.Mutex
+	last *rateLimitHit
+}
+
+func recordRateLimitHit(ctx context.Context, hit rateLimitHit) {
+	if hits, ok := ctx.Value(rateLimitHitCtxKey{}).(*rateLimitHits); ok {
+		hits.mu.Lock()
+		hits.last = &hit
+		hits.mu.Unlock()
+	}
+}
+
+// rateLimitMiddleware turns failed tool calls that ran into a rate limit into a tool
+// error that tells the model when it can try again, rather than a generic failure.
+func rateLimitMiddleware(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		hits := &rateLimitHits{}
+		result, err := next(context.WithValue(ctx, rateLimitHitCtxKey{}, hits), request)
+		if err == nil && (result == nil || !result.IsError) {
+			return result, err
+		}
+
+		hits.mu.Lock()
+		hit := hits.last
+		hits.mu.Unlock()
+
+		if hit == nil {
+			hit = rateLimitHitFromError(err)
+		}
+		if hit == nil {
+			return result, err
+		}
+		return rateLimitResult(*hit, time.Now()), nil
+	}
+}
+
+// rateLimitHitFromError recognizes the rate limit errors go-github returns, which it
+// may do without sending the request when it already knows the quota is exhausted.
+func rateLimitHitFromError(err error) *rateLimitHit {
+	var rateLimitErr *gogithub.RateLimitError
+	if errors.As(err, &rateLimitErr) {
+		return &rateLimitHit{RetryAt: rateLimitErr.Rate.Reset.Time}
+	}
+	var abuseErr *gogithub.AbuseRateLimitError
+	if errors.As(err, &abuseErr) {
+		hit := &rateLimitHit{Secondary: true, RetryAt: time.Now().Add(secondaryRateLimitBackoff)}
+		if abuseErr.RetryAfter != nil {
+			hit.RetryAt = time.Now().Add(*abuseErr.RetryAfter)
+		}
+		return hit
+	}
+	return nil
+}
+
+func rateLimitResult(hit rateLimitHit, now time.Time) *mcp.CallToolResult {
+	kind, message := "primary", "GitHub API rate limit exceeded"
+	if hit.Secondary {
+		kind, message = "secondary", "GitHub API secondary rate limit exceeded"
+	}
+
+	retryAfter := int(math.Ceil(hit.RetryAt.Sub(now).Seconds()))
+	if retryAfter < 0 {
+		retryAfter = 0
+	}
+
+	r, _ := json.Marshal(struct {
+		Error             string    `json:"error"`
+		Kind              string    `json:"kind"`
+		RetryAt           time.Time `json:"retry_at"`
+		RetryAfterSeconds int       `json:"retry_after_seconds"`
+	}{
+		Error:             message + ", do not retry before retry_at",
+		Kind:              kind,
+		RetryAt:           hit.RetryAt.UTC().Truncate(time.Second),
+		RetryAfterSeconds: retryAfter,
+	})
+	return mcp.NewToolResultError(string(r))
+}
@@ -0,0 +1,330 @@
+package ghmcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	gogithub "github.com/google/go-github/v72/github"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// roundTripFunc adapts a function to http.RoundTripper.
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func rateLimitResponse(status int, headers map[string]string, body string) *http.Response {
+	resp := &http.Response{
+		StatusCode: status,
+		Header:     make(http.Header),
+		Body:       io.NopCloser(strings.NewReader(body)),
+	}
+	for key, value := range headers {
+		resp.Header.Set(key, value)
+	}
+	return resp
+}
+
+func Test_RateLimitTransport(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	ok := rateLimitResponse(http.StatusOK, map[string]string{
+		"X-RateLimit-Limit":     "5000",
+		"X-RateLimit-Remaining": "4999",
+		"X-RateLimit-Used":      "1",
+		"X-RateLimit-Reset":     strconv.FormatInt(now.Add(time.Hour).Unix(), 10),
+	}, `{}`)
+
+	tests := []struct {
+		name          string
+		config        RateLimitConfig
+		responses     []*http.Response
+		expectStatus  int
+		expectWaits   []time.Duration
+		expectHit     *rateLimitHit
+		expectAttempt int
+	}{
+		{
+			name:          "passes through responses that are not rate limited",
+			config:        RateLimitConfig{MaxRetries: 3, MaxWait: time.Minute},
+			responses:     []*http.Response{ok},
+			expectStatus:  http.StatusOK,
+			expectAttempt: 1,
+		},
+		{
+			name:   "waits out a secondary rate limit with retry-after",
+			config: RateLimitConfig{MaxRetries: 3, MaxWait: time.Minute},
+			responses: []*http.Response{
+				rateLimitResponse(http.StatusForbidden, map[string]string{"Retry-After": "10"}, `{"message": "You have exceeded a secondary rate limit"}`),
+				ok,
+			},
+			expectStatus:  http.StatusOK,
+			expectWaits:   []time.Duration{10 * time.Second},
+			expectAttempt: 2,
+		},
+		{
+			name:   "waits out a secondary rate limit with retry-after as a date",
+			config: RateLimitConfig{MaxRetries: 3, MaxWait: time.Minute},
+			responses: []*http.Response{
+				rateLimitResponse(http.StatusTooManyRequests, map[string]string{"Retry-After": now.Add(30 * time.Second).Format(http.TimeFormat)}, ``),
+				ok,
+			},
+			expectStatus:  http.StatusOK,
+			expectWaits:   []time.Duration{30 * time.Second},
+			expectAttempt: 2,
+		},
+		{
+			name:   "backs off on a secondary rate limit without retry-after",
+			config: RateLimitConfig{MaxRetries: 3, MaxWait: 5 * time.Minute},
+			responses: []*http.Response{
+				rateLimitResponse(http.StatusForbidden, nil, `{"message": "You have exceeded a secondary rate limit"}`),
+				rateLimitResponse(http.StatusForbidden, nil, `{"message": "You have exceeded a secondary rate limit"}`),
+				ok,
+			},
+			expectStatus:  http.StatusOK,
+			expectWaits:   []time.Duration{time.Minute, 2 * time.Minute},
+			expectAttempt: 3,
+		},
+		{
+			name:   "waits for the primary rate limit to reset when it fits the budget",
+			config: RateLimitConfig{MaxRetries: 3, MaxWait: time.Minute},
+			responses: []*http.Response{
+				rateLimitResponse(http.StatusForbidden, map[string]string{
+					"X-RateLimit-Limit":     "5000",
+					"X-RateLimit-Remaining": "0",
+					"X-RateLimit-Reset":     strconv.FormatInt(now.Add(20*time.Second).Unix(), 10),
+				}, `{"message": "API rate limit exceeded"}`),
+				ok,
+			},
+			expectStatus:  http.StatusOK,
+			expectWaits:   []time.Duration{21 * time.Second},
+			expectAttempt: 2,
+		},
+		{
+			name:   "gives up when the wait exceeds the budget",
+			config: RateLimitConfig{MaxRetries: 3, MaxWait: time.Minute},
+			responses: []*http.Response{
+				rateLimitResponse(http.StatusForbidden, map[string]string{
+					"X-RateLimit-Limit":     "5000",
+					"X-RateLimit-Remaining": "0",
+					"X-RateLimit-Reset":     strconv.FormatInt(now.Add(time.Hour).Unix(), 10),
+				}, `{"message": "API rate limit exceeded"}`),
+			},
+			expectStatus:  http.StatusForbidden,
+			expectHit:     &rateLimitHit{RetryAt: now.Add(time.Hour + time.Second)},
+			expectAttempt: 1,
+		},
+		{
+			name:   "gives up when retries are exhausted",
+			config: RateLimitConfig{MaxRetries: 1, MaxWait: time.Hour},
+			responses: []*http.Response{
+				rateLimitResponse(http.StatusTooManyRequests, map[string]string{"Retry-After": "5"}, ``),
+				rateLimitResponse(http.StatusTooManyRequests, map[string]string{"Retry-After": "7"}, ``),
+			},
+			expectStatus:  http.StatusTooManyRequests,
+			expectWaits:   []time.Duration{5 * time.Second},
+			expectHit:     &rateLimitHit{Secondary: true, RetryAt: now.Add(7 * time.Second)},
+			expectAttempt: 2,
+		},
+		{
+			name:   "leaves other forbidden responses alone",
+			config: RateLimitConfig{MaxRetries: 3, MaxWait: time.Minute},
+			responses: []*http.Response{
+				rateLimitResponse(http.StatusForbidden, nil, `{"message": "Resource not accessible by integration"}`),
+			},
+			expectStatus:  http.StatusForbidden,
+			expectAttempt: 1,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var bodies []string
+			attempt := 0
+			inner := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+				body, err := io.ReadAll(req.Body)
+				require.NoError(t, err)
+				bodies = append(bodies, string(body))
+
+				resp := tc.responses[attempt]
+				attempt++
+				return resp, nil
+			})
+
+			var waits []time.Duration
+			tracker := newRateLimitTracker()
+			transport := newRateLimitTransport(inner, tc.config, tracker)
+			transport.now = func() time.Time { return now }
+			transport.sleep = func(_ context.Context, d time.Duration) error {
+				waits = append(waits, d)
+				return nil
+			}
+
+			hits := &rateLimitHits{}
+			ctx := context.WithValue(context.Background(), rateLimitHitCtxKey{}, hits)
+			req, err := http.NewRequestWithContext(ctx, http.MethodPost, "https://api.github.com/graphql", strings.NewReader(`{"query": "{}"}`))
+			require.NoError(t, err)
+
+			resp, err := transport.RoundTrip(req)
+			require.NoError(t, err)
+			defer func() { _ = resp.Body.Close() }()
+
+			assert.Equal(t, tc.expectStatus, resp.StatusCode)
+			assert.Equal(t, tc.expectWaits, waits)
+			assert.Equal(t, tc.expectHit, hits.last)
+			assert.Equal(t, tc.expectAttempt, attempt)
+
+			// Every attempt sends the whole body
+			for _, body := range bodies {
+				assert.Equal(t, `{"query": "{}"}`, body)
+			}
+
+			// The body of the final response is still readable after being inspected
+			_, err = io.ReadAll(resp.Body)
+			require.NoError(t, err)
+		})
+	}
+}
+
+func Test_parseRetryAfter(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name          string
+		value         string
+		expectRetryAt time.Time
+		expectOK      bool
+	}{
+		{name: "seconds", value: "120", expectRetryAt: now.Add(2 * time.Minute), expectOK: true},
+		{name: "date", value: "Wed, 01 Jan 2025 12:01:00 GMT", expectRetryAt: now.Add(time.Minute), expectOK: true},
+		{name: "date in an obsolete format", value: "Wednesday, 01-Jan-25 12:01:00 GMT", expectRetryAt: now.Add(time.Minute), expectOK: true},
+		{name: "date that has passed", value: "Wed, 01 Jan 2025 11:59:00 GMT", expectRetryAt: now, expectOK: true},
+		{name: "missing", value: ""},
+		{name: "invalid", value: "soon"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			retryAt, ok := parseRetryAfter(tc.value, now)
+			assert.Equal(t, tc.expectOK, ok)
+			assert.True(t, tc.expectRetryAt.Equal(retryAt), "expected %s, got %s", tc.expectRetryAt, retryAt)
+		})
+	}
+}
+
+func Test_RateLimitTracker(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	reset := now.Add(time.Hour)
+
+	tracker := newRateLimitTracker()
+	tracker.observe(rateLimitResponse(http.StatusOK, nil, ""), now)
+	assert.Empty(t, tracker.snapshot(), "responses without rate limit headers are ignored")
+
+	for _, resource := range []string{"", "search", "graphql"} {
+		resp := rateLimitResponse(http.StatusOK, map[string]string{
+			"X-RateLimit-Limit":     "100",
+			"X-RateLimit-Remaining": "60",
+			"X-RateLimit-Used":      "40",
+			"X-RateLimit-Reset":     strconv.FormatInt(reset.Unix(), 10),
+		}, "")
+		if resource != "" {
+			resp.Header.Set("X-RateLimit-Resource", resource)
+		}
+		tracker.observe(resp, now)
+	}
+
+	limits := tracker.snapshot()
+	require.Len(t, limits, 3)
+	assert.Equal(t, "core", limits[0].Resource)
+	assert.Equal(t, "graphql", limits[1].Resource)
+	assert.Equal(t, "search", limits[2].Resource)
+	assert.Equal(t, 60, limits[0].Remaining)
+	assert.Equal(t, 40, limits[0].Used)
+	assert.True(t, reset.Equal(limits[0].Reset))
+}
+
+func Test_RateLimitMiddleware(t *testing.T) {
+	retryAt := time.Now().Add(30 * time.Second)
+
+	tests := []struct {
+		name        string
+		handler     func(ctx context.Context) (*mcp.CallToolResult, error)
+		expectKind  string
+		expectError bool
+	}{
+		{
+			name: "successful calls are untouched",
+			handler: func(ctx context.Context) (*mcp.CallToolResult, error) {
+				recordRateLimitHit(ctx, rateLimitHit{RetryAt: retryAt})
+				return mcp.NewToolResultText("ok"), nil
+			},
+		},
+		{
+			name: "tool errors after a rate limit say when to retry",
+			handler: func(ctx context.Context) (*mcp.CallToolResult, error) {
+				recordRateLimitHit(ctx, rateLimitHit{Secondary: true, RetryAt: retryAt})
+				return mcp.NewToolResultError("failed to list issues"), nil
+			},
+			expectKind: "secondary",
+		},
+		{
+			name: "rate limit errors returned by go-github say when to retry",
+			handler: func(_ context.Context) (*mcp.CallToolResult, error) {
+				return nil, fmt.Errorf("failed to get issue: %w", &gogithub.RateLimitError{
+					Rate:     gogithub.Rate{Reset: gogithub.Timestamp{Time: retryAt}},
+					Response: &http.Response{Request: &http.Request{Method: http.MethodGet, URL: nil}},
+				})
+			},
+			expectKind: "primary",
+		},
+		{
+			name: "other errors are untouched",
+			handler: func(_ context.Context) (*mcp.CallToolResult, error) {
+				return nil, fmt.Errorf("failed to get issue")
+			},
+			expectError: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			handler := rateLimitMiddleware(func(ctx context.Context, _ mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+				return tc.handler(ctx)
+			})
+
+			result, err := handler(context.Background(), mcp.CallToolRequest{})
+			if tc.expectError {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			if tc.expectKind == "" {
+				assert.False(t, result.IsError)
+				return
+			}
+
+			require.True(t, result.IsError)
+			var structured struct {
+				Error             string    `json:"error"`
+				Kind              string    `json:"kind"`
+				RetryAt           time.Time `json:"retry_at"`
+				RetryAfterSeconds int       `json:"retry_after_seconds"`
+			}
+			require.NoError(t, json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &structured))
+			assert.Equal(t, tc.expectKind, structured.Kind)
+			assert.Contains(t, structured.Error, "rate limit exceeded")
+			assert.InDelta(t, 30, structured.RetryAfterSeconds, 1)
+			assert.WithinDuration(t, retryAt, structured.RetryAt, time.Second)
+		})
+	}
+}
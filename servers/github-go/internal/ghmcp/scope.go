@@ -0,0 +1,323 @@
+package ghmcp
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/github/github-mcp-server/pkg/github"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// searchQueryArguments names the argument holding the search query of tools that search
+// across repositories. Their queries have to be limited to repositories in scope.
+var searchQueryArguments = map[string]string{
+	"search_code":         "q",
+	"search_discussions":  "query",
+	"search_issues":       "q",
+	"search_repositories": "query",
+}
+
+// unscopedTools name tools that can reach repositories other than those of their owner and
+// repo arguments, through notification thread IDs or the items of a project. They are
+// refused while the scope restricts their access, unless the value is true and the call
+// is limited to a repository with owner and repo.
+var unscopedTools = map[string]bool{
+	"archive_project_item":             false,
+	"dismiss_notification":             false,
+	"get_notification_details":         false,
+	"list_notifications":               true,
+	"list_project_items":               false,
+	"manage_notification_subscription": false,
+	"mark_all_notifications_read":      true,
+	"update_project_item_field":        false,
+}
+
+// RepositoryRules allow and deny repositories by glob patterns of the form "owner/repo", e.g.
+// "octocat/*" or "octocat/hello-world". Patterns use the syntax of path.Match and are
+// matched case-insensitively.
+type RepositoryRules struct {
+	// Allow, if not empty, lists the only repositories that may be accessed
+	Allow []string
+
+	// Deny lists repositories that may never be accessed, even if they are allowed
+	Deny []string
+}
+
+// restricts reports whether the rules limit access at all.
+func (r RepositoryRules) restricts() bool {
+	return len(r.Allow) > 0 || len(r.Deny) > 0
+}
+
+// allowsRepository reports whether the lowercased "owner/repo" name passes the rules.
+func (r RepositoryRules) allowsRepository(name string) bool {
+	for _, pattern := range r.Deny {
+		if matchRepository(pattern, name) {
+			return false
+		}
+	}
+	if len(r.Allow) == 0 {
+		return true
+	}
+	for _, pattern := range r.Allow {
+		if matchRepository(pattern, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// allowsOwner reports whether the lowercased owner passes the rules, for tool calls that
+// act on an owner rather than a repository. An owner is allowed if some of its repositories
+// could be, and denied only if all of them are.
+func (r RepositoryRules) allowsOwner(owner string) bool {
+	for _, pattern := range r.Deny {
+		if ownerPattern, repoPattern, _ := strings.Cut(strings.ToLower(pattern), "/"); repoPattern == "*" && matchOwner(ownerPattern, owner) {
+			return false
+		}
+	}
+	if len(r.Allow) == 0 {
+		return true
+	}
+	for _, pattern := range r.Allow {
+		if ownerPattern, _, _ := strings.Cut(strings.ToLower(pattern), "/"); matchOwner(ownerPattern, owner) {
+			return true
+		}
+	}
+	return false
+}
+
+// allowsWholeOwner reports whether the lowercased owner passes the rules with all of its
+// repositories, i.e. it is allowed as "owner/*" and no pattern denies any of its repositories.
+func (r RepositoryRules) allowsWholeOwner(owner string) bool {
+	for _, pattern := range r.Deny {
+		if ownerPattern, _, _ := strings.Cut(strings.ToLower(pattern), "/"); matchOwner(ownerPattern, owner) {
+			return false
+		}
+	}
+	if len(r.Allow) == 0 {
+		return true
+	}
+	for _, pattern := range r.Allow {
+		if ownerPattern, repoPattern, _ := strings.Cut(strings.ToLower(pattern), "/"); repoPattern == "*" && matchOwner(ownerPattern, owner) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchRepository(pattern, name string) bool {
+	matched, _ := path.Match(strings.ToLower(pattern), name)
+	return matched
+}
+
+func matchOwner(pattern, owner string) bool {
+	matched, _ := path.Match(pattern, owner)
+	return matched
+}
+
+// RepositoryScope restricts the repositories that tools and resources can touch. Calls have
+// to pass the rules in All, and those in Read or Write depending on whether they can make
+// changes.
+type RepositoryScope struct {
+	All   RepositoryRules
+	Read  RepositoryRules
+	Write RepositoryRules
+}
+
+// Restricts reports whether the scope limits access to any repository.
+func (s *RepositoryScope) Restricts() bool {
+	return s.All.restricts() || s.Read.restricts() || s.Write.restricts()
+}
+
+// validate checks that every pattern is a valid "owner/repo" glob.
+func (s *RepositoryScope) validate() error {
+	for _, rules := range []RepositoryRules{s.All, s.Read, s.Write} {
+		for _, pattern := range append(append([]string{}, rules.Allow...), rules.Deny...) {
+			if strings.Count(pattern, "/") != 1 {
+				return fmt.Errorf("invalid repository pattern %q, expected owner/repo", pattern)
+			}
+			if _, err := path.Match(pattern, ""); err != nil {
+				return fmt.Errorf("invalid repository pattern %q: %w", pattern, err)
+			}
+		}
+	}
+	return nil
+}
+
+func (s *RepositoryScope) rules(write bool) []RepositoryRules {
+	if write {
+		return []RepositoryRules{s.All, s.Write}
+	}
+	return []RepositoryRules{s.All, s.Read}
+}
+
+func accessName(write bool) string {
+	if write {
+		return "writing"
+	}
+	return "reading"
+}
+
+// checkRepository returns an error if owner/repo is out of scope.
+func (s *RepositoryScope) checkRepository(owner, repo string, write bool) error {
+	name := strings.ToLower(owner + "/" + repo)
+	for _, rules := range s.rules(write) {
+		if !rules.allowsRepository(name) {
+			return fmt.Errorf("repository %s/%s is out of scope for %s", owner, repo, accessName(write))
+		}
+	}
+	return nil
+}
+
+// checkOwner returns an error if none of the repositories of owner are in scope.
+func (s *RepositoryScope) checkOwner(owner string, write bool) error {
+	lowered := strings.ToLower(owner)
+	for _, rules := range s.rules(write) {
+		if !rules.allowsOwner(lowered) {
+			return fmt.Errorf("owner %s is out of scope for %s", owner, accessName(write))
+		}
+	}
+	return nil
+}
+
+// checkWholeOwner returns an error unless all of the repositories of owner are in scope.
+func (s *RepositoryScope) checkWholeOwner(owner string, write bool) error {
+	lowered := strings.ToLower(owner)
+	for _, rules := range s.rules(write) {
+		if !rules.allowsWholeOwner(lowered) {
+			return fmt.Errorf("only some repositories of %s are in scope for %s, limit the search with repo: qualifiers", owner, accessName(write))
+		}
+	}
+	return nil
+}
+
+// restrictsAccess reports whether the rules for reading or writing limit access at all.
+func (s *RepositoryScope) restrictsAccess(write bool) bool {
+	for _, rules := range s.rules(write) {
+		if rules.restricts() {
+			return true
+		}
+	}
+	return false
+}
+
+// checkQuery returns an error if a search query can find anything out of scope. Queries
+// have to be limited with repo: qualifiers that are all in scope, or with org: or user:
+// qualifiers of owners whose repositories are all in scope, unless the scope doesn't
+// restrict reads at all.
+func (s *RepositoryScope) checkQuery(query string) error {
+	if !s.restrictsAccess(false) {
+		return nil
+	}
+
+	limited := false
+	for _, term := range strings.Fields(query) {
+		qualifier, value, ok := strings.Cut(term, ":")
+		if !ok || value == "" {
+			continue
+		}
+		switch strings.ToLower(qualifier) {
+		case "repo":
+			owner, repo, ok := strings.Cut(value, "/")
+			if !ok {
+				return fmt.Errorf("invalid repo qualifier %q in search query", term)
+			}
+			if err := s.checkRepository(owner, repo, false); err != nil {
+				return err
+			}
+		case "org", "user":
+			if err := s.checkWholeOwner(value, false); err != nil {
+				return err
+			}
+		default:
+			continue
+		}
+		limited = true
+	}
+	if !limited {
+		return fmt.Errorf("search queries must be limited to repositories in scope with repo:, org: or user: qualifiers")
+	}
+	return nil
+}
+
+// checkToolCall checks the repository and owner arguments of a tool call, and its search
+// query if it searches across repositories.
+func (s *RepositoryScope) checkToolCall(request mcp.CallToolRequest, write bool) error {
+	args := request.GetArguments()
+
+	owner, _ := args["owner"].(string)
+	repo, _ := args["repo"].(string)
+	switch {
+	case owner != "" && repo != "":
+		if err := s.checkRepository(owner, repo, write); err != nil {
+			return err
+		}
+	case owner != "":
+		if err := s.checkOwner(owner, write); err != nil {
+			return err
+		}
+	}
+
+	if byRepository, ok := unscopedTools[request.Params.Name]; ok && s.restrictsAccess(write) {
+		if !byRepository {
+			return fmt.Errorf("%s is not available while repositories are restricted for %s", request.Params.Name, accessName(write))
+		}
+		if owner == "" || repo == "" {
+			return fmt.Errorf("%s must be limited to a repository with owner and repo while repositories are restricted for %s", request.Params.Name, accessName(write))
+		}
+	}
+
+	// The organization a repository is forked into
+	if org, _ := args["organization"].(string); org != "" {
+		if err := s.checkOwner(org, write); err != nil {
+			return err
+		}
+	}
+
+	// The repository of an issue or pull request added to a project, which is only read
+	itemOwner, _ := args["item_owner"].(string)
+	itemRepo, _ := args["item_repo"].(string)
+	if itemOwner != "" && itemRepo != "" {
+		if err := s.checkRepository(itemOwner, itemRepo, false); err != nil {
+			return err
+		}
+	}
+
+	if arg, ok := searchQueryArguments[request.Params.Name]; ok {
+		if query, _ := args[arg].(string); query != "" {
+			// Tools that also take a repository limit their query to it
+			if owner != "" && repo != "" {
+				query = fmt.Sprintf("repo:%s/%s %s", owner, repo, query)
+			}
+			return s.checkQuery(query)
+		}
+	}
+	return nil
+}
+
+// toolMiddleware refuses tool calls that are out of scope before their handler runs. It is
+// meant to wrap read tools with write set to false, and write tools with write set to true.
+func (s *RepositoryScope) toolMiddleware(write bool) server.ToolHandlerMiddleware {
+	return func(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+		return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			if err := s.checkToolCall(request, write); err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			return next(ctx, request)
+		}
+	}
+}
+
+// resourceCheck returns the check that repository resources apply before reading content,
+// or nil if there is no scope.
+func (s *RepositoryScope) resourceCheck() github.CheckRepositoryFn {
+	if s == nil {
+		return nil
+	}
+	return func(owner, repo string) error {
+		return s.checkRepository(owner, repo, false)
+	}
+}
@@ -0,0 +1,239 @@
+package ghmcp
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_RepositoryScope(t *testing.T) {
+	scope := &RepositoryScope{
+		All: RepositoryRules{
+			Allow: []string{"octocat/*", "github/github-mcp-server", "hubot/*"},
+			Deny:  []string{"octocat/secret-*"},
+		},
+		Write: RepositoryRules{
+			Allow: []string{"octocat/hello-world"},
+		},
+	}
+	require.NoError(t, scope.validate())
+
+	tests := []struct {
+		name        string
+		tool        string
+		write       bool
+		args        map[string]any
+		expectError string
+	}{
+		{
+			name: "allowed repository",
+			tool: "get_file_contents",
+			args: map[string]any{"owner": "octocat", "repo": "spoon-knife"},
+		},
+		{
+			name: "patterns are case-insensitive",
+			tool: "get_file_contents",
+			args: map[string]any{"owner": "GitHub", "repo": "GitHub-MCP-Server"},
+		},
+		{
+			name:        "repository not allowed",
+			tool:        "get_file_contents",
+			args:        map[string]any{"owner": "github", "repo": "docs"},
+			expectError: "repository github/docs is out of scope for reading",
+		},
+		{
+			name:        "denied repository",
+			tool:        "list_issues",
+			args:        map[string]any{"owner": "octocat", "repo": "secret-plans"},
+			expectError: "repository octocat/secret-plans is out of scope for reading",
+		},
+		{
+			name:  "allowed for writing",
+			tool:  "create_issue",
+			write: true,
+			args:  map[string]any{"owner": "octocat", "repo": "hello-world"},
+		},
+		{
+			name:        "readable but not writable",
+			tool:        "create_issue",
+			write:       true,
+			args:        map[string]any{"owner": "octocat", "repo": "spoon-knife"},
+			expectError: "repository octocat/spoon-knife is out of scope for writing",
+		},
+		{
+			name:        "fork into an organization out of scope",
+			tool:        "fork_repository",
+			write:       true,
+			args:        map[string]any{"owner": "octocat", "repo": "hello-world", "organization": "evil-corp"},
+			expectError: "owner evil-corp is out of scope for writing",
+		},
+		{
+			name:        "project item from a repository out of scope",
+			tool:        "add_project_item",
+			write:       true,
+			args:        map[string]any{"owner": "octocat", "project_number": float64(1), "item_owner": "octocat", "item_repo": "secret-plans", "item_number": float64(42)},
+			expectError: "repository octocat/secret-plans is out of scope for reading",
+		},
+		{
+			name: "owner with allowed repositories",
+			tool: "list_projects",
+			args: map[string]any{"owner": "github"},
+		},
+		{
+			name:        "owner without allowed repositories",
+			tool:        "list_projects",
+			args:        map[string]any{"owner": "microsoft"},
+			expectError: "owner microsoft is out of scope for reading",
+		},
+		{
+			name: "notifications of an allowed repository",
+			tool: "list_notifications",
+			args: map[string]any{"owner": "octocat", "repo": "hello-world"},
+		},
+		{
+			name:        "notifications of all repositories",
+			tool:        "list_notifications",
+			args:        map[string]any{},
+			expectError: "list_notifications must be limited to a repository with owner and repo while repositories are restricted for reading",
+		},
+		{
+			name:        "notifications of an owner",
+			tool:        "mark_all_notifications_read",
+			write:       true,
+			args:        map[string]any{"owner": "octocat"},
+			expectError: "mark_all_notifications_read must be limited to a repository with owner and repo while repositories are restricted for writing",
+		},
+		{
+			name:        "notification thread",
+			tool:        "get_notification_details",
+			args:        map[string]any{"notificationID": "1"},
+			expectError: "get_notification_details is not available while repositories are restricted for reading",
+		},
+		{
+			name:        "project items",
+			tool:        "list_project_items",
+			args:        map[string]any{"owner": "octocat", "project_number": float64(1)},
+			expectError: "list_project_items is not available while repositories are restricted for reading",
+		},
+		{
+			name:        "project item field",
+			tool:        "update_project_item_field",
+			write:       true,
+			args:        map[string]any{"owner": "octocat", "project_number": float64(1), "item_id": "PVTI_1", "field": "Status", "value": "Done"},
+			expectError: "update_project_item_field is not available while repositories are restricted for writing",
+		},
+		{
+			name:        "archived project item",
+			tool:        "archive_project_item",
+			write:       true,
+			args:        map[string]any{"owner": "octocat", "project_number": float64(1), "item_id": "PVTI_1"},
+			expectError: "archive_project_item is not available while repositories are restricted for writing",
+		},
+		{
+			name: "tool without repository",
+			tool: "get_me",
+			args: map[string]any{},
+		},
+		{
+			name: "search limited to allowed repositories",
+			tool: "search_issues",
+			args: map[string]any{"q": "is:open repo:octocat/hello-world repo:github/github-mcp-server bug"},
+		},
+		{
+			name: "search limited to an owner",
+			tool: "search_code",
+			args: map[string]any{"q": "func main user:hubot"},
+		},
+		{
+			name:        "search limited to an owner with denied repositories",
+			tool:        "search_code",
+			args:        map[string]any{"q": "password org:octocat"},
+			expectError: "only some repositories of octocat are in scope for reading, limit the search with repo: qualifiers",
+		},
+		{
+			name:        "search limited to an owner with some allowed repositories",
+			tool:        "search_issues",
+			args:        map[string]any{"q": "is:open org:github"},
+			expectError: "only some repositories of github are in scope for reading, limit the search with repo: qualifiers",
+		},
+		{
+			name:        "search across all repositories",
+			tool:        "search_repositories",
+			args:        map[string]any{"query": "language:go stars:>1000"},
+			expectError: "search queries must be limited to repositories in scope with repo:, org: or user: qualifiers",
+		},
+		{
+			name:        "search including a repository out of scope",
+			tool:        "search_code",
+			args:        map[string]any{"q": "password repo:octocat/hello-world repo:octocat/secret-plans"},
+			expectError: "repository octocat/secret-plans is out of scope for reading",
+		},
+		{
+			name: "search limited by the repository arguments",
+			tool: "search_discussions",
+			args: map[string]any{"owner": "octocat", "repo": "hello-world", "query": "is:unanswered"},
+		},
+		{
+			name:        "search widened beyond the repository arguments",
+			tool:        "search_discussions",
+			args:        map[string]any{"owner": "octocat", "repo": "hello-world", "query": "is:unanswered repo:octocat/secret-plans"},
+			expectError: "repository octocat/secret-plans is out of scope for reading",
+		},
+		{
+			name: "search_users is not limited",
+			tool: "search_users",
+			args: map[string]any{"q": "location:berlin"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			called := false
+			handler := scope.toolMiddleware(tc.write)(func(_ context.Context, _ mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+				called = true
+				return mcp.NewToolResultText("ok"), nil
+			})
+
+			request := mcp.CallToolRequest{}
+			request.Params.Name = tc.tool
+			request.Params.Arguments = tc.args
+
+			result, err := handler(context.Background(), request)
+			require.NoError(t, err)
+			if tc.expectError == "" {
+				assert.True(t, called)
+				assert.False(t, result.IsError)
+				return
+			}
+			assert.False(t, called, "the handler must not run")
+			require.True(t, result.IsError)
+			assert.Equal(t, tc.expectError, result.Content[0].(mcp.TextContent).Text)
+		})
+	}
+
+	// Restricting only writes leaves tools that read notifications of every repository usable
+	writeOnly := &RepositoryScope{Write: RepositoryRules{Allow: []string{"octocat/hello-world"}}}
+	request := mcp.CallToolRequest{}
+	request.Params.Name = "list_notifications"
+	assert.NoError(t, writeOnly.checkToolCall(request, false))
+	request.Params.Name = "dismiss_notification"
+	assert.EqualError(t, writeOnly.checkToolCall(request, true), "dismiss_notification is not available while repositories are restricted for writing")
+	request.Params.Name = "update_project_item_field"
+	request.Params.Arguments = map[string]any{"owner": "octocat", "project_number": float64(1), "item_id": "PVTI_1"}
+	assert.EqualError(t, writeOnly.checkToolCall(request, true), "update_project_item_field is not available while repositories are restricted for writing")
+
+	check := scope.resourceCheck()
+	assert.NoError(t, check("octocat", "hello-world"))
+	assert.EqualError(t, check("octocat", "secret-plans"), "repository octocat/secret-plans is out of scope for reading")
+}
+
+func Test_RepositoryScopeValidate(t *testing.T) {
+	assert.EqualError(t, (&RepositoryScope{All: RepositoryRules{Allow: []string{"octocat"}}}).validate(),
+		`invalid repository pattern "octocat", expected owner/repo`)
+	assert.EqualError(t, (&RepositoryScope{Read: RepositoryRules{Deny: []string{"octocat/[a-"}}}).validate(),
+		`invalid repository pattern "octocat/[a-": syntax error in pattern`)
+	assert.Nil(t, (*RepositoryScope)(nil).resourceCheck())
+}
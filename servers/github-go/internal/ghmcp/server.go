@@ -10,17 +10,20 @@ import (
 	"net/url"
 	"os"
 	"os/signal"
+	"sort"
 	"strings"
 	"syscall"
 
+	"github.com/github/github-mcp-server/pkg/audit"
 	"github.com/github/github-mcp-server/pkg/github"
 	mcplog "github.com/github/github-mcp-server/pkg/log"
+	"github.com/github/github-mcp-server/pkg/toolsets"
 	"github.com/github/github-mcp-server/pkg/translations"
 	gogithub "github.com/google/go-github/v72/github"
-	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
 	"github.com/shurcooL/githubv4"
 	"github.com/sirupsen/logrus"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
 )
 
 type MCPServerConfig struct {
@@ -30,7 +33,8 @@ type MCPServerConfig struct {
 	// GitHub Host to target for API requests (e.g. github.com or github.enterprise.com)
 	Host string
 
-	// GitHub Token to authenticate with the GitHub API
+	// GitHub Token to authenticate with the GitHub API when the request context
+	// does not carry one of its own (see ContextWithToken)
 	Token string
 
 	// EnabledToolsets is a list of toolsets to enable
@@ -41,9 +45,52 @@ type MCPServerConfig struct {
 	// See: https://github.com/github/github-mcp-server?tab=readme-ov-file#dynamic-tool-discovery
 	DynamicToolsets bool
 
+	// App authenticates as a GitHub App instead of with Token, if set
+	App *AppAuthConfig
+
+	// Accounts are additional named accounts that tools can act as via their "account" argument
+	Accounts []AccountConfig
+
+	// RateLimit controls how requests that hit a GitHub API rate limit are retried
+	RateLimit RateLimitConfig
+
+	// Cache serves unchanged REST API responses from cache when set
+	Cache *ResponseCache
+
+	// Audit records every call of a write tool when set
+	Audit *audit.Logger
+
+	// Confirmation has the user confirm every call of a destructive tool when set
+	Confirmation *ConfirmationConfig
+
+	// Scope restricts the repositories tools and resources can touch when set
+	Scope *RepositoryScope
+
+	// ResponseBudget splits tool results that are too large into parts when set
+	ResponseBudget *ResponseBudgetConfig
+
+	// Metrics measures tool calls and GitHub API requests when set
+	Metrics *Metrics
+
+	// Tracing records a trace of every tool call and the GitHub API requests it makes when set
+	Tracing *sdktrace.TracerProvider
+
 	// ReadOnly indicates if we should only offer read-only tools
 	ReadOnly bool
 
+	// DryRun holds back every GitHub API request that would change anything, and has
+	// write tools return the requests they would have sent instead
+	DryRun bool
+
+	// Tools selects individual tools within the enabled toolsets
+	Tools toolsets.ToolFilter
+
+	// ToolDescriptions overrides the descriptions of tools by tool name
+	ToolDescriptions map[string]string
+
+	// Locales describes tools in the locale of each session when set
+	Locales *LocaleConfig
+
 	// Translator provides translated text for the server tooling
 	Translator translations.TranslationHelperFunc
 }
@@ -54,45 +101,53 @@ func NewMCPServer(cfg MCPServerConfig) (*server.MCPServer, error) {
 		return nil, fmt.Errorf("failed to parse API host: %w", err)
 	}
 
-	// Construct our REST client
-	restClient := gogithub.NewClient(nil).WithAuthToken(cfg.Token)
-	restClient.UserAgent = fmt.Sprintf("github-mcp-server/%s", cfg.Version)
-	restClient.BaseURL = apiHost.baseRESTURL
-	restClient.UploadURL = apiHost.uploadURL
-
-	// Construct our GraphQL client
-	// We're using NewEnterpriseClient here unconditionally as opposed to NewClient because we already
-	// did the necessary API host parsing so that github.com will return the correct URL anyway.
-	gqlHTTPClient := &http.Client{
-		Transport: &bearerAuthTransport{
-			transport: http.DefaultTransport,
-			token:     cfg.Token,
-		},
-	} // We're going to wrap the Transport later in beforeInit
-	gqlClient := githubv4.NewEnterpriseClient(apiHost.graphqlURL.String(), gqlHTTPClient)
-
-	// When a client send an initialize request, update the user agent to include the client info.
-	beforeInit := func(_ context.Context, _ any, message *mcp.InitializeRequest) {
-		userAgent := fmt.Sprintf(
-			"github-mcp-server/%s (%s/%s)",
-			cfg.Version,
-			message.Params.ClientInfo.Name,
-			message.Params.ClientInfo.Version,
-		)
+	if cfg.Confirmation != nil {
+		if err := cfg.Confirmation.validate(); err != nil {
+			return nil, err
+		}
+	}
+	if cfg.Scope != nil {
+		if err := cfg.Scope.validate(); err != nil {
+			return nil, err
+		}
+	}
 
-		restClient.UserAgent = userAgent
+	// Tools are described in the default locale, and in the locale of their client for
+	// sessions of clients that have one
+	t := cfg.Translator
+	var locales *sessionLocales
+	if cfg.Locales != nil {
+		loaded, err := cfg.Locales.load()
+		if err != nil {
+			return nil, err
+		}
+		locales, err = newSessionLocales(*cfg.Locales, loaded, cfg.Translator, cfg.ToolDescriptions)
+		if err != nil {
+			return nil, err
+		}
+		t = loaded.Helper(cfg.Locales.defaultLocale(), cfg.Translator)
+	}
 
-		gqlHTTPClient.Transport = &userAgentTransport{
-			transport: gqlHTTPClient.Transport,
-			agent:     userAgent,
+	var budget *responseBudget
+	if cfg.ResponseBudget != nil {
+		budget, err = newResponseBudget(*cfg.ResponseBudget)
+		if err != nil {
+			return nil, err
 		}
 	}
 
-	hooks := &server.Hooks{
-		OnBeforeInitialize: []server.OnBeforeInitializeFunc{beforeInit},
+	var tokens tokenSource = staticTokenSource(cfg.Token)
+	if cfg.App != nil {
+		tokens, err = newAppTokenSource(*cfg.App, cfg.Version, apiHost)
+		if err != nil {
+			return nil, fmt.Errorf("failed to configure GitHub App authentication: %w", err)
+		}
 	}
 
-	ghServer := github.NewServer(cfg.Version, server.WithHooks(hooks))
+	resolver, err := newClientResolver(cfg, apiHost, tokens)
+	if err != nil {
+		return nil, err
+	}
 
 	enabledToolsets := cfg.EnabledToolsets
 	if cfg.DynamicToolsets {
@@ -105,41 +160,193 @@ func NewMCPServer(cfg MCPServerConfig) (*server.MCPServer, error) {
 		}
 	}
 
-	getClient := func(_ context.Context) (*gogithub.Client, error) {
-		return restClient, nil // closing over client
+	getClient := func(ctx context.Context) (*gogithub.Client, error) {
+		clients, err := resolver.resolve(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return clients.rest, nil
+	}
+
+	getGQLClient := func(ctx context.Context) (*githubv4.Client, error) {
+		clients, err := resolver.resolve(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return clients.gql, nil
 	}
 
-	getGQLClient := func(_ context.Context) (*githubv4.Client, error) {
-		return gqlClient, nil // closing over client
+	getRateLimits := func(ctx context.Context) ([]github.RateLimitStatus, error) {
+		clients, err := resolver.resolve(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return clients.rateLimits.snapshot(), nil
 	}
 
 	// Create default toolsets
-	toolsets, err := github.InitToolsets(
+	tsg, err := github.InitToolsets(
 		enabledToolsets,
 		cfg.ReadOnly,
 		getClient,
 		getGQLClient,
-		cfg.Translator,
+		github.ResourceReader(getClient, cfg.Scope.resourceCheck(), t),
+		t,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("failed to initialize toolsets: %w", err)
 	}
 
-	context := github.InitContextToolset(getClient, cfg.Translator)
-	github.RegisterResources(ghServer, getClient, cfg.Translator)
+	if cfg.Cache != nil {
+		tsg.WrapWriteToolHandlers(cacheInvalidationMiddleware(cfg.Cache))
+	}
 
-	// Register the tools with the server
-	toolsets.RegisterTools(ghServer)
-	context.RegisterTools(ghServer)
+	// Nothing is changed in dry-run mode, so there is nothing to confirm
+	if cfg.Confirmation != nil && !cfg.DryRun {
+		tsg.WrapDestructiveToolHandlers(confirmationMiddleware(*cfg.Confirmation))
+	}
+
+	// Calls out of scope are refused before anything else, but still audited
+	if cfg.Scope != nil {
+		tsg.WrapReadToolHandlers(cfg.Scope.toolMiddleware(false))
+		tsg.WrapWriteToolHandlers(cfg.Scope.toolMiddleware(true))
+	}
+
+	// Wrapped last so that the audit record reflects the outcome of everything above
+	if cfg.Audit != nil {
+		tsg.WrapWriteToolHandlers(cfg.Audit.Middleware)
+	}
+
+	accountNames := resolver.accountNames()
+	contextToolset := github.InitContextToolset(getClient, getRateLimits, accountNames, t)
+	if budget != nil {
+		contextToolset.AddReadTools(toolsets.NewServerTool(github.ContinueResult(budget.chunk, t)))
+	}
+
+	if cfg.Metrics != nil {
+		tsg.WrapToolHandlers(cfg.Metrics.ToolMiddleware)
+		contextToolset.WrapToolHandlers(cfg.Metrics.ToolMiddleware)
+	}
+
+	// Write tools are left out of tsg in read-only mode, but a configuration naming them is
+	// still valid, so that the same one can be used both ways
+	allTools := tsg
+	if cfg.ReadOnly {
+		allTools, err = github.InitToolsets(nil, false, nil, nil, nil, t)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list tools: %w", err)
+		}
+	}
+	if err := checkToolNames(cfg, allTools, contextToolset); err != nil {
+		return nil, err
+	}
+	tsg.ApplyToolFilter(cfg.Tools)
+	contextToolset.ApplyToolFilter(cfg.Tools)
+	tsg.SetToolDescriptions(cfg.ToolDescriptions)
+	contextToolset.SetToolDescriptions(cfg.ToolDescriptions)
+
+	if len(accountNames) > 0 {
+		withAccount := github.WithAccount(append([]string{github.DefaultAccountName}, accountNames...))
+		tsg.ApplyToolOptions(withAccount)
+		contextToolset.ApplyToolOptions(withAccount)
+	}
 
+	// The owner of each tool call is recorded so that GitHub App installations can be chosen per owner,
+	// and the account argument selects which named account's clients are used. Tool calls that run
+	// into a rate limit report when they can be retried.
+	serverOpts := []server.ServerOption{
+		server.WithToolHandlerMiddleware(rateLimitMiddleware),
+		server.WithToolHandlerMiddleware(ownerMiddleware),
+		server.WithToolHandlerMiddleware(accountMiddleware),
+	}
+
+	// Results are split last, once everything else had a chance to add to them
+	if budget != nil {
+		serverOpts = append([]server.ServerOption{server.WithToolHandlerMiddleware(budget.middleware)}, serverOpts...)
+	}
+
+	// Tool calls are traced as a whole, so their span is started before any other middleware runs
+	if cfg.Tracing != nil {
+		tracer := cfg.Tracing.Tracer(tracerName)
+		serverOpts = append([]server.ServerOption{server.WithToolHandlerMiddleware(toolTracingMiddleware(tracer))}, serverOpts...)
+	}
+
+	if cfg.DryRun {
+		serverOpts = append(serverOpts, server.WithToolHandlerMiddleware(dryRunMiddleware))
+	}
+
+	if locales != nil {
+		serverOpts = append(serverOpts, server.WithToolFilter(locales.filterTools))
+	}
+
+	// With dynamic toolsets, each session enables and disables toolsets for itself. All tools are
+	// registered, and those of toolsets the session hasn't enabled are hidden from it.
+	var sessionToolsets *toolsets.SessionToolsets
 	if cfg.DynamicToolsets {
-		dynamic := github.InitDynamicToolset(ghServer, toolsets, cfg.Translator)
+		sessionToolsets = toolsets.NewSessionToolsets(tsg)
+
+		hooks := &server.Hooks{}
+		hooks.AddOnUnregisterSession(func(_ context.Context, session server.ClientSession) {
+			sessionToolsets.Forget(session.SessionID())
+		})
+		serverOpts = append(serverOpts,
+			server.WithToolFilter(sessionToolsets.FilterTools),
+			server.WithToolHandlerMiddleware(sessionToolsets.Middleware),
+			server.WithHooks(hooks),
+		)
+	}
+
+	ghServer := github.NewServer(cfg.Version, serverOpts...)
+	github.RegisterResources(ghServer, getClient, cfg.Scope.resourceCheck(), t)
+
+	// Register the tools with the server
+	contextToolset.RegisterTools(ghServer)
+	if cfg.DynamicToolsets {
+		sessionToolsets.RegisterTools(ghServer)
+
+		dynamic := github.InitDynamicToolset(ghServer, sessionToolsets, t)
 		dynamic.RegisterTools(ghServer)
+	} else {
+		tsg.RegisterTools(ghServer)
 	}
 
 	return ghServer, nil
 }
 
+// checkToolNames makes sure that the tools named in the configuration exist, so that a typo
+// doesn't silently leave a tool enabled. tsg has to hold write tools even in read-only mode,
+// where the names of write tools are accepted and then have no effect.
+func checkToolNames(cfg MCPServerConfig, tsg *toolsets.ToolsetGroup, extra ...*toolsets.Toolset) error {
+	known := make(map[string]bool)
+	for _, toolset := range tsg.Toolsets {
+		for _, tool := range toolset.GetAvailableTools() {
+			known[tool.Tool.Name] = true
+		}
+	}
+	for _, toolset := range extra {
+		for _, tool := range toolset.GetAvailableTools() {
+			known[tool.Tool.Name] = true
+		}
+	}
+
+	names := append(append([]string{}, cfg.Tools.Allowed...), cfg.Tools.Denied...)
+	for name := range cfg.ToolDescriptions {
+		names = append(names, name)
+	}
+
+	var unknown []string
+	for _, name := range names {
+		if !known[name] {
+			unknown = append(unknown, name)
+		}
+	}
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return fmt.Errorf("unknown tools in configuration: %s", strings.Join(unknown, ", "))
+	}
+	return nil
+}
+
 type StdioServerConfig struct {
 	// Version of the server
 	Version string
@@ -150,6 +357,27 @@ type StdioServerConfig struct {
 	// GitHub Token to authenticate with the GitHub API
 	Token string
 
+	// App authenticates as a GitHub App instead of with Token, if set
+	App *AppAuthConfig
+
+	// Accounts are additional named accounts that tools can act as via their "account" argument
+	Accounts []AccountConfig
+
+	// RateLimit controls how requests that hit a GitHub API rate limit are retried
+	RateLimit RateLimitConfig
+
+	// Cache configures the REST API response cache, which is disabled if nil
+	Cache *CacheConfig
+
+	// Audit configures the audit log of write tool calls, which is disabled if nil
+	Audit *AuditConfig
+
+	// MetricsAddress is the TCP address to serve Prometheus metrics on, which are disabled if empty
+	MetricsAddress string
+
+	// Tracing configures how traces of tool calls are exported, which are disabled if nil
+	Tracing *TracingConfig
+
 	// EnabledToolsets is a list of toolsets to enable
 	// See: https://github.com/github/github-mcp-server?tab=readme-ov-file#tool-configuration
 	EnabledToolsets []string
@@ -161,6 +389,27 @@ type StdioServerConfig struct {
 	// ReadOnly indicates if we should only register read-only tools
 	ReadOnly bool
 
+	// DryRun has write tools return the GitHub API requests they would send instead of sending them
+	DryRun bool
+
+	// Confirmation has the user confirm every call of a destructive tool, which is not required if nil
+	Confirmation *ConfirmationConfig
+
+	// Scope restricts the repositories tools and resources can touch, which is not restricted if nil
+	Scope *RepositoryScope
+
+	// ResponseBudget splits tool results that are too large into parts, which is disabled if nil
+	ResponseBudget *ResponseBudgetConfig
+
+	// Tools selects individual tools within the enabled toolsets
+	Tools toolsets.ToolFilter
+
+	// ToolDescriptions overrides the descriptions of tools by tool name
+	ToolDescriptions map[string]string
+
+	// Locales describes tools in the locale of each session, which only uses the built-in texts if nil
+	Locales *LocaleConfig
+
 	// ExportTranslations indicates if we should export translations
 	// See: https://github.com/github/github-mcp-server?tab=readme-ov-file#i18n--overriding-descriptions
 	ExportTranslations bool
@@ -168,6 +417,9 @@ type StdioServerConfig struct {
 	// EnableCommandLogging indicates if we should log commands
 	EnableCommandLogging bool
 
+	// Redaction configures how secrets are removed from log output
+	Redaction mcplog.RedactionConfig
+
 	// Path to the log file if not stderr
 	LogFilePath string
 }
@@ -180,14 +432,56 @@ func RunStdioServer(cfg StdioServerConfig) error {
 
 	t, dumpTranslations := translations.TranslationHelper()
 
+	logrusLogger, redactor, err := newLogger(cfg.LogFilePath, cfg.Redaction)
+	if err != nil {
+		return err
+	}
+
+	cache, err := newResponseCache(cfg.Cache)
+	if err != nil {
+		return err
+	}
+	defer logCacheStats(logrusLogger, cache)
+
+	auditLog, err := openAuditLog(cfg.Audit)
+	if err != nil {
+		return err
+	}
+	defer closeAuditLog(logrusLogger, auditLog)
+
+	metrics, err := startMetrics(ctx, cfg.MetricsAddress, logrusLogger)
+	if err != nil {
+		return err
+	}
+
+	tracing, err := newTracerProvider(ctx, cfg.Tracing, cfg.Version)
+	if err != nil {
+		return err
+	}
+	defer shutdownTracerProvider(logrusLogger, tracing)
+
 	ghServer, err := NewMCPServer(MCPServerConfig{
-		Version:         cfg.Version,
-		Host:            cfg.Host,
-		Token:           cfg.Token,
-		EnabledToolsets: cfg.EnabledToolsets,
-		DynamicToolsets: cfg.DynamicToolsets,
-		ReadOnly:        cfg.ReadOnly,
-		Translator:      t,
+		Version:          cfg.Version,
+		Host:             cfg.Host,
+		Token:            cfg.Token,
+		App:              cfg.App,
+		Accounts:         cfg.Accounts,
+		RateLimit:        cfg.RateLimit,
+		Cache:            cache,
+		Audit:            auditLog,
+		Metrics:          metrics,
+		Tracing:          tracing,
+		EnabledToolsets:  cfg.EnabledToolsets,
+		DynamicToolsets:  cfg.DynamicToolsets,
+		ReadOnly:         cfg.ReadOnly,
+		DryRun:           cfg.DryRun,
+		Confirmation:     cfg.Confirmation,
+		Scope:            cfg.Scope,
+		ResponseBudget:   cfg.ResponseBudget,
+		Tools:            cfg.Tools,
+		ToolDescriptions: cfg.ToolDescriptions,
+		Locales:          cfg.Locales,
+		Translator:       t,
 	})
 	if err != nil {
 		return fmt.Errorf("failed to create MCP server: %w", err)
@@ -195,16 +489,6 @@ func RunStdioServer(cfg StdioServerConfig) error {
 
 	stdioServer := server.NewStdioServer(ghServer)
 
-	logrusLogger := logrus.New()
-	if cfg.LogFilePath != "" {
-		file, err := os.OpenFile(cfg.LogFilePath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
-		if err != nil {
-			return fmt.Errorf("failed to open log file: %w", err)
-		}
-
-		logrusLogger.SetLevel(logrus.DebugLevel)
-		logrusLogger.SetOutput(file)
-	}
 	stdLogger := log.New(logrusLogger.Writer(), "stdioserver", 0)
 	stdioServer.SetErrorLogger(stdLogger)
 
@@ -219,7 +503,7 @@ func RunStdioServer(cfg StdioServerConfig) error {
 		in, out := io.Reader(os.Stdin), io.Writer(os.Stdout)
 
 		if cfg.EnableCommandLogging {
-			loggedIO := mcplog.NewIOLogger(in, out, logrusLogger)
+			loggedIO := mcplog.NewIOLogger(in, out, logrusLogger, redactor)
 			in, out = loggedIO, loggedIO
 		}
 
@@ -261,6 +545,95 @@ func RunStdioServer(cfg StdioServerConfig) error {
 	return nil
 }
 
+// startMetrics serves metrics on address until ctx is done, or returns nil if there is no address.
+func startMetrics(ctx context.Context, address string, logger *logrus.Logger) (*Metrics, error) {
+	if address == "" {
+		return nil, nil
+	}
+	metrics := NewMetrics()
+	if err := serveMetrics(ctx, address, metrics, logger); err != nil {
+		return nil, err
+	}
+	return metrics, nil
+}
+
+// newResponseCache creates the response cache described by cfg, or returns nil if there is none.
+func newResponseCache(cfg *CacheConfig) (*ResponseCache, error) {
+	if cfg == nil {
+		return nil, nil
+	}
+	cache, err := NewResponseCache(*cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create response cache: %w", err)
+	}
+	return cache, nil
+}
+
+// AuditConfig configures the audit log of write tool calls.
+type AuditConfig struct {
+	// Path is the JSONL file records are appended to
+	Path string
+
+	// HashChain links every record to the previous one, so that tampering can be detected
+	HashChain bool
+}
+
+// openAuditLog opens the audit log described by cfg, or returns nil if there is none.
+func openAuditLog(cfg *AuditConfig) (*audit.Logger, error) {
+	if cfg == nil {
+		return nil, nil
+	}
+	return audit.Open(cfg.Path, cfg.HashChain)
+}
+
+// closeAuditLog closes the audit log, if there is one.
+func closeAuditLog(logger *logrus.Logger, auditLog *audit.Logger) {
+	if auditLog == nil {
+		return
+	}
+	if err := auditLog.Close(); err != nil {
+		logger.WithError(err).Error("failed to close audit log")
+	}
+}
+
+// logCacheStats reports how effective the response cache has been, if there is one.
+func logCacheStats(logger *logrus.Logger, cache *ResponseCache) {
+	if cache == nil {
+		return
+	}
+	stats := cache.Stats()
+	logger.WithFields(logrus.Fields{
+		"hits":          stats.Hits,
+		"misses":        stats.Misses,
+		"evictions":     stats.Evictions,
+		"invalidations": stats.Invalidations,
+		"entries":       stats.Entries,
+		"bytes":         stats.Bytes,
+	}).Infof("response cache stats")
+}
+
+// newLogger creates a logrus logger that writes to stderr, or to the file at logFilePath if one is given.
+// Secrets are redacted from everything it logs, with the returned redactor.
+func newLogger(logFilePath string, redaction mcplog.RedactionConfig) (*logrus.Logger, *mcplog.Redactor, error) {
+	redactor, err := mcplog.NewRedactor(redaction)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	logrusLogger := logrus.New()
+	logrusLogger.AddHook(&mcplog.RedactionHook{Redactor: redactor})
+	if logFilePath != "" {
+		file, err := os.OpenFile(logFilePath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to open log file: %w", err)
+		}
+
+		logrusLogger.SetLevel(logrus.DebugLevel)
+		logrusLogger.SetOutput(file)
+	}
+	return logrusLogger, redactor, nil
+}
+
 type apiHost struct {
 	baseRESTURL *url.URL
 	graphqlURL  *url.URL
@@ -278,7 +651,7 @@ func newDotcomHost() (apiHost, error) {
 		return apiHost{}, fmt.Errorf("failed to parse dotcom GraphQL URL: %w", err)
 	}
 
-	uploadURL, err := url.Parse("https://uploads.github.com")
+	uploadURL, err := url.Parse("https://uploads.github.com/")
 	if err != nil {
 		return apiHost{}, fmt.Errorf("failed to parse dotcom Upload URL: %w", err)
 	}
@@ -301,17 +674,17 @@ func newGHECHost(hostname string) (apiHost, error) {
 		return apiHost{}, fmt.Errorf("GHEC URL must be HTTPS")
 	}
 
-	restURL, err := url.Parse(fmt.Sprintf("https://api.%s/", u.Hostname()))
+	restURL, err := url.Parse(fmt.Sprintf("https://api.%s/", u.Host))
 	if err != nil {
 		return apiHost{}, fmt.Errorf("failed to parse GHEC REST URL: %w", err)
 	}
 
-	gqlURL, err := url.Parse(fmt.Sprintf("https://api.%s/graphql", u.Hostname()))
+	gqlURL, err := url.Parse(fmt.Sprintf("https://api.%s/graphql", u.Host))
 	if err != nil {
 		return apiHost{}, fmt.Errorf("failed to parse GHEC GraphQL URL: %w", err)
 	}
 
-	uploadURL, err := url.Parse(fmt.Sprintf("https://uploads.%s", u.Hostname()))
+	uploadURL, err := url.Parse(fmt.Sprintf("https://uploads.%s/", u.Host))
 	if err != nil {
 		return apiHost{}, fmt.Errorf("failed to parse GHEC Upload URL: %w", err)
 	}
@@ -329,17 +702,17 @@ func newGHESHost(hostname string) (apiHost, error) {
 		return apiHost{}, fmt.Errorf("failed to parse GHES URL: %w", err)
 	}
 
-	restURL, err := url.Parse(fmt.Sprintf("%s://%s/api/v3/", u.Scheme, u.Hostname()))
+	restURL, err := url.Parse(fmt.Sprintf("%s://%s/api/v3/", u.Scheme, u.Host))
 	if err != nil {
 		return apiHost{}, fmt.Errorf("failed to parse GHES REST URL: %w", err)
 	}
 
-	gqlURL, err := url.Parse(fmt.Sprintf("%s://%s/api/graphql", u.Scheme, u.Hostname()))
+	gqlURL, err := url.Parse(fmt.Sprintf("%s://%s/api/graphql", u.Scheme, u.Host))
 	if err != nil {
 		return apiHost{}, fmt.Errorf("failed to parse GHES GraphQL URL: %w", err)
 	}
 
-	uploadURL, err := url.Parse(fmt.Sprintf("%s://%s/api/uploads/", u.Scheme, u.Hostname()))
+	uploadURL, err := url.Parse(fmt.Sprintf("%s://%s/api/uploads/", u.Scheme, u.Host))
 	if err != nil {
 		return apiHost{}, fmt.Errorf("failed to parse GHES Upload URL: %w", err)
 	}
@@ -351,7 +724,8 @@ func newGHESHost(hostname string) (apiHost, error) {
 	}, nil
 }
 
-// Note that this does not handle ports yet, so development environments are out.
+// parseAPIHost works out the API URLs of the host given with --gh-host. Ports are kept, so that
+// GHES instances and fakes on development machines such as http://localhost:8080 can be used.
 func parseAPIHost(s string) (apiHost, error) {
 	if s == "" {
 		return newDotcomHost()
@@ -377,24 +751,31 @@ func parseAPIHost(s string) (apiHost, error) {
 	return newGHESHost(s)
 }
 
+// userAgentTransport sets the user agent from the MCP session the request is made for,
+// rather than fixing it per client, since clients are shared by sessions using the same token.
 type userAgentTransport struct {
 	transport http.RoundTripper
-	agent     string
+	version   string
 }
 
 func (t *userAgentTransport) RoundTrip(req *http.Request) (*http.Response, error) {
 	req = req.Clone(req.Context())
-	req.Header.Set("User-Agent", t.agent)
+	req.Header.Set("User-Agent", userAgentFromContext(req.Context(), t.version))
 	return t.transport.RoundTrip(req)
 }
 
+// bearerAuthTransport authenticates every request with the current token of its source.
 type bearerAuthTransport struct {
 	transport http.RoundTripper
-	token     string
+	tokens    tokenSource
 }
 
 func (t *bearerAuthTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	token, err := t.tokens.Token(req.Context())
+	if err != nil {
+		return nil, err
+	}
 	req = req.Clone(req.Context())
-	req.Header.Set("Authorization", "Bearer "+t.token)
+	req.Header.Set("Authorization", "Bearer "+token)
 	return t.transport.RoundTrip(req)
 }
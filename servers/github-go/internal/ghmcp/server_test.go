@@ -0,0 +1,321 @@
+package ghmcp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/github/github-mcp-server/pkg/audit"
+	"github.com/github/github-mcp-server/pkg/toolsets"
+	"github.com/github/github-mcp-server/pkg/translations"
+	gogithub "github.com/google/go-github/v72/github"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// listTools returns the tools ghServer offers to session, or outside of a session if it is nil, keyed by name.
+func listTools(t *testing.T, ghServer *server.MCPServer, session server.ClientSession) map[string]mcp.Tool {
+	t.Helper()
+
+	ctx := context.Background()
+	if session != nil {
+		ctx = ghServer.WithContext(ctx, session)
+	}
+	response := ghServer.HandleMessage(ctx, json.RawMessage(`{"jsonrpc": "2.0", "id": 1, "method": "tools/list"}`))
+	data, err := json.Marshal(response)
+	require.NoError(t, err)
+
+	var decoded struct {
+		Result mcp.ListToolsResult `json:"result"`
+	}
+	require.NoError(t, json.Unmarshal(data, &decoded))
+
+	tools := make(map[string]mcp.Tool, len(decoded.Result.Tools))
+	for _, tool := range decoded.Result.Tools {
+		tools[tool.Name] = tool
+	}
+	return tools
+}
+
+func Test_NewMCPServerToolConfiguration(t *testing.T) {
+	cfg := MCPServerConfig{
+		Version:         "test",
+		Token:           "token",
+		EnabledToolsets: []string{"repos", "pull_requests"},
+		Tools: toolsets.ToolFilter{
+			Denied: []string{"merge_pull_request", "delete_file"},
+		},
+		ToolDescriptions: map[string]string{
+			"get_pull_request": "Look up a pull request",
+		},
+		Translator: translations.NullTranslationHelper,
+	}
+
+	ghServer, err := NewMCPServer(cfg)
+	require.NoError(t, err)
+
+	tools := listTools(t, ghServer, nil)
+	assert.Contains(t, tools, "create_pull_request")
+	assert.Contains(t, tools, "get_file_contents")
+	assert.NotContains(t, tools, "merge_pull_request")
+	assert.NotContains(t, tools, "delete_file")
+	assert.NotContains(t, tools, "list_issues", "toolsets that are not enabled stay disabled")
+	assert.Equal(t, "Look up a pull request", tools["get_pull_request"].Description)
+
+	t.Run("allow list", func(t *testing.T) {
+		cfg := cfg
+		cfg.Tools = toolsets.ToolFilter{Allowed: []string{"get_me", "get_pull_request"}}
+
+		ghServer, err := NewMCPServer(cfg)
+		require.NoError(t, err)
+		tools := listTools(t, ghServer, nil)
+		assert.Len(t, tools, 2)
+		assert.Contains(t, tools, "get_me")
+		assert.Contains(t, tools, "get_pull_request")
+	})
+
+	t.Run("unknown tools are rejected", func(t *testing.T) {
+		cfg := cfg
+		cfg.Tools = toolsets.ToolFilter{Denied: []string{"merge_pull_requests"}}
+
+		_, err := NewMCPServer(cfg)
+		require.ErrorContains(t, err, "unknown tools in configuration: merge_pull_requests")
+	})
+
+	t.Run("write tools are accepted in read-only mode", func(t *testing.T) {
+		cfg := cfg
+		cfg.ReadOnly = true
+		cfg.Tools = toolsets.ToolFilter{
+			Allowed: []string{"get_pull_request", "create_pull_request"},
+			Denied:  []string{"merge_pull_request"},
+		}
+		cfg.ToolDescriptions = map[string]string{"create_pull_request": "Open a pull request"}
+
+		ghServer, err := NewMCPServer(cfg)
+		require.NoError(t, err)
+		tools := listTools(t, ghServer, nil)
+		assert.Len(t, tools, 1)
+		assert.Contains(t, tools, "get_pull_request")
+	})
+
+	t.Run("unknown tools are rejected in read-only mode", func(t *testing.T) {
+		cfg := cfg
+		cfg.ReadOnly = true
+		cfg.Tools = toolsets.ToolFilter{Allowed: []string{"create_pull_requests"}}
+
+		_, err := NewMCPServer(cfg)
+		require.ErrorContains(t, err, "unknown tools in configuration: create_pull_requests")
+	})
+}
+
+// testSession is a minimal MCP client session that records the notifications it receives.
+type testSession struct {
+	id            string
+	notifications chan mcp.JSONRPCNotification
+}
+
+func newTestSession(id string) *testSession {
+	return &testSession{id: id, notifications: make(chan mcp.JSONRPCNotification, 10)}
+}
+
+func (s *testSession) SessionID() string                                   { return s.id }
+func (s *testSession) NotificationChannel() chan<- mcp.JSONRPCNotification { return s.notifications }
+func (s *testSession) Initialize()                                         {}
+func (s *testSession) Initialized() bool                                   { return true }
+
+// callTool calls the named tool as session and returns the text of the result.
+func callTool(t *testing.T, ghServer *server.MCPServer, session server.ClientSession, name string, args map[string]any) (string, bool) {
+	t.Helper()
+
+	request, err := json.Marshal(map[string]any{
+		"jsonrpc": "2.0",
+		"id":      1,
+		"method":  "tools/call",
+		"params":  map[string]any{"name": name, "arguments": args},
+	})
+	require.NoError(t, err)
+
+	response := ghServer.HandleMessage(ghServer.WithContext(context.Background(), session), request)
+	data, err := json.Marshal(response)
+	require.NoError(t, err)
+
+	var decoded struct {
+		Result struct {
+			Content []mcp.TextContent `json:"content"`
+			IsError bool              `json:"isError"`
+		} `json:"result"`
+	}
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	require.Len(t, decoded.Result.Content, 1)
+	return decoded.Result.Content[0].Text, decoded.Result.IsError
+}
+
+func Test_NewMCPServerDynamicToolsetsPerSession(t *testing.T) {
+	ghServer, err := NewMCPServer(MCPServerConfig{
+		Version:         "test",
+		Token:           "token",
+		EnabledToolsets: []string{"repos"},
+		DynamicToolsets: true,
+		Translator:      translations.NullTranslationHelper,
+	})
+	require.NoError(t, err)
+
+	alice, bob := newTestSession("alice"), newTestSession("bob")
+	require.NoError(t, ghServer.RegisterSession(context.Background(), alice))
+	require.NoError(t, ghServer.RegisterSession(context.Background(), bob))
+
+	sessionTools := func(session server.ClientSession) map[string]mcp.Tool {
+		return listTools(t, ghServer, session)
+	}
+
+	assert.Contains(t, sessionTools(alice), "get_file_contents")
+	assert.NotContains(t, sessionTools(alice), "get_issue")
+
+	text, isError := callTool(t, ghServer, alice, "enable_toolset", map[string]any{"toolset": "issues"})
+	require.False(t, isError, text)
+	assert.Equal(t, "Toolset issues enabled", text)
+
+	// Only the session that enabled the toolset is notified and sees its tools
+	require.Len(t, alice.notifications, 1)
+	assert.Equal(t, "notifications/tools/list_changed", (<-alice.notifications).Method)
+	assert.Empty(t, bob.notifications)
+	assert.Contains(t, sessionTools(alice), "get_issue")
+	assert.NotContains(t, sessionTools(bob), "get_issue")
+
+	text, isError = callTool(t, ghServer, bob, "get_issue", map[string]any{"owner": "octocat", "repo": "hello-world", "issue_number": 1})
+	assert.True(t, isError)
+	assert.Contains(t, text, "not enabled")
+
+	text, _ = callTool(t, ghServer, bob, "list_available_toolsets", nil)
+	var available []map[string]string
+	require.NoError(t, json.Unmarshal([]byte(text), &available))
+	for _, toolset := range available {
+		if toolset["name"] == "issues" {
+			assert.Equal(t, "false", toolset["currently_enabled"])
+		}
+	}
+
+	// Toolsets can be turned back off, including those enabled by default
+	text, isError = callTool(t, ghServer, alice, "disable_toolset", map[string]any{"toolset": "repos"})
+	require.False(t, isError, text)
+	assert.Len(t, alice.notifications, 1)
+	assert.NotContains(t, sessionTools(alice), "get_file_contents")
+	assert.Contains(t, sessionTools(bob), "get_file_contents")
+}
+
+func Test_NewMCPServerAuditLog(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	auditLog, err := audit.Open(path, true)
+	require.NoError(t, err)
+
+	ghServer, err := NewMCPServer(MCPServerConfig{
+		Version:         "test",
+		Token:           "token",
+		EnabledToolsets: []string{"issues"},
+		Audit:           auditLog,
+		Translator:      translations.NullTranslationHelper,
+	})
+	require.NoError(t, err)
+
+	session := newTestSession("session-1")
+	require.NoError(t, ghServer.RegisterSession(context.Background(), session))
+
+	// Neither call reaches GitHub, they fail on the missing arguments
+	_, isError := callTool(t, ghServer, session, "get_issue", map[string]any{})
+	require.True(t, isError)
+	_, isError = callTool(t, ghServer, session, "create_issue", map[string]any{"owner": "octocat"})
+	require.True(t, isError)
+	require.NoError(t, auditLog.Close())
+
+	// Only the write tool is audited
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	count, err := audit.Verify(bytes.NewReader(data))
+	require.NoError(t, err)
+	require.Equal(t, 1, count)
+
+	var record audit.Record
+	require.NoError(t, json.Unmarshal(data, &record))
+	assert.Equal(t, "create_issue", record.Tool)
+	assert.Equal(t, "session-1", record.SessionID)
+	assert.Equal(t, map[string]any{"owner": "octocat"}, record.Arguments)
+	assert.Equal(t, audit.OutcomeToolError, record.Outcome)
+	assert.Equal(t, "missing required parameter: repo", record.Error)
+}
+
+func Test_parseAPIHost(t *testing.T) {
+	tests := []struct {
+		name        string
+		host        string
+		expectedErr string
+		rest        string
+		graphql     string
+		upload      string
+	}{
+		{
+			name:    "dotcom by default",
+			rest:    "https://api.github.com/",
+			graphql: "https://api.github.com/graphql",
+			upload:  "https://uploads.github.com/",
+		},
+		{
+			name:    "GHEC",
+			host:    "https://octo.ghe.com",
+			rest:    "https://api.octo.ghe.com/",
+			graphql: "https://api.octo.ghe.com/graphql",
+			upload:  "https://uploads.octo.ghe.com/",
+		},
+		{
+			name:    "GHES",
+			host:    "https://github.example.com",
+			rest:    "https://github.example.com/api/v3/",
+			graphql: "https://github.example.com/api/graphql",
+			upload:  "https://github.example.com/api/uploads/",
+		},
+		{
+			name:    "GHES with a port",
+			host:    "http://localhost:8080",
+			rest:    "http://localhost:8080/api/v3/",
+			graphql: "http://localhost:8080/api/graphql",
+			upload:  "http://localhost:8080/api/uploads/",
+		},
+		{
+			name:        "no scheme",
+			host:        "github.example.com",
+			expectedErr: "host must have a scheme (http or https): github.example.com",
+		},
+		{
+			name:        "unsecured GHEC",
+			host:        "http://octo.ghe.com",
+			expectedErr: "GHEC URL must be HTTPS",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			host, err := parseAPIHost(tc.host)
+			if tc.expectedErr != "" {
+				require.EqualError(t, err, tc.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.rest, host.baseRESTURL.String())
+			assert.Equal(t, tc.graphql, host.graphqlURL.String())
+			assert.Equal(t, tc.upload, host.uploadURL.String())
+
+			// Release assets are uploaded relative to the upload URL
+			client := gogithub.NewClient(nil)
+			client.BaseURL = host.baseRESTURL
+			client.UploadURL = host.uploadURL
+			req, err := client.NewUploadRequest("repos/octocat/hello-world/releases/1/assets?name=app.zip", strings.NewReader("PK"), 2, "application/zip")
+			require.NoError(t, err)
+			assert.Equal(t, tc.upload+"repos/octocat/hello-world/releases/1/assets?name=app.zip", req.URL.String())
+		})
+	}
+}
@@ -0,0 +1,190 @@
+package ghmcp
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	// TraceExporterOTLP exports spans over OTLP/HTTP.
+	TraceExporterOTLP = "otlp"
+	// TraceExporterFile appends spans to a local file as JSON, one span per line.
+	TraceExporterFile = "file"
+
+	tracerName = "github.com/github/github-mcp-server"
+)
+
+// TracingConfig configures how traces of tool calls are exported.
+type TracingConfig struct {
+	// Exporter is TraceExporterOTLP or TraceExporterFile
+	Exporter string
+
+	// Endpoint is the URL of the OTLP/HTTP endpoint, e.g. "http://localhost:4318/v1/traces".
+	// If empty, the standard OTEL_EXPORTER_OTLP_* environment variables apply
+	Endpoint string
+
+	// File is the path spans are appended to with the file exporter
+	File string
+}
+
+// newTracerProvider creates a tracer provider that exports spans as described by cfg,
+// or returns nil if there is no configuration.
+func newTracerProvider(ctx context.Context, cfg *TracingConfig, version string) (*sdktrace.TracerProvider, error) {
+	if cfg == nil {
+		return nil, nil
+	}
+
+	var exporter sdktrace.SpanExporter
+	switch cfg.Exporter {
+	case TraceExporterOTLP:
+		var opts []otlptracehttp.Option
+		if cfg.Endpoint != "" {
+			opts = append(opts, otlptracehttp.WithEndpointURL(cfg.Endpoint))
+		}
+		otlpExporter, err := otlptracehttp.New(ctx, opts...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create OTLP trace exporter: %w", err)
+		}
+		exporter = otlpExporter
+	case TraceExporterFile:
+		if cfg.File == "" {
+			return nil, fmt.Errorf("a trace file is required for the %s trace exporter", TraceExporterFile)
+		}
+		file, err := os.OpenFile(cfg.File, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open trace file: %w", err)
+		}
+		fileExporter, err := stdouttrace.New(stdouttrace.WithWriter(file))
+		if err != nil {
+			_ = file.Close()
+			return nil, fmt.Errorf("failed to create file trace exporter: %w", err)
+		}
+		exporter = &closingExporter{SpanExporter: fileExporter, file: file}
+	default:
+		return nil, fmt.Errorf("unknown trace exporter %q, expected %s or %s", cfg.Exporter, TraceExporterOTLP, TraceExporterFile)
+	}
+
+	res, err := resource.Merge(resource.Default(), resource.NewSchemaless(
+		semconv.ServiceName("github-mcp-server"),
+		semconv.ServiceVersion(version),
+	))
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe trace resource: %w", err)
+	}
+
+	return sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+	), nil
+}
+
+// closingExporter closes the file a span exporter writes to when it is shut down.
+type closingExporter struct {
+	sdktrace.SpanExporter
+	file *os.File
+}
+
+func (e *closingExporter) Shutdown(ctx context.Context) error {
+	err := e.SpanExporter.Shutdown(ctx)
+	if closeErr := e.file.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+// shutdownTracerProvider flushes the spans that haven't been exported yet, if there is a tracer provider.
+func shutdownTracerProvider(logger *logrus.Logger, tp *sdktrace.TracerProvider) {
+	if tp == nil {
+		return
+	}
+	if err := tp.Shutdown(context.Background()); err != nil {
+		logger.WithError(err).Error("failed to shut down tracing")
+	}
+}
+
+// toolTracingMiddleware starts a root span for every tool call. The spans of the GitHub
+// API requests the tool makes are its children.
+func toolTracingMiddleware(tracer trace.Tracer) server.ToolHandlerMiddleware {
+	return func(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+		return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			attrs := []attribute.KeyValue{attribute.String("mcp.tool.name", request.Params.Name)}
+			if session := server.ClientSessionFromContext(ctx); session != nil {
+				attrs = append(attrs, attribute.String("mcp.session.id", session.SessionID()))
+			}
+
+			ctx, span := tracer.Start(ctx, "tools/call "+request.Params.Name,
+				trace.WithNewRoot(),
+				trace.WithSpanKind(trace.SpanKindServer),
+				trace.WithAttributes(attrs...),
+			)
+			defer span.End()
+
+			result, err := next(ctx, request)
+			switch {
+			case err != nil:
+				span.RecordError(err)
+				span.SetStatus(codes.Error, err.Error())
+			case result != nil && result.IsError:
+				span.SetAttributes(attribute.Bool("mcp.tool.is_error", true))
+				span.SetStatus(codes.Error, "tool returned an error")
+			}
+			return result, err
+		}
+	}
+}
+
+// tracingTransport records a span for every request made to the GitHub API.
+type tracingTransport struct {
+	transport http.RoundTripper
+	tracer    trace.Tracer
+}
+
+func (t *tracingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	api := "rest"
+	if strings.HasSuffix(req.URL.Path, "/graphql") {
+		api = "graphql"
+	}
+
+	// The URL is recorded without its query, which can carry pagination cursors and search terms
+	fullURL := *req.URL
+	fullURL.RawQuery = ""
+
+	ctx, span := t.tracer.Start(req.Context(), req.Method+" "+req.URL.Path,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("github.api", api),
+			semconv.HTTPRequestMethodKey.String(req.Method),
+			semconv.URLFull(fullURL.String()),
+			semconv.ServerAddress(req.URL.Hostname()),
+		),
+	)
+	defer span.End()
+
+	resp, err := t.transport.RoundTrip(req.WithContext(ctx))
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+
+	span.SetAttributes(semconv.HTTPResponseStatusCode(resp.StatusCode))
+	if resp.StatusCode >= 400 {
+		span.SetStatus(codes.Error, resp.Status)
+	}
+	return resp, nil
+}
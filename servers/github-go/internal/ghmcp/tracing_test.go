@@ -0,0 +1,99 @@
+package ghmcp
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func Test_Tracing(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/status") {
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer ts.Close()
+
+	exporter := tracetest.NewInMemoryExporter()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	tracer := tp.Tracer(tracerName)
+	client := &http.Client{Transport: &tracingTransport{transport: http.DefaultTransport, tracer: tracer}}
+
+	// A tool that makes two requests, like get_pull_request_status does
+	handler := toolTracingMiddleware(tracer)(func(ctx context.Context, _ mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		for _, path := range []string{"/repos/octocat/hello-world/pulls/1", "/repos/octocat/hello-world/commits/abc/status?per_page=100"} {
+			req, err := http.NewRequestWithContext(ctx, http.MethodGet, ts.URL+path, nil)
+			require.NoError(t, err)
+			resp, err := client.Do(req)
+			require.NoError(t, err)
+			_ = resp.Body.Close()
+		}
+		return mcp.NewToolResultError("failed to get combined status"), nil
+	})
+
+	request := mcp.CallToolRequest{}
+	request.Params.Name = "get_pull_request_status"
+	_, err := handler(context.Background(), request)
+	require.NoError(t, err)
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 3)
+	pull, status, root := spans[0], spans[1], spans[2]
+
+	assert.Equal(t, "tools/call get_pull_request_status", root.Name)
+	assert.False(t, root.Parent.IsValid(), "tool calls are root spans")
+	assert.Equal(t, codes.Error, root.Status.Code)
+
+	assert.Equal(t, "GET /repos/octocat/hello-world/pulls/1", pull.Name)
+	assert.Equal(t, "GET /repos/octocat/hello-world/commits/abc/status", status.Name)
+	for _, child := range []tracetest.SpanStub{pull, status} {
+		assert.Equal(t, root.SpanContext.TraceID(), child.SpanContext.TraceID())
+		assert.Equal(t, root.SpanContext.SpanID(), child.Parent.SpanID())
+	}
+
+	attrs := make(map[string]string)
+	for _, attr := range status.Attributes {
+		attrs[string(attr.Key)] = attr.Value.Emit()
+	}
+	assert.Equal(t, ts.URL+"/repos/octocat/hello-world/commits/abc/status", attrs["url.full"], "queries are not recorded")
+	assert.Equal(t, "404", attrs["http.response.status_code"])
+	assert.Equal(t, "rest", attrs["github.api"])
+	assert.Equal(t, codes.Error, status.Status.Code)
+}
+
+func Test_FileTraceExporter(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "traces.jsonl")
+
+	tp, err := newTracerProvider(context.Background(), &TracingConfig{Exporter: TraceExporterFile, File: path}, "test")
+	require.NoError(t, err)
+
+	_, span := tp.Tracer(tracerName).Start(context.Background(), "tools/call get_me")
+	span.End()
+	require.NoError(t, tp.Shutdown(context.Background()))
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	require.Len(t, lines, 1, "one span per line")
+
+	var exported struct {
+		Name string
+	}
+	require.NoError(t, json.Unmarshal([]byte(lines[0]), &exported))
+	assert.Equal(t, "tools/call get_me", exported.Name)
+
+	_, err = newTracerProvider(context.Background(), &TracingConfig{Exporter: "jaeger"}, "test")
+	require.EqualError(t, err, `unknown trace exporter "jaeger", expected otlp or file`)
+}
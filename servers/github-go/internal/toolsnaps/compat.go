@@ -0,0 +1,313 @@
+package toolsnaps
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// Change is a difference between two snapshots of the same tool or resource template.
+type Change struct {
+	// Snapshot is the name of the snapshot the change was found in.
+	Snapshot string
+	// Breaking is true if clients that work with the old snapshot may fail with the new one.
+	Breaking bool
+	// Description says what changed.
+	Description string
+}
+
+func (c Change) String() string {
+	return c.Snapshot + ": " + c.Description
+}
+
+// ReadDir reads the snapshots in dir, keyed by their names.
+func ReadDir(dir string) (map[string][]byte, error) {
+	if _, err := os.Stat(dir); err != nil {
+		return nil, fmt.Errorf("failed to read snapshots: %w", err)
+	}
+	paths, err := filepath.Glob(filepath.Join(dir, "*.snap"))
+	if err != nil {
+		return nil, err
+	}
+
+	snaps := make(map[string][]byte, len(paths))
+	for _, path := range paths {
+		snap, err := os.ReadFile(path) //nolint:gosec // the snapshot directories are given by the user
+		if err != nil {
+			return nil, fmt.Errorf("failed to read snapshot: %w", err)
+		}
+		snaps[strings.TrimSuffix(filepath.Base(path), ".snap")] = snap
+	}
+	return snaps, nil
+}
+
+// snapshot holds the fields of tool and resource template snapshots that are compared.
+type snapshot struct {
+	Description string         `json:"description"`
+	Annotations map[string]any `json:"annotations"`
+	InputSchema *schema        `json:"inputSchema"`
+
+	Name        string `json:"name"`
+	URITemplate string `json:"uriTemplate"`
+	MIMEType    string `json:"mimeType"`
+}
+
+func (s snapshot) kind() string {
+	if s.InputSchema != nil {
+		return "tool"
+	}
+	return "resource template"
+}
+
+// schema is the part of a JSON schema that clients build arguments from.
+type schema struct {
+	Type        any                `json:"type"`
+	Description string             `json:"description"`
+	Enum        []any              `json:"enum"`
+	Properties  map[string]*schema `json:"properties"`
+	Required    []string           `json:"required"`
+	Items       *schema            `json:"items"`
+}
+
+// Compare classifies the changes from the old to the new snapshots, ordered by snapshot name.
+// Removing a tool, parameter or resource template is breaking, as are requiring a parameter
+// that was optional or new, narrowing the values of an enum, changing the type of a parameter
+// to one that doesn't accept the old values, and changing a URI template. Other changes, such
+// as adding optional parameters or changing descriptions, are not.
+func Compare(oldSnaps, newSnaps map[string][]byte) ([]Change, error) {
+	names := make([]string, 0, len(oldSnaps)+len(newSnaps))
+	for name := range oldSnaps {
+		names = append(names, name)
+	}
+	for name := range newSnaps {
+		if _, ok := oldSnaps[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	var changes []Change
+	for _, name := range names {
+		c := comparison{snapshot: name}
+		oldSnap, err := parseSnapshot(name, oldSnaps[name])
+		if err != nil {
+			return nil, err
+		}
+		newSnap, err := parseSnapshot(name, newSnaps[name])
+		if err != nil {
+			return nil, err
+		}
+
+		switch {
+		case newSnap == nil:
+			c.breaking("%s removed", oldSnap.kind())
+		case oldSnap == nil:
+			c.compatible("%s added", newSnap.kind())
+		case oldSnap.kind() != newSnap.kind():
+			c.breaking("changed from a %s to a %s", oldSnap.kind(), newSnap.kind())
+		case newSnap.InputSchema != nil:
+			c.compareTool(oldSnap, newSnap)
+		default:
+			c.compareResourceTemplate(oldSnap, newSnap)
+		}
+		changes = append(changes, c.changes...)
+	}
+	return changes, nil
+}
+
+func parseSnapshot(name string, snap []byte) (*snapshot, error) {
+	if snap == nil {
+		return nil, nil
+	}
+	var s snapshot
+	if err := json.Unmarshal(snap, &s); err != nil {
+		return nil, fmt.Errorf("failed to parse snapshot %s: %w", name, err)
+	}
+	if s.InputSchema == nil && s.URITemplate == "" {
+		return nil, fmt.Errorf("snapshot %s is neither a tool nor a resource template", name)
+	}
+	return &s, nil
+}
+
+// comparison collects the changes found in a snapshot.
+type comparison struct {
+	snapshot string
+	changes  []Change
+}
+
+func (c *comparison) breaking(format string, args ...any) {
+	c.changes = append(c.changes, Change{Snapshot: c.snapshot, Breaking: true, Description: fmt.Sprintf(format, args...)})
+}
+
+func (c *comparison) compatible(format string, args ...any) {
+	c.changes = append(c.changes, Change{Snapshot: c.snapshot, Description: fmt.Sprintf(format, args...)})
+}
+
+func (c *comparison) compareTool(oldTool, newTool *snapshot) {
+	if oldTool.Description != newTool.Description {
+		c.compatible("description changed")
+	}
+
+	keys := make([]string, 0, len(oldTool.Annotations)+len(newTool.Annotations))
+	for key := range oldTool.Annotations {
+		keys = append(keys, key)
+	}
+	for key := range newTool.Annotations {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range slices.Compact(keys) {
+		oldValue, newValue := formatValue(oldTool.Annotations[key]), formatValue(newTool.Annotations[key])
+		if oldValue != newValue {
+			c.compatible("annotation %s changed from %s to %s", key, oldValue, newValue)
+		}
+	}
+
+	c.compareObject("", oldTool.InputSchema, newTool.InputSchema)
+}
+
+func (c *comparison) compareResourceTemplate(oldTemplate, newTemplate *snapshot) {
+	if oldTemplate.URITemplate != newTemplate.URITemplate {
+		c.breaking("URI template changed from %s to %s", oldTemplate.URITemplate, newTemplate.URITemplate)
+	}
+	if oldTemplate.Name != newTemplate.Name {
+		c.compatible("name changed from %q to %q", oldTemplate.Name, newTemplate.Name)
+	}
+	if oldTemplate.Description != newTemplate.Description {
+		c.compatible("description changed")
+	}
+	if oldTemplate.MIMEType != newTemplate.MIMEType {
+		c.compatible("MIME type changed from %q to %q", oldTemplate.MIMEType, newTemplate.MIMEType)
+	}
+}
+
+// compareObject compares the properties of two object schemas. Their names are prefixed
+// with prefix, to tell nested properties apart, e.g. "files[].path".
+func (c *comparison) compareObject(prefix string, oldSchema, newSchema *schema) {
+	names := make([]string, 0, len(oldSchema.Properties)+len(newSchema.Properties))
+	for name := range oldSchema.Properties {
+		names = append(names, name)
+	}
+	for name := range newSchema.Properties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range slices.Compact(names) {
+		oldProperty, newProperty := oldSchema.Properties[name], newSchema.Properties[name]
+		oldRequired, newRequired := slices.Contains(oldSchema.Required, name), slices.Contains(newSchema.Required, name)
+		path := prefix + name
+
+		switch {
+		case newProperty == nil:
+			c.breaking("parameter %s removed", path)
+		case oldProperty == nil && newRequired:
+			c.breaking("required parameter %s added", path)
+		case oldProperty == nil:
+			c.compatible("parameter %s added", path)
+		default:
+			if !oldRequired && newRequired {
+				c.breaking("parameter %s is now required", path)
+			}
+			if oldRequired && !newRequired {
+				c.compatible("parameter %s is no longer required", path)
+			}
+			c.compareProperty(path, oldProperty, newProperty)
+		}
+	}
+}
+
+func (c *comparison) compareProperty(path string, oldProperty, newProperty *schema) {
+	oldTypes, newTypes := types(oldProperty), types(newProperty)
+	switch {
+	case slices.Equal(oldTypes, newTypes):
+	case newTypes == nil || (oldTypes != nil && isSubset(oldTypes, newTypes)):
+		c.compatible("parameter %s changed type from %s to %s", path, formatTypes(oldTypes), formatTypes(newTypes))
+	default:
+		c.breaking("parameter %s changed type from %s to %s", path, formatTypes(oldTypes), formatTypes(newTypes))
+	}
+
+	oldEnum, newEnum := formatValues(oldProperty.Enum), formatValues(newProperty.Enum)
+	switch {
+	case oldEnum == nil && newEnum != nil:
+		c.breaking("parameter %s is now limited to %s", path, strings.Join(newEnum, ", "))
+	case oldEnum != nil && newEnum == nil:
+		c.compatible("parameter %s is no longer limited to a set of values", path)
+	default:
+		if removed := difference(oldEnum, newEnum); len(removed) > 0 {
+			c.breaking("parameter %s no longer accepts %s", path, strings.Join(removed, ", "))
+		}
+		if added := difference(newEnum, oldEnum); len(added) > 0 {
+			c.compatible("parameter %s now also accepts %s", path, strings.Join(added, ", "))
+		}
+	}
+
+	if oldProperty.Description != newProperty.Description {
+		c.compatible("description of parameter %s changed", path)
+	}
+
+	if oldProperty.Properties != nil || newProperty.Properties != nil {
+		c.compareObject(path+".", oldProperty, newProperty)
+	}
+	if oldProperty.Items != nil && newProperty.Items != nil {
+		c.compareProperty(path+"[]", oldProperty.Items, newProperty.Items)
+	}
+}
+
+// types returns the sorted types a schema accepts, or nil if it accepts any type.
+func types(s *schema) []string {
+	switch t := s.Type.(type) {
+	case string:
+		return []string{t}
+	case []any:
+		names := formatValues(t)
+		sort.Strings(names)
+		return names
+	default:
+		return nil
+	}
+}
+
+func formatTypes(types []string) string {
+	if types == nil {
+		return "any"
+	}
+	return strings.Join(types, " or ")
+}
+
+func formatValue(value any) string {
+	if value == nil {
+		return "unset"
+	}
+	return fmt.Sprint(value)
+}
+
+func formatValues(values []any) []string {
+	if values == nil {
+		return nil
+	}
+	formatted := make([]string, len(values))
+	for i, value := range values {
+		formatted[i] = fmt.Sprint(value)
+	}
+	return formatted
+}
+
+func isSubset(subset, set []string) bool {
+	return len(difference(subset, set)) == 0
+}
+
+// difference returns the values of a that are not in b.
+func difference(a, b []string) []string {
+	var values []string
+	for _, value := range a {
+		if !slices.Contains(b, value) {
+			values = append(values, value)
+		}
+	}
+	return values
+}
@@ -0,0 +1,158 @@
+package toolsnaps
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const issueTool = `{
+  "name": "get_issue",
+  "description": "Get an issue",
+  "annotations": {"readOnlyHint": true},
+  "inputSchema": {
+    "type": "object",
+    "properties": {
+      "owner": {"type": "string", "description": "Repository owner"},
+      "issue_number": {"type": "number"},
+      "state": {"type": "string", "enum": ["open", "closed"]},
+      "labels": {"type": "array", "items": {"type": "object", "properties": {"name": {"type": "string"}}, "required": ["name"]}}
+    },
+    "required": ["owner", "issue_number"]
+  }
+}`
+
+const contentTemplate = `{"uriTemplate": "repo://{owner}/{repo}/contents{/path*}", "name": "Repository Content"}`
+
+func Test_Compare(t *testing.T) {
+	tests := []struct {
+		name     string
+		oldSnaps map[string]string
+		newSnaps map[string]string
+		expected []Change
+	}{
+		{
+			name:     "unchanged",
+			oldSnaps: map[string]string{"get_issue": issueTool, "content": contentTemplate},
+			newSnaps: map[string]string{"get_issue": issueTool, "content": contentTemplate},
+		},
+		{
+			name:     "tool and template added and removed",
+			oldSnaps: map[string]string{"get_issue": issueTool},
+			newSnaps: map[string]string{"content": contentTemplate},
+			expected: []Change{
+				{Snapshot: "content", Description: "resource template added"},
+				{Snapshot: "get_issue", Breaking: true, Description: "tool removed"},
+			},
+		},
+		{
+			name:     "parameters removed and added",
+			oldSnaps: map[string]string{"get_issue": issueTool},
+			newSnaps: map[string]string{"get_issue": `{"inputSchema": {"type": "object", "properties": {
+				"owner": {"type": "string", "description": "Repository owner"},
+				"issue_number": {"type": "number"},
+				"state": {"type": "string", "enum": ["open", "closed"]},
+				"labels": {"type": "array", "items": {"type": "object", "properties": {"name": {"type": "string"}}, "required": ["name"]}},
+				"repo": {"type": "string"},
+				"page": {"type": "number"}
+			}, "required": ["owner", "issue_number", "repo"]}, "description": "Get an issue", "annotations": {"readOnlyHint": true}}`},
+			expected: []Change{
+				{Snapshot: "get_issue", Description: "parameter page added"},
+				{Snapshot: "get_issue", Breaking: true, Description: "required parameter repo added"},
+			},
+		},
+		{
+			name:     "parameter removed and required changed",
+			oldSnaps: map[string]string{"get_issue": issueTool},
+			newSnaps: map[string]string{"get_issue": `{"inputSchema": {"type": "object", "properties": {
+				"owner": {"type": "string", "description": "Repository owner"},
+				"issue_number": {"type": "number"},
+				"state": {"type": "string", "enum": ["open", "closed"]}
+			}, "required": ["issue_number", "state"]}, "description": "Get an issue", "annotations": {"readOnlyHint": true}}`},
+			expected: []Change{
+				{Snapshot: "get_issue", Breaking: true, Description: "parameter labels removed"},
+				{Snapshot: "get_issue", Description: "parameter owner is no longer required"},
+				{Snapshot: "get_issue", Breaking: true, Description: "parameter state is now required"},
+			},
+		},
+		{
+			name:     "types, enums and descriptions changed",
+			oldSnaps: map[string]string{"get_issue": issueTool},
+			newSnaps: map[string]string{"get_issue": `{"inputSchema": {"type": "object", "properties": {
+				"owner": {"type": ["string", "null"], "description": "Owner of the repository"},
+				"issue_number": {"type": "string"},
+				"state": {"type": "string", "enum": ["open", "all"]},
+				"labels": {"type": "array", "items": {"type": "object", "properties": {"name": {"type": "string", "enum": ["bug"]}}}}
+			}, "required": ["owner", "issue_number"]}, "description": "Get an issue by number", "annotations": {"readOnlyHint": false}}`},
+			expected: []Change{
+				{Snapshot: "get_issue", Description: "description changed"},
+				{Snapshot: "get_issue", Description: "annotation readOnlyHint changed from true to false"},
+				{Snapshot: "get_issue", Breaking: true, Description: "parameter issue_number changed type from number to string"},
+				{Snapshot: "get_issue", Description: "parameter labels[].name is no longer required"},
+				{Snapshot: "get_issue", Breaking: true, Description: "parameter labels[].name is now limited to bug"},
+				{Snapshot: "get_issue", Description: "parameter owner changed type from string to null or string"},
+				{Snapshot: "get_issue", Description: "description of parameter owner changed"},
+				{Snapshot: "get_issue", Breaking: true, Description: "parameter state no longer accepts closed"},
+				{Snapshot: "get_issue", Description: "parameter state now also accepts all"},
+			},
+		},
+		{
+			name:     "resource template changed",
+			oldSnaps: map[string]string{"content": contentTemplate},
+			newSnaps: map[string]string{"content": `{"uriTemplate": "repo://{owner}/{repo}/files{/path*}", "name": "Repository Files", "mimeType": "text/plain"}`},
+			expected: []Change{
+				{Snapshot: "content", Breaking: true, Description: "URI template changed from repo://{owner}/{repo}/contents{/path*} to repo://{owner}/{repo}/files{/path*}"},
+				{Snapshot: "content", Description: `name changed from "Repository Content" to "Repository Files"`},
+				{Snapshot: "content", Description: `MIME type changed from "" to "text/plain"`},
+			},
+		},
+		{
+			name:     "tool became a resource template",
+			oldSnaps: map[string]string{"get_issue": issueTool},
+			newSnaps: map[string]string{"get_issue": contentTemplate},
+			expected: []Change{
+				{Snapshot: "get_issue", Breaking: true, Description: "changed from a tool to a resource template"},
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			changes, err := Compare(snaps(tc.oldSnaps), snaps(tc.newSnaps))
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, changes)
+		})
+	}
+}
+
+func Test_CompareInvalidSnapshot(t *testing.T) {
+	_, err := Compare(snaps(map[string]string{"dummy": `{"name": "foo", "value": 42}`}), nil)
+	require.EqualError(t, err, "snapshot dummy is neither a tool nor a resource template")
+
+	_, err = Compare(nil, snaps(map[string]string{"dummy": `not-json`}))
+	require.ErrorContains(t, err, "failed to parse snapshot dummy")
+}
+
+func Test_ReadDir(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "get_issue.snap"), []byte(issueTool), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "README.md"), []byte("not a snapshot"), 0600))
+
+	snaps, err := ReadDir(dir)
+	require.NoError(t, err)
+	assert.Equal(t, map[string][]byte{"get_issue": []byte(issueTool)}, snaps)
+
+	_, err = ReadDir(filepath.Join(dir, "missing"))
+	require.ErrorContains(t, err, "failed to read snapshots")
+}
+
+func snaps(s map[string]string) map[string][]byte {
+	snaps := make(map[string][]byte, len(s))
+	for name, snap := range s {
+		snaps[name] = []byte(snap)
+	}
+	return snaps
+}
@@ -0,0 +1,386 @@
+// Package audit records the calls made to write tools, so that what an agent changed on
+// GitHub can be shown afterwards.
+package audit
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// Outcome is how a tool call ended.
+type Outcome string
+
+const (
+	// OutcomeSuccess means the tool call completed.
+	OutcomeSuccess Outcome = "success"
+	// OutcomeToolError means the tool reported an error to the model, e.g. a GitHub API error.
+	OutcomeToolError Outcome = "tool_error"
+	// OutcomeFailure means the tool call failed with a protocol level error.
+	OutcomeFailure Outcome = "failure"
+	// OutcomeDryRun means the tool call completed in dry-run mode, so the changes it would
+	// have made were held back.
+	OutcomeDryRun Outcome = "dry_run"
+)
+
+// Confirmation is how a call of a destructive tool was confirmed by the user.
+type Confirmation string
+
+const (
+	// ConfirmationConfirmed means the user was asked and accepted.
+	ConfirmationConfirmed Confirmation = "confirmed"
+	// ConfirmationDeclined means the user was asked and declined or cancelled.
+	ConfirmationDeclined Confirmation = "declined"
+	// ConfirmationUnconfirmed means the client could not ask the user, and the call
+	// went ahead without confirmation.
+	ConfirmationUnconfirmed Confirmation = "unconfirmed"
+)
+
+const (
+	// maxErrorLength bounds the error message kept in a record.
+	maxErrorLength = 1024
+
+	// maxResources bounds the number of resources kept in a record.
+	maxResources = 50
+
+	// hashField is appended to every record when hash chaining is enabled.
+	hashField = `,"hash":"`
+)
+
+// Client identifies the MCP client a session belongs to, as reported during initialization.
+type Client struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+// Record is one audited tool call.
+type Record struct {
+	Time      time.Time      `json:"time"`
+	SessionID string         `json:"session_id,omitempty"`
+	Client    *Client        `json:"client,omitempty"`
+	Tool      string         `json:"tool"`
+	Arguments map[string]any `json:"arguments,omitempty"`
+
+	// Resources are the URLs of the GitHub objects the tool created or changed, as found in its result
+	Resources []string `json:"resources,omitempty"`
+	Outcome   Outcome  `json:"outcome"`
+	Error     string   `json:"error,omitempty"`
+
+	// Confirmation is set for tools that ask the user for confirmation before they run
+	Confirmation Confirmation `json:"confirmation,omitempty"`
+
+	// PrevHash and Hash chain records together when hash chaining is enabled
+	PrevHash string `json:"prev_hash,omitempty"`
+	Hash     string `json:"hash,omitempty"`
+
+	// dryRun is set when the tool call held back requests, see SetDryRun
+	dryRun bool
+}
+
+// Logger appends records to a JSONL file. With hash chaining, every record carries the hash
+// of the previous one and a hash over its own content, so that editing, removing or reordering
+// records can be detected with Verify.
+type Logger struct {
+	mu       sync.Mutex
+	file     *os.File
+	chain    bool
+	lastHash string
+
+	now func() time.Time
+}
+
+// Open opens the audit log at path for appending, creating it if needed. When chain is true
+// and the file already has records, the chain continues from the last one.
+func Open(path string, chain bool) (*Logger, error) {
+	l := &Logger{chain: chain, now: time.Now}
+
+	if chain {
+		lastHash, err := lastRecordHash(path)
+		if err != nil {
+			return nil, err
+		}
+		l.lastHash = lastHash
+	}
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open audit log: %w", err)
+	}
+	l.file = file
+	return l, nil
+}
+
+// lastRecordHash returns the hash of the last record in the audit log at path, if there is one.
+func lastRecordHash(path string) (string, error) {
+	file, err := os.Open(path) //nolint:gosec // the path is provided by the operator
+	if errors.Is(err, os.ErrNotExist) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to read audit log: %w", err)
+	}
+	defer func() { _ = file.Close() }()
+
+	var last []byte
+	scanner := newScanner(file)
+	for scanner.Scan() {
+		if line := bytes.TrimSpace(scanner.Bytes()); len(line) > 0 {
+			last = append(last[:0], line...)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return "", fmt.Errorf("failed to read audit log: %w", err)
+	}
+	if last == nil {
+		return "", nil
+	}
+
+	_, hash, ok := splitHash(last)
+	if !ok {
+		return "", fmt.Errorf("the last record of the audit log is not hash chained")
+	}
+	return hash, nil
+}
+
+// Log appends record to the audit log and syncs it to disk.
+func (l *Logger) Log(record Record) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	record.Hash = ""
+	if l.chain {
+		record.PrevHash = l.lastHash
+	}
+
+	line, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("failed to marshal audit record: %w", err)
+	}
+
+	var hash string
+	if l.chain {
+		hash = hashRecord(record.PrevHash, line)
+		line = append(line[:len(line)-1], hashField+hash+`"}`...)
+	}
+
+	if _, err := l.file.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("failed to write audit record: %w", err)
+	}
+	if err := l.file.Sync(); err != nil {
+		return fmt.Errorf("failed to sync audit log: %w", err)
+	}
+
+	l.lastHash = hash
+	return nil
+}
+
+// Close closes the audit log.
+func (l *Logger) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.file.Close()
+}
+
+// hashRecord hashes the JSON of a record, without its hash, together with the previous hash.
+func hashRecord(prevHash string, recordJSON []byte) string {
+	h := sha256.New()
+	h.Write([]byte(prevHash))
+	h.Write([]byte{'\n'})
+	h.Write(recordJSON)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// splitHash splits a hash chained record into the JSON it was hashed over and its hash.
+func splitHash(line []byte) ([]byte, string, bool) {
+	i := bytes.LastIndex(line, []byte(hashField))
+	if i < 0 || !bytes.HasSuffix(line, []byte(`"}`)) {
+		return nil, "", false
+	}
+	hash := string(line[i+len(hashField) : len(line)-2])
+	return append(line[:i:i], '}'), hash, true
+}
+
+// Verify checks the hash chain of the audit log read from r, and returns the number of
+// records it contains. It fails at the first record that was changed, removed or reordered.
+func Verify(r io.Reader) (int, error) {
+	var (
+		prevHash string
+		count    int
+	)
+
+	scanner := newScanner(r)
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		count++
+
+		body, hash, ok := splitHash(line)
+		if !ok {
+			return count, fmt.Errorf("record %d is not hash chained", count)
+		}
+
+		var record Record
+		if err := json.Unmarshal(body, &record); err != nil {
+			return count, fmt.Errorf("record %d is not valid JSON: %w", count, err)
+		}
+		if record.PrevHash != prevHash {
+			return count, fmt.Errorf("record %d does not follow the previous record", count)
+		}
+		if hashRecord(prevHash, body) != hash {
+			return count, fmt.Errorf("record %d has been modified", count)
+		}
+		prevHash = hash
+	}
+	if err := scanner.Err(); err != nil {
+		return count, fmt.Errorf("failed to read audit log: %w", err)
+	}
+	return count, nil
+}
+
+func newScanner(r io.Reader) *bufio.Scanner {
+	scanner := bufio.NewScanner(r)
+	// Records hold redacted arguments, but tool results can still make them long
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	return scanner
+}
+
+// Middleware records every call of the tools it wraps. It is meant to wrap write tools,
+// see toolsets.ToolsetGroup.WrapWriteToolHandlers. Failing to write the audit record fails
+// the tool call, since an unaudited change must not go unnoticed.
+func (l *Logger) Middleware(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		record := Record{
+			Time:      l.now().UTC(),
+			Tool:      request.Params.Name,
+			Arguments: RedactArguments(request.GetArguments()),
+		}
+		if session := server.ClientSessionFromContext(ctx); session != nil {
+			record.SessionID = session.SessionID()
+			if withInfo, ok := session.(server.SessionWithClientInfo); ok {
+				if info := withInfo.GetClientInfo(); info.Name != "" {
+					record.Client = &Client{Name: info.Name, Version: info.Version}
+				}
+			}
+		}
+
+		result, err := next(context.WithValue(ctx, recordCtxKey{}, &record), request)
+
+		switch {
+		case err != nil:
+			record.Outcome = OutcomeFailure
+			record.Error = truncate(err.Error(), maxErrorLength)
+		case result != nil && result.IsError:
+			record.Outcome = OutcomeToolError
+			record.Error = truncate(resultText(result), maxErrorLength)
+		case record.dryRun:
+			// The result only reflects placeholder responses, so it names no real resources
+			record.Outcome = OutcomeDryRun
+		default:
+			record.Outcome = OutcomeSuccess
+			record.Resources = touchedResources(result)
+		}
+
+		if logErr := l.Log(record); logErr != nil {
+			return nil, fmt.Errorf("tool call was not audited: %w", logErr)
+		}
+		return result, err
+	}
+}
+
+type recordCtxKey struct{}
+
+// SetConfirmation records how the tool call audited in ctx was confirmed. It does nothing
+// if the call is not audited.
+func SetConfirmation(ctx context.Context, confirmation Confirmation) {
+	if record, ok := ctx.Value(recordCtxKey{}).(*Record); ok {
+		record.Confirmation = confirmation
+	}
+}
+
+// SetDryRun records that the tool call audited in ctx held back requests that would have
+// changed something, because the server runs in dry-run mode. It does nothing if the call
+// is not audited.
+func SetDryRun(ctx context.Context) {
+	if record, ok := ctx.Value(recordCtxKey{}).(*Record); ok {
+		record.dryRun = true
+	}
+}
+
+func resultText(result *mcp.CallToolResult) string {
+	var texts []string
+	for _, content := range result.Content {
+		if text, ok := content.(mcp.TextContent); ok {
+			texts = append(texts, text.Text)
+		}
+	}
+	return strings.Join(texts, "\n")
+}
+
+// touchedResources collects the html_url of the objects in a tool result, which is how
+// the GitHub API identifies what a write created or changed, e.g. an issue, a commit or
+// a pull request. Nested objects are searched too, for results such as file updates that
+// return both the content and the commit.
+func touchedResources(result *mcp.CallToolResult) []string {
+	if result == nil {
+		return nil
+	}
+
+	var resources []string
+	seen := make(map[string]bool)
+	var collect func(value any, depth int)
+	collect = func(value any, depth int) {
+		if depth > 3 || len(resources) >= maxResources {
+			return
+		}
+		switch v := value.(type) {
+		case map[string]any:
+			if url, ok := v["html_url"].(string); ok && url != "" && !seen[url] {
+				seen[url] = true
+				resources = append(resources, url)
+			}
+			for key, nested := range v {
+				if key != "html_url" {
+					collect(nested, depth+1)
+				}
+			}
+		case []any:
+			for _, nested := range v {
+				collect(nested, depth+1)
+			}
+		}
+	}
+
+	for _, content := range result.Content {
+		text, ok := content.(mcp.TextContent)
+		if !ok {
+			continue
+		}
+		var value any
+		if err := json.Unmarshal([]byte(text.Text), &value); err == nil {
+			collect(value, 0)
+		}
+	}
+	return resources
+}
+
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return s[:n] + "..."
+}
@@ -0,0 +1,206 @@
+package audit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func readRecords(t *testing.T, path string) []Record {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+
+	var records []Record
+	for _, line := range strings.Split(strings.TrimSpace(string(data)), "\n") {
+		var record Record
+		require.NoError(t, json.Unmarshal([]byte(line), &record))
+		records = append(records, record)
+	}
+	return records
+}
+
+func callRequest(name string, args map[string]any) mcp.CallToolRequest {
+	request := mcp.CallToolRequest{}
+	request.Params.Name = name
+	request.Params.Arguments = args
+	return request
+}
+
+// clientSession is a client session that reports the client it belongs to.
+type clientSession struct {
+	id   string
+	info mcp.Implementation
+}
+
+func (s *clientSession) SessionID() string                                   { return s.id }
+func (s *clientSession) NotificationChannel() chan<- mcp.JSONRPCNotification { return nil }
+func (s *clientSession) Initialize()                                         {}
+func (s *clientSession) Initialized() bool                                   { return true }
+func (s *clientSession) GetClientInfo() mcp.Implementation                   { return s.info }
+func (s *clientSession) SetClientInfo(info mcp.Implementation)               { s.info = info }
+func (s *clientSession) GetClientCapabilities() mcp.ClientCapabilities {
+	return mcp.ClientCapabilities{}
+}
+func (s *clientSession) SetClientCapabilities(mcp.ClientCapabilities) {}
+
+func Test_Middleware(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	logger, err := Open(path, false)
+	require.NoError(t, err)
+	logger.now = func() time.Time { return time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC) }
+
+	handler := logger.Middleware(func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		switch request.Params.Name {
+		case "push_files":
+			SetDryRun(ctx)
+			return mcp.NewToolResultText(`{"sha": "dry-run-sha-2", "html_url": ""}`), nil
+		case "create_issue":
+			return mcp.NewToolResultText(`{"number": 42, "html_url": "https://github.com/octocat/hello-world/issues/42", "user": {"login": "octocat", "html_url": "https://github.com/octocat"}}`), nil
+		case "merge_pull_request":
+			return mcp.NewToolResultError("failed to merge pull request: 405 Pull Request is not mergeable"), nil
+		default:
+			return nil, errors.New("boom")
+		}
+	})
+
+	session := &clientSession{id: "session-1", info: mcp.Implementation{Name: "test-client", Version: "1.0.0"}}
+	ctx := server.NewMCPServer("test", "1.0.0").WithContext(context.Background(), session)
+
+	_, err = handler(ctx, callRequest("create_issue", map[string]any{"owner": "octocat", "repo": "hello-world", "title": "Bug"}))
+	require.NoError(t, err)
+	_, err = handler(ctx, callRequest("merge_pull_request", map[string]any{"owner": "octocat", "repo": "hello-world", "pullNumber": float64(1)}))
+	require.NoError(t, err)
+	_, err = handler(context.Background(), callRequest("delete_file", nil))
+	require.EqualError(t, err, "boom")
+	_, err = handler(ctx, callRequest("push_files", map[string]any{"owner": "octocat", "repo": "hello-world", "branch": "main"}))
+	require.NoError(t, err)
+	require.NoError(t, logger.Close())
+
+	records := readRecords(t, path)
+	require.Len(t, records, 4)
+
+	assert.Equal(t, Record{
+		Time:      time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC),
+		SessionID: "session-1",
+		Client:    &Client{Name: "test-client", Version: "1.0.0"},
+		Tool:      "create_issue",
+		Arguments: map[string]any{"owner": "octocat", "repo": "hello-world", "title": "Bug"},
+		Resources: []string{"https://github.com/octocat/hello-world/issues/42", "https://github.com/octocat"},
+		Outcome:   OutcomeSuccess,
+	}, records[0])
+
+	assert.Equal(t, OutcomeToolError, records[1].Outcome)
+	assert.Equal(t, "failed to merge pull request: 405 Pull Request is not mergeable", records[1].Error)
+	assert.Empty(t, records[1].Resources)
+
+	assert.Equal(t, OutcomeFailure, records[2].Outcome)
+	assert.Equal(t, "boom", records[2].Error)
+	assert.Empty(t, records[2].SessionID)
+	assert.Nil(t, records[2].Client)
+
+	assert.Equal(t, OutcomeDryRun, records[3].Outcome)
+	assert.Empty(t, records[3].Resources)
+}
+
+func Test_HashChain(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+
+	logger, err := Open(path, true)
+	require.NoError(t, err)
+	require.NoError(t, logger.Log(Record{Tool: "create_issue", Outcome: OutcomeSuccess}))
+	require.NoError(t, logger.Log(Record{Tool: "add_issue_comment", Outcome: OutcomeSuccess}))
+	require.NoError(t, logger.Close())
+
+	// Reopening the log continues the chain
+	logger, err = Open(path, true)
+	require.NoError(t, err)
+	require.NoError(t, logger.Log(Record{Tool: "merge_pull_request", Outcome: OutcomeSuccess}))
+	require.NoError(t, logger.Close())
+
+	records := readRecords(t, path)
+	require.Len(t, records, 3)
+	assert.Empty(t, records[0].PrevHash)
+	assert.Equal(t, records[0].Hash, records[1].PrevHash)
+	assert.Equal(t, records[1].Hash, records[2].PrevHash)
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	count, err := Verify(bytes.NewReader(data))
+	require.NoError(t, err)
+	assert.Equal(t, 3, count)
+
+	lines := strings.SplitAfter(string(data), "\n")
+
+	tests := []struct {
+		name          string
+		log           string
+		expectedError string
+	}{
+		{
+			name:          "modified record",
+			log:           strings.Replace(string(data), "add_issue_comment", "get_issue_comments", 1),
+			expectedError: "record 2 has been modified",
+		},
+		{
+			name:          "removed record",
+			log:           lines[0] + lines[2],
+			expectedError: "record 2 does not follow the previous record",
+		},
+		{
+			name:          "reordered records",
+			log:           lines[1] + lines[0] + lines[2],
+			expectedError: "record 1 does not follow the previous record",
+		},
+		{
+			name:          "unchained record",
+			log:           string(data) + `{"tool":"delete_file","outcome":"success"}` + "\n",
+			expectedError: "record 4 is not hash chained",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := Verify(strings.NewReader(tc.log))
+			require.EqualError(t, err, tc.expectedError)
+		})
+	}
+}
+
+func Test_RedactArguments(t *testing.T) {
+	long := strings.Repeat("x", maxArgumentLength+1)
+
+	redacted := RedactArguments(map[string]any{
+		"owner":   "octocat",
+		"path":    "README.md",
+		"content": "hello",
+		"body":    long,
+		"files": []any{
+			map[string]any{"path": "a.txt", "content": "secret"},
+		},
+		"pullNumber": float64(1),
+	})
+
+	assert.Equal(t, "octocat", redacted["owner"])
+	assert.Equal(t, "README.md", redacted["path"])
+	assert.Equal(t, float64(1), redacted["pullNumber"])
+	assert.Equal(t, "[redacted 5 bytes sha256:2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824]", redacted["content"])
+	assert.True(t, strings.HasPrefix(redacted["body"].(string), "[redacted 257 bytes sha256:"))
+
+	file := redacted["files"].([]any)[0].(map[string]any)
+	assert.Equal(t, "a.txt", file["path"])
+	assert.True(t, strings.HasPrefix(file["content"].(string), "[redacted 6 bytes sha256:"))
+
+	assert.Nil(t, RedactArguments(nil))
+}
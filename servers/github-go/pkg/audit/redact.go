@@ -0,0 +1,68 @@
+package audit
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+// maxArgumentLength is the length above which string arguments are replaced by a digest.
+const maxArgumentLength = 256
+
+// sensitiveArguments are argument names whose values are never written to the audit log.
+// File contents are included since they are both large and a common place for secrets.
+var sensitiveArguments = []string{"content", "token", "secret", "password", "private_key"}
+
+// RedactArguments returns a copy of the tool arguments that is safe to keep in the audit
+// log. Values of sensitive arguments and long strings are replaced by their length and a
+// SHA-256 digest, which still allows matching them against a known value.
+func RedactArguments(args map[string]any) map[string]any {
+	if args == nil {
+		return nil
+	}
+	redacted, _ := redactValue("", args).(map[string]any)
+	return redacted
+}
+
+func redactValue(name string, value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		redacted := make(map[string]any, len(v))
+		for key, nested := range v {
+			redacted[key] = redactValue(key, nested)
+		}
+		return redacted
+	case []any:
+		redacted := make([]any, len(v))
+		for i, nested := range v {
+			redacted[i] = redactValue(name, nested)
+		}
+		return redacted
+	case string:
+		if isSensitive(name) || len(v) > maxArgumentLength {
+			return digest(v)
+		}
+		return v
+	default:
+		if isSensitive(name) && v != nil {
+			return digest(fmt.Sprint(v))
+		}
+		return v
+	}
+}
+
+func isSensitive(name string) bool {
+	name = strings.ToLower(name)
+	for _, sensitive := range sensitiveArguments {
+		if strings.Contains(name, sensitive) {
+			return true
+		}
+	}
+	return false
+}
+
+func digest(value string) string {
+	sum := sha256.Sum256([]byte(value))
+	return fmt.Sprintf("[redacted %d bytes sha256:%s]", len(value), hex.EncodeToString(sum[:]))
+}
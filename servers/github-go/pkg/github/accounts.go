@@ -0,0 +1,34 @@
+package github
+
+import (
+	"context"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// DefaultAccountName is how the account the server was started with is referred to
+// when named accounts are configured alongside it.
+const DefaultAccountName = "default"
+
+type accountCtxKey struct{}
+
+// ContextWithAccount returns a copy of ctx that selects the named account for GitHub
+// API requests, e.g. when a tool is called with the "account" argument.
+func ContextWithAccount(ctx context.Context, account string) context.Context {
+	return context.WithValue(ctx, accountCtxKey{}, account)
+}
+
+// AccountFromContext returns the account selected by ContextWithAccount, if any.
+func AccountFromContext(ctx context.Context) (string, bool) {
+	account, ok := ctx.Value(accountCtxKey{}).(string)
+	return account, ok && account != ""
+}
+
+// WithAccount returns a ToolOption that adds the optional "account" parameter to a tool,
+// which selects one of the named accounts the server has been configured with.
+func WithAccount(accounts []string) mcp.ToolOption {
+	return mcp.WithString("account",
+		mcp.Description("Name of the configured GitHub account to act as. Uses the default account if omitted"),
+		mcp.Enum(accounts...),
+	)
+}
@@ -0,0 +1,924 @@
+package github
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/github/github-mcp-server/pkg/translations"
+	"github.com/google/go-github/v72/github"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+const (
+	// defaultLogTailLines is how many lines from the end of each log are returned by default.
+	defaultLogTailLines = 500
+	// maxLogBytes bounds the size of a log, or of a run's log archive, that is downloaded.
+	maxLogBytes = 64 << 20
+	// maxLogRedirects is how many redirects are followed to find where logs are stored.
+	maxLogRedirects = 3
+)
+
+// JobLogs is the end of the log of a workflow job.
+type JobLogs struct {
+	JobID      int64  `json:"job_id"`
+	Name       string `json:"name,omitempty"`
+	Conclusion string `json:"conclusion,omitempty"`
+	TotalLines int    `json:"total_lines"`
+	Logs       string `json:"logs"`
+}
+
+// WorkflowRunLogs is the end of each job log in the log archive of a workflow run.
+type WorkflowRunLogs struct {
+	RunID int64            `json:"run_id"`
+	Files []WorkflowRunLog `json:"files"`
+}
+
+// WorkflowRunLog is the end of one file of a workflow run's log archive.
+type WorkflowRunLog struct {
+	Name       string `json:"name"`
+	TotalLines int    `json:"total_lines"`
+	Logs       string `json:"logs"`
+}
+
+// ListWorkflows creates a tool to list the workflows of a repository.
+func ListWorkflows(getClient GetClientFn, t translations.TranslationHelperFunc) (tool mcp.Tool, handler server.ToolHandlerFunc) {
+	return mcp.NewTool("list_workflows",
+			mcp.WithDescription(t("TOOL_LIST_WORKFLOWS_DESCRIPTION", "List the GitHub Actions workflows of a repository")),
+			mcp.WithToolAnnotation(mcp.ToolAnnotation{
+				Title:        t("TOOL_LIST_WORKFLOWS_USER_TITLE", "List workflows"),
+				ReadOnlyHint: toBoolPtr(true),
+			}),
+			mcp.WithString("owner",
+				mcp.Required(),
+				mcp.Description("Repository owner"),
+			),
+			mcp.WithString("repo",
+				mcp.Required(),
+				mcp.Description("Repository name"),
+			),
+			WithPagination(),
+		),
+		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			owner, err := requiredParam[string](request, "owner")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			repo, err := requiredParam[string](request, "repo")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			pagination, err := OptionalPaginationParams(request)
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+
+			client, err := getClient(ctx)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get GitHub client: %w", err)
+			}
+			workflows, resp, err := client.Actions.ListWorkflows(ctx, owner, repo, &github.ListOptions{
+				Page:    pagination.page,
+				PerPage: pagination.perPage,
+			})
+			if err != nil {
+				return nil, fmt.Errorf("failed to list workflows: %w", err)
+			}
+			defer func() { _ = resp.Body.Close() }()
+
+			if resp.StatusCode != http.StatusOK {
+				body, err := io.ReadAll(resp.Body)
+				if err != nil {
+					return nil, fmt.Errorf("failed to read response body: %w", err)
+				}
+				return mcp.NewToolResultError(fmt.Sprintf("failed to list workflows: %s", string(body))), nil
+			}
+
+			r, err := json.Marshal(workflows)
+			if err != nil {
+				return nil, fmt.Errorf("failed to marshal response: %w", err)
+			}
+
+			return mcp.NewToolResultText(string(r)), nil
+		}
+}
+
+// ListWorkflowRuns creates a tool to list the workflow runs of a repository, or of one of its workflows.
+func ListWorkflowRuns(getClient GetClientFn, t translations.TranslationHelperFunc) (tool mcp.Tool, handler server.ToolHandlerFunc) {
+	return mcp.NewTool("list_workflow_runs",
+			mcp.WithDescription(t("TOOL_LIST_WORKFLOW_RUNS_DESCRIPTION", "List the GitHub Actions workflow runs of a repository, newest first, optionally only those of one workflow")),
+			mcp.WithToolAnnotation(mcp.ToolAnnotation{
+				Title:        t("TOOL_LIST_WORKFLOW_RUNS_USER_TITLE", "List workflow runs"),
+				ReadOnlyHint: toBoolPtr(true),
+			}),
+			mcp.WithString("owner",
+				mcp.Required(),
+				mcp.Description("Repository owner"),
+			),
+			mcp.WithString("repo",
+				mcp.Required(),
+				mcp.Description("Repository name"),
+			),
+			mcp.WithString("workflow_id",
+				mcp.Description("The ID of the workflow, or its file name such as 'ci.yml'. If omitted, runs of all workflows are listed."),
+			),
+			mcp.WithString("actor",
+				mcp.Description("Only list runs triggered by this user"),
+			),
+			mcp.WithString("branch",
+				mcp.Description("Only list runs for this branch"),
+			),
+			mcp.WithString("event",
+				mcp.Description("Only list runs triggered by this event, such as 'push' or 'pull_request'"),
+			),
+			mcp.WithString("status",
+				mcp.Description("Only list runs with this status or conclusion"),
+				mcp.Enum("queued", "in_progress", "completed", "requested", "waiting", "pending", "action_required", "cancelled", "failure", "neutral", "skipped", "stale", "success", "timed_out"),
+			),
+			WithPagination(),
+		),
+		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			owner, err := requiredParam[string](request, "owner")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			repo, err := requiredParam[string](request, "repo")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			workflowID, err := OptionalParam[string](request, "workflow_id")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			actor, err := OptionalParam[string](request, "actor")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			branch, err := OptionalParam[string](request, "branch")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			event, err := OptionalParam[string](request, "event")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			status, err := OptionalParam[string](request, "status")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			pagination, err := OptionalPaginationParams(request)
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+
+			client, err := getClient(ctx)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get GitHub client: %w", err)
+			}
+
+			opts := &github.ListWorkflowRunsOptions{
+				Actor:  actor,
+				Branch: branch,
+				Event:  event,
+				Status: status,
+				ListOptions: github.ListOptions{
+					Page:    pagination.page,
+					PerPage: pagination.perPage,
+				},
+			}
+			var runs *github.WorkflowRuns
+			var resp *github.Response
+			switch id, parseErr := strconv.ParseInt(workflowID, 10, 64); {
+			case workflowID == "":
+				runs, resp, err = client.Actions.ListRepositoryWorkflowRuns(ctx, owner, repo, opts)
+			case parseErr == nil:
+				runs, resp, err = client.Actions.ListWorkflowRunsByID(ctx, owner, repo, id, opts)
+			default:
+				runs, resp, err = client.Actions.ListWorkflowRunsByFileName(ctx, owner, repo, workflowID, opts)
+			}
+			if err != nil {
+				return nil, fmt.Errorf("failed to list workflow runs: %w", err)
+			}
+			defer func() { _ = resp.Body.Close() }()
+
+			if resp.StatusCode != http.StatusOK {
+				body, err := io.ReadAll(resp.Body)
+				if err != nil {
+					return nil, fmt.Errorf("failed to read response body: %w", err)
+				}
+				return mcp.NewToolResultError(fmt.Sprintf("failed to list workflow runs: %s", string(body))), nil
+			}
+
+			r, err := json.Marshal(runs)
+			if err != nil {
+				return nil, fmt.Errorf("failed to marshal response: %w", err)
+			}
+
+			return mcp.NewToolResultText(string(r)), nil
+		}
+}
+
+// GetWorkflowRun creates a tool to get the details of a workflow run.
+func GetWorkflowRun(getClient GetClientFn, t translations.TranslationHelperFunc) (tool mcp.Tool, handler server.ToolHandlerFunc) {
+	return mcp.NewTool("get_workflow_run",
+			mcp.WithDescription(t("TOOL_GET_WORKFLOW_RUN_DESCRIPTION", "Get the details of a GitHub Actions workflow run, including its status and conclusion")),
+			mcp.WithToolAnnotation(mcp.ToolAnnotation{
+				Title:        t("TOOL_GET_WORKFLOW_RUN_USER_TITLE", "Get workflow run"),
+				ReadOnlyHint: toBoolPtr(true),
+			}),
+			mcp.WithString("owner",
+				mcp.Required(),
+				mcp.Description("Repository owner"),
+			),
+			mcp.WithString("repo",
+				mcp.Required(),
+				mcp.Description("Repository name"),
+			),
+			mcp.WithNumber("run_id",
+				mcp.Required(),
+				mcp.Description("The ID of the workflow run"),
+			),
+		),
+		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			owner, err := requiredParam[string](request, "owner")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			repo, err := requiredParam[string](request, "repo")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			runID, err := RequiredInt(request, "run_id")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+
+			client, err := getClient(ctx)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get GitHub client: %w", err)
+			}
+			run, resp, err := client.Actions.GetWorkflowRunByID(ctx, owner, repo, int64(runID))
+			if err != nil {
+				return nil, fmt.Errorf("failed to get workflow run: %w", err)
+			}
+			defer func() { _ = resp.Body.Close() }()
+
+			if resp.StatusCode != http.StatusOK {
+				body, err := io.ReadAll(resp.Body)
+				if err != nil {
+					return nil, fmt.Errorf("failed to read response body: %w", err)
+				}
+				return mcp.NewToolResultError(fmt.Sprintf("failed to get workflow run: %s", string(body))), nil
+			}
+
+			r, err := json.Marshal(run)
+			if err != nil {
+				return nil, fmt.Errorf("failed to marshal response: %w", err)
+			}
+
+			return mcp.NewToolResultText(string(r)), nil
+		}
+}
+
+// ListWorkflowJobs creates a tool to list the jobs of a workflow run.
+func ListWorkflowJobs(getClient GetClientFn, t translations.TranslationHelperFunc) (tool mcp.Tool, handler server.ToolHandlerFunc) {
+	return mcp.NewTool("list_workflow_jobs",
+			mcp.WithDescription(t("TOOL_LIST_WORKFLOW_JOBS_DESCRIPTION", "List the jobs of a GitHub Actions workflow run, with the status and conclusion of each job and its steps")),
+			mcp.WithToolAnnotation(mcp.ToolAnnotation{
+				Title:        t("TOOL_LIST_WORKFLOW_JOBS_USER_TITLE", "List workflow jobs"),
+				ReadOnlyHint: toBoolPtr(true),
+			}),
+			mcp.WithString("owner",
+				mcp.Required(),
+				mcp.Description("Repository owner"),
+			),
+			mcp.WithString("repo",
+				mcp.Required(),
+				mcp.Description("Repository name"),
+			),
+			mcp.WithNumber("run_id",
+				mcp.Required(),
+				mcp.Description("The ID of the workflow run"),
+			),
+			mcp.WithString("filter",
+				mcp.Description("Whether to list only the jobs of the latest attempt of the run, or of all attempts"),
+				mcp.Enum("latest", "all"),
+			),
+			WithPagination(),
+		),
+		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			owner, err := requiredParam[string](request, "owner")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			repo, err := requiredParam[string](request, "repo")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			runID, err := RequiredInt(request, "run_id")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			filter, err := OptionalParam[string](request, "filter")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			pagination, err := OptionalPaginationParams(request)
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+
+			client, err := getClient(ctx)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get GitHub client: %w", err)
+			}
+			jobs, resp, err := client.Actions.ListWorkflowJobs(ctx, owner, repo, int64(runID), &github.ListWorkflowJobsOptions{
+				Filter: filter,
+				ListOptions: github.ListOptions{
+					Page:    pagination.page,
+					PerPage: pagination.perPage,
+				},
+			})
+			if err != nil {
+				return nil, fmt.Errorf("failed to list workflow jobs: %w", err)
+			}
+			defer func() { _ = resp.Body.Close() }()
+
+			if resp.StatusCode != http.StatusOK {
+				body, err := io.ReadAll(resp.Body)
+				if err != nil {
+					return nil, fmt.Errorf("failed to read response body: %w", err)
+				}
+				return mcp.NewToolResultError(fmt.Sprintf("failed to list workflow jobs: %s", string(body))), nil
+			}
+
+			r, err := json.Marshal(jobs)
+			if err != nil {
+				return nil, fmt.Errorf("failed to marshal response: %w", err)
+			}
+
+			return mcp.NewToolResultText(string(r)), nil
+		}
+}
+
+// GetJobLogs creates a tool to get the end of the logs of a workflow job, or of the failed
+// jobs of a workflow run.
+func GetJobLogs(getClient GetClientFn, t translations.TranslationHelperFunc) (tool mcp.Tool, handler server.ToolHandlerFunc) {
+	return mcp.NewTool("get_job_logs",
+			mcp.WithDescription(t("TOOL_GET_JOB_LOGS_DESCRIPTION", "Get the last lines of the logs of a GitHub Actions job. Pass job_id for one job, or run_id for all failed jobs of the latest attempt of a workflow run, which is the quickest way to find out why a run failed.")),
+			mcp.WithToolAnnotation(mcp.ToolAnnotation{
+				Title:        t("TOOL_GET_JOB_LOGS_USER_TITLE", "Get job logs"),
+				ReadOnlyHint: toBoolPtr(true),
+			}),
+			mcp.WithString("owner",
+				mcp.Required(),
+				mcp.Description("Repository owner"),
+			),
+			mcp.WithString("repo",
+				mcp.Required(),
+				mcp.Description("Repository name"),
+			),
+			mcp.WithNumber("job_id",
+				mcp.Description("The ID of the job. Either job_id or run_id is required."),
+			),
+			mcp.WithNumber("run_id",
+				mcp.Description("The ID of a workflow run, to get the logs of its failed jobs. Either job_id or run_id is required."),
+			),
+			mcp.WithNumber("tail_lines",
+				mcp.Description("Number of lines to return from the end of each log"),
+				mcp.DefaultNumber(defaultLogTailLines),
+				mcp.Min(1),
+			),
+		),
+		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			owner, err := requiredParam[string](request, "owner")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			repo, err := requiredParam[string](request, "repo")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			jobID, err := OptionalIntParam(request, "job_id")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			runID, err := OptionalIntParam(request, "run_id")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			tailLines, err := OptionalIntParamWithDefault(request, "tail_lines", defaultLogTailLines)
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			if (jobID == 0) == (runID == 0) {
+				return mcp.NewToolResultError("exactly one of job_id and run_id must be provided"), nil
+			}
+
+			client, err := getClient(ctx)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get GitHub client: %w", err)
+			}
+
+			var jobs []*github.WorkflowJob
+			if jobID != 0 {
+				job, resp, err := client.Actions.GetWorkflowJobByID(ctx, owner, repo, int64(jobID))
+				if err != nil {
+					return nil, fmt.Errorf("failed to get workflow job: %w", err)
+				}
+				_ = resp.Body.Close()
+				jobs = append(jobs, job)
+			} else {
+				opts := &github.ListWorkflowJobsOptions{Filter: "latest", ListOptions: github.ListOptions{PerPage: 100}}
+				for {
+					page, resp, err := client.Actions.ListWorkflowJobs(ctx, owner, repo, int64(runID), opts)
+					if err != nil {
+						return nil, fmt.Errorf("failed to list workflow jobs: %w", err)
+					}
+					_ = resp.Body.Close()
+					for _, job := range page.Jobs {
+						if job.GetConclusion() == "failure" || job.GetConclusion() == "timed_out" {
+							jobs = append(jobs, job)
+						}
+					}
+					if resp.NextPage == 0 {
+						break
+					}
+					opts.Page = resp.NextPage
+				}
+				if len(jobs) == 0 {
+					return mcp.NewToolResultText(fmt.Sprintf("workflow run %d has no failed jobs", runID)), nil
+				}
+			}
+
+			logs := make([]JobLogs, 0, len(jobs))
+			for _, job := range jobs {
+				url, resp, err := client.Actions.GetWorkflowJobLogs(ctx, owner, repo, job.GetID(), maxLogRedirects)
+				if err != nil {
+					return nil, fmt.Errorf("failed to get logs of job %d: %w", job.GetID(), err)
+				}
+				_ = resp.Body.Close()
+
+				content, err := downloadLogs(ctx, url.String())
+				if err != nil {
+					return nil, fmt.Errorf("failed to download logs of job %d: %w", job.GetID(), err)
+				}
+				tail, total := lastLines(string(content), tailLines)
+				logs = append(logs, JobLogs{
+					JobID:      job.GetID(),
+					Name:       job.GetName(),
+					Conclusion: job.GetConclusion(),
+					TotalLines: total,
+					Logs:       tail,
+				})
+			}
+
+			r, err := json.Marshal(logs)
+			if err != nil {
+				return nil, fmt.Errorf("failed to marshal response: %w", err)
+			}
+
+			return mcp.NewToolResultText(string(r)), nil
+		}
+}
+
+// GetWorkflowRunLogs creates a tool to get the end of each job log in the log archive of a
+// workflow run.
+func GetWorkflowRunLogs(getClient GetClientFn, t translations.TranslationHelperFunc) (tool mcp.Tool, handler server.ToolHandlerFunc) {
+	return mcp.NewTool("get_workflow_run_logs",
+			mcp.WithDescription(t("TOOL_GET_WORKFLOW_RUN_LOGS_DESCRIPTION", "Download the log archive of a GitHub Actions workflow run and get the last lines of the log of each of its jobs. Prefer get_job_logs when only failed jobs matter.")),
+			mcp.WithToolAnnotation(mcp.ToolAnnotation{
+				Title:        t("TOOL_GET_WORKFLOW_RUN_LOGS_USER_TITLE", "Get workflow run logs"),
+				ReadOnlyHint: toBoolPtr(true),
+			}),
+			mcp.WithString("owner",
+				mcp.Required(),
+				mcp.Description("Repository owner"),
+			),
+			mcp.WithString("repo",
+				mcp.Required(),
+				mcp.Description("Repository name"),
+			),
+			mcp.WithNumber("run_id",
+				mcp.Required(),
+				mcp.Description("The ID of the workflow run"),
+			),
+			mcp.WithNumber("tail_lines",
+				mcp.Description("Number of lines to return from the end of each job log"),
+				mcp.DefaultNumber(defaultLogTailLines),
+				mcp.Min(1),
+			),
+		),
+		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			owner, err := requiredParam[string](request, "owner")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			repo, err := requiredParam[string](request, "repo")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			runID, err := RequiredInt(request, "run_id")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			tailLines, err := OptionalIntParamWithDefault(request, "tail_lines", defaultLogTailLines)
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+
+			client, err := getClient(ctx)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get GitHub client: %w", err)
+			}
+			url, resp, err := client.Actions.GetWorkflowRunLogs(ctx, owner, repo, int64(runID), maxLogRedirects)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get workflow run logs: %w", err)
+			}
+			_ = resp.Body.Close()
+
+			archive, err := downloadLogs(ctx, url.String())
+			if err != nil {
+				return nil, fmt.Errorf("failed to download workflow run logs: %w", err)
+			}
+			files, err := tailLogArchive(archive, tailLines)
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+
+			r, err := json.Marshal(WorkflowRunLogs{RunID: int64(runID), Files: files})
+			if err != nil {
+				return nil, fmt.Errorf("failed to marshal response: %w", err)
+			}
+
+			return mcp.NewToolResultText(string(r)), nil
+		}
+}
+
+// ListWorkflowRunArtifacts creates a tool to list the artifacts of a workflow run.
+func ListWorkflowRunArtifacts(getClient GetClientFn, t translations.TranslationHelperFunc) (tool mcp.Tool, handler server.ToolHandlerFunc) {
+	return mcp.NewTool("list_workflow_run_artifacts",
+			mcp.WithDescription(t("TOOL_LIST_WORKFLOW_RUN_ARTIFACTS_DESCRIPTION", "List the artifacts uploaded by a GitHub Actions workflow run")),
+			mcp.WithToolAnnotation(mcp.ToolAnnotation{
+				Title:        t("TOOL_LIST_WORKFLOW_RUN_ARTIFACTS_USER_TITLE", "List workflow run artifacts"),
+				ReadOnlyHint: toBoolPtr(true),
+			}),
+			mcp.WithString("owner",
+				mcp.Required(),
+				mcp.Description("Repository owner"),
+			),
+			mcp.WithString("repo",
+				mcp.Required(),
+				mcp.Description("Repository name"),
+			),
+			mcp.WithNumber("run_id",
+				mcp.Required(),
+				mcp.Description("The ID of the workflow run"),
+			),
+			WithPagination(),
+		),
+		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			owner, err := requiredParam[string](request, "owner")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			repo, err := requiredParam[string](request, "repo")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			runID, err := RequiredInt(request, "run_id")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			pagination, err := OptionalPaginationParams(request)
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+
+			client, err := getClient(ctx)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get GitHub client: %w", err)
+			}
+			artifacts, resp, err := client.Actions.ListWorkflowRunArtifacts(ctx, owner, repo, int64(runID), &github.ListOptions{
+				Page:    pagination.page,
+				PerPage: pagination.perPage,
+			})
+			if err != nil {
+				return nil, fmt.Errorf("failed to list workflow run artifacts: %w", err)
+			}
+			defer func() { _ = resp.Body.Close() }()
+
+			if resp.StatusCode != http.StatusOK {
+				body, err := io.ReadAll(resp.Body)
+				if err != nil {
+					return nil, fmt.Errorf("failed to read response body: %w", err)
+				}
+				return mcp.NewToolResultError(fmt.Sprintf("failed to list workflow run artifacts: %s", string(body))), nil
+			}
+
+			r, err := json.Marshal(artifacts)
+			if err != nil {
+				return nil, fmt.Errorf("failed to marshal response: %w", err)
+			}
+
+			return mcp.NewToolResultText(string(r)), nil
+		}
+}
+
+// RerunFailedJobs creates a tool to re-run the failed jobs of a workflow run.
+func RerunFailedJobs(getClient GetClientFn, t translations.TranslationHelperFunc) (tool mcp.Tool, handler server.ToolHandlerFunc) {
+	return mcp.NewTool("rerun_failed_jobs",
+			mcp.WithDescription(t("TOOL_RERUN_FAILED_JOBS_DESCRIPTION", "Re-run the failed jobs of a GitHub Actions workflow run, and the jobs that depend on them")),
+			mcp.WithToolAnnotation(mcp.ToolAnnotation{
+				Title:           t("TOOL_RERUN_FAILED_JOBS_USER_TITLE", "Re-run failed jobs"),
+				ReadOnlyHint:    toBoolPtr(false),
+				DestructiveHint: toBoolPtr(false),
+			}),
+			mcp.WithString("owner",
+				mcp.Required(),
+				mcp.Description("Repository owner"),
+			),
+			mcp.WithString("repo",
+				mcp.Required(),
+				mcp.Description("Repository name"),
+			),
+			mcp.WithNumber("run_id",
+				mcp.Required(),
+				mcp.Description("The ID of the workflow run"),
+			),
+		),
+		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			owner, err := requiredParam[string](request, "owner")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			repo, err := requiredParam[string](request, "repo")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			runID, err := RequiredInt(request, "run_id")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+
+			client, err := getClient(ctx)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get GitHub client: %w", err)
+			}
+			resp, err := client.Actions.RerunFailedJobsByID(ctx, owner, repo, int64(runID))
+			if err != nil {
+				return nil, fmt.Errorf("failed to re-run failed jobs: %w", err)
+			}
+			defer func() { _ = resp.Body.Close() }()
+
+			if resp.StatusCode != http.StatusCreated {
+				body, err := io.ReadAll(resp.Body)
+				if err != nil {
+					return nil, fmt.Errorf("failed to read response body: %w", err)
+				}
+				return mcp.NewToolResultError(fmt.Sprintf("failed to re-run failed jobs: %s", string(body))), nil
+			}
+
+			return mcp.NewToolResultText(fmt.Sprintf("failed jobs of workflow run %d have been queued to re-run", runID)), nil
+		}
+}
+
+// CancelWorkflowRun creates a tool to cancel a workflow run.
+func CancelWorkflowRun(getClient GetClientFn, t translations.TranslationHelperFunc) (tool mcp.Tool, handler server.ToolHandlerFunc) {
+	return mcp.NewTool("cancel_workflow_run",
+			mcp.WithDescription(t("TOOL_CANCEL_WORKFLOW_RUN_DESCRIPTION", "Cancel a queued or in progress GitHub Actions workflow run")),
+			mcp.WithToolAnnotation(mcp.ToolAnnotation{
+				Title:           t("TOOL_CANCEL_WORKFLOW_RUN_USER_TITLE", "Cancel workflow run"),
+				ReadOnlyHint:    toBoolPtr(false),
+				DestructiveHint: toBoolPtr(true),
+			}),
+			mcp.WithString("owner",
+				mcp.Required(),
+				mcp.Description("Repository owner"),
+			),
+			mcp.WithString("repo",
+				mcp.Required(),
+				mcp.Description("Repository name"),
+			),
+			mcp.WithNumber("run_id",
+				mcp.Required(),
+				mcp.Description("The ID of the workflow run"),
+			),
+		),
+		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			owner, err := requiredParam[string](request, "owner")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			repo, err := requiredParam[string](request, "repo")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			runID, err := RequiredInt(request, "run_id")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+
+			client, err := getClient(ctx)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get GitHub client: %w", err)
+			}
+			// The run is cancelled asynchronously, which the API answers with 202 Accepted.
+			resp, err := client.Actions.CancelWorkflowRunByID(ctx, owner, repo, int64(runID))
+			if err != nil && !isAcceptedError(err) {
+				return nil, fmt.Errorf("failed to cancel workflow run: %w", err)
+			}
+			defer func() { _ = resp.Body.Close() }()
+
+			if resp.StatusCode != http.StatusAccepted {
+				body, err := io.ReadAll(resp.Body)
+				if err != nil {
+					return nil, fmt.Errorf("failed to read response body: %w", err)
+				}
+				return mcp.NewToolResultError(fmt.Sprintf("failed to cancel workflow run: %s", string(body))), nil
+			}
+
+			return mcp.NewToolResultText(fmt.Sprintf("workflow run %d is being cancelled", runID)), nil
+		}
+}
+
+// RunWorkflow creates a tool to trigger a workflow_dispatch event for a workflow.
+func RunWorkflow(getClient GetClientFn, t translations.TranslationHelperFunc) (tool mcp.Tool, handler server.ToolHandlerFunc) {
+	return mcp.NewTool("run_workflow",
+			mcp.WithDescription(t("TOOL_RUN_WORKFLOW_DESCRIPTION", "Run a GitHub Actions workflow that has a workflow_dispatch trigger, with inputs. The run is created asynchronously, use list_workflow_runs to find it.")),
+			mcp.WithToolAnnotation(mcp.ToolAnnotation{
+				Title:           t("TOOL_RUN_WORKFLOW_USER_TITLE", "Run workflow"),
+				ReadOnlyHint:    toBoolPtr(false),
+				DestructiveHint: toBoolPtr(false),
+			}),
+			mcp.WithString("owner",
+				mcp.Required(),
+				mcp.Description("Repository owner"),
+			),
+			mcp.WithString("repo",
+				mcp.Required(),
+				mcp.Description("Repository name"),
+			),
+			mcp.WithString("workflow_id",
+				mcp.Required(),
+				mcp.Description("The ID of the workflow, or its file name such as 'deploy.yml'"),
+			),
+			mcp.WithString("ref",
+				mcp.Required(),
+				mcp.Description("The branch or tag to run the workflow on"),
+			),
+			mcp.WithObject("inputs",
+				mcp.Description("Inputs of the workflow, by name. Only inputs the workflow declares can be given."),
+			),
+		),
+		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			owner, err := requiredParam[string](request, "owner")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			repo, err := requiredParam[string](request, "repo")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			workflowID, err := requiredParam[string](request, "workflow_id")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			ref, err := requiredParam[string](request, "ref")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			inputs, err := OptionalParam[map[string]any](request, "inputs")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+
+			client, err := getClient(ctx)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get GitHub client: %w", err)
+			}
+			event := github.CreateWorkflowDispatchEventRequest{Ref: ref, Inputs: inputs}
+			var resp *github.Response
+			if id, parseErr := strconv.ParseInt(workflowID, 10, 64); parseErr == nil {
+				resp, err = client.Actions.CreateWorkflowDispatchEventByID(ctx, owner, repo, id, event)
+			} else {
+				resp, err = client.Actions.CreateWorkflowDispatchEventByFileName(ctx, owner, repo, workflowID, event)
+			}
+			if err != nil {
+				return nil, fmt.Errorf("failed to run workflow: %w", err)
+			}
+			defer func() { _ = resp.Body.Close() }()
+
+			if resp.StatusCode != http.StatusNoContent {
+				body, err := io.ReadAll(resp.Body)
+				if err != nil {
+					return nil, fmt.Errorf("failed to read response body: %w", err)
+				}
+				return mcp.NewToolResultError(fmt.Sprintf("failed to run workflow: %s", string(body))), nil
+			}
+
+			return mcp.NewToolResultText(fmt.Sprintf("workflow %s has been dispatched on %s", workflowID, ref)), nil
+		}
+}
+
+// downloadLogs downloads logs from the storage URL the API redirected to. The URL is
+// pre-signed, so the request is made without the GitHub client, which would send the token
+// to the storage host.
+func downloadLogs(ctx context.Context, url string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	content, err := io.ReadAll(io.LimitReader(resp.Body, maxLogBytes+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+	if len(content) > maxLogBytes {
+		return nil, fmt.Errorf("logs are larger than %d MiB", maxLogBytes>>20)
+	}
+	return content, nil
+}
+
+// lastLines returns the last n lines of a log, and how many lines it has in total.
+func lastLines(log string, n int) (string, int) {
+	lines := strings.Split(strings.TrimSuffix(log, "\n"), "\n")
+	if log == "" {
+		lines = nil
+	}
+	total := len(lines)
+	if total > n {
+		lines = lines[total-n:]
+	}
+	return strings.Join(lines, "\n"), total
+}
+
+// tailLogArchive returns the last n lines of each job log in the log archive of a workflow
+// run. The archive has a log per job at its root, and a directory per job with a log per
+// step. The step logs repeat the job logs, so they are only used if there are no job logs.
+func tailLogArchive(archive []byte, n int) ([]WorkflowRunLog, error) {
+	reader, err := zip.NewReader(bytes.NewReader(archive), int64(len(archive)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open log archive: %w", err)
+	}
+
+	var jobLogs, stepLogs []*zip.File
+	for _, f := range reader.File {
+		switch {
+		case f.FileInfo().IsDir() || path.Ext(f.Name) != ".txt":
+		case !strings.Contains(f.Name, "/"):
+			jobLogs = append(jobLogs, f)
+		default:
+			stepLogs = append(stepLogs, f)
+		}
+	}
+	if len(jobLogs) == 0 {
+		jobLogs = stepLogs
+	}
+
+	logs := make([]WorkflowRunLog, 0, len(jobLogs))
+	for _, f := range jobLogs {
+		content, err := readArchiveFile(f)
+		if err != nil {
+			return nil, err
+		}
+		tail, total := lastLines(string(content), n)
+		logs = append(logs, WorkflowRunLog{Name: f.Name, TotalLines: total, Logs: tail})
+	}
+	return logs, nil
+}
+
+func readArchiveFile(f *zip.File) ([]byte, error) {
+	rc, err := f.Open()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s in log archive: %w", f.Name, err)
+	}
+	defer func() { _ = rc.Close() }()
+
+	// Archive entries are decompressed with the same bound as downloads, to guard against
+	// archives that expand far beyond their size.
+	content, err := io.ReadAll(io.LimitReader(rc, maxLogBytes+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s in log archive: %w", f.Name, err)
+	}
+	if len(content) > maxLogBytes {
+		return nil, fmt.Errorf("%s in log archive is larger than %d MiB", f.Name, maxLogBytes>>20)
+	}
+	return content, nil
+}
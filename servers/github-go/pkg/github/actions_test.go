@@ -0,0 +1,595 @@
+package github
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/github/github-mcp-server/internal/toolsnaps"
+	"github.com/github/github-mcp-server/pkg/translations"
+	"github.com/google/go-github/v72/github"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/migueleliasweb/go-github-mock/src/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ActionsTools(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		create   func(GetClientFn, translations.TranslationHelperFunc) (mcp.Tool, server.ToolHandlerFunc)
+		readOnly bool
+	}{
+		{name: "list_workflows", create: ListWorkflows, readOnly: true},
+		{name: "list_workflow_runs", create: ListWorkflowRuns, readOnly: true},
+		{name: "get_workflow_run", create: GetWorkflowRun, readOnly: true},
+		{name: "list_workflow_jobs", create: ListWorkflowJobs, readOnly: true},
+		{name: "get_job_logs", create: GetJobLogs, readOnly: true},
+		{name: "get_workflow_run_logs", create: GetWorkflowRunLogs, readOnly: true},
+		{name: "list_workflow_run_artifacts", create: ListWorkflowRunArtifacts, readOnly: true},
+		{name: "rerun_failed_jobs", create: RerunFailedJobs},
+		{name: "cancel_workflow_run", create: CancelWorkflowRun},
+		{name: "run_workflow", create: RunWorkflow},
+	}
+
+	for _, tc := range tests {
+		tool, _ := tc.create(nil, translations.NullTranslationHelper)
+		require.NoError(t, toolsnaps.Test(tool.Name, tool))
+
+		assert.Equal(t, tc.name, tool.Name)
+		assert.Equal(t, tc.readOnly, *tool.Annotations.ReadOnlyHint, "unexpected read-only hint for %s", tc.name)
+	}
+}
+
+func Test_ListWorkflowRuns(t *testing.T) {
+	mockRuns := &github.WorkflowRuns{
+		TotalCount: github.Ptr(1),
+		WorkflowRuns: []*github.WorkflowRun{
+			{ID: github.Ptr(int64(30433642)), Name: github.Ptr("CI"), Status: github.Ptr("completed"), Conclusion: github.Ptr("failure")},
+		},
+	}
+
+	tests := []struct {
+		name           string
+		mockedClient   *http.Client
+		requestArgs    map[string]interface{}
+		expectError    bool
+		expectedErrMsg string
+	}{
+		{
+			name: "runs of all workflows",
+			mockedClient: mock.NewMockedHTTPClient(
+				mock.WithRequestMatchHandler(
+					mock.GetReposActionsRunsByOwnerByRepo,
+					expectQueryParams(t, map[string]string{
+						"branch":   "main",
+						"status":   "failure",
+						"page":     "1",
+						"per_page": "30",
+					}).andThen(
+						mockResponse(t, http.StatusOK, mockRuns),
+					),
+				),
+			),
+			requestArgs: map[string]interface{}{
+				"owner":  "octocat",
+				"repo":   "hello-world",
+				"branch": "main",
+				"status": "failure",
+			},
+		},
+		{
+			name: "runs of a workflow by ID",
+			mockedClient: mock.NewMockedHTTPClient(
+				mock.WithRequestMatchHandler(
+					mock.GetReposActionsWorkflowsRunsByOwnerByRepoByWorkflowId,
+					expectPath(t, "/repos/octocat/hello-world/actions/workflows/161335/runs").andThen(
+						mockResponse(t, http.StatusOK, mockRuns),
+					),
+				),
+			),
+			requestArgs: map[string]interface{}{
+				"owner":       "octocat",
+				"repo":        "hello-world",
+				"workflow_id": "161335",
+			},
+		},
+		{
+			name: "runs of a workflow by file name",
+			mockedClient: mock.NewMockedHTTPClient(
+				mock.WithRequestMatchHandler(
+					mock.GetReposActionsWorkflowsRunsByOwnerByRepoByWorkflowId,
+					expectPath(t, "/repos/octocat/hello-world/actions/workflows/ci.yml/runs").andThen(
+						mockResponse(t, http.StatusOK, mockRuns),
+					),
+				),
+			),
+			requestArgs: map[string]interface{}{
+				"owner":       "octocat",
+				"repo":        "hello-world",
+				"workflow_id": "ci.yml",
+			},
+		},
+		{
+			name: "workflow not found",
+			mockedClient: mock.NewMockedHTTPClient(
+				mock.WithRequestMatchHandler(
+					mock.GetReposActionsWorkflowsRunsByOwnerByRepoByWorkflowId,
+					mockResponse(t, http.StatusNotFound, `{"message": "Not Found"}`),
+				),
+			),
+			requestArgs: map[string]interface{}{
+				"owner":       "octocat",
+				"repo":        "hello-world",
+				"workflow_id": "missing.yml",
+			},
+			expectError:    true,
+			expectedErrMsg: "failed to list workflow runs",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			client := github.NewClient(tc.mockedClient)
+			_, handler := ListWorkflowRuns(stubGetClientFn(client), translations.NullTranslationHelper)
+
+			result, err := handler(context.Background(), createMCPRequest(tc.requestArgs))
+
+			if tc.expectError {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tc.expectedErrMsg)
+				return
+			}
+
+			require.NoError(t, err)
+			var returned github.WorkflowRuns
+			require.NoError(t, json.Unmarshal([]byte(getTextResult(t, result).Text), &returned))
+			assert.Equal(t, mockRuns, &returned)
+		})
+	}
+}
+
+// mockLogStorage serves logs by path, as the storage the logs endpoints redirect to does.
+func mockLogStorage(t *testing.T, logs map[string][]byte) *httptest.Server {
+	t.Helper()
+	storage := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Empty(t, r.Header.Get("Authorization"), "the token must not be sent to the log storage")
+		content, ok := logs[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write(content)
+	}))
+	t.Cleanup(storage.Close)
+	return storage
+}
+
+// redirectTo answers like the logs endpoints, with a redirect to where the logs are stored.
+func redirectTo(location string) http.HandlerFunc {
+	return func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Location", location)
+		w.WriteHeader(http.StatusFound)
+	}
+}
+
+func Test_GetJobLogs(t *testing.T) {
+	storage := mockLogStorage(t, map[string][]byte{
+		"/jobs/1": []byte("Set up job\nRun tests\n--- FAIL: TestFoo\nFAIL\n"),
+		"/jobs/2": []byte("Set up job\nRun lint\n"),
+	})
+
+	failedJob := &github.WorkflowJob{ID: github.Ptr(int64(1)), Name: github.Ptr("test"), Conclusion: github.Ptr("failure")}
+	succeededJob := &github.WorkflowJob{ID: github.Ptr(int64(2)), Name: github.Ptr("lint"), Conclusion: github.Ptr("success")}
+
+	tests := []struct {
+		name               string
+		mockedClient       *http.Client
+		requestArgs        map[string]interface{}
+		expectToolError    bool
+		expectedToolErrMsg string
+		expectedText       string
+		expectedLogs       []JobLogs
+	}{
+		{
+			name: "logs of a job",
+			mockedClient: mock.NewMockedHTTPClient(
+				mock.WithRequestMatch(
+					mock.GetReposActionsJobsByOwnerByRepoByJobId,
+					succeededJob,
+				),
+				mock.WithRequestMatchHandler(
+					mock.GetReposActionsJobsLogsByOwnerByRepoByJobId,
+					redirectTo(storage.URL+"/jobs/2"),
+				),
+			),
+			requestArgs: map[string]interface{}{
+				"owner":  "octocat",
+				"repo":   "hello-world",
+				"job_id": float64(2),
+			},
+			expectedLogs: []JobLogs{
+				{JobID: 2, Name: "lint", Conclusion: "success", TotalLines: 2, Logs: "Set up job\nRun lint"},
+			},
+		},
+		{
+			name: "tail of the failed jobs of a run",
+			mockedClient: mock.NewMockedHTTPClient(
+				mock.WithRequestMatchHandler(
+					mock.GetReposActionsRunsJobsByOwnerByRepoByRunId,
+					expectQueryParams(t, map[string]string{
+						"filter":   "latest",
+						"per_page": "100",
+					}).andThen(
+						mockResponse(t, http.StatusOK, &github.Jobs{
+							TotalCount: github.Ptr(2),
+							Jobs:       []*github.WorkflowJob{failedJob, succeededJob},
+						}),
+					),
+				),
+				mock.WithRequestMatchHandler(
+					mock.GetReposActionsJobsLogsByOwnerByRepoByJobId,
+					expectPath(t, "/repos/octocat/hello-world/actions/jobs/1/logs").andThen(
+						redirectTo(storage.URL+"/jobs/1"),
+					),
+				),
+			),
+			requestArgs: map[string]interface{}{
+				"owner":      "octocat",
+				"repo":       "hello-world",
+				"run_id":     float64(42),
+				"tail_lines": float64(2),
+			},
+			expectedLogs: []JobLogs{
+				{JobID: 1, Name: "test", Conclusion: "failure", TotalLines: 4, Logs: "--- FAIL: TestFoo\nFAIL"},
+			},
+		},
+		{
+			name: "run without failed jobs",
+			mockedClient: mock.NewMockedHTTPClient(
+				mock.WithRequestMatch(
+					mock.GetReposActionsRunsJobsByOwnerByRepoByRunId,
+					&github.Jobs{TotalCount: github.Ptr(1), Jobs: []*github.WorkflowJob{succeededJob}},
+				),
+			),
+			requestArgs: map[string]interface{}{
+				"owner":  "octocat",
+				"repo":   "hello-world",
+				"run_id": float64(42),
+			},
+			expectedText: "workflow run 42 has no failed jobs",
+		},
+		{
+			name:         "neither job nor run",
+			mockedClient: mock.NewMockedHTTPClient(),
+			requestArgs: map[string]interface{}{
+				"owner": "octocat",
+				"repo":  "hello-world",
+			},
+			expectToolError:    true,
+			expectedToolErrMsg: "exactly one of job_id and run_id must be provided",
+		},
+		{
+			name:         "both job and run",
+			mockedClient: mock.NewMockedHTTPClient(),
+			requestArgs: map[string]interface{}{
+				"owner":  "octocat",
+				"repo":   "hello-world",
+				"job_id": float64(1),
+				"run_id": float64(42),
+			},
+			expectToolError:    true,
+			expectedToolErrMsg: "exactly one of job_id and run_id must be provided",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			client := github.NewClient(tc.mockedClient)
+			_, handler := GetJobLogs(stubGetClientFn(client), translations.NullTranslationHelper)
+
+			result, err := handler(context.Background(), createMCPRequest(tc.requestArgs))
+			require.NoError(t, err)
+
+			textContent := getTextResult(t, result)
+			if tc.expectToolError {
+				require.True(t, result.IsError)
+				assert.Contains(t, textContent.Text, tc.expectedToolErrMsg)
+				return
+			}
+			require.False(t, result.IsError)
+
+			if tc.expectedText != "" {
+				assert.Equal(t, tc.expectedText, textContent.Text)
+				return
+			}
+			var returned []JobLogs
+			require.NoError(t, json.Unmarshal([]byte(textContent.Text), &returned))
+			assert.Equal(t, tc.expectedLogs, returned)
+		})
+	}
+}
+
+func Test_GetWorkflowRunLogs(t *testing.T) {
+	archive := func(files map[string]string) []byte {
+		var buf bytes.Buffer
+		w := zip.NewWriter(&buf)
+		for _, name := range []string{"0_build.txt", "build/", "build/1_Set up job.txt", "1_test.txt", "test/1_Run tests.txt"} {
+			content, ok := files[name]
+			if !ok {
+				continue
+			}
+			f, err := w.Create(name)
+			require.NoError(t, err)
+			_, err = f.Write([]byte(content))
+			require.NoError(t, err)
+		}
+		require.NoError(t, w.Close())
+		return buf.Bytes()
+	}
+
+	storage := mockLogStorage(t, map[string][]byte{
+		"/runs/1.zip": archive(map[string]string{
+			"0_build.txt":            "Set up job\nBuild\nDone\n",
+			"build/":                 "",
+			"build/1_Set up job.txt": "Set up job\n",
+			"1_test.txt":             "Set up job\nRun tests\nFAIL\n",
+		}),
+		"/runs/2.zip": archive(map[string]string{
+			"test/1_Run tests.txt": "Run tests\nFAIL\n",
+		}),
+		"/runs/3.zip": []byte("not a zip"),
+	})
+
+	tests := []struct {
+		name               string
+		archive            string
+		requestArgs        map[string]interface{}
+		expectToolError    bool
+		expectedToolErrMsg string
+		expectedFiles      []WorkflowRunLog
+	}{
+		{
+			name:    "job logs at the root of the archive",
+			archive: "/runs/1.zip",
+			requestArgs: map[string]interface{}{
+				"owner":      "octocat",
+				"repo":       "hello-world",
+				"run_id":     float64(1),
+				"tail_lines": float64(2),
+			},
+			expectedFiles: []WorkflowRunLog{
+				{Name: "0_build.txt", TotalLines: 3, Logs: "Build\nDone"},
+				{Name: "1_test.txt", TotalLines: 3, Logs: "Run tests\nFAIL"},
+			},
+		},
+		{
+			name:    "only step logs in the archive",
+			archive: "/runs/2.zip",
+			requestArgs: map[string]interface{}{
+				"owner":  "octocat",
+				"repo":   "hello-world",
+				"run_id": float64(2),
+			},
+			expectedFiles: []WorkflowRunLog{
+				{Name: "test/1_Run tests.txt", TotalLines: 2, Logs: "Run tests\nFAIL"},
+			},
+		},
+		{
+			name:    "corrupt archive",
+			archive: "/runs/3.zip",
+			requestArgs: map[string]interface{}{
+				"owner":  "octocat",
+				"repo":   "hello-world",
+				"run_id": float64(3),
+			},
+			expectToolError:    true,
+			expectedToolErrMsg: "failed to open log archive",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			client := github.NewClient(mock.NewMockedHTTPClient(
+				mock.WithRequestMatchHandler(
+					mock.GetReposActionsRunsLogsByOwnerByRepoByRunId,
+					redirectTo(storage.URL+tc.archive),
+				),
+			))
+			_, handler := GetWorkflowRunLogs(stubGetClientFn(client), translations.NullTranslationHelper)
+
+			result, err := handler(context.Background(), createMCPRequest(tc.requestArgs))
+			require.NoError(t, err)
+
+			textContent := getTextResult(t, result)
+			if tc.expectToolError {
+				require.True(t, result.IsError)
+				assert.Contains(t, textContent.Text, tc.expectedToolErrMsg)
+				return
+			}
+			require.False(t, result.IsError)
+
+			var returned WorkflowRunLogs
+			require.NoError(t, json.Unmarshal([]byte(textContent.Text), &returned))
+			assert.Equal(t, tc.expectedFiles, returned.Files)
+		})
+	}
+}
+
+func Test_CancelWorkflowRun(t *testing.T) {
+	tests := []struct {
+		name           string
+		mockedClient   *http.Client
+		expectError    bool
+		expectedErrMsg string
+		expectedText   string
+	}{
+		{
+			name: "run is being cancelled",
+			mockedClient: mock.NewMockedHTTPClient(
+				mock.WithRequestMatchHandler(
+					mock.PostReposActionsRunsCancelByOwnerByRepoByRunId,
+					expectPath(t, "/repos/octocat/hello-world/actions/runs/42/cancel").andThen(
+						mockResponse(t, http.StatusAccepted, `{}`),
+					),
+				),
+			),
+			expectedText: "workflow run 42 is being cancelled",
+		},
+		{
+			name: "run has already completed",
+			mockedClient: mock.NewMockedHTTPClient(
+				mock.WithRequestMatchHandler(
+					mock.PostReposActionsRunsCancelByOwnerByRepoByRunId,
+					mockResponse(t, http.StatusConflict, `{"message": "Cannot cancel a workflow run that is completed."}`),
+				),
+			),
+			expectError:    true,
+			expectedErrMsg: "Cannot cancel a workflow run that is completed.",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			client := github.NewClient(tc.mockedClient)
+			_, handler := CancelWorkflowRun(stubGetClientFn(client), translations.NullTranslationHelper)
+
+			result, err := handler(context.Background(), createMCPRequest(map[string]interface{}{
+				"owner":  "octocat",
+				"repo":   "hello-world",
+				"run_id": float64(42),
+			}))
+
+			if tc.expectError {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tc.expectedErrMsg)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedText, getTextResult(t, result).Text)
+		})
+	}
+}
+
+func Test_RunWorkflow(t *testing.T) {
+	tests := []struct {
+		name           string
+		mockedClient   *http.Client
+		requestArgs    map[string]interface{}
+		expectError    bool
+		expectedErrMsg string
+		expectedText   string
+	}{
+		{
+			name: "dispatch by file name with inputs",
+			mockedClient: mock.NewMockedHTTPClient(
+				mock.WithRequestMatchHandler(
+					mock.PostReposActionsWorkflowsDispatchesByOwnerByRepoByWorkflowId,
+					expect(t, expectations{
+						path: "/repos/octocat/hello-world/actions/workflows/deploy.yml/dispatches",
+						requestBody: map[string]any{
+							"ref":    "main",
+							"inputs": map[string]any{"environment": "staging", "dry_run": true},
+						},
+					}).andThen(
+						mockResponse(t, http.StatusNoContent, ""),
+					),
+				),
+			),
+			requestArgs: map[string]interface{}{
+				"owner":       "octocat",
+				"repo":        "hello-world",
+				"workflow_id": "deploy.yml",
+				"ref":         "main",
+				"inputs":      map[string]any{"environment": "staging", "dry_run": true},
+			},
+			expectedText: "workflow deploy.yml has been dispatched on main",
+		},
+		{
+			name: "dispatch by ID",
+			mockedClient: mock.NewMockedHTTPClient(
+				mock.WithRequestMatchHandler(
+					mock.PostReposActionsWorkflowsDispatchesByOwnerByRepoByWorkflowId,
+					expect(t, expectations{
+						path:        "/repos/octocat/hello-world/actions/workflows/161335/dispatches",
+						requestBody: map[string]any{"ref": "v1.0.0"},
+					}).andThen(
+						mockResponse(t, http.StatusNoContent, ""),
+					),
+				),
+			),
+			requestArgs: map[string]interface{}{
+				"owner":       "octocat",
+				"repo":        "hello-world",
+				"workflow_id": "161335",
+				"ref":         "v1.0.0",
+			},
+			expectedText: "workflow 161335 has been dispatched on v1.0.0",
+		},
+		{
+			name: "workflow without a workflow_dispatch trigger",
+			mockedClient: mock.NewMockedHTTPClient(
+				mock.WithRequestMatchHandler(
+					mock.PostReposActionsWorkflowsDispatchesByOwnerByRepoByWorkflowId,
+					mockResponse(t, http.StatusUnprocessableEntity, `{"message": "Workflow does not have 'workflow_dispatch' trigger"}`),
+				),
+			),
+			requestArgs: map[string]interface{}{
+				"owner":       "octocat",
+				"repo":        "hello-world",
+				"workflow_id": "ci.yml",
+				"ref":         "main",
+			},
+			expectError:    true,
+			expectedErrMsg: "Workflow does not have 'workflow_dispatch' trigger",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			client := github.NewClient(tc.mockedClient)
+			_, handler := RunWorkflow(stubGetClientFn(client), translations.NullTranslationHelper)
+
+			result, err := handler(context.Background(), createMCPRequest(tc.requestArgs))
+
+			if tc.expectError {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tc.expectedErrMsg)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedText, getTextResult(t, result).Text)
+		})
+	}
+}
+
+func Test_lastLines(t *testing.T) {
+	tests := []struct {
+		name          string
+		log           string
+		n             int
+		expectedTail  string
+		expectedTotal int
+	}{
+		{name: "empty", log: "", n: 10, expectedTail: "", expectedTotal: 0},
+		{name: "shorter than the tail", log: "a\nb\n", n: 10, expectedTail: "a\nb", expectedTotal: 2},
+		{name: "longer than the tail", log: "a\nb\nc\nd", n: 2, expectedTail: "c\nd", expectedTotal: 4},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			tail, total := lastLines(tc.log, tc.n)
+			assert.Equal(t, tc.expectedTail, tail)
+			assert.Equal(t, tc.expectedTotal, total)
+		})
+	}
+}
@@ -2,14 +2,25 @@ package github
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/github/github-mcp-server/pkg/translations"
+	"github.com/google/go-github/v72/github"
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
 )
 
-// GetMe creates a tool to get details of the authenticated user.
-func GetMe(getClient GetClientFn, t translations.TranslationHelperFunc) (mcp.Tool, server.ToolHandlerFunc) {
+// accountInfo describes the user behind one of the configured accounts.
+type accountInfo struct {
+	Name    string       `json:"name"`
+	Default bool         `json:"default,omitempty"`
+	User    *github.User `json:"user,omitempty"`
+	Error   string       `json:"error,omitempty"`
+}
+
+// GetMe creates a tool to get details of the authenticated user. If named accounts are
+// configured and no account is selected, it reports the user behind every account.
+func GetMe(getClient GetClientFn, accounts []string, t translations.TranslationHelperFunc) (mcp.Tool, server.ToolHandlerFunc) {
 	tool := mcp.NewTool("get_me",
 		mcp.WithDescription(t("TOOL_GET_ME_DESCRIPTION", "Get details of the authenticated GitHub user. Use this when a request includes \"me\", \"my\". The output will not change unless the user changes their profile, so only call this once.")),
 		mcp.WithToolAnnotation(mcp.ToolAnnotation{
@@ -23,17 +34,45 @@ func GetMe(getClient GetClientFn, t translations.TranslationHelperFunc) (mcp.Too
 
 	type args struct{}
 	handler := mcp.NewTypedToolHandler(func(ctx context.Context, _ mcp.CallToolRequest, _ args) (*mcp.CallToolResult, error) {
-		client, err := getClient(ctx)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("failed to get GitHub client", err), nil
+		if _, selected := AccountFromContext(ctx); selected || len(accounts) == 0 {
+			client, err := getClient(ctx)
+			if err != nil {
+				return mcp.NewToolResultErrorFromErr("failed to get GitHub client", err), nil
+			}
+
+			user, _, err := client.Users.Get(ctx, "")
+			if err != nil {
+				return mcp.NewToolResultErrorFromErr("failed to get user", err), nil
+			}
+
+			return MarshalledTextResult(user), nil
 		}
 
-		user, _, err := client.Users.Get(ctx, "")
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("failed to get user", err), nil
+		// A failure for one account shouldn't hide the others, so errors are reported per account.
+		infos := make([]accountInfo, 0, len(accounts)+1)
+		for _, name := range append([]string{DefaultAccountName}, accounts...) {
+			info := accountInfo{Name: name, Default: name == DefaultAccountName}
+			accountCtx := ctx
+			if !info.Default {
+				accountCtx = ContextWithAccount(ctx, name)
+			}
+
+			client, err := getClient(accountCtx)
+			if err != nil {
+				info.Error = fmt.Sprintf("failed to get GitHub client: %s", err)
+				infos = append(infos, info)
+				continue
+			}
+
+			user, _, err := client.Users.Get(accountCtx, "")
+			if err != nil {
+				info.Error = fmt.Sprintf("failed to get user: %s", err)
+			}
+			info.User = user
+			infos = append(infos, info)
 		}
 
-		return MarshalledTextResult(user), nil
+		return MarshalledTextResult(infos), nil
 	})
 
 	return tool, handler
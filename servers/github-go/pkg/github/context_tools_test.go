@@ -3,6 +3,7 @@ package github
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"testing"
 	"time"
 
@@ -17,7 +18,7 @@ import (
 func Test_GetMe(t *testing.T) {
 	t.Parallel()
 
-	tool, _ := GetMe(nil, translations.NullTranslationHelper)
+	tool, _ := GetMe(nil, nil, translations.NullTranslationHelper)
 	require.NoError(t, toolsnaps.Test(tool.Name, tool))
 
 	// Verify some basic very important properties
@@ -103,7 +104,7 @@ func Test_GetMe(t *testing.T) {
 
 	for _, tc := range tests {
 		t.Run(tc.name, func(t *testing.T) {
-			_, handler := GetMe(tc.stubbedGetClientFn, translations.NullTranslationHelper)
+			_, handler := GetMe(tc.stubbedGetClientFn, nil, translations.NullTranslationHelper)
 
 			request := createMCPRequest(tc.requestArgs)
 			result, err := handler(context.Background(), request)
@@ -131,3 +132,59 @@ func Test_GetMe(t *testing.T) {
 		})
 	}
 }
+
+func Test_GetMe_Accounts(t *testing.T) {
+	t.Parallel()
+
+	personal := &github.User{Login: github.Ptr("personal-user")}
+	work := &github.User{Login: github.Ptr("work-user")}
+
+	// Each account gets its own client, so that we can tell which one a request was made with
+	getClient := func(ctx context.Context) (*github.Client, error) {
+		account, _ := AccountFromContext(ctx)
+		switch account {
+		case "", DefaultAccountName:
+			return github.NewClient(mock.NewMockedHTTPClient(mock.WithRequestMatch(mock.GetUser, personal))), nil
+		case "work":
+			return github.NewClient(mock.NewMockedHTTPClient(mock.WithRequestMatch(mock.GetUser, work))), nil
+		default:
+			return nil, fmt.Errorf("unknown account %q", account)
+		}
+	}
+
+	tool, handler := GetMe(getClient, []string{"work", "broken"}, translations.NullTranslationHelper)
+	require.NoError(t, toolsnaps.Test(tool.Name, tool))
+
+	t.Run("reports all accounts when none is selected", func(t *testing.T) {
+		result, err := handler(context.Background(), createMCPRequest(map[string]any{}))
+		require.NoError(t, err)
+		textContent := getTextResult(t, result)
+		require.False(t, result.IsError)
+
+		var infos []accountInfo
+		require.NoError(t, json.Unmarshal([]byte(textContent.Text), &infos))
+		require.Len(t, infos, 3)
+
+		assert.Equal(t, DefaultAccountName, infos[0].Name)
+		assert.True(t, infos[0].Default)
+		assert.Equal(t, "personal-user", infos[0].User.GetLogin())
+
+		assert.Equal(t, "work", infos[1].Name)
+		assert.Equal(t, "work-user", infos[1].User.GetLogin())
+
+		assert.Equal(t, "broken", infos[2].Name)
+		assert.Nil(t, infos[2].User)
+		assert.Contains(t, infos[2].Error, "unknown account")
+	})
+
+	t.Run("reports only the selected account", func(t *testing.T) {
+		ctx := ContextWithAccount(context.Background(), "work")
+		result, err := handler(ctx, createMCPRequest(map[string]any{"account": "work"}))
+		require.NoError(t, err)
+		textContent := getTextResult(t, result)
+
+		var user github.User
+		require.NoError(t, json.Unmarshal([]byte(textContent.Text), &user))
+		assert.Equal(t, "work-user", user.GetLogin())
+	})
+}
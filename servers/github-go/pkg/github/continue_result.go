@@ -0,0 +1,43 @@
+package github
+
+import (
+	"context"
+
+	"github.com/github/github-mcp-server/pkg/translations"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// GetResultChunkFn returns the part of a split tool result that a continuation token refers to.
+type GetResultChunkFn func(ctx context.Context, token string) (*mcp.CallToolResult, error)
+
+// ContinueResult creates a tool to get the next part of a tool result that was split because
+// it exceeded the response budget of the server.
+func ContinueResult(getChunk GetResultChunkFn, t translations.TranslationHelperFunc) (mcp.Tool, server.ToolHandlerFunc) {
+	tool := mcp.NewTool("continue_result",
+		mcp.WithDescription(t("TOOL_CONTINUE_RESULT_DESCRIPTION", "Get the next part of a tool result that was too large to return at once, using the continuation token that came with the previous part. Parts of a JSON array are arrays of their own, but an item too large for a single part is split into text fragments that have to be joined in order. Tokens expire after a few minutes.")),
+		mcp.WithToolAnnotation(mcp.ToolAnnotation{
+			Title:        t("TOOL_CONTINUE_RESULT_USER_TITLE", "Continue tool result"),
+			ReadOnlyHint: toBoolPtr(true),
+		}),
+		mcp.WithString("token",
+			mcp.Required(),
+			mcp.Description("Continuation token from the previous part of the result"),
+		),
+	)
+
+	handler := func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		token, err := requiredParam[string](request, "token")
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		result, err := getChunk(ctx, token)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		return result, nil
+	}
+
+	return tool, handler
+}
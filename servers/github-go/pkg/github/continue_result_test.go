@@ -0,0 +1,71 @@
+package github
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/github/github-mcp-server/internal/toolsnaps"
+	"github.com/github/github-mcp-server/pkg/translations"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ContinueResult(t *testing.T) {
+	t.Parallel()
+
+	getChunk := func(_ context.Context, token string) (*mcp.CallToolResult, error) {
+		if token != "abc.1" {
+			return nil, errors.New("continuation token is unknown or has expired")
+		}
+		return mcp.NewToolResultText(`[{"sha":"def"}]`), nil
+	}
+
+	tool, handler := ContinueResult(getChunk, translations.NullTranslationHelper)
+	require.NoError(t, toolsnaps.Test(tool.Name, tool))
+
+	assert.Equal(t, "continue_result", tool.Name)
+	assert.True(t, *tool.Annotations.ReadOnlyHint, "continue_result tool should be read-only")
+	assert.Contains(t, tool.InputSchema.Properties, "token")
+	assert.ElementsMatch(t, tool.InputSchema.Required, []string{"token"})
+
+	tests := []struct {
+		name           string
+		requestArgs    map[string]any
+		expectedResult string
+		expectedError  string
+	}{
+		{
+			name:           "next part",
+			requestArgs:    map[string]any{"token": "abc.1"},
+			expectedResult: `[{"sha":"def"}]`,
+		},
+		{
+			name:          "expired token",
+			requestArgs:   map[string]any{"token": "abc.7"},
+			expectedError: "continuation token is unknown or has expired",
+		},
+		{
+			name:          "missing token",
+			requestArgs:   map[string]any{},
+			expectedError: "missing required parameter: token",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			result, err := handler(context.Background(), createMCPRequest(tc.requestArgs))
+			require.NoError(t, err)
+
+			text := getTextResult(t, result)
+			if tc.expectedError != "" {
+				assert.True(t, result.IsError)
+				assert.Equal(t, tc.expectedError, text.Text)
+				return
+			}
+			assert.False(t, result.IsError)
+			assert.Equal(t, tc.expectedResult, text.Text)
+		})
+	}
+}
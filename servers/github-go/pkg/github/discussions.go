@@ -0,0 +1,733 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/github/github-mcp-server/pkg/translations"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/shurcooL/githubv4"
+)
+
+// maxDiscussionsPerPage is the most nodes GraphQL connections return at once.
+const maxDiscussionsPerPage = 100
+
+// DiscussionCategory is a category that the discussions of a repository are organised in.
+type DiscussionCategory struct {
+	ID           githubv4.ID      `json:"id"`
+	Name         githubv4.String  `json:"name"`
+	Slug         githubv4.String  `json:"slug"`
+	Emoji        githubv4.String  `json:"emoji"`
+	Description  githubv4.String  `json:"description"`
+	IsAnswerable githubv4.Boolean `json:"is_answerable"`
+}
+
+// DiscussionAuthor is the login of the author of a discussion or comment, which is empty for deleted users.
+type DiscussionAuthor struct {
+	Login githubv4.String `json:"login"`
+}
+
+// DiscussionPageInfo is where a page of a list of discussions or comments ends, to request the next page after it.
+type DiscussionPageInfo struct {
+	HasNextPage githubv4.Boolean `json:"has_next_page"`
+	EndCursor   githubv4.String  `json:"end_cursor"`
+}
+
+// DiscussionSummary is a discussion as it is listed, without its body and comments.
+type DiscussionSummary struct {
+	ID       githubv4.ID      `json:"id"`
+	Number   githubv4.Int     `json:"number"`
+	Title    githubv4.String  `json:"title"`
+	URL      githubv4.String  `json:"url"`
+	Author   DiscussionAuthor `json:"author"`
+	Category struct {
+		Name githubv4.String `json:"name"`
+	} `json:"category"`
+	Closed     githubv4.Boolean  `json:"closed"`
+	IsAnswered githubv4.Boolean  `json:"is_answered"`
+	CreatedAt  githubv4.DateTime `json:"created_at"`
+	UpdatedAt  githubv4.DateTime `json:"updated_at"`
+	Comments   struct {
+		TotalCount githubv4.Int `json:"total_count"`
+	} `json:"comments"`
+}
+
+// Discussion is a discussion with its body and a page of its comments.
+type Discussion struct {
+	ID       githubv4.ID      `json:"id"`
+	Number   githubv4.Int     `json:"number"`
+	Title    githubv4.String  `json:"title"`
+	Body     githubv4.String  `json:"body"`
+	URL      githubv4.String  `json:"url"`
+	Author   DiscussionAuthor `json:"author"`
+	Category struct {
+		Name githubv4.String `json:"name"`
+	} `json:"category"`
+	Closed     githubv4.Boolean  `json:"closed"`
+	IsAnswered githubv4.Boolean  `json:"is_answered"`
+	CreatedAt  githubv4.DateTime `json:"created_at"`
+	Comments   struct {
+		TotalCount githubv4.Int        `json:"total_count"`
+		PageInfo   DiscussionPageInfo  `json:"page_info"`
+		Nodes      []DiscussionComment `json:"nodes"`
+	} `graphql:"comments(first: $perPage, after: $after)" json:"comments"`
+}
+
+// DiscussionComment is a top level comment on a discussion, with its replies. Replies can't
+// be replied to in turn, so threads are only this deep.
+type DiscussionComment struct {
+	ID          githubv4.ID       `json:"id"`
+	Body        githubv4.String   `json:"body"`
+	URL         githubv4.String   `json:"url"`
+	Author      DiscussionAuthor  `json:"author"`
+	CreatedAt   githubv4.DateTime `json:"created_at"`
+	UpvoteCount githubv4.Int      `json:"upvote_count"`
+	IsAnswer    githubv4.Boolean  `json:"is_answer"`
+	Replies     struct {
+		TotalCount githubv4.Int      `json:"total_count"`
+		Nodes      []DiscussionReply `json:"nodes"`
+	} `graphql:"replies(first: 100)" json:"replies"`
+}
+
+// DiscussionReply is a reply to a comment on a discussion.
+type DiscussionReply struct {
+	ID          githubv4.ID       `json:"id"`
+	Body        githubv4.String   `json:"body"`
+	URL         githubv4.String   `json:"url"`
+	Author      DiscussionAuthor  `json:"author"`
+	CreatedAt   githubv4.DateTime `json:"created_at"`
+	UpvoteCount githubv4.Int      `json:"upvote_count"`
+	IsAnswer    githubv4.Boolean  `json:"is_answer"`
+}
+
+// DiscussionList is a page of discussions.
+type DiscussionList struct {
+	TotalCount  int                 `json:"total_count"`
+	PageInfo    DiscussionPageInfo  `json:"page_info"`
+	Discussions []DiscussionSummary `json:"discussions"`
+}
+
+type discussionCategoriesQuery struct {
+	Repository struct {
+		ID                   githubv4.ID
+		DiscussionCategories struct {
+			Nodes []DiscussionCategory
+		} `graphql:"discussionCategories(first: 100)"`
+	} `graphql:"repository(owner: $owner, name: $repo)"`
+}
+
+type listDiscussionsQuery struct {
+	Repository struct {
+		Discussions struct {
+			TotalCount githubv4.Int
+			PageInfo   DiscussionPageInfo
+			Nodes      []DiscussionSummary
+		} `graphql:"discussions(first: $perPage, after: $after, categoryId: $categoryId, answered: $answered, orderBy: {field: UPDATED_AT, direction: DESC})"`
+	} `graphql:"repository(owner: $owner, name: $repo)"`
+}
+
+type searchDiscussionsQuery struct {
+	Search struct {
+		DiscussionCount githubv4.Int
+		PageInfo        DiscussionPageInfo
+		Nodes           []struct {
+			Discussion DiscussionSummary `graphql:"... on Discussion"`
+		}
+	} `graphql:"search(query: $query, type: DISCUSSION, first: $perPage, after: $after)"`
+}
+
+type getDiscussionQuery struct {
+	Repository struct {
+		Discussion Discussion `graphql:"discussion(number: $number)"`
+	} `graphql:"repository(owner: $owner, name: $repo)"`
+}
+
+type discussionIDQuery struct {
+	Repository struct {
+		Discussion struct {
+			ID githubv4.ID
+		} `graphql:"discussion(number: $number)"`
+	} `graphql:"repository(owner: $owner, name: $repo)"`
+}
+
+type discussionCommentQuery struct {
+	Node struct {
+		DiscussionComment struct {
+			Discussion struct {
+				Number     githubv4.Int
+				Repository struct {
+					NameWithOwner githubv4.String
+				}
+			}
+		} `graphql:"... on DiscussionComment"`
+	} `graphql:"node(id: $id)"`
+}
+
+// withCursorPagination adds the perPage and after parameters of tools that page through
+// GraphQL connections.
+func withCursorPagination() mcp.ToolOption {
+	return func(tool *mcp.Tool) {
+		mcp.WithNumber("perPage",
+			mcp.Description("Results per page (min 1, max 100)"),
+			mcp.Min(1),
+			mcp.Max(maxDiscussionsPerPage),
+		)(tool)
+		mcp.WithString("after",
+			mcp.Description("Cursor to get the page after, the end_cursor of the previous page"),
+		)(tool)
+	}
+}
+
+// cursorPaginationVariables returns the perPage and after variables of a paginated query from the request.
+func cursorPaginationVariables(request mcp.CallToolRequest) (map[string]any, error) {
+	perPage, err := OptionalIntParamWithDefault(request, "perPage", 30)
+	if err != nil {
+		return nil, err
+	}
+	if perPage < 1 || perPage > maxDiscussionsPerPage {
+		return nil, fmt.Errorf("perPage must be between 1 and %d", maxDiscussionsPerPage)
+	}
+	after, err := OptionalParam[string](request, "after")
+	if err != nil {
+		return nil, err
+	}
+	variables := map[string]any{
+		"perPage": githubv4.Int(perPage),
+		"after":   (*githubv4.String)(nil),
+	}
+	if after != "" {
+		variables["after"] = githubv4.String(after)
+	}
+	return variables, nil
+}
+
+// ListDiscussionCategories creates a tool to list the discussion categories of a repository.
+func ListDiscussionCategories(getGQLClient GetGQLClientFn, t translations.TranslationHelperFunc) (mcp.Tool, server.ToolHandlerFunc) {
+	return mcp.NewTool("list_discussion_categories",
+			mcp.WithDescription(t("TOOL_LIST_DISCUSSION_CATEGORIES_DESCRIPTION", "List the discussion categories of a GitHub repository. Discussions in answerable categories, such as Q&A, can have a comment marked as the answer.")),
+			mcp.WithToolAnnotation(mcp.ToolAnnotation{
+				Title:        t("TOOL_LIST_DISCUSSION_CATEGORIES_USER_TITLE", "List discussion categories"),
+				ReadOnlyHint: toBoolPtr(true),
+			}),
+			mcp.WithString("owner",
+				mcp.Required(),
+				mcp.Description("Repository owner"),
+			),
+			mcp.WithString("repo",
+				mcp.Required(),
+				mcp.Description("Repository name"),
+			),
+		),
+		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			owner, err := requiredParam[string](request, "owner")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			repo, err := requiredParam[string](request, "repo")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+
+			client, err := getGQLClient(ctx)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get GitHub GQL client: %w", err)
+			}
+			var query discussionCategoriesQuery
+			if err := client.Query(ctx, &query, map[string]any{
+				"owner": githubv4.String(owner),
+				"repo":  githubv4.String(repo),
+			}); err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("failed to list discussion categories: %v", err)), nil
+			}
+
+			r, err := json.Marshal(query.Repository.DiscussionCategories.Nodes)
+			if err != nil {
+				return nil, fmt.Errorf("failed to marshal response: %w", err)
+			}
+
+			return mcp.NewToolResultText(string(r)), nil
+		}
+}
+
+// ListDiscussions creates a tool to list the discussions of a repository.
+func ListDiscussions(getGQLClient GetGQLClientFn, t translations.TranslationHelperFunc) (mcp.Tool, server.ToolHandlerFunc) {
+	return mcp.NewTool("list_discussions",
+			mcp.WithDescription(t("TOOL_LIST_DISCUSSIONS_DESCRIPTION", "List the discussions of a GitHub repository, most recently updated first")),
+			mcp.WithToolAnnotation(mcp.ToolAnnotation{
+				Title:        t("TOOL_LIST_DISCUSSIONS_USER_TITLE", "List discussions"),
+				ReadOnlyHint: toBoolPtr(true),
+			}),
+			mcp.WithString("owner",
+				mcp.Required(),
+				mcp.Description("Repository owner"),
+			),
+			mcp.WithString("repo",
+				mcp.Required(),
+				mcp.Description("Repository name"),
+			),
+			mcp.WithString("category_id",
+				mcp.Description("Only list discussions in the category with this ID, from list_discussion_categories"),
+			),
+			mcp.WithBoolean("answered",
+				mcp.Description("Only list discussions that have, or don't have, an answer"),
+			),
+			withCursorPagination(),
+		),
+		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			owner, err := requiredParam[string](request, "owner")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			repo, err := requiredParam[string](request, "repo")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			categoryID, err := OptionalParam[string](request, "category_id")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			answered, ok, err := OptionalParamOK[bool](request, "answered")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			variables, err := cursorPaginationVariables(request)
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			variables["owner"] = githubv4.String(owner)
+			variables["repo"] = githubv4.String(repo)
+			variables["categoryId"] = (*githubv4.ID)(nil)
+			if categoryID != "" {
+				variables["categoryId"] = githubv4.ID(categoryID)
+			}
+			variables["answered"] = (*githubv4.Boolean)(nil)
+			if ok {
+				variables["answered"] = githubv4.Boolean(answered)
+			}
+
+			client, err := getGQLClient(ctx)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get GitHub GQL client: %w", err)
+			}
+			var query listDiscussionsQuery
+			if err := client.Query(ctx, &query, variables); err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("failed to list discussions: %v", err)), nil
+			}
+
+			discussions := query.Repository.Discussions
+			r, err := json.Marshal(DiscussionList{
+				TotalCount:  int(discussions.TotalCount),
+				PageInfo:    discussions.PageInfo,
+				Discussions: discussions.Nodes,
+			})
+			if err != nil {
+				return nil, fmt.Errorf("failed to marshal response: %w", err)
+			}
+
+			return mcp.NewToolResultText(string(r)), nil
+		}
+}
+
+// SearchDiscussions creates a tool to search for discussions.
+func SearchDiscussions(getGQLClient GetGQLClientFn, t translations.TranslationHelperFunc) (mcp.Tool, server.ToolHandlerFunc) {
+	return mcp.NewTool("search_discussions",
+			mcp.WithDescription(t("TOOL_SEARCH_DISCUSSIONS_DESCRIPTION", "Search for discussions across GitHub, or in one repository")),
+			mcp.WithToolAnnotation(mcp.ToolAnnotation{
+				Title:        t("TOOL_SEARCH_DISCUSSIONS_USER_TITLE", "Search discussions"),
+				ReadOnlyHint: toBoolPtr(true),
+			}),
+			mcp.WithString("query",
+				mcp.Required(),
+				mcp.Description("Search query using GitHub discussions search syntax, such as 'is:unanswered label:bug'"),
+			),
+			mcp.WithString("owner",
+				mcp.Description("Repository owner, to only search the discussions of one repository"),
+			),
+			mcp.WithString("repo",
+				mcp.Description("Repository name, to only search the discussions of one repository"),
+			),
+			withCursorPagination(),
+		),
+		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			q, err := requiredParam[string](request, "query")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			owner, err := OptionalParam[string](request, "owner")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			repo, err := OptionalParam[string](request, "repo")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			if (owner == "") != (repo == "") {
+				return mcp.NewToolResultError("owner and repo must be given together"), nil
+			}
+			if owner != "" {
+				q = fmt.Sprintf("repo:%s/%s %s", owner, repo, q)
+			}
+			variables, err := cursorPaginationVariables(request)
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			variables["query"] = githubv4.String(q)
+
+			client, err := getGQLClient(ctx)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get GitHub GQL client: %w", err)
+			}
+			var query searchDiscussionsQuery
+			if err := client.Query(ctx, &query, variables); err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("failed to search discussions: %v", err)), nil
+			}
+
+			result := DiscussionList{
+				TotalCount:  int(query.Search.DiscussionCount),
+				PageInfo:    query.Search.PageInfo,
+				Discussions: make([]DiscussionSummary, 0, len(query.Search.Nodes)),
+			}
+			for _, node := range query.Search.Nodes {
+				result.Discussions = append(result.Discussions, node.Discussion)
+			}
+			r, err := json.Marshal(result)
+			if err != nil {
+				return nil, fmt.Errorf("failed to marshal response: %w", err)
+			}
+
+			return mcp.NewToolResultText(string(r)), nil
+		}
+}
+
+// GetDiscussion creates a tool to get a discussion with its comments and their replies.
+func GetDiscussion(getGQLClient GetGQLClientFn, t translations.TranslationHelperFunc) (mcp.Tool, server.ToolHandlerFunc) {
+	return mcp.NewTool("get_discussion",
+			mcp.WithDescription(t("TOOL_GET_DISCUSSION_DESCRIPTION", "Get a discussion in a GitHub repository with a page of its comments, oldest first, and the replies to each comment")),
+			mcp.WithToolAnnotation(mcp.ToolAnnotation{
+				Title:        t("TOOL_GET_DISCUSSION_USER_TITLE", "Get discussion"),
+				ReadOnlyHint: toBoolPtr(true),
+			}),
+			mcp.WithString("owner",
+				mcp.Required(),
+				mcp.Description("Repository owner"),
+			),
+			mcp.WithString("repo",
+				mcp.Required(),
+				mcp.Description("Repository name"),
+			),
+			mcp.WithNumber("discussion_number",
+				mcp.Required(),
+				mcp.Description("Discussion number"),
+			),
+			withCursorPagination(),
+		),
+		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			owner, err := requiredParam[string](request, "owner")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			repo, err := requiredParam[string](request, "repo")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			number, err := RequiredInt(request, "discussion_number")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			variables, err := cursorPaginationVariables(request)
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			variables["owner"] = githubv4.String(owner)
+			variables["repo"] = githubv4.String(repo)
+			variables["number"] = githubv4.Int(number)
+
+			client, err := getGQLClient(ctx)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get GitHub GQL client: %w", err)
+			}
+			var query getDiscussionQuery
+			if err := client.Query(ctx, &query, variables); err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("failed to get discussion: %v", err)), nil
+			}
+
+			r, err := json.Marshal(query.Repository.Discussion)
+			if err != nil {
+				return nil, fmt.Errorf("failed to marshal response: %w", err)
+			}
+
+			return mcp.NewToolResultText(string(r)), nil
+		}
+}
+
+// CreateDiscussion creates a tool to start a discussion in a repository.
+func CreateDiscussion(getGQLClient GetGQLClientFn, t translations.TranslationHelperFunc) (mcp.Tool, server.ToolHandlerFunc) {
+	return mcp.NewTool("create_discussion",
+			mcp.WithDescription(t("TOOL_CREATE_DISCUSSION_DESCRIPTION", "Start a new discussion in a GitHub repository")),
+			mcp.WithToolAnnotation(mcp.ToolAnnotation{
+				Title:           t("TOOL_CREATE_DISCUSSION_USER_TITLE", "Create discussion"),
+				ReadOnlyHint:    toBoolPtr(false),
+				DestructiveHint: toBoolPtr(false),
+			}),
+			mcp.WithString("owner",
+				mcp.Required(),
+				mcp.Description("Repository owner"),
+			),
+			mcp.WithString("repo",
+				mcp.Required(),
+				mcp.Description("Repository name"),
+			),
+			mcp.WithString("category",
+				mcp.Required(),
+				mcp.Description("Name, slug or ID of the discussion category, such as 'Q&A'"),
+			),
+			mcp.WithString("title",
+				mcp.Required(),
+				mcp.Description("Discussion title"),
+			),
+			mcp.WithString("body",
+				mcp.Required(),
+				mcp.Description("Discussion body, in Markdown"),
+			),
+		),
+		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			owner, err := requiredParam[string](request, "owner")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			repo, err := requiredParam[string](request, "repo")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			category, err := requiredParam[string](request, "category")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			title, err := requiredParam[string](request, "title")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			body, err := requiredParam[string](request, "body")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+
+			client, err := getGQLClient(ctx)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get GitHub GQL client: %w", err)
+			}
+
+			// The mutation needs the IDs of the repository and the category
+			var query discussionCategoriesQuery
+			if err := client.Query(ctx, &query, map[string]any{
+				"owner": githubv4.String(owner),
+				"repo":  githubv4.String(repo),
+			}); err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("failed to get discussion categories: %v", err)), nil
+			}
+			var categoryID githubv4.ID
+			var names []string
+			for _, c := range query.Repository.DiscussionCategories.Nodes {
+				if c.ID == category || strings.EqualFold(string(c.Name), category) || strings.EqualFold(string(c.Slug), category) {
+					categoryID = c.ID
+					break
+				}
+				names = append(names, string(c.Name))
+			}
+			if categoryID == nil {
+				return mcp.NewToolResultError(fmt.Sprintf("%s/%s has no discussion category %q, the categories are: %s", owner, repo, category, strings.Join(names, ", "))), nil
+			}
+
+			var mutation struct {
+				CreateDiscussion struct {
+					Discussion struct {
+						ID     githubv4.ID     `json:"id"`
+						Number githubv4.Int    `json:"number"`
+						URL    githubv4.String `json:"url"`
+					}
+				} `graphql:"createDiscussion(input: $input)"`
+			}
+			if err := client.Mutate(ctx, &mutation, githubv4.CreateDiscussionInput{
+				RepositoryID: query.Repository.ID,
+				CategoryID:   categoryID,
+				Title:        githubv4.String(title),
+				Body:         githubv4.String(body),
+			}, nil); err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("failed to create discussion: %v", err)), nil
+			}
+
+			r, err := json.Marshal(mutation.CreateDiscussion.Discussion)
+			if err != nil {
+				return nil, fmt.Errorf("failed to marshal response: %w", err)
+			}
+
+			return mcp.NewToolResultText(string(r)), nil
+		}
+}
+
+// AddDiscussionComment creates a tool to comment on a discussion, or reply to a comment on it.
+func AddDiscussionComment(getGQLClient GetGQLClientFn, t translations.TranslationHelperFunc) (mcp.Tool, server.ToolHandlerFunc) {
+	return mcp.NewTool("add_discussion_comment",
+			mcp.WithDescription(t("TOOL_ADD_DISCUSSION_COMMENT_DESCRIPTION", "Add a comment to a discussion in a GitHub repository, or reply to one of its comments")),
+			mcp.WithToolAnnotation(mcp.ToolAnnotation{
+				Title:           t("TOOL_ADD_DISCUSSION_COMMENT_USER_TITLE", "Add discussion comment"),
+				ReadOnlyHint:    toBoolPtr(false),
+				DestructiveHint: toBoolPtr(false),
+			}),
+			mcp.WithString("owner",
+				mcp.Required(),
+				mcp.Description("Repository owner"),
+			),
+			mcp.WithString("repo",
+				mcp.Required(),
+				mcp.Description("Repository name"),
+			),
+			mcp.WithNumber("discussion_number",
+				mcp.Required(),
+				mcp.Description("Discussion number"),
+			),
+			mcp.WithString("body",
+				mcp.Required(),
+				mcp.Description("Comment body, in Markdown"),
+			),
+			mcp.WithString("reply_to_id",
+				mcp.Description("ID of a top level comment of the discussion to reply to, from get_discussion. Replies can't be replied to."),
+			),
+		),
+		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			owner, err := requiredParam[string](request, "owner")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			repo, err := requiredParam[string](request, "repo")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			number, err := RequiredInt(request, "discussion_number")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			body, err := requiredParam[string](request, "body")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			replyToID, err := OptionalParam[string](request, "reply_to_id")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+
+			client, err := getGQLClient(ctx)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get GitHub GQL client: %w", err)
+			}
+			var query discussionIDQuery
+			if err := client.Query(ctx, &query, map[string]any{
+				"owner":  githubv4.String(owner),
+				"repo":   githubv4.String(repo),
+				"number": githubv4.Int(number),
+			}); err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("failed to get discussion: %v", err)), nil
+			}
+
+			input := githubv4.AddDiscussionCommentInput{
+				DiscussionID: query.Repository.Discussion.ID,
+				Body:         githubv4.String(body),
+			}
+			if replyToID != "" {
+				input.ReplyToID = githubv4.NewID(replyToID)
+			}
+			var mutation struct {
+				AddDiscussionComment struct {
+					Comment struct {
+						ID  githubv4.ID     `json:"id"`
+						URL githubv4.String `json:"url"`
+					}
+				} `graphql:"addDiscussionComment(input: $input)"`
+			}
+			if err := client.Mutate(ctx, &mutation, input, nil); err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("failed to add discussion comment: %v", err)), nil
+			}
+
+			r, err := json.Marshal(mutation.AddDiscussionComment.Comment)
+			if err != nil {
+				return nil, fmt.Errorf("failed to marshal response: %w", err)
+			}
+
+			return mcp.NewToolResultText(string(r)), nil
+		}
+}
+
+// MarkDiscussionCommentAsAnswer creates a tool to mark a comment as the answer of its discussion.
+func MarkDiscussionCommentAsAnswer(getGQLClient GetGQLClientFn, t translations.TranslationHelperFunc) (mcp.Tool, server.ToolHandlerFunc) {
+	return mcp.NewTool("mark_discussion_comment_as_answer",
+			mcp.WithDescription(t("TOOL_MARK_DISCUSSION_COMMENT_AS_ANSWER_DESCRIPTION", "Mark a comment as the answer of its discussion, which must be in an answerable category. Any previous answer is unmarked.")),
+			mcp.WithToolAnnotation(mcp.ToolAnnotation{
+				Title:           t("TOOL_MARK_DISCUSSION_COMMENT_AS_ANSWER_USER_TITLE", "Mark discussion comment as answer"),
+				ReadOnlyHint:    toBoolPtr(false),
+				DestructiveHint: toBoolPtr(false),
+				IdempotentHint:  toBoolPtr(true),
+			}),
+			mcp.WithString("owner",
+				mcp.Required(),
+				mcp.Description("Repository owner"),
+			),
+			mcp.WithString("repo",
+				mcp.Required(),
+				mcp.Description("Repository name"),
+			),
+			mcp.WithString("comment_id",
+				mcp.Required(),
+				mcp.Description("ID of the comment, from get_discussion"),
+			),
+		),
+		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			owner, err := requiredParam[string](request, "owner")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			repo, err := requiredParam[string](request, "repo")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			commentID, err := requiredParam[string](request, "comment_id")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+
+			client, err := getGQLClient(ctx)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get GitHub GQL client: %w", err)
+			}
+
+			// The comment is only identified by its ID, so check that it is in the repository
+			// that was named, which is the one that scopes and confirmations are applied to
+			var query discussionCommentQuery
+			if err := client.Query(ctx, &query, map[string]any{
+				"id": githubv4.ID(commentID),
+			}); err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("failed to get discussion comment: %v", err)), nil
+			}
+			discussion := query.Node.DiscussionComment.Discussion
+			if !strings.EqualFold(string(discussion.Repository.NameWithOwner), owner+"/"+repo) {
+				return mcp.NewToolResultError(fmt.Sprintf("%s is not a comment on a discussion in %s/%s", commentID, owner, repo)), nil
+			}
+
+			var mutation struct {
+				MarkDiscussionCommentAsAnswer struct {
+					Discussion struct {
+						ID githubv4.ID
+					}
+				} `graphql:"markDiscussionCommentAsAnswer(input: $input)"`
+			}
+			if err := client.Mutate(ctx, &mutation, githubv4.MarkDiscussionCommentAsAnswerInput{
+				ID: githubv4.ID(commentID),
+			}, nil); err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("failed to mark discussion comment as answer: %v", err)), nil
+			}
+
+			return mcp.NewToolResultText(fmt.Sprintf("comment %s has been marked as the answer of discussion #%d", commentID, discussion.Number)), nil
+		}
+}
@@ -0,0 +1,576 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/github/github-mcp-server/internal/githubv4mock"
+	"github.com/github/github-mcp-server/internal/toolsnaps"
+	"github.com/github/github-mcp-server/pkg/translations"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/shurcooL/githubv4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_DiscussionsTools(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		create   func(GetGQLClientFn, translations.TranslationHelperFunc) (mcp.Tool, server.ToolHandlerFunc)
+		readOnly bool
+	}{
+		{name: "list_discussion_categories", create: ListDiscussionCategories, readOnly: true},
+		{name: "list_discussions", create: ListDiscussions, readOnly: true},
+		{name: "search_discussions", create: SearchDiscussions, readOnly: true},
+		{name: "get_discussion", create: GetDiscussion, readOnly: true},
+		{name: "create_discussion", create: CreateDiscussion},
+		{name: "add_discussion_comment", create: AddDiscussionComment},
+		{name: "mark_discussion_comment_as_answer", create: MarkDiscussionCommentAsAnswer},
+	}
+
+	for _, tc := range tests {
+		tool, _ := tc.create(nil, translations.NullTranslationHelper)
+		require.NoError(t, toolsnaps.Test(tool.Name, tool))
+
+		assert.Equal(t, tc.name, tool.Name)
+		assert.Equal(t, tc.readOnly, *tool.Annotations.ReadOnlyHint, "unexpected read-only hint for %s", tc.name)
+	}
+}
+
+var mockDiscussionCategories = githubv4mock.DataResponse(map[string]any{
+	"repository": map[string]any{
+		"id": "R_1",
+		"discussionCategories": map[string]any{
+			"nodes": []any{
+				map[string]any{"id": "DIC_1", "name": "Announcements", "slug": "announcements", "emoji": ":mega:", "isAnswerable": false},
+				map[string]any{"id": "DIC_2", "name": "Q&A", "slug": "q-a", "emoji": ":pray:", "isAnswerable": true},
+			},
+		},
+	},
+})
+
+func Test_ListDiscussionCategories(t *testing.T) {
+	mockedClient := githubv4mock.NewMockedHTTPClient(
+		githubv4mock.NewQueryMatcher(
+			discussionCategoriesQuery{},
+			map[string]any{
+				"owner": githubv4.String("octocat"),
+				"repo":  githubv4.String("hello-world"),
+			},
+			mockDiscussionCategories,
+		),
+	)
+	_, handler := ListDiscussionCategories(stubGetGQLClientFn(githubv4.NewClient(mockedClient)), translations.NullTranslationHelper)
+
+	result, err := handler(context.Background(), createMCPRequest(map[string]any{
+		"owner": "octocat",
+		"repo":  "hello-world",
+	}))
+	require.NoError(t, err)
+
+	var categories []map[string]any
+	require.NoError(t, json.Unmarshal([]byte(getTextResult(t, result).Text), &categories))
+	require.Len(t, categories, 2)
+	assert.Equal(t, "Q&A", categories[1]["name"])
+	assert.Equal(t, true, categories[1]["is_answerable"])
+}
+
+func Test_ListDiscussions(t *testing.T) {
+	mockDiscussions := githubv4mock.DataResponse(map[string]any{
+		"repository": map[string]any{
+			"discussions": map[string]any{
+				"totalCount": 31,
+				"pageInfo":   map[string]any{"hasNextPage": true, "endCursor": "Y3Vyc29yOjI="},
+				"nodes": []any{
+					map[string]any{
+						"id":         "D_1",
+						"number":     7,
+						"title":      "How do I greet the fake?",
+						"url":        "https://github.com/octocat/hello-world/discussions/7",
+						"author":     map[string]any{"login": "hubot"},
+						"category":   map[string]any{"name": "Q&A"},
+						"closed":     false,
+						"isAnswered": false,
+						"createdAt":  "2025-06-01T10:00:00Z",
+						"updatedAt":  "2025-06-02T10:00:00Z",
+						"comments":   map[string]any{"totalCount": 2},
+					},
+				},
+			},
+		},
+	})
+
+	tests := []struct {
+		name               string
+		requestArgs        map[string]any
+		mockedClient       *http.Client
+		expectToolError    bool
+		expectedToolErrMsg string
+	}{
+		{
+			name: "unanswered discussions in a category",
+			requestArgs: map[string]any{
+				"owner":       "octocat",
+				"repo":        "hello-world",
+				"category_id": "DIC_2",
+				"answered":    false,
+				"perPage":     float64(1),
+			},
+			mockedClient: githubv4mock.NewMockedHTTPClient(
+				githubv4mock.NewQueryMatcher(
+					listDiscussionsQuery{},
+					map[string]any{
+						"owner":      githubv4.String("octocat"),
+						"repo":       githubv4.String("hello-world"),
+						"perPage":    githubv4.Int(1),
+						"after":      (*githubv4.String)(nil),
+						"categoryId": githubv4.ID("DIC_2"),
+						"answered":   githubv4.Boolean(false),
+					},
+					mockDiscussions,
+				),
+			),
+		},
+		{
+			name: "next page of all discussions",
+			requestArgs: map[string]any{
+				"owner": "octocat",
+				"repo":  "hello-world",
+				"after": "Y3Vyc29yOjE=",
+			},
+			mockedClient: githubv4mock.NewMockedHTTPClient(
+				githubv4mock.NewQueryMatcher(
+					listDiscussionsQuery{},
+					map[string]any{
+						"owner":      githubv4.String("octocat"),
+						"repo":       githubv4.String("hello-world"),
+						"perPage":    githubv4.Int(30),
+						"after":      githubv4.String("Y3Vyc29yOjE="),
+						"categoryId": (*githubv4.ID)(nil),
+						"answered":   (*githubv4.Boolean)(nil),
+					},
+					mockDiscussions,
+				),
+			),
+		},
+		{
+			name: "too many per page",
+			requestArgs: map[string]any{
+				"owner":   "octocat",
+				"repo":    "hello-world",
+				"perPage": float64(500),
+			},
+			mockedClient:       githubv4mock.NewMockedHTTPClient(),
+			expectToolError:    true,
+			expectedToolErrMsg: "perPage must be between 1 and 100",
+		},
+		{
+			name: "repository without discussions",
+			requestArgs: map[string]any{
+				"owner": "octocat",
+				"repo":  "hello-world",
+			},
+			mockedClient: githubv4mock.NewMockedHTTPClient(
+				githubv4mock.NewQueryMatcher(
+					listDiscussionsQuery{},
+					map[string]any{
+						"owner":      githubv4.String("octocat"),
+						"repo":       githubv4.String("hello-world"),
+						"perPage":    githubv4.Int(30),
+						"after":      (*githubv4.String)(nil),
+						"categoryId": (*githubv4.ID)(nil),
+						"answered":   (*githubv4.Boolean)(nil),
+					},
+					githubv4mock.ErrorResponse("Could not resolve to a Repository with the name 'octocat/hello-world'."),
+				),
+			),
+			expectToolError:    true,
+			expectedToolErrMsg: "failed to list discussions: Could not resolve to a Repository",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			_, handler := ListDiscussions(stubGetGQLClientFn(githubv4.NewClient(tc.mockedClient)), translations.NullTranslationHelper)
+
+			result, err := handler(context.Background(), createMCPRequest(tc.requestArgs))
+			require.NoError(t, err)
+
+			textContent := getTextResult(t, result)
+			if tc.expectToolError {
+				require.True(t, result.IsError)
+				assert.Contains(t, textContent.Text, tc.expectedToolErrMsg)
+				return
+			}
+
+			require.False(t, result.IsError, textContent.Text)
+			var list DiscussionList
+			require.NoError(t, json.Unmarshal([]byte(textContent.Text), &list))
+			assert.Equal(t, 31, list.TotalCount)
+			assert.Equal(t, githubv4.String("Y3Vyc29yOjI="), list.PageInfo.EndCursor)
+			require.Len(t, list.Discussions, 1)
+			assert.Equal(t, githubv4.Int(7), list.Discussions[0].Number)
+			assert.Equal(t, githubv4.String("hubot"), list.Discussions[0].Author.Login)
+		})
+	}
+}
+
+func Test_SearchDiscussions(t *testing.T) {
+	mockedClient := githubv4mock.NewMockedHTTPClient(
+		githubv4mock.NewQueryMatcher(
+			searchDiscussionsQuery{},
+			map[string]any{
+				"query":   githubv4.String("repo:octocat/hello-world is:unanswered greet"),
+				"perPage": githubv4.Int(30),
+				"after":   (*githubv4.String)(nil),
+			},
+			githubv4mock.DataResponse(map[string]any{
+				"search": map[string]any{
+					"discussionCount": 1,
+					"pageInfo":        map[string]any{"hasNextPage": false, "endCursor": "Y3Vyc29yOjE="},
+					"nodes": []any{
+						map[string]any{"id": "D_1", "number": 7, "title": "How do I greet the fake?"},
+					},
+				},
+			}),
+		),
+	)
+	_, handler := SearchDiscussions(stubGetGQLClientFn(githubv4.NewClient(mockedClient)), translations.NullTranslationHelper)
+
+	result, err := handler(context.Background(), createMCPRequest(map[string]any{
+		"query": "is:unanswered greet",
+		"owner": "octocat",
+		"repo":  "hello-world",
+	}))
+	require.NoError(t, err)
+
+	var list DiscussionList
+	require.NoError(t, json.Unmarshal([]byte(getTextResult(t, result).Text), &list))
+	assert.Equal(t, 1, list.TotalCount)
+	require.Len(t, list.Discussions, 1)
+	assert.Equal(t, githubv4.String("How do I greet the fake?"), list.Discussions[0].Title)
+
+	result, err = handler(context.Background(), createMCPRequest(map[string]any{
+		"query": "greet",
+		"owner": "octocat",
+	}))
+	require.NoError(t, err)
+	require.True(t, result.IsError)
+	assert.Equal(t, "owner and repo must be given together", getTextResult(t, result).Text)
+}
+
+func Test_GetDiscussion(t *testing.T) {
+	mockedClient := githubv4mock.NewMockedHTTPClient(
+		githubv4mock.NewQueryMatcher(
+			getDiscussionQuery{},
+			map[string]any{
+				"owner":   githubv4.String("octocat"),
+				"repo":    githubv4.String("hello-world"),
+				"number":  githubv4.Int(7),
+				"perPage": githubv4.Int(30),
+				"after":   (*githubv4.String)(nil),
+			},
+			githubv4mock.DataResponse(map[string]any{
+				"repository": map[string]any{
+					"discussion": map[string]any{
+						"id":         "D_1",
+						"number":     7,
+						"title":      "How do I greet the fake?",
+						"body":       "It won't say hello.",
+						"isAnswered": true,
+						"comments": map[string]any{
+							"totalCount": 1,
+							"pageInfo":   map[string]any{"hasNextPage": false, "endCursor": "Y3Vyc29yOjE="},
+							"nodes": []any{
+								map[string]any{
+									"id":       "DC_1",
+									"body":     "Have you tried asking it nicely?",
+									"author":   map[string]any{"login": "octocat"},
+									"isAnswer": false,
+									"replies": map[string]any{
+										"totalCount": 1,
+										"nodes": []any{
+											map[string]any{
+												"id":       "DC_2",
+												"body":     "Asking nicely works.",
+												"author":   map[string]any{"login": "hubot"},
+												"isAnswer": true,
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			}),
+		),
+	)
+	_, handler := GetDiscussion(stubGetGQLClientFn(githubv4.NewClient(mockedClient)), translations.NullTranslationHelper)
+
+	result, err := handler(context.Background(), createMCPRequest(map[string]any{
+		"owner":             "octocat",
+		"repo":              "hello-world",
+		"discussion_number": float64(7),
+	}))
+	require.NoError(t, err)
+
+	var discussion Discussion
+	require.NoError(t, json.Unmarshal([]byte(getTextResult(t, result).Text), &discussion))
+	assert.Equal(t, githubv4.String("It won't say hello."), discussion.Body)
+	require.Len(t, discussion.Comments.Nodes, 1)
+	comment := discussion.Comments.Nodes[0]
+	assert.Equal(t, githubv4.String("octocat"), comment.Author.Login)
+	require.Len(t, comment.Replies.Nodes, 1)
+	assert.Equal(t, githubv4.String("Asking nicely works."), comment.Replies.Nodes[0].Body)
+	assert.True(t, bool(comment.Replies.Nodes[0].IsAnswer))
+}
+
+func Test_CreateDiscussion(t *testing.T) {
+	categoriesMatcher := githubv4mock.NewQueryMatcher(
+		discussionCategoriesQuery{},
+		map[string]any{
+			"owner": githubv4.String("octocat"),
+			"repo":  githubv4.String("hello-world"),
+		},
+		mockDiscussionCategories,
+	)
+	createMatcher := githubv4mock.NewMutationMatcher(
+		struct {
+			CreateDiscussion struct {
+				Discussion struct {
+					ID     githubv4.ID
+					Number githubv4.Int
+					URL    githubv4.String
+				}
+			} `graphql:"createDiscussion(input: $input)"`
+		}{},
+		githubv4.CreateDiscussionInput{
+			RepositoryID: githubv4.ID("R_1"),
+			CategoryID:   githubv4.ID("DIC_2"),
+			Title:        "How do I greet the fake?",
+			Body:         "It won't say hello.",
+		},
+		nil,
+		githubv4mock.DataResponse(map[string]any{
+			"createDiscussion": map[string]any{
+				"discussion": map[string]any{
+					"id":     "D_1",
+					"number": 7,
+					"url":    "https://github.com/octocat/hello-world/discussions/7",
+				},
+			},
+		}),
+	)
+
+	tests := []struct {
+		name               string
+		category           string
+		mockedClient       *http.Client
+		expectToolError    bool
+		expectedToolErrMsg string
+	}{
+		{
+			name:         "category by name",
+			category:     "q&a",
+			mockedClient: githubv4mock.NewMockedHTTPClient(categoriesMatcher, createMatcher),
+		},
+		{
+			name:         "category by slug",
+			category:     "q-a",
+			mockedClient: githubv4mock.NewMockedHTTPClient(categoriesMatcher, createMatcher),
+		},
+		{
+			name:         "category by ID",
+			category:     "DIC_2",
+			mockedClient: githubv4mock.NewMockedHTTPClient(categoriesMatcher, createMatcher),
+		},
+		{
+			name:               "unknown category",
+			category:           "Ideas",
+			mockedClient:       githubv4mock.NewMockedHTTPClient(categoriesMatcher),
+			expectToolError:    true,
+			expectedToolErrMsg: `octocat/hello-world has no discussion category "Ideas", the categories are: Announcements, Q&A`,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			_, handler := CreateDiscussion(stubGetGQLClientFn(githubv4.NewClient(tc.mockedClient)), translations.NullTranslationHelper)
+
+			result, err := handler(context.Background(), createMCPRequest(map[string]any{
+				"owner":    "octocat",
+				"repo":     "hello-world",
+				"category": tc.category,
+				"title":    "How do I greet the fake?",
+				"body":     "It won't say hello.",
+			}))
+			require.NoError(t, err)
+
+			textContent := getTextResult(t, result)
+			if tc.expectToolError {
+				require.True(t, result.IsError)
+				assert.Equal(t, tc.expectedToolErrMsg, textContent.Text)
+				return
+			}
+
+			require.False(t, result.IsError, textContent.Text)
+			assert.JSONEq(t, `{"id":"D_1","number":7,"url":"https://github.com/octocat/hello-world/discussions/7"}`, textContent.Text)
+		})
+	}
+}
+
+func Test_AddDiscussionComment(t *testing.T) {
+	idMatcher := githubv4mock.NewQueryMatcher(
+		discussionIDQuery{},
+		map[string]any{
+			"owner":  githubv4.String("octocat"),
+			"repo":   githubv4.String("hello-world"),
+			"number": githubv4.Int(7),
+		},
+		githubv4mock.DataResponse(map[string]any{
+			"repository": map[string]any{"discussion": map[string]any{"id": "D_1"}},
+		}),
+	)
+	addComment := struct {
+		AddDiscussionComment struct {
+			Comment struct {
+				ID  githubv4.ID
+				URL githubv4.String
+			}
+		} `graphql:"addDiscussionComment(input: $input)"`
+	}{}
+	commentResponse := githubv4mock.DataResponse(map[string]any{
+		"addDiscussionComment": map[string]any{
+			"comment": map[string]any{"id": "DC_3", "url": "https://github.com/octocat/hello-world/discussions/7#discussioncomment-3"},
+		},
+	})
+
+	tests := []struct {
+		name         string
+		requestArgs  map[string]any
+		mockedClient *http.Client
+	}{
+		{
+			name: "comment",
+			requestArgs: map[string]any{
+				"owner":             "octocat",
+				"repo":              "hello-world",
+				"discussion_number": float64(7),
+				"body":              "Try asking it nicely.",
+			},
+			mockedClient: githubv4mock.NewMockedHTTPClient(
+				idMatcher,
+				githubv4mock.NewMutationMatcher(addComment, githubv4.AddDiscussionCommentInput{
+					DiscussionID: githubv4.ID("D_1"),
+					Body:         "Try asking it nicely.",
+				}, nil, commentResponse),
+			),
+		},
+		{
+			name: "reply",
+			requestArgs: map[string]any{
+				"owner":             "octocat",
+				"repo":              "hello-world",
+				"discussion_number": float64(7),
+				"body":              "That worked, thanks!",
+				"reply_to_id":       "DC_1",
+			},
+			mockedClient: githubv4mock.NewMockedHTTPClient(
+				idMatcher,
+				githubv4mock.NewMutationMatcher(addComment, githubv4.AddDiscussionCommentInput{
+					DiscussionID: githubv4.ID("D_1"),
+					Body:         "That worked, thanks!",
+					ReplyToID:    githubv4.NewID("DC_1"),
+				}, nil, commentResponse),
+			),
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			_, handler := AddDiscussionComment(stubGetGQLClientFn(githubv4.NewClient(tc.mockedClient)), translations.NullTranslationHelper)
+
+			result, err := handler(context.Background(), createMCPRequest(tc.requestArgs))
+			require.NoError(t, err)
+
+			textContent := getTextResult(t, result)
+			require.False(t, result.IsError, textContent.Text)
+			assert.JSONEq(t, `{"id":"DC_3","url":"https://github.com/octocat/hello-world/discussions/7#discussioncomment-3"}`, textContent.Text)
+		})
+	}
+}
+
+func Test_MarkDiscussionCommentAsAnswer(t *testing.T) {
+	commentMatcher := githubv4mock.NewQueryMatcher(
+		discussionCommentQuery{},
+		map[string]any{"id": githubv4.ID("DC_2")},
+		githubv4mock.DataResponse(map[string]any{
+			"node": map[string]any{
+				"discussion": map[string]any{
+					"number":     7,
+					"repository": map[string]any{"nameWithOwner": "octocat/hello-world"},
+				},
+			},
+		}),
+	)
+
+	tests := []struct {
+		name            string
+		repo            string
+		mockedClient    *http.Client
+		expectToolError bool
+		expectedText    string
+	}{
+		{
+			name: "comment in the repository",
+			repo: "Hello-World",
+			mockedClient: githubv4mock.NewMockedHTTPClient(
+				commentMatcher,
+				githubv4mock.NewMutationMatcher(
+					struct {
+						MarkDiscussionCommentAsAnswer struct {
+							Discussion struct {
+								ID githubv4.ID
+							}
+						} `graphql:"markDiscussionCommentAsAnswer(input: $input)"`
+					}{},
+					githubv4.MarkDiscussionCommentAsAnswerInput{ID: githubv4.ID("DC_2")},
+					nil,
+					githubv4mock.DataResponse(map[string]any{
+						"markDiscussionCommentAsAnswer": map[string]any{"discussion": map[string]any{"id": "D_1"}},
+					}),
+				),
+			),
+			expectedText: "comment DC_2 has been marked as the answer of discussion #7",
+		},
+		{
+			name:            "comment in another repository",
+			repo:            "spoon-knife",
+			mockedClient:    githubv4mock.NewMockedHTTPClient(commentMatcher),
+			expectToolError: true,
+			expectedText:    "DC_2 is not a comment on a discussion in octocat/spoon-knife",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			_, handler := MarkDiscussionCommentAsAnswer(stubGetGQLClientFn(githubv4.NewClient(tc.mockedClient)), translations.NullTranslationHelper)
+
+			result, err := handler(context.Background(), createMCPRequest(map[string]any{
+				"owner":      "octocat",
+				"repo":       tc.repo,
+				"comment_id": "DC_2",
+			}))
+			require.NoError(t, err)
+
+			assert.Equal(t, tc.expectToolError, result.IsError)
+			assert.Equal(t, tc.expectedText, getTextResult(t, result).Text)
+		})
+	}
+}
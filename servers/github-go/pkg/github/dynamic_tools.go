@@ -19,7 +19,7 @@ func ToolsetEnum(toolsetGroup *toolsets.ToolsetGroup) mcp.PropertyOption {
 	return mcp.Enum(toolsetNames...)
 }
 
-func EnableToolset(s *server.MCPServer, toolsetGroup *toolsets.ToolsetGroup, t translations.TranslationHelperFunc) (tool mcp.Tool, handler server.ToolHandlerFunc) {
+func EnableToolset(s *server.MCPServer, sessionToolsets *toolsets.SessionToolsets, t translations.TranslationHelperFunc) (tool mcp.Tool, handler server.ToolHandlerFunc) {
 	return mcp.NewTool("enable_toolset",
 			mcp.WithDescription(t("TOOL_ENABLE_TOOLSET_DESCRIPTION", "Enable one of the sets of tools the GitHub MCP server provides, use get_toolset_tools and list_available_toolsets first to see what this will enable")),
 			mcp.WithToolAnnotation(mcp.ToolAnnotation{
@@ -30,36 +30,75 @@ func EnableToolset(s *server.MCPServer, toolsetGroup *toolsets.ToolsetGroup, t t
 			mcp.WithString("toolset",
 				mcp.Required(),
 				mcp.Description("The name of the toolset to enable"),
-				ToolsetEnum(toolsetGroup),
+				ToolsetEnum(sessionToolsets.Group()),
 			),
 		),
-		func(_ context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-			// We need to convert the toolsets back to a map for JSON serialization
+		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 			toolsetName, err := requiredParam[string](request, "toolset")
 			if err != nil {
 				return mcp.NewToolResultError(err.Error()), nil
 			}
-			toolset := toolsetGroup.Toolsets[toolsetName]
-			if toolset == nil {
+			if sessionToolsets.Group().Toolsets[toolsetName] == nil {
 				return mcp.NewToolResultError(fmt.Sprintf("Toolset %s not found", toolsetName)), nil
 			}
-			if toolset.Enabled {
+
+			// Only the calling session is affected, other clients keep their own toolsets
+			changed, err := sessionToolsets.Enable(toolsets.SessionIDFromContext(ctx), toolsetName)
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			if !changed {
 				return mcp.NewToolResultText(fmt.Sprintf("Toolset %s is already enabled", toolsetName)), nil
 			}
+			notifyToolsChanged(ctx, s)
 
-			toolset.Enabled = true
+			return mcp.NewToolResultText(fmt.Sprintf("Toolset %s enabled", toolsetName)), nil
+		}
+}
 
-			// caution: this currently affects the global tools and notifies all clients:
-			//
-			// Send notification to all initialized sessions
-			// s.sendNotificationToAllClients("notifications/tools/list_changed", nil)
-			s.AddTools(toolset.GetActiveTools()...)
+func DisableToolset(s *server.MCPServer, sessionToolsets *toolsets.SessionToolsets, t translations.TranslationHelperFunc) (tool mcp.Tool, handler server.ToolHandlerFunc) {
+	return mcp.NewTool("disable_toolset",
+			mcp.WithDescription(t("TOOL_DISABLE_TOOLSET_DESCRIPTION", "Disable one of the enabled sets of tools the GitHub MCP server provides, to remove tools that are no longer needed for the task")),
+			mcp.WithToolAnnotation(mcp.ToolAnnotation{
+				Title: t("TOOL_DISABLE_TOOLSET_USER_TITLE", "Disable a toolset"),
+				// Not modifying GitHub data so no need to show a warning
+				ReadOnlyHint: toBoolPtr(true),
+			}),
+			mcp.WithString("toolset",
+				mcp.Required(),
+				mcp.Description("The name of the toolset to disable"),
+				ToolsetEnum(sessionToolsets.Group()),
+			),
+		),
+		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			toolsetName, err := requiredParam[string](request, "toolset")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			if sessionToolsets.Group().Toolsets[toolsetName] == nil {
+				return mcp.NewToolResultError(fmt.Sprintf("Toolset %s not found", toolsetName)), nil
+			}
 
-			return mcp.NewToolResultText(fmt.Sprintf("Toolset %s enabled", toolsetName)), nil
+			changed, err := sessionToolsets.Disable(toolsets.SessionIDFromContext(ctx), toolsetName)
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			if !changed {
+				return mcp.NewToolResultText(fmt.Sprintf("Toolset %s is already disabled", toolsetName)), nil
+			}
+			notifyToolsChanged(ctx, s)
+
+			return mcp.NewToolResultText(fmt.Sprintf("Toolset %s disabled", toolsetName)), nil
 		}
 }
 
-func ListAvailableToolsets(toolsetGroup *toolsets.ToolsetGroup, t translations.TranslationHelperFunc) (tool mcp.Tool, handler server.ToolHandlerFunc) {
+// notifyToolsChanged tells the calling session, and only that session, to list its tools again.
+func notifyToolsChanged(ctx context.Context, s *server.MCPServer) {
+	// Outside of a session there is no one to notify
+	_ = s.SendNotificationToClient(ctx, "notifications/tools/list_changed", nil)
+}
+
+func ListAvailableToolsets(sessionToolsets *toolsets.SessionToolsets, t translations.TranslationHelperFunc) (tool mcp.Tool, handler server.ToolHandlerFunc) {
 	return mcp.NewTool("list_available_toolsets",
 			mcp.WithDescription(t("TOOL_LIST_AVAILABLE_TOOLSETS_DESCRIPTION", "List all available toolsets this GitHub MCP server can offer, providing the enabled status of each. Use this when a task could be achieved with a GitHub tool and the currently available tools aren't enough. Call get_toolset_tools with these toolset names to discover specific tools you can call")),
 			mcp.WithToolAnnotation(mcp.ToolAnnotation{
@@ -67,18 +106,20 @@ func ListAvailableToolsets(toolsetGroup *toolsets.ToolsetGroup, t translations.T
 				ReadOnlyHint: toBoolPtr(true),
 			}),
 		),
-		func(_ context.Context, _ mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		func(ctx context.Context, _ mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 			// We need to convert the toolsetGroup back to a map for JSON serialization
 
 			payload := []map[string]string{}
 
-			for name, ts := range toolsetGroup.Toolsets {
+			// The enabled status is that of the calling session
+			sessionID := toolsets.SessionIDFromContext(ctx)
+			for name, ts := range sessionToolsets.Group().Toolsets {
 				{
 					t := map[string]string{
 						"name":              name,
 						"description":       ts.Description,
 						"can_enable":        "true",
-						"currently_enabled": fmt.Sprintf("%t", ts.Enabled),
+						"currently_enabled": fmt.Sprintf("%t", sessionToolsets.IsEnabled(sessionID, name)),
 					}
 					payload = append(payload, t)
 				}
@@ -84,8 +84,9 @@ func AddIssueComment(getClient GetClientFn, t translations.TranslationHelperFunc
 	return mcp.NewTool("add_issue_comment",
 			mcp.WithDescription(t("TOOL_ADD_ISSUE_COMMENT_DESCRIPTION", "Add a comment to a specific issue in a GitHub repository.")),
 			mcp.WithToolAnnotation(mcp.ToolAnnotation{
-				Title:        t("TOOL_ADD_ISSUE_COMMENT_USER_TITLE", "Add comment to issue"),
-				ReadOnlyHint: toBoolPtr(false),
+				Title:           t("TOOL_ADD_ISSUE_COMMENT_USER_TITLE", "Add comment to issue"),
+				ReadOnlyHint:    toBoolPtr(false),
+				DestructiveHint: toBoolPtr(false),
 			}),
 			mcp.WithString("owner",
 				mcp.Required(),
@@ -246,8 +247,9 @@ func CreateIssue(getClient GetClientFn, t translations.TranslationHelperFunc) (t
 	return mcp.NewTool("create_issue",
 			mcp.WithDescription(t("TOOL_CREATE_ISSUE_DESCRIPTION", "Create a new issue in a GitHub repository.")),
 			mcp.WithToolAnnotation(mcp.ToolAnnotation{
-				Title:        t("TOOL_CREATE_ISSUE_USER_TITLE", "Open new issue"),
-				ReadOnlyHint: toBoolPtr(false),
+				Title:           t("TOOL_CREATE_ISSUE_USER_TITLE", "Open new issue"),
+				ReadOnlyHint:    toBoolPtr(false),
+				DestructiveHint: toBoolPtr(false),
 			}),
 			mcp.WithString("owner",
 				mcp.Required(),
@@ -490,8 +492,9 @@ func UpdateIssue(getClient GetClientFn, t translations.TranslationHelperFunc) (t
 	return mcp.NewTool("update_issue",
 			mcp.WithDescription(t("TOOL_UPDATE_ISSUE_DESCRIPTION", "Update an existing issue in a GitHub repository.")),
 			mcp.WithToolAnnotation(mcp.ToolAnnotation{
-				Title:        t("TOOL_UPDATE_ISSUE_USER_TITLE", "Edit issue"),
-				ReadOnlyHint: toBoolPtr(false),
+				Title:           t("TOOL_UPDATE_ISSUE_USER_TITLE", "Edit issue"),
+				ReadOnlyHint:    toBoolPtr(false),
+				DestructiveHint: toBoolPtr(true),
 			}),
 			mcp.WithString("owner",
 				mcp.Required(),
@@ -758,9 +761,10 @@ func AssignCopilotToIssue(getGQLClient GetGQLClientFn, t translations.Translatio
 	return mcp.NewTool("assign_copilot_to_issue",
 			mcp.WithDescription(t("TOOL_ASSIGN_COPILOT_TO_ISSUE_DESCRIPTION", description.String())),
 			mcp.WithToolAnnotation(mcp.ToolAnnotation{
-				Title:          t("TOOL_ASSIGN_COPILOT_TO_ISSUE_USER_TITLE", "Assign Copilot to issue"),
-				ReadOnlyHint:   toBoolPtr(false),
-				IdempotentHint: toBoolPtr(true),
+				Title:           t("TOOL_ASSIGN_COPILOT_TO_ISSUE_USER_TITLE", "Assign Copilot to issue"),
+				ReadOnlyHint:    toBoolPtr(false),
+				DestructiveHint: toBoolPtr(false),
+				IdempotentHint:  toBoolPtr(true),
 			}),
 			mcp.WithString("owner",
 				mcp.Required(),
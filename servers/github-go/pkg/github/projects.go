@@ -0,0 +1,807 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/github/github-mcp-server/pkg/translations"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/shurcooL/githubv4"
+)
+
+// Project is a Projects (v2) project of a user or organization.
+type Project struct {
+	ID               githubv4.ID       `json:"id"`
+	Number           githubv4.Int      `json:"number"`
+	Title            githubv4.String   `json:"title"`
+	ShortDescription githubv4.String   `json:"short_description"`
+	URL              githubv4.String   `json:"url"`
+	Closed           githubv4.Boolean  `json:"closed"`
+	Public           githubv4.Boolean  `json:"public"`
+	UpdatedAt        githubv4.DateTime `json:"updated_at"`
+	Items            struct {
+		TotalCount githubv4.Int `json:"total_count"`
+	} `json:"items"`
+}
+
+// ProjectList is a page of projects.
+type ProjectList struct {
+	TotalCount int                `json:"total_count"`
+	PageInfo   DiscussionPageInfo `json:"page_info"`
+	Projects   []Project          `json:"projects"`
+}
+
+// ProjectField is a field of a project, with the options of single select fields and the
+// iterations of iteration fields.
+type ProjectField struct {
+	ID         githubv4.ID          `json:"id"`
+	Name       githubv4.String      `json:"name"`
+	DataType   githubv4.String      `json:"data_type"`
+	Options    []ProjectFieldOption `json:"options,omitempty"`
+	Iterations []ProjectIteration   `json:"iterations,omitempty"`
+}
+
+// ProjectFieldOption is an option of a single select field.
+type ProjectFieldOption struct {
+	ID   githubv4.String `json:"id"`
+	Name githubv4.String `json:"name"`
+}
+
+// ProjectIteration is an iteration of an iteration field.
+type ProjectIteration struct {
+	ID        githubv4.String `json:"id"`
+	Title     githubv4.String `json:"title"`
+	StartDate githubv4.String `json:"start_date"`
+	Duration  githubv4.Int    `json:"duration"`
+	Completed bool            `json:"completed"`
+}
+
+// ProjectItem is an item of a project with the values of its fields by field name.
+type ProjectItem struct {
+	ID       githubv4.ID         `json:"id"`
+	Type     githubv4.String     `json:"type"`
+	Archived githubv4.Boolean    `json:"archived"`
+	Content  *ProjectItemContent `json:"content,omitempty"`
+	Fields   map[string]any      `json:"fields"`
+}
+
+// ProjectItemContent is the issue, pull request or draft issue that a project item tracks.
+// Draft issues only have a title.
+type ProjectItemContent struct {
+	Number     githubv4.Int     `json:"number,omitempty"`
+	Title      githubv4.String  `json:"title"`
+	URL        githubv4.String  `json:"url,omitempty"`
+	Closed     githubv4.Boolean `json:"closed,omitempty"`
+	Repository string           `json:"repository,omitempty"`
+}
+
+// ProjectItemList is a page of project items.
+type ProjectItemList struct {
+	TotalCount int                `json:"total_count"`
+	PageInfo   DiscussionPageInfo `json:"page_info"`
+	Items      []ProjectItem      `json:"items"`
+}
+
+type projectIterationNode struct {
+	ID        githubv4.String
+	Title     githubv4.String
+	StartDate githubv4.String
+	Duration  githubv4.Int
+}
+
+type projectFieldNode struct {
+	Field struct {
+		ID       githubv4.ID
+		Name     githubv4.String
+		DataType githubv4.String
+	} `graphql:"... on ProjectV2Field"`
+	SingleSelectField struct {
+		ID       githubv4.ID
+		Name     githubv4.String
+		DataType githubv4.String
+		Options  []ProjectFieldOption
+	} `graphql:"... on ProjectV2SingleSelectField"`
+	IterationField struct {
+		ID            githubv4.ID
+		Name          githubv4.String
+		DataType      githubv4.String
+		Configuration struct {
+			Iterations          []projectIterationNode
+			CompletedIterations []projectIterationNode
+		}
+	} `graphql:"... on ProjectV2IterationField"`
+}
+
+// projectField flattens the field. The id, name and data type are decoded into every fragment,
+// so the data type decides where options and iterations are taken from.
+func (n projectFieldNode) projectField() ProjectField {
+	field := ProjectField{ID: n.Field.ID, Name: n.Field.Name, DataType: n.Field.DataType}
+	switch field.DataType {
+	case "SINGLE_SELECT":
+		field.Options = n.SingleSelectField.Options
+	case "ITERATION":
+		field.Iterations = appendIterations(field.Iterations, n.IterationField.Configuration.Iterations, false)
+		field.Iterations = appendIterations(field.Iterations, n.IterationField.Configuration.CompletedIterations, true)
+	}
+	return field
+}
+
+func appendIterations(iterations []ProjectIteration, nodes []projectIterationNode, completed bool) []ProjectIteration {
+	for _, i := range nodes {
+		iterations = append(iterations, ProjectIteration{
+			ID:        i.ID,
+			Title:     i.Title,
+			StartDate: i.StartDate,
+			Duration:  i.Duration,
+			Completed: completed,
+		})
+	}
+	return iterations
+}
+
+type projectItemContentNode struct {
+	Number     githubv4.Int
+	Title      githubv4.String
+	URL        githubv4.String
+	Closed     githubv4.Boolean
+	Repository struct {
+		NameWithOwner githubv4.String
+	}
+}
+
+type projectFieldNameNode struct {
+	Common struct {
+		Name githubv4.String
+	} `graphql:"... on ProjectV2FieldCommon"`
+}
+
+type projectItemNode struct {
+	ID         githubv4.ID
+	Type       githubv4.String
+	IsArchived githubv4.Boolean
+	Content    struct {
+		Issue       projectItemContentNode `graphql:"... on Issue"`
+		PullRequest projectItemContentNode `graphql:"... on PullRequest"`
+		DraftIssue  struct {
+			Title githubv4.String
+		} `graphql:"... on DraftIssue"`
+	}
+	FieldValues struct {
+		Nodes []struct {
+			TypeName string `graphql:"__typename"`
+			Text     struct {
+				Text  githubv4.String
+				Field projectFieldNameNode
+			} `graphql:"... on ProjectV2ItemFieldTextValue"`
+			Number struct {
+				Number githubv4.Float
+				Field  projectFieldNameNode
+			} `graphql:"... on ProjectV2ItemFieldNumberValue"`
+			Date struct {
+				Date  githubv4.String
+				Field projectFieldNameNode
+			} `graphql:"... on ProjectV2ItemFieldDateValue"`
+			SingleSelect struct {
+				Name  githubv4.String
+				Field projectFieldNameNode
+			} `graphql:"... on ProjectV2ItemFieldSingleSelectValue"`
+			Iteration struct {
+				Title githubv4.String
+				Field projectFieldNameNode
+			} `graphql:"... on ProjectV2ItemFieldIterationValue"`
+		}
+	} `graphql:"fieldValues(first: 50)"`
+}
+
+// projectItem flattens the item, keeping the values of the fields that can be set with
+// update_project_item_field, by field name.
+func (n projectItemNode) projectItem() ProjectItem {
+	item := ProjectItem{ID: n.ID, Type: n.Type, Archived: n.IsArchived, Fields: map[string]any{}}
+	switch n.Type {
+	case "ISSUE":
+		item.Content = n.Content.Issue.projectItemContent()
+	case "PULL_REQUEST":
+		item.Content = n.Content.PullRequest.projectItemContent()
+	case "DRAFT_ISSUE":
+		item.Content = &ProjectItemContent{Title: n.Content.DraftIssue.Title}
+	}
+	for _, v := range n.FieldValues.Nodes {
+		switch v.TypeName {
+		case "ProjectV2ItemFieldTextValue":
+			item.Fields[string(v.Text.Field.Common.Name)] = v.Text.Text
+		case "ProjectV2ItemFieldNumberValue":
+			item.Fields[string(v.Number.Field.Common.Name)] = v.Number.Number
+		case "ProjectV2ItemFieldDateValue":
+			item.Fields[string(v.Date.Field.Common.Name)] = v.Date.Date
+		case "ProjectV2ItemFieldSingleSelectValue":
+			item.Fields[string(v.SingleSelect.Field.Common.Name)] = v.SingleSelect.Name
+		case "ProjectV2ItemFieldIterationValue":
+			item.Fields[string(v.Iteration.Field.Common.Name)] = v.Iteration.Title
+		}
+	}
+	return item
+}
+
+func (n projectItemContentNode) projectItemContent() *ProjectItemContent {
+	return &ProjectItemContent{
+		Number:     n.Number,
+		Title:      n.Title,
+		URL:        n.URL,
+		Closed:     n.Closed,
+		Repository: string(n.Repository.NameWithOwner),
+	}
+}
+
+type listProjectsQuery struct {
+	RepositoryOwner struct {
+		ProjectV2Owner struct {
+			ProjectsV2 struct {
+				TotalCount githubv4.Int
+				PageInfo   DiscussionPageInfo
+				Nodes      []Project
+			} `graphql:"projectsV2(first: $perPage, after: $after, query: $query, orderBy: {field: UPDATED_AT, direction: DESC})"`
+		} `graphql:"... on ProjectV2Owner"`
+	} `graphql:"repositoryOwner(login: $owner)"`
+}
+
+type projectFieldsQuery struct {
+	RepositoryOwner struct {
+		ProjectV2Owner struct {
+			ProjectV2 struct {
+				ID     githubv4.ID
+				Fields struct {
+					Nodes []projectFieldNode
+				} `graphql:"fields(first: 100)"`
+			} `graphql:"projectV2(number: $number)"`
+		} `graphql:"... on ProjectV2Owner"`
+	} `graphql:"repositoryOwner(login: $owner)"`
+}
+
+type projectIDQuery struct {
+	RepositoryOwner struct {
+		ProjectV2Owner struct {
+			ProjectV2 struct {
+				ID githubv4.ID
+			} `graphql:"projectV2(number: $number)"`
+		} `graphql:"... on ProjectV2Owner"`
+	} `graphql:"repositoryOwner(login: $owner)"`
+}
+
+type listProjectItemsQuery struct {
+	RepositoryOwner struct {
+		ProjectV2Owner struct {
+			ProjectV2 struct {
+				Items struct {
+					TotalCount githubv4.Int
+					PageInfo   DiscussionPageInfo
+					Nodes      []projectItemNode
+				} `graphql:"items(first: $perPage, after: $after)"`
+			} `graphql:"projectV2(number: $number)"`
+		} `graphql:"... on ProjectV2Owner"`
+	} `graphql:"repositoryOwner(login: $owner)"`
+}
+
+type issueOrPullRequestIDQuery struct {
+	Repository struct {
+		IssueOrPullRequest struct {
+			Issue struct {
+				ID githubv4.ID
+			} `graphql:"... on Issue"`
+			PullRequest struct {
+				ID githubv4.ID
+			} `graphql:"... on PullRequest"`
+		} `graphql:"issueOrPullRequest(number: $number)"`
+	} `graphql:"repository(owner: $owner, name: $repo)"`
+}
+
+// projectID looks up the node ID of the project of owner with the given number.
+func projectID(ctx context.Context, client *githubv4.Client, owner string, number int) (githubv4.ID, error) {
+	var query projectIDQuery
+	if err := client.Query(ctx, &query, map[string]any{
+		"owner":  githubv4.String(owner),
+		"number": githubv4.Int(number),
+	}); err != nil {
+		return nil, err
+	}
+	return query.RepositoryOwner.ProjectV2Owner.ProjectV2.ID, nil
+}
+
+// ListProjects creates a tool to list the projects of a user or organization.
+func ListProjects(getGQLClient GetGQLClientFn, t translations.TranslationHelperFunc) (mcp.Tool, server.ToolHandlerFunc) {
+	return mcp.NewTool("list_projects",
+			mcp.WithDescription(t("TOOL_LIST_PROJECTS_DESCRIPTION", "List the projects (v2) of a GitHub user or organization, most recently updated first")),
+			mcp.WithToolAnnotation(mcp.ToolAnnotation{
+				Title:        t("TOOL_LIST_PROJECTS_USER_TITLE", "List projects"),
+				ReadOnlyHint: toBoolPtr(true),
+			}),
+			mcp.WithString("owner",
+				mcp.Required(),
+				mcp.Description("Login of the user or organization that owns the projects"),
+			),
+			mcp.WithString("query",
+				mcp.Description("Only list projects matching this search, such as 'roadmap is:open'"),
+			),
+			withCursorPagination(),
+		),
+		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			owner, err := requiredParam[string](request, "owner")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			q, err := OptionalParam[string](request, "query")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			variables, err := cursorPaginationVariables(request)
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			variables["owner"] = githubv4.String(owner)
+			variables["query"] = (*githubv4.String)(nil)
+			if q != "" {
+				variables["query"] = githubv4.String(q)
+			}
+
+			client, err := getGQLClient(ctx)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get GitHub GQL client: %w", err)
+			}
+			var query listProjectsQuery
+			if err := client.Query(ctx, &query, variables); err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("failed to list projects: %v", err)), nil
+			}
+
+			projects := query.RepositoryOwner.ProjectV2Owner.ProjectsV2
+			r, err := json.Marshal(ProjectList{
+				TotalCount: int(projects.TotalCount),
+				PageInfo:   projects.PageInfo,
+				Projects:   projects.Nodes,
+			})
+			if err != nil {
+				return nil, fmt.Errorf("failed to marshal response: %w", err)
+			}
+
+			return mcp.NewToolResultText(string(r)), nil
+		}
+}
+
+// GetProjectFields creates a tool to get the fields of a project with their options and iterations.
+func GetProjectFields(getGQLClient GetGQLClientFn, t translations.TranslationHelperFunc) (mcp.Tool, server.ToolHandlerFunc) {
+	return mcp.NewTool("get_project_fields",
+			mcp.WithDescription(t("TOOL_GET_PROJECT_FIELDS_DESCRIPTION", "Get the fields of a project (v2), such as Status, with the options of single select fields and the iterations of iteration fields")),
+			mcp.WithToolAnnotation(mcp.ToolAnnotation{
+				Title:        t("TOOL_GET_PROJECT_FIELDS_USER_TITLE", "Get project fields"),
+				ReadOnlyHint: toBoolPtr(true),
+			}),
+			mcp.WithString("owner",
+				mcp.Required(),
+				mcp.Description("Login of the user or organization that owns the project"),
+			),
+			mcp.WithNumber("project_number",
+				mcp.Required(),
+				mcp.Description("Project number"),
+			),
+		),
+		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			owner, err := requiredParam[string](request, "owner")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			number, err := RequiredInt(request, "project_number")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+
+			client, err := getGQLClient(ctx)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get GitHub GQL client: %w", err)
+			}
+			fields, _, err := projectFields(ctx, client, owner, number)
+			if err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("failed to get project fields: %v", err)), nil
+			}
+
+			r, err := json.Marshal(fields)
+			if err != nil {
+				return nil, fmt.Errorf("failed to marshal response: %w", err)
+			}
+
+			return mcp.NewToolResultText(string(r)), nil
+		}
+}
+
+// projectFields returns the fields of the project of owner with the given number, and the project's node ID.
+func projectFields(ctx context.Context, client *githubv4.Client, owner string, number int) ([]ProjectField, githubv4.ID, error) {
+	var query projectFieldsQuery
+	if err := client.Query(ctx, &query, map[string]any{
+		"owner":  githubv4.String(owner),
+		"number": githubv4.Int(number),
+	}); err != nil {
+		return nil, nil, err
+	}
+	project := query.RepositoryOwner.ProjectV2Owner.ProjectV2
+	fields := make([]ProjectField, 0, len(project.Fields.Nodes))
+	for _, node := range project.Fields.Nodes {
+		fields = append(fields, node.projectField())
+	}
+	return fields, project.ID, nil
+}
+
+// ListProjectItems creates a tool to list the items of a project with their field values.
+func ListProjectItems(getGQLClient GetGQLClientFn, t translations.TranslationHelperFunc) (mcp.Tool, server.ToolHandlerFunc) {
+	return mcp.NewTool("list_project_items",
+			mcp.WithDescription(t("TOOL_LIST_PROJECT_ITEMS_DESCRIPTION", "List the items of a project (v2), which are issues, pull requests and draft issues, with the values of their text, number, date, single select and iteration fields")),
+			mcp.WithToolAnnotation(mcp.ToolAnnotation{
+				Title:        t("TOOL_LIST_PROJECT_ITEMS_USER_TITLE", "List project items"),
+				ReadOnlyHint: toBoolPtr(true),
+			}),
+			mcp.WithString("owner",
+				mcp.Required(),
+				mcp.Description("Login of the user or organization that owns the project"),
+			),
+			mcp.WithNumber("project_number",
+				mcp.Required(),
+				mcp.Description("Project number"),
+			),
+			withCursorPagination(),
+		),
+		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			owner, err := requiredParam[string](request, "owner")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			number, err := RequiredInt(request, "project_number")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			variables, err := cursorPaginationVariables(request)
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			variables["owner"] = githubv4.String(owner)
+			variables["number"] = githubv4.Int(number)
+
+			client, err := getGQLClient(ctx)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get GitHub GQL client: %w", err)
+			}
+			var query listProjectItemsQuery
+			if err := client.Query(ctx, &query, variables); err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("failed to list project items: %v", err)), nil
+			}
+
+			items := query.RepositoryOwner.ProjectV2Owner.ProjectV2.Items
+			result := ProjectItemList{
+				TotalCount: int(items.TotalCount),
+				PageInfo:   items.PageInfo,
+				Items:      make([]ProjectItem, 0, len(items.Nodes)),
+			}
+			for _, node := range items.Nodes {
+				result.Items = append(result.Items, node.projectItem())
+			}
+			r, err := json.Marshal(result)
+			if err != nil {
+				return nil, fmt.Errorf("failed to marshal response: %w", err)
+			}
+
+			return mcp.NewToolResultText(string(r)), nil
+		}
+}
+
+// AddProjectItem creates a tool to add an issue or pull request to a project.
+func AddProjectItem(getGQLClient GetGQLClientFn, t translations.TranslationHelperFunc) (mcp.Tool, server.ToolHandlerFunc) {
+	return mcp.NewTool("add_project_item",
+			mcp.WithDescription(t("TOOL_ADD_PROJECT_ITEM_DESCRIPTION", "Add an issue or pull request to a project (v2). Returns the ID of the item, which is needed to set its fields. Adding an issue or pull request that is already in the project returns its existing item.")),
+			mcp.WithToolAnnotation(mcp.ToolAnnotation{
+				Title:           t("TOOL_ADD_PROJECT_ITEM_USER_TITLE", "Add project item"),
+				ReadOnlyHint:    toBoolPtr(false),
+				DestructiveHint: toBoolPtr(false),
+				IdempotentHint:  toBoolPtr(true),
+			}),
+			mcp.WithString("owner",
+				mcp.Required(),
+				mcp.Description("Login of the user or organization that owns the project"),
+			),
+			mcp.WithNumber("project_number",
+				mcp.Required(),
+				mcp.Description("Project number"),
+			),
+			mcp.WithString("item_owner",
+				mcp.Required(),
+				mcp.Description("Owner of the repository of the issue or pull request"),
+			),
+			mcp.WithString("item_repo",
+				mcp.Required(),
+				mcp.Description("Name of the repository of the issue or pull request"),
+			),
+			mcp.WithNumber("item_number",
+				mcp.Required(),
+				mcp.Description("Number of the issue or pull request"),
+			),
+		),
+		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			owner, err := requiredParam[string](request, "owner")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			number, err := RequiredInt(request, "project_number")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			itemOwner, err := requiredParam[string](request, "item_owner")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			itemRepo, err := requiredParam[string](request, "item_repo")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			itemNumber, err := RequiredInt(request, "item_number")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+
+			client, err := getGQLClient(ctx)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get GitHub GQL client: %w", err)
+			}
+			id, err := projectID(ctx, client, owner, number)
+			if err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("failed to get project: %v", err)), nil
+			}
+			var contentQuery issueOrPullRequestIDQuery
+			if err := client.Query(ctx, &contentQuery, map[string]any{
+				"owner":  githubv4.String(itemOwner),
+				"repo":   githubv4.String(itemRepo),
+				"number": githubv4.Int(itemNumber),
+			}); err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("failed to get issue or pull request: %v", err)), nil
+			}
+			// The id is decoded into both fragments, whichever of the two the number refers to.
+			contentID := contentQuery.Repository.IssueOrPullRequest.Issue.ID
+
+			var mutation struct {
+				AddProjectV2ItemByID struct {
+					Item struct {
+						ID githubv4.ID `json:"id"`
+					}
+				} `graphql:"addProjectV2ItemById(input: $input)"`
+			}
+			if err := client.Mutate(ctx, &mutation, githubv4.AddProjectV2ItemByIdInput{
+				ProjectID: id,
+				ContentID: contentID,
+			}, nil); err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("failed to add project item: %v", err)), nil
+			}
+
+			r, err := json.Marshal(mutation.AddProjectV2ItemByID.Item)
+			if err != nil {
+				return nil, fmt.Errorf("failed to marshal response: %w", err)
+			}
+
+			return mcp.NewToolResultText(string(r)), nil
+		}
+}
+
+// UpdateProjectItemField creates a tool to set or clear the value of a field of a project item.
+func UpdateProjectItemField(getGQLClient GetGQLClientFn, t translations.TranslationHelperFunc) (mcp.Tool, server.ToolHandlerFunc) {
+	return mcp.NewTool("update_project_item_field",
+			mcp.WithDescription(t("TOOL_UPDATE_PROJECT_ITEM_FIELD_DESCRIPTION", "Set the value of a text, number, date, single select or iteration field of a project (v2) item, such as its Status or Iteration, or clear it")),
+			mcp.WithToolAnnotation(mcp.ToolAnnotation{
+				Title:           t("TOOL_UPDATE_PROJECT_ITEM_FIELD_USER_TITLE", "Update project item field"),
+				ReadOnlyHint:    toBoolPtr(false),
+				DestructiveHint: toBoolPtr(true),
+				IdempotentHint:  toBoolPtr(true),
+			}),
+			mcp.WithString("owner",
+				mcp.Required(),
+				mcp.Description("Login of the user or organization that owns the project"),
+			),
+			mcp.WithNumber("project_number",
+				mcp.Required(),
+				mcp.Description("Project number"),
+			),
+			mcp.WithString("item_id",
+				mcp.Required(),
+				mcp.Description("ID of the project item, from list_project_items or add_project_item"),
+			),
+			mcp.WithString("field",
+				mcp.Required(),
+				mcp.Description("Name or ID of the field, such as 'Status'"),
+			),
+			mcp.WithString("value",
+				mcp.Description("The new value: the name of an option of a single select field, the title of an iteration, a number, a date as YYYY-MM-DD or text. Leave out to clear the field."),
+			),
+		),
+		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			owner, err := requiredParam[string](request, "owner")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			number, err := RequiredInt(request, "project_number")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			itemID, err := requiredParam[string](request, "item_id")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			fieldName, err := requiredParam[string](request, "field")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			value, err := OptionalParam[string](request, "value")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+
+			client, err := getGQLClient(ctx)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get GitHub GQL client: %w", err)
+			}
+			fields, id, err := projectFields(ctx, client, owner, number)
+			if err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("failed to get project fields: %v", err)), nil
+			}
+			var field *ProjectField
+			for i, f := range fields {
+				if f.ID == fieldName || strings.EqualFold(string(f.Name), fieldName) {
+					field = &fields[i]
+					break
+				}
+			}
+			if field == nil {
+				return mcp.NewToolResultError(fmt.Sprintf("project %d of %s has no field %q", number, owner, fieldName)), nil
+			}
+
+			if value == "" {
+				var mutation struct {
+					ClearProjectV2ItemFieldValue struct {
+						ProjectV2Item struct {
+							ID githubv4.ID
+						}
+					} `graphql:"clearProjectV2ItemFieldValue(input: $input)"`
+				}
+				if err := client.Mutate(ctx, &mutation, githubv4.ClearProjectV2ItemFieldValueInput{
+					ProjectID: id,
+					ItemID:    githubv4.ID(itemID),
+					FieldID:   field.ID,
+				}, nil); err != nil {
+					return mcp.NewToolResultError(fmt.Sprintf("failed to clear project item field: %v", err)), nil
+				}
+				return mcp.NewToolResultText(fmt.Sprintf("%s of item %s has been cleared", field.Name, itemID)), nil
+			}
+
+			fieldValue, err := projectFieldValue(*field, value)
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			var mutation struct {
+				UpdateProjectV2ItemFieldValue struct {
+					ProjectV2Item struct {
+						ID githubv4.ID
+					}
+				} `graphql:"updateProjectV2ItemFieldValue(input: $input)"`
+			}
+			if err := client.Mutate(ctx, &mutation, githubv4.UpdateProjectV2ItemFieldValueInput{
+				ProjectID: id,
+				ItemID:    githubv4.ID(itemID),
+				FieldID:   field.ID,
+				Value:     fieldValue,
+			}, nil); err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("failed to update project item field: %v", err)), nil
+			}
+
+			return mcp.NewToolResultText(fmt.Sprintf("%s of item %s has been set to %s", field.Name, itemID, value)), nil
+		}
+}
+
+// projectFieldValue converts value to a value of the type of field. Options and iterations
+// are matched by name or ID.
+func projectFieldValue(field ProjectField, value string) (githubv4.ProjectV2FieldValue, error) {
+	switch field.DataType {
+	case "TEXT":
+		return githubv4.ProjectV2FieldValue{Text: githubv4.NewString(githubv4.String(value))}, nil
+	case "NUMBER":
+		n, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return githubv4.ProjectV2FieldValue{}, fmt.Errorf("%s is a number field, but %q is not a number", field.Name, value)
+		}
+		return githubv4.ProjectV2FieldValue{Number: githubv4.NewFloat(githubv4.Float(n))}, nil
+	case "DATE":
+		d, err := time.Parse(time.DateOnly, value)
+		if err != nil {
+			return githubv4.ProjectV2FieldValue{}, fmt.Errorf("%s is a date field, but %q is not a date of the form YYYY-MM-DD", field.Name, value)
+		}
+		return githubv4.ProjectV2FieldValue{Date: githubv4.NewDate(githubv4.Date{Time: d})}, nil
+	case "SINGLE_SELECT":
+		var names []string
+		for _, o := range field.Options {
+			if string(o.ID) == value || strings.EqualFold(string(o.Name), value) {
+				return githubv4.ProjectV2FieldValue{SingleSelectOptionID: githubv4.NewString(o.ID)}, nil
+			}
+			names = append(names, string(o.Name))
+		}
+		return githubv4.ProjectV2FieldValue{}, fmt.Errorf("%s has no option %q, the options are: %s", field.Name, value, strings.Join(names, ", "))
+	case "ITERATION":
+		var titles []string
+		for _, i := range field.Iterations {
+			if string(i.ID) == value || strings.EqualFold(string(i.Title), value) {
+				return githubv4.ProjectV2FieldValue{IterationID: githubv4.NewString(i.ID)}, nil
+			}
+			titles = append(titles, string(i.Title))
+		}
+		return githubv4.ProjectV2FieldValue{}, fmt.Errorf("%s has no iteration %q, the iterations are: %s", field.Name, value, strings.Join(titles, ", "))
+	default:
+		return githubv4.ProjectV2FieldValue{}, fmt.Errorf("%s is a %s field, which can't be set with this tool", field.Name, field.DataType)
+	}
+}
+
+// ArchiveProjectItem creates a tool to archive an item of a project.
+func ArchiveProjectItem(getGQLClient GetGQLClientFn, t translations.TranslationHelperFunc) (mcp.Tool, server.ToolHandlerFunc) {
+	return mcp.NewTool("archive_project_item",
+			mcp.WithDescription(t("TOOL_ARCHIVE_PROJECT_ITEM_DESCRIPTION", "Archive an item of a project (v2), which hides it from the project's views. Archived items can be restored in the project.")),
+			mcp.WithToolAnnotation(mcp.ToolAnnotation{
+				Title:           t("TOOL_ARCHIVE_PROJECT_ITEM_USER_TITLE", "Archive project item"),
+				ReadOnlyHint:    toBoolPtr(false),
+				DestructiveHint: toBoolPtr(false),
+				IdempotentHint:  toBoolPtr(true),
+			}),
+			mcp.WithString("owner",
+				mcp.Required(),
+				mcp.Description("Login of the user or organization that owns the project"),
+			),
+			mcp.WithNumber("project_number",
+				mcp.Required(),
+				mcp.Description("Project number"),
+			),
+			mcp.WithString("item_id",
+				mcp.Required(),
+				mcp.Description("ID of the project item, from list_project_items"),
+			),
+		),
+		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			owner, err := requiredParam[string](request, "owner")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			number, err := RequiredInt(request, "project_number")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			itemID, err := requiredParam[string](request, "item_id")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+
+			client, err := getGQLClient(ctx)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get GitHub GQL client: %w", err)
+			}
+			id, err := projectID(ctx, client, owner, number)
+			if err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("failed to get project: %v", err)), nil
+			}
+
+			var mutation struct {
+				ArchiveProjectV2Item struct {
+					Item struct {
+						ID githubv4.ID
+					}
+				} `graphql:"archiveProjectV2Item(input: $input)"`
+			}
+			if err := client.Mutate(ctx, &mutation, githubv4.ArchiveProjectV2ItemInput{
+				ProjectID: id,
+				ItemID:    githubv4.ID(itemID),
+			}, nil); err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("failed to archive project item: %v", err)), nil
+			}
+
+			return mcp.NewToolResultText(fmt.Sprintf("item %s has been archived", itemID)), nil
+		}
+}
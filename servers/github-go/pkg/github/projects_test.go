@@ -0,0 +1,557 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/github/github-mcp-server/internal/githubv4mock"
+	"github.com/github/github-mcp-server/internal/toolsnaps"
+	"github.com/github/github-mcp-server/pkg/translations"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/shurcooL/githubv4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ProjectsTools(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		create      func(GetGQLClientFn, translations.TranslationHelperFunc) (mcp.Tool, server.ToolHandlerFunc)
+		readOnly    bool
+		destructive bool
+	}{
+		{name: "list_projects", create: ListProjects, readOnly: true},
+		{name: "get_project_fields", create: GetProjectFields, readOnly: true},
+		{name: "list_project_items", create: ListProjectItems, readOnly: true},
+		{name: "add_project_item", create: AddProjectItem},
+		{name: "update_project_item_field", create: UpdateProjectItemField, destructive: true},
+		{name: "archive_project_item", create: ArchiveProjectItem},
+	}
+
+	for _, tc := range tests {
+		tool, _ := tc.create(nil, translations.NullTranslationHelper)
+		require.NoError(t, toolsnaps.Test(tool.Name, tool))
+
+		assert.Equal(t, tc.name, tool.Name)
+		assert.Equal(t, tc.readOnly, *tool.Annotations.ReadOnlyHint, "unexpected read-only hint for %s", tc.name)
+		if !tc.readOnly {
+			assert.Equal(t, tc.destructive, *tool.Annotations.DestructiveHint, "unexpected destructive hint for %s", tc.name)
+		}
+	}
+}
+
+var mockProjectVariables = map[string]any{
+	"owner":  githubv4.String("octo-org"),
+	"number": githubv4.Int(3),
+}
+
+var mockProjectFields = githubv4mock.DataResponse(map[string]any{
+	"repositoryOwner": map[string]any{
+		"projectV2": map[string]any{
+			"id": "PVT_1",
+			"fields": map[string]any{
+				"nodes": []any{
+					map[string]any{"id": "PVTF_1", "name": "Title", "dataType": "TITLE"},
+					map[string]any{"id": "PVTF_2", "name": "Estimate", "dataType": "NUMBER"},
+					map[string]any{"id": "PVTF_3", "name": "Due", "dataType": "DATE"},
+					map[string]any{
+						"id":       "PVTSSF_1",
+						"name":     "Status",
+						"dataType": "SINGLE_SELECT",
+						"options": []any{
+							map[string]any{"id": "f75ad846", "name": "Todo"},
+							map[string]any{"id": "47fc9ee4", "name": "In Progress"},
+						},
+					},
+					map[string]any{
+						"id":       "PVTIF_1",
+						"name":     "Iteration",
+						"dataType": "ITERATION",
+						"configuration": map[string]any{
+							"iterations": []any{
+								map[string]any{"id": "it2", "title": "Sprint 2", "startDate": "2025-06-09", "duration": 14},
+							},
+							"completedIterations": []any{
+								map[string]any{"id": "it1", "title": "Sprint 1", "startDate": "2025-05-26", "duration": 14},
+							},
+						},
+					},
+				},
+			},
+		},
+	},
+})
+
+var mockProjectID = githubv4mock.DataResponse(map[string]any{
+	"repositoryOwner": map[string]any{
+		"projectV2": map[string]any{"id": "PVT_1"},
+	},
+})
+
+func Test_ListProjects(t *testing.T) {
+	mockProjects := githubv4mock.DataResponse(map[string]any{
+		"repositoryOwner": map[string]any{
+			"projectsV2": map[string]any{
+				"totalCount": 1,
+				"pageInfo":   map[string]any{"hasNextPage": false, "endCursor": "Y3Vyc29yOjE="},
+				"nodes": []any{
+					map[string]any{
+						"id":               "PVT_1",
+						"number":           3,
+						"title":            "Triage",
+						"shortDescription": "Incoming issues",
+						"url":              "https://github.com/orgs/octo-org/projects/3",
+						"closed":           false,
+						"public":           true,
+						"updatedAt":        "2025-06-02T10:00:00Z",
+						"items":            map[string]any{"totalCount": 12},
+					},
+				},
+			},
+		},
+	})
+
+	tests := []struct {
+		name         string
+		requestArgs  map[string]any
+		mockedClient *http.Client
+	}{
+		{
+			name:        "all projects",
+			requestArgs: map[string]any{"owner": "octo-org"},
+			mockedClient: githubv4mock.NewMockedHTTPClient(
+				githubv4mock.NewQueryMatcher(
+					listProjectsQuery{},
+					map[string]any{
+						"owner":   githubv4.String("octo-org"),
+						"perPage": githubv4.Int(30),
+						"after":   (*githubv4.String)(nil),
+						"query":   (*githubv4.String)(nil),
+					},
+					mockProjects,
+				),
+			),
+		},
+		{
+			name: "matching projects",
+			requestArgs: map[string]any{
+				"owner":   "octo-org",
+				"query":   "triage is:open",
+				"perPage": float64(5),
+			},
+			mockedClient: githubv4mock.NewMockedHTTPClient(
+				githubv4mock.NewQueryMatcher(
+					listProjectsQuery{},
+					map[string]any{
+						"owner":   githubv4.String("octo-org"),
+						"perPage": githubv4.Int(5),
+						"after":   (*githubv4.String)(nil),
+						"query":   githubv4.String("triage is:open"),
+					},
+					mockProjects,
+				),
+			),
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			_, handler := ListProjects(stubGetGQLClientFn(githubv4.NewClient(tc.mockedClient)), translations.NullTranslationHelper)
+
+			result, err := handler(context.Background(), createMCPRequest(tc.requestArgs))
+			require.NoError(t, err)
+
+			var projects ProjectList
+			require.NoError(t, json.Unmarshal([]byte(getTextResult(t, result).Text), &projects))
+			assert.Equal(t, 1, projects.TotalCount)
+			require.Len(t, projects.Projects, 1)
+			assert.Equal(t, githubv4.String("Triage"), projects.Projects[0].Title)
+			assert.Equal(t, githubv4.Int(12), projects.Projects[0].Items.TotalCount)
+		})
+	}
+}
+
+func Test_GetProjectFields(t *testing.T) {
+	mockedClient := githubv4mock.NewMockedHTTPClient(
+		githubv4mock.NewQueryMatcher(projectFieldsQuery{}, mockProjectVariables, mockProjectFields),
+	)
+	_, handler := GetProjectFields(stubGetGQLClientFn(githubv4.NewClient(mockedClient)), translations.NullTranslationHelper)
+
+	result, err := handler(context.Background(), createMCPRequest(map[string]any{
+		"owner":          "octo-org",
+		"project_number": float64(3),
+	}))
+	require.NoError(t, err)
+
+	var fields []ProjectField
+	require.NoError(t, json.Unmarshal([]byte(getTextResult(t, result).Text), &fields))
+	require.Len(t, fields, 5)
+	assert.Equal(t, ProjectField{ID: "PVTF_2", Name: "Estimate", DataType: "NUMBER"}, fields[1])
+	assert.Equal(t, []ProjectFieldOption{{ID: "f75ad846", Name: "Todo"}, {ID: "47fc9ee4", Name: "In Progress"}}, fields[3].Options)
+	assert.Equal(t, []ProjectIteration{
+		{ID: "it2", Title: "Sprint 2", StartDate: "2025-06-09", Duration: 14},
+		{ID: "it1", Title: "Sprint 1", StartDate: "2025-05-26", Duration: 14, Completed: true},
+	}, fields[4].Iterations)
+}
+
+func Test_ListProjectItems(t *testing.T) {
+	field := func(name string) map[string]any { return map[string]any{"name": name} }
+	mockedClient := githubv4mock.NewMockedHTTPClient(
+		githubv4mock.NewQueryMatcher(
+			listProjectItemsQuery{},
+			map[string]any{
+				"owner":   githubv4.String("octo-org"),
+				"number":  githubv4.Int(3),
+				"perPage": githubv4.Int(30),
+				"after":   githubv4.String("Y3Vyc29yOjE="),
+			},
+			githubv4mock.DataResponse(map[string]any{
+				"repositoryOwner": map[string]any{
+					"projectV2": map[string]any{
+						"items": map[string]any{
+							"totalCount": 2,
+							"pageInfo":   map[string]any{"hasNextPage": false, "endCursor": "Y3Vyc29yOjM="},
+							"nodes": []any{
+								map[string]any{
+									"id":         "PVTI_1",
+									"type":       "ISSUE",
+									"isArchived": false,
+									"content": map[string]any{
+										"number":     42,
+										"title":      "Greeting is rude",
+										"url":        "https://github.com/octo-org/hello-world/issues/42",
+										"closed":     false,
+										"repository": map[string]any{"nameWithOwner": "octo-org/hello-world"},
+									},
+									"fieldValues": map[string]any{
+										"nodes": []any{
+											map[string]any{"__typename": "ProjectV2ItemFieldTextValue", "text": "Greeting is rude", "field": field("Title")},
+											map[string]any{"__typename": "ProjectV2ItemFieldSingleSelectValue", "name": "In Progress", "field": field("Status")},
+											map[string]any{"__typename": "ProjectV2ItemFieldIterationValue", "title": "Sprint 2", "field": field("Iteration")},
+											map[string]any{"__typename": "ProjectV2ItemFieldNumberValue", "number": 3, "field": field("Estimate")},
+											map[string]any{"__typename": "ProjectV2ItemFieldDateValue", "date": "2025-06-20", "field": field("Due")},
+											map[string]any{"__typename": "ProjectV2ItemFieldRepositoryValue"},
+										},
+									},
+								},
+								map[string]any{
+									"id":          "PVTI_2",
+									"type":        "DRAFT_ISSUE",
+									"isArchived":  false,
+									"content":     map[string]any{"title": "Write the greeting guide"},
+									"fieldValues": map[string]any{"nodes": []any{}},
+								},
+							},
+						},
+					},
+				},
+			}),
+		),
+	)
+	_, handler := ListProjectItems(stubGetGQLClientFn(githubv4.NewClient(mockedClient)), translations.NullTranslationHelper)
+
+	result, err := handler(context.Background(), createMCPRequest(map[string]any{
+		"owner":          "octo-org",
+		"project_number": float64(3),
+		"after":          "Y3Vyc29yOjE=",
+	}))
+	require.NoError(t, err)
+
+	var items ProjectItemList
+	require.NoError(t, json.Unmarshal([]byte(getTextResult(t, result).Text), &items))
+	require.Len(t, items.Items, 2)
+	assert.Equal(t, &ProjectItemContent{
+		Number:     42,
+		Title:      "Greeting is rude",
+		URL:        "https://github.com/octo-org/hello-world/issues/42",
+		Repository: "octo-org/hello-world",
+	}, items.Items[0].Content)
+	assert.Equal(t, map[string]any{
+		"Title":     "Greeting is rude",
+		"Status":    "In Progress",
+		"Iteration": "Sprint 2",
+		"Estimate":  float64(3),
+		"Due":       "2025-06-20",
+	}, items.Items[0].Fields)
+	assert.Equal(t, &ProjectItemContent{Title: "Write the greeting guide"}, items.Items[1].Content)
+	assert.Empty(t, items.Items[1].Fields)
+}
+
+func Test_AddProjectItem(t *testing.T) {
+	addItem := struct {
+		AddProjectV2ItemByID struct {
+			Item struct {
+				ID githubv4.ID `json:"id"`
+			}
+		} `graphql:"addProjectV2ItemById(input: $input)"`
+	}{}
+	contentVariables := map[string]any{
+		"owner":  githubv4.String("octo-org"),
+		"repo":   githubv4.String("hello-world"),
+		"number": githubv4.Int(42),
+	}
+	requestArgs := map[string]any{
+		"owner":          "octo-org",
+		"project_number": float64(3),
+		"item_owner":     "octo-org",
+		"item_repo":      "hello-world",
+		"item_number":    float64(42),
+	}
+
+	tests := []struct {
+		name               string
+		mockedClient       *http.Client
+		expectToolError    bool
+		expectedToolErrMsg string
+	}{
+		{
+			name: "add issue",
+			mockedClient: githubv4mock.NewMockedHTTPClient(
+				githubv4mock.NewQueryMatcher(projectIDQuery{}, mockProjectVariables, mockProjectID),
+				githubv4mock.NewQueryMatcher(issueOrPullRequestIDQuery{}, contentVariables, githubv4mock.DataResponse(map[string]any{
+					"repository": map[string]any{
+						"issueOrPullRequest": map[string]any{"id": "I_42"},
+					},
+				})),
+				githubv4mock.NewMutationMatcher(addItem, githubv4.AddProjectV2ItemByIdInput{
+					ProjectID: githubv4.ID("PVT_1"),
+					ContentID: githubv4.ID("I_42"),
+				}, nil, githubv4mock.DataResponse(map[string]any{
+					"addProjectV2ItemById": map[string]any{
+						"item": map[string]any{"id": "PVTI_1"},
+					},
+				})),
+			),
+		},
+		{
+			name: "project not found",
+			mockedClient: githubv4mock.NewMockedHTTPClient(
+				githubv4mock.NewQueryMatcher(projectIDQuery{}, mockProjectVariables, githubv4mock.ErrorResponse("Could not resolve to a ProjectV2 with the number 3.")),
+			),
+			expectToolError:    true,
+			expectedToolErrMsg: "failed to get project: Could not resolve to a ProjectV2",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			_, handler := AddProjectItem(stubGetGQLClientFn(githubv4.NewClient(tc.mockedClient)), translations.NullTranslationHelper)
+
+			result, err := handler(context.Background(), createMCPRequest(requestArgs))
+			require.NoError(t, err)
+
+			textContent := getTextResult(t, result)
+			if tc.expectToolError {
+				require.True(t, result.IsError)
+				assert.Contains(t, textContent.Text, tc.expectedToolErrMsg)
+				return
+			}
+			require.False(t, result.IsError, textContent.Text)
+			assert.JSONEq(t, `{"id":"PVTI_1"}`, textContent.Text)
+		})
+	}
+}
+
+func Test_UpdateProjectItemField(t *testing.T) {
+	updateField := struct {
+		UpdateProjectV2ItemFieldValue struct {
+			ProjectV2Item struct {
+				ID githubv4.ID
+			}
+		} `graphql:"updateProjectV2ItemFieldValue(input: $input)"`
+	}{}
+	updateMatcher := func(fieldID string, value githubv4.ProjectV2FieldValue) githubv4mock.Matcher {
+		return githubv4mock.NewMutationMatcher(updateField, githubv4.UpdateProjectV2ItemFieldValueInput{
+			ProjectID: githubv4.ID("PVT_1"),
+			ItemID:    githubv4.ID("PVTI_1"),
+			FieldID:   githubv4.ID(fieldID),
+			Value:     value,
+		}, nil, githubv4mock.DataResponse(map[string]any{
+			"updateProjectV2ItemFieldValue": map[string]any{
+				"projectV2Item": map[string]any{"id": "PVTI_1"},
+			},
+		}))
+	}
+	fieldsMatcher := githubv4mock.NewQueryMatcher(projectFieldsQuery{}, mockProjectVariables, mockProjectFields)
+
+	tests := []struct {
+		name               string
+		field              string
+		value              any
+		mockedClient       *http.Client
+		expectToolError    bool
+		expectedToolErrMsg string
+		expectedText       string
+	}{
+		{
+			name:  "single select option by name",
+			field: "status",
+			value: "in progress",
+			mockedClient: githubv4mock.NewMockedHTTPClient(
+				fieldsMatcher,
+				updateMatcher("PVTSSF_1", githubv4.ProjectV2FieldValue{SingleSelectOptionID: githubv4.NewString("47fc9ee4")}),
+			),
+			expectedText: "Status of item PVTI_1 has been set to in progress",
+		},
+		{
+			name:  "completed iteration by ID",
+			field: "PVTIF_1",
+			value: "it1",
+			mockedClient: githubv4mock.NewMockedHTTPClient(
+				fieldsMatcher,
+				updateMatcher("PVTIF_1", githubv4.ProjectV2FieldValue{IterationID: githubv4.NewString("it1")}),
+			),
+			expectedText: "Iteration of item PVTI_1 has been set to it1",
+		},
+		{
+			name:  "number",
+			field: "Estimate",
+			value: "2.5",
+			mockedClient: githubv4mock.NewMockedHTTPClient(
+				fieldsMatcher,
+				updateMatcher("PVTF_2", githubv4.ProjectV2FieldValue{Number: githubv4.NewFloat(2.5)}),
+			),
+			expectedText: "Estimate of item PVTI_1 has been set to 2.5",
+		},
+		{
+			name:  "date",
+			field: "Due",
+			value: "2025-06-20",
+			mockedClient: githubv4mock.NewMockedHTTPClient(
+				fieldsMatcher,
+				updateMatcher("PVTF_3", githubv4.ProjectV2FieldValue{Date: githubv4.NewDate(githubv4.Date{Time: time.Date(2025, 6, 20, 0, 0, 0, 0, time.UTC)})}),
+			),
+			expectedText: "Due of item PVTI_1 has been set to 2025-06-20",
+		},
+		{
+			name:  "clear",
+			field: "Status",
+			mockedClient: githubv4mock.NewMockedHTTPClient(
+				fieldsMatcher,
+				githubv4mock.NewMutationMatcher(
+					struct {
+						ClearProjectV2ItemFieldValue struct {
+							ProjectV2Item struct {
+								ID githubv4.ID
+							}
+						} `graphql:"clearProjectV2ItemFieldValue(input: $input)"`
+					}{},
+					githubv4.ClearProjectV2ItemFieldValueInput{
+						ProjectID: githubv4.ID("PVT_1"),
+						ItemID:    githubv4.ID("PVTI_1"),
+						FieldID:   githubv4.ID("PVTSSF_1"),
+					},
+					nil,
+					githubv4mock.DataResponse(map[string]any{
+						"clearProjectV2ItemFieldValue": map[string]any{
+							"projectV2Item": map[string]any{"id": "PVTI_1"},
+						},
+					}),
+				),
+			),
+			expectedText: "Status of item PVTI_1 has been cleared",
+		},
+		{
+			name:               "unknown option",
+			field:              "Status",
+			value:              "Done",
+			mockedClient:       githubv4mock.NewMockedHTTPClient(fieldsMatcher),
+			expectToolError:    true,
+			expectedToolErrMsg: `Status has no option "Done", the options are: Todo, In Progress`,
+		},
+		{
+			name:               "not a date",
+			field:              "Due",
+			value:              "next friday",
+			mockedClient:       githubv4mock.NewMockedHTTPClient(fieldsMatcher),
+			expectToolError:    true,
+			expectedToolErrMsg: `Due is a date field, but "next friday" is not a date of the form YYYY-MM-DD`,
+		},
+		{
+			name:               "unsupported field type",
+			field:              "Title",
+			value:              "Greeting is very rude",
+			mockedClient:       githubv4mock.NewMockedHTTPClient(fieldsMatcher),
+			expectToolError:    true,
+			expectedToolErrMsg: "Title is a TITLE field, which can't be set with this tool",
+		},
+		{
+			name:               "unknown field",
+			field:              "Priority",
+			value:              "High",
+			mockedClient:       githubv4mock.NewMockedHTTPClient(fieldsMatcher),
+			expectToolError:    true,
+			expectedToolErrMsg: `project 3 of octo-org has no field "Priority"`,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			_, handler := UpdateProjectItemField(stubGetGQLClientFn(githubv4.NewClient(tc.mockedClient)), translations.NullTranslationHelper)
+
+			requestArgs := map[string]any{
+				"owner":          "octo-org",
+				"project_number": float64(3),
+				"item_id":        "PVTI_1",
+				"field":          tc.field,
+			}
+			if tc.value != nil {
+				requestArgs["value"] = tc.value
+			}
+			result, err := handler(context.Background(), createMCPRequest(requestArgs))
+			require.NoError(t, err)
+
+			textContent := getTextResult(t, result)
+			if tc.expectToolError {
+				require.True(t, result.IsError)
+				assert.Contains(t, textContent.Text, tc.expectedToolErrMsg)
+				return
+			}
+			require.False(t, result.IsError, textContent.Text)
+			assert.Equal(t, tc.expectedText, textContent.Text)
+		})
+	}
+}
+
+func Test_ArchiveProjectItem(t *testing.T) {
+	mockedClient := githubv4mock.NewMockedHTTPClient(
+		githubv4mock.NewQueryMatcher(projectIDQuery{}, mockProjectVariables, mockProjectID),
+		githubv4mock.NewMutationMatcher(
+			struct {
+				ArchiveProjectV2Item struct {
+					Item struct {
+						ID githubv4.ID
+					}
+				} `graphql:"archiveProjectV2Item(input: $input)"`
+			}{},
+			githubv4.ArchiveProjectV2ItemInput{
+				ProjectID: githubv4.ID("PVT_1"),
+				ItemID:    githubv4.ID("PVTI_1"),
+			},
+			nil,
+			githubv4mock.DataResponse(map[string]any{
+				"archiveProjectV2Item": map[string]any{
+					"item": map[string]any{"id": "PVTI_1"},
+				},
+			}),
+		),
+	)
+	_, handler := ArchiveProjectItem(stubGetGQLClientFn(githubv4.NewClient(mockedClient)), translations.NullTranslationHelper)
+
+	result, err := handler(context.Background(), createMCPRequest(map[string]any{
+		"owner":          "octo-org",
+		"project_number": float64(3),
+		"item_id":        "PVTI_1",
+	}))
+	require.NoError(t, err)
+
+	textContent := getTextResult(t, result)
+	require.False(t, result.IsError, textContent.Text)
+	assert.Equal(t, "item PVTI_1 has been archived", textContent.Text)
+}
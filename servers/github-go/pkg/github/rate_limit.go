@@ -0,0 +1,83 @@
+package github
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/github/github-mcp-server/pkg/translations"
+	"github.com/google/go-github/v72/github"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// RateLimitStatus is the most recently observed rate limit for one GitHub API resource,
+// such as "core", "search" or "graphql".
+type RateLimitStatus struct {
+	Resource   string    `json:"resource"`
+	Limit      int       `json:"limit"`
+	Remaining  int       `json:"remaining"`
+	Used       int       `json:"used"`
+	Reset      time.Time `json:"reset"`
+	ObservedAt time.Time `json:"observed_at"`
+}
+
+// GetRateLimitsFn returns the rate limits observed for the client that serves ctx.
+type GetRateLimitsFn func(context.Context) ([]RateLimitStatus, error)
+
+// GetRateLimit creates a tool that reports the remaining GitHub API quota. It reports what
+// has been observed on recent API responses, and only asks the API when nothing has been
+// observed yet. Querying the rate limit does not count against it.
+func GetRateLimit(getClient GetClientFn, getRateLimits GetRateLimitsFn, t translations.TranslationHelperFunc) (mcp.Tool, server.ToolHandlerFunc) {
+	tool := mcp.NewTool("get_rate_limit",
+		mcp.WithDescription(t("TOOL_GET_RATE_LIMIT_DESCRIPTION", "Get the remaining GitHub API rate limit quota for each API resource, and when it resets. Use this to pace work that needs many API calls.")),
+		mcp.WithToolAnnotation(mcp.ToolAnnotation{
+			Title:        t("TOOL_GET_RATE_LIMIT_USER_TITLE", "Get API rate limit"),
+			ReadOnlyHint: toBoolPtr(true),
+		}),
+	)
+
+	handler := func(ctx context.Context, _ mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		limits, err := getRateLimits(ctx)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("failed to get rate limits", err), nil
+		}
+		if len(limits) > 0 {
+			return MarshalledTextResult(limits), nil
+		}
+
+		client, err := getClient(ctx)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("failed to get GitHub client", err), nil
+		}
+
+		rateLimits, _, err := client.RateLimit.Get(ctx)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("failed to get rate limits", err), nil
+		}
+
+		now := time.Now()
+		for resource, rate := range map[string]*github.Rate{
+			"core":    rateLimits.GetCore(),
+			"search":  rateLimits.GetSearch(),
+			"graphql": rateLimits.GetGraphQL(),
+		} {
+			if rate == nil {
+				continue
+			}
+			limits = append(limits, RateLimitStatus{
+				Resource:   resource,
+				Limit:      rate.Limit,
+				Remaining:  rate.Remaining,
+				Used:       rate.Used,
+				Reset:      rate.Reset.Time,
+				ObservedAt: now,
+			})
+		}
+		sort.Slice(limits, func(i, j int) bool { return limits[i].Resource < limits[j].Resource })
+
+		return MarshalledTextResult(limits), nil
+	}
+
+	return tool, handler
+}
@@ -0,0 +1,127 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/github/github-mcp-server/internal/toolsnaps"
+	"github.com/github/github-mcp-server/pkg/translations"
+	"github.com/google/go-github/v72/github"
+	"github.com/migueleliasweb/go-github-mock/src/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_GetRateLimit(t *testing.T) {
+	t.Parallel()
+
+	tool, _ := GetRateLimit(nil, nil, translations.NullTranslationHelper)
+	require.NoError(t, toolsnaps.Test(tool.Name, tool))
+
+	assert.Equal(t, "get_rate_limit", tool.Name)
+	assert.True(t, *tool.Annotations.ReadOnlyHint, "get_rate_limit tool should be read-only")
+
+	reset := time.Date(2025, 1, 1, 13, 0, 0, 0, time.UTC)
+	observed := []RateLimitStatus{
+		{Resource: "core", Limit: 5000, Remaining: 4990, Used: 10, Reset: reset, ObservedAt: reset.Add(-time.Hour)},
+	}
+
+	mockRateLimits := &github.RateLimits{
+		Core:    &github.Rate{Limit: 5000, Remaining: 5000, Reset: github.Timestamp{Time: reset}},
+		Search:  &github.Rate{Limit: 30, Remaining: 29, Used: 1, Reset: github.Timestamp{Time: reset}},
+		GraphQL: &github.Rate{Limit: 5000, Remaining: 4000, Used: 1000, Reset: github.Timestamp{Time: reset}},
+	}
+
+	noneObserved := func(_ context.Context) ([]RateLimitStatus, error) { return nil, nil }
+
+	tests := []struct {
+		name               string
+		getClient          GetClientFn
+		getRateLimits      GetRateLimitsFn
+		expectToolError    bool
+		expectedToolErrMsg string
+		expectedRemaining  map[string]int
+	}{
+		{
+			name:      "reports observed rate limits without calling the API",
+			getClient: stubGetClientFnErr("should not be called"),
+			getRateLimits: func(_ context.Context) ([]RateLimitStatus, error) {
+				return observed, nil
+			},
+			expectedRemaining: map[string]int{"core": 4990},
+		},
+		{
+			name: "asks the API when nothing has been observed",
+			getClient: stubGetClientFromHTTPFn(
+				mock.NewMockedHTTPClient(
+					mock.WithRequestMatch(
+						mock.GetRateLimit,
+						struct {
+							Resources *github.RateLimits `json:"resources"`
+						}{Resources: mockRateLimits},
+					),
+				),
+			),
+			getRateLimits:     noneObserved,
+			expectedRemaining: map[string]int{"core": 5000, "search": 29, "graphql": 4000},
+		},
+		{
+			name: "observed rate limits unavailable",
+			getRateLimits: func(_ context.Context) ([]RateLimitStatus, error) {
+				return nil, errors.New("unknown account")
+			},
+			expectToolError:    true,
+			expectedToolErrMsg: "failed to get rate limits: unknown account",
+		},
+		{
+			name:               "getting client fails",
+			getClient:          stubGetClientFnErr("expected test error"),
+			getRateLimits:      noneObserved,
+			expectToolError:    true,
+			expectedToolErrMsg: "failed to get GitHub client: expected test error",
+		},
+		{
+			name: "rate limit request fails",
+			getClient: stubGetClientFromHTTPFn(
+				mock.NewMockedHTTPClient(
+					mock.WithRequestMatchHandler(
+						mock.GetRateLimit,
+						badRequestHandler("expected test failure"),
+					),
+				),
+			),
+			getRateLimits:      noneObserved,
+			expectToolError:    true,
+			expectedToolErrMsg: "expected test failure",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			_, handler := GetRateLimit(tc.getClient, tc.getRateLimits, translations.NullTranslationHelper)
+
+			result, err := handler(context.Background(), createMCPRequest(map[string]any{}))
+			require.NoError(t, err)
+			textContent := getTextResult(t, result)
+
+			if tc.expectToolError {
+				assert.True(t, result.IsError, "expected tool call result to be an error")
+				assert.Contains(t, textContent.Text, tc.expectedToolErrMsg)
+				return
+			}
+
+			var limits []RateLimitStatus
+			require.NoError(t, json.Unmarshal([]byte(textContent.Text), &limits))
+
+			remaining := make(map[string]int, len(limits))
+			for _, limit := range limits {
+				remaining[limit.Resource] = limit.Remaining
+				assert.True(t, reset.Equal(limit.Reset), "unexpected reset for %s", limit.Resource)
+			}
+			assert.Equal(t, tc.expectedRemaining, remaining)
+		})
+	}
+}
@@ -0,0 +1,452 @@
+package github
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/github/github-mcp-server/internal/toolsnaps"
+	"github.com/github/github-mcp-server/pkg/translations"
+	"github.com/google/go-github/v72/github"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/migueleliasweb/go-github-mock/src/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ReleasesTools(t *testing.T) {
+	t.Parallel()
+
+	uploadReleaseAsset := func(getClient GetClientFn, t translations.TranslationHelperFunc) (mcp.Tool, server.ToolHandlerFunc) {
+		return UploadReleaseAsset(getClient, nil, t)
+	}
+	tests := []struct {
+		name        string
+		create      func(GetClientFn, translations.TranslationHelperFunc) (mcp.Tool, server.ToolHandlerFunc)
+		readOnly    bool
+		destructive bool
+	}{
+		{name: "list_releases", create: ListReleases, readOnly: true},
+		{name: "get_latest_release", create: GetLatestRelease, readOnly: true},
+		{name: "get_release_by_tag", create: GetReleaseByTag, readOnly: true},
+		{name: "generate_release_notes", create: GenerateReleaseNotes, readOnly: true},
+		{name: "create_release", create: CreateRelease},
+		{name: "update_release", create: UpdateRelease, destructive: true},
+		{name: "delete_release", create: DeleteRelease, destructive: true},
+		{name: "upload_release_asset", create: uploadReleaseAsset},
+		{name: "delete_release_asset", create: DeleteReleaseAsset, destructive: true},
+	}
+
+	for _, tc := range tests {
+		tool, _ := tc.create(nil, translations.NullTranslationHelper)
+		require.NoError(t, toolsnaps.Test(tool.Name, tool))
+
+		assert.Equal(t, tc.name, tool.Name)
+		assert.Equal(t, tc.readOnly, *tool.Annotations.ReadOnlyHint, "unexpected read-only hint for %s", tc.name)
+		if !tc.readOnly {
+			assert.Equal(t, tc.destructive, *tool.Annotations.DestructiveHint, "unexpected destructive hint for %s", tc.name)
+		}
+	}
+}
+
+func Test_GetReleaseByTag(t *testing.T) {
+	mockRelease := &github.RepositoryRelease{
+		ID:      github.Ptr(int64(1)),
+		TagName: github.Ptr("v1.0.0"),
+		Name:    github.Ptr("v1.0.0"),
+	}
+
+	tests := []struct {
+		name           string
+		mockedClient   *http.Client
+		requestArgs    map[string]interface{}
+		expectError    bool
+		expectedErrMsg string
+	}{
+		{
+			name: "release found",
+			mockedClient: mock.NewMockedHTTPClient(
+				mock.WithRequestMatchHandler(
+					mock.GetReposReleasesTagsByOwnerByRepoByTag,
+					expectPath(t, "/repos/octocat/hello-world/releases/tags/v1.0.0").andThen(
+						mockResponse(t, http.StatusOK, mockRelease),
+					),
+				),
+			),
+			requestArgs: map[string]interface{}{
+				"owner": "octocat",
+				"repo":  "hello-world",
+				"tag":   "v1.0.0",
+			},
+		},
+		{
+			name: "release not found",
+			mockedClient: mock.NewMockedHTTPClient(
+				mock.WithRequestMatchHandler(
+					mock.GetReposReleasesTagsByOwnerByRepoByTag,
+					mockResponse(t, http.StatusNotFound, `{"message": "Not Found"}`),
+				),
+			),
+			requestArgs: map[string]interface{}{
+				"owner": "octocat",
+				"repo":  "hello-world",
+				"tag":   "v9.9.9",
+			},
+			expectError:    true,
+			expectedErrMsg: "failed to get release by tag",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			client := github.NewClient(tc.mockedClient)
+			_, handler := GetReleaseByTag(stubGetClientFn(client), translations.NullTranslationHelper)
+
+			result, err := handler(context.Background(), createMCPRequest(tc.requestArgs))
+
+			if tc.expectError {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tc.expectedErrMsg)
+				return
+			}
+
+			require.NoError(t, err)
+			textContent := getTextResult(t, result)
+			var returned github.RepositoryRelease
+			require.NoError(t, json.Unmarshal([]byte(textContent.Text), &returned))
+			assert.Equal(t, *mockRelease.TagName, *returned.TagName)
+		})
+	}
+}
+
+func Test_GenerateReleaseNotes(t *testing.T) {
+	mockedClient := mock.NewMockedHTTPClient(
+		mock.WithRequestMatchHandler(
+			mock.PostReposReleasesGenerateNotesByOwnerByRepo,
+			expectRequestBody(t, map[string]any{
+				"tag_name":          "v1.1.0",
+				"previous_tag_name": "v1.0.0",
+			}).andThen(
+				mockResponse(t, http.StatusOK, &github.RepositoryReleaseNotes{
+					Name: "v1.1.0",
+					Body: "## What's Changed\n* Greet the fake by @hubot",
+				}),
+			),
+		),
+	)
+	_, handler := GenerateReleaseNotes(stubGetClientFn(github.NewClient(mockedClient)), translations.NullTranslationHelper)
+
+	result, err := handler(context.Background(), createMCPRequest(map[string]interface{}{
+		"owner":             "octocat",
+		"repo":              "hello-world",
+		"tag_name":          "v1.1.0",
+		"previous_tag_name": "v1.0.0",
+	}))
+	require.NoError(t, err)
+
+	var notes github.RepositoryReleaseNotes
+	require.NoError(t, json.Unmarshal([]byte(getTextResult(t, result).Text), &notes))
+	assert.Equal(t, "v1.1.0", notes.Name)
+}
+
+func Test_CreateRelease(t *testing.T) {
+	tests := []struct {
+		name           string
+		mockedClient   *http.Client
+		requestArgs    map[string]interface{}
+		expectError    bool
+		expectedErrMsg string
+	}{
+		{
+			name: "draft with generated notes",
+			mockedClient: mock.NewMockedHTTPClient(
+				mock.WithRequestMatchHandler(
+					mock.PostReposReleasesByOwnerByRepo,
+					expectRequestBody(t, map[string]any{
+						"tag_name":               "v1.0.0",
+						"target_commitish":       "main",
+						"draft":                  true,
+						"generate_release_notes": true,
+						"make_latest":            "true",
+					}).andThen(
+						mockResponse(t, http.StatusCreated, &github.RepositoryRelease{ID: github.Ptr(int64(1)), TagName: github.Ptr("v1.0.0")}),
+					),
+				),
+			),
+			requestArgs: map[string]interface{}{
+				"owner":                  "octocat",
+				"repo":                   "hello-world",
+				"tag_name":               "v1.0.0",
+				"target_commitish":       "main",
+				"draft":                  true,
+				"generate_release_notes": true,
+				"make_latest":            "true",
+			},
+		},
+		{
+			name: "tag already released",
+			mockedClient: mock.NewMockedHTTPClient(
+				mock.WithRequestMatchHandler(
+					mock.PostReposReleasesByOwnerByRepo,
+					mockResponse(t, http.StatusUnprocessableEntity, `{"message": "Validation Failed"}`),
+				),
+			),
+			requestArgs: map[string]interface{}{
+				"owner":    "octocat",
+				"repo":     "hello-world",
+				"tag_name": "v1.0.0",
+			},
+			expectError:    true,
+			expectedErrMsg: "failed to create release",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			client := github.NewClient(tc.mockedClient)
+			_, handler := CreateRelease(stubGetClientFn(client), translations.NullTranslationHelper)
+
+			result, err := handler(context.Background(), createMCPRequest(tc.requestArgs))
+
+			if tc.expectError {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tc.expectedErrMsg)
+				return
+			}
+
+			require.NoError(t, err)
+			var returned github.RepositoryRelease
+			require.NoError(t, json.Unmarshal([]byte(getTextResult(t, result).Text), &returned))
+			assert.Equal(t, int64(1), returned.GetID())
+		})
+	}
+}
+
+func Test_UpdateRelease(t *testing.T) {
+	// Only the fields that are given are sent, so that false and empty values can be set
+	mockedClient := mock.NewMockedHTTPClient(
+		mock.WithRequestMatchHandler(
+			mock.PatchReposReleasesByOwnerByRepoByReleaseId,
+			expect(t, expectations{
+				path: "/repos/octocat/hello-world/releases/1",
+				requestBody: map[string]any{
+					"body":  "",
+					"draft": false,
+				},
+			}).andThen(
+				mockResponse(t, http.StatusOK, &github.RepositoryRelease{ID: github.Ptr(int64(1)), Draft: github.Ptr(false)}),
+			),
+		),
+	)
+	_, handler := UpdateRelease(stubGetClientFn(github.NewClient(mockedClient)), translations.NullTranslationHelper)
+
+	result, err := handler(context.Background(), createMCPRequest(map[string]interface{}{
+		"owner":      "octocat",
+		"repo":       "hello-world",
+		"release_id": float64(1),
+		"body":       "",
+		"draft":      false,
+	}))
+	require.NoError(t, err)
+	require.False(t, result.IsError, getTextResult(t, result).Text)
+}
+
+func Test_UploadReleaseAsset(t *testing.T) {
+	archive := []byte("PK\x03\x04 not really a zip")
+	readResource := func(_ context.Context, uri string) ([]mcp.ResourceContents, error) {
+		switch uri {
+		case "repo://octocat/hello-world/refs/tags/v1.0.0/contents/dist/app.zip":
+			return []mcp.ResourceContents{mcp.BlobResourceContents{URI: uri, MIMEType: "application/zip", Blob: base64.StdEncoding.EncodeToString(archive)}}, nil
+		case "repo://octocat/hello-world/contents/dist":
+			return []mcp.ResourceContents{
+				mcp.TextResourceContents{URI: "https://github.com/octocat/hello-world/blob/main/dist/app.zip", MIMEType: "application/zip", Text: "app.zip"},
+				mcp.TextResourceContents{URI: "https://github.com/octocat/hello-world/blob/main/dist/app.tar.gz", MIMEType: "application/gzip", Text: "app.tar.gz"},
+			}, nil
+		default:
+			return nil, errors.New("no resource matches " + uri)
+		}
+	}
+	expectUpload := func(t *testing.T, query map[string]string, contentType string, content []byte) http.HandlerFunc {
+		return expect(t, expectations{
+			path:        "/repos/octocat/hello-world/releases/1/assets",
+			queryParams: query,
+		}).andThen(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, contentType, r.Header.Get("Content-Type"))
+			body, err := io.ReadAll(r.Body)
+			require.NoError(t, err)
+			assert.Equal(t, content, body)
+			mockResponse(t, http.StatusCreated, &github.ReleaseAsset{ID: github.Ptr(int64(7)), Name: github.Ptr(query["name"])})(w, r)
+		})
+	}
+
+	tests := []struct {
+		name           string
+		mockedClient   *http.Client
+		requestArgs    map[string]interface{}
+		expectToolErr  string
+		expectedAsset  string
+		expectedErrMsg string
+	}{
+		{
+			name: "base64 content",
+			mockedClient: mock.NewMockedHTTPClient(
+				mock.WithRequestMatchHandler(
+					mock.PostReposReleasesAssetsByOwnerByRepoByReleaseId,
+					expectUpload(t, map[string]string{"name": "notes.txt", "label": "Notes"}, "text/plain; charset=utf-8", []byte("hello")),
+				),
+			),
+			requestArgs: map[string]interface{}{
+				"owner":      "octocat",
+				"repo":       "hello-world",
+				"release_id": float64(1),
+				"name":       "notes.txt",
+				"label":      "Notes",
+				"content":    base64.StdEncoding.EncodeToString([]byte("hello")),
+			},
+			expectedAsset: "notes.txt",
+		},
+		{
+			name: "resource reference",
+			mockedClient: mock.NewMockedHTTPClient(
+				mock.WithRequestMatchHandler(
+					mock.PostReposReleasesAssetsByOwnerByRepoByReleaseId,
+					expectUpload(t, map[string]string{"name": "app.zip"}, "application/zip", archive),
+				),
+			),
+			requestArgs: map[string]interface{}{
+				"owner":        "octocat",
+				"repo":         "hello-world",
+				"release_id":   float64(1),
+				"resource_uri": "repo://octocat/hello-world/refs/tags/v1.0.0/contents/dist/app.zip",
+			},
+			expectedAsset: "app.zip",
+		},
+		{
+			name:         "both content and resource",
+			mockedClient: mock.NewMockedHTTPClient(),
+			requestArgs: map[string]interface{}{
+				"owner":        "octocat",
+				"repo":         "hello-world",
+				"release_id":   float64(1),
+				"name":         "app.zip",
+				"content":      "aGVsbG8=",
+				"resource_uri": "repo://octocat/hello-world/refs/tags/v1.0.0/contents/dist/app.zip",
+			},
+			expectToolErr: "exactly one of content and resource_uri must be given",
+		},
+		{
+			name:         "neither content nor resource",
+			mockedClient: mock.NewMockedHTTPClient(),
+			requestArgs: map[string]interface{}{
+				"owner":      "octocat",
+				"repo":       "hello-world",
+				"release_id": float64(1),
+				"name":       "app.zip",
+			},
+			expectToolErr: "exactly one of content and resource_uri must be given",
+		},
+		{
+			name:         "content without a name",
+			mockedClient: mock.NewMockedHTTPClient(),
+			requestArgs: map[string]interface{}{
+				"owner":      "octocat",
+				"repo":       "hello-world",
+				"release_id": float64(1),
+				"content":    "aGVsbG8=",
+			},
+			expectToolErr: "name is required with content",
+		},
+		{
+			name:         "directory resource",
+			mockedClient: mock.NewMockedHTTPClient(),
+			requestArgs: map[string]interface{}{
+				"owner":        "octocat",
+				"repo":         "hello-world",
+				"release_id":   float64(1),
+				"resource_uri": "repo://octocat/hello-world/contents/dist",
+			},
+			expectToolErr: "is not a single file",
+		},
+		{
+			name:         "unknown resource",
+			mockedClient: mock.NewMockedHTTPClient(),
+			requestArgs: map[string]interface{}{
+				"owner":        "octocat",
+				"repo":         "hello-world",
+				"release_id":   float64(1),
+				"resource_uri": "file:///etc/passwd",
+			},
+			expectToolErr: "no resource matches file:///etc/passwd",
+		},
+		{
+			name: "release not found",
+			mockedClient: mock.NewMockedHTTPClient(
+				mock.WithRequestMatchHandler(
+					mock.PostReposReleasesAssetsByOwnerByRepoByReleaseId,
+					mockResponse(t, http.StatusNotFound, `{"message": "Not Found"}`),
+				),
+			),
+			requestArgs: map[string]interface{}{
+				"owner":      "octocat",
+				"repo":       "hello-world",
+				"release_id": float64(2),
+				"name":       "notes.txt",
+				"content":    "aGVsbG8=",
+			},
+			expectedErrMsg: "failed to upload release asset",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			client := github.NewClient(tc.mockedClient)
+			_, handler := UploadReleaseAsset(stubGetClientFn(client), readResource, translations.NullTranslationHelper)
+
+			result, err := handler(context.Background(), createMCPRequest(tc.requestArgs))
+
+			if tc.expectedErrMsg != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tc.expectedErrMsg)
+				return
+			}
+			require.NoError(t, err)
+
+			textContent := getTextResult(t, result)
+			if tc.expectToolErr != "" {
+				require.True(t, result.IsError)
+				assert.Contains(t, textContent.Text, tc.expectToolErr)
+				return
+			}
+
+			require.False(t, result.IsError, textContent.Text)
+			var asset github.ReleaseAsset
+			require.NoError(t, json.Unmarshal([]byte(textContent.Text), &asset))
+			assert.Equal(t, tc.expectedAsset, asset.GetName())
+		})
+	}
+}
+
+func Test_DeleteReleaseAsset(t *testing.T) {
+	mockedClient := mock.NewMockedHTTPClient(
+		mock.WithRequestMatchHandler(
+			mock.DeleteReposReleasesAssetsByOwnerByRepoByAssetId,
+			expectPath(t, "/repos/octocat/hello-world/releases/assets/7").andThen(
+				mockResponse(t, http.StatusNoContent, ""),
+			),
+		),
+	)
+	_, handler := DeleteReleaseAsset(stubGetClientFn(github.NewClient(mockedClient)), translations.NullTranslationHelper)
+
+	result, err := handler(context.Background(), createMCPRequest(map[string]interface{}{
+		"owner":    "octocat",
+		"repo":     "hello-world",
+		"asset_id": float64(7),
+	}))
+	require.NoError(t, err)
+	assert.Equal(t, "release asset 7 has been deleted", getTextResult(t, result).Text)
+}
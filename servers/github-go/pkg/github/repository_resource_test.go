@@ -2,9 +2,11 @@ package github
 
 import (
 	"context"
+	"fmt"
 	"net/http"
 	"testing"
 
+	"github.com/github/github-mcp-server/internal/toolsnaps"
 	"github.com/github/github-mcp-server/pkg/translations"
 	"github.com/google/go-github/v72/github"
 	"github.com/mark3labs/mcp-go/mcp"
@@ -218,7 +220,7 @@ func Test_repositoryResourceContentsHandler(t *testing.T) {
 	for _, tc := range tests {
 		t.Run(tc.name, func(t *testing.T) {
 			client := github.NewClient(tc.mockedClient)
-			handler := RepositoryResourceContentsHandler((stubGetClientFn(client)))
+			handler := RepositoryResourceContentsHandler(stubGetClientFn(client), nil)
 
 			request := mcp.ReadResourceRequest{
 				Params: struct {
@@ -242,26 +244,95 @@ func Test_repositoryResourceContentsHandler(t *testing.T) {
 	}
 }
 
+func Test_RepositoryResourceContentsHandlerChecksRepository(t *testing.T) {
+	handler := RepositoryResourceContentsHandler(stubGetClientFn(github.NewClient(mock.NewMockedHTTPClient())), func(owner, repo string) error {
+		return fmt.Errorf("repository %s/%s is out of scope for reading", owner, repo)
+	})
+
+	request := mcp.ReadResourceRequest{}
+	request.Params.Arguments = map[string]any{
+		"owner": []string{"octocat"},
+		"repo":  []string{"secret"},
+		"path":  []string{"README.md"},
+	}
+
+	_, err := handler(context.Background(), request)
+	require.EqualError(t, err, "repository octocat/secret is out of scope for reading")
+}
+
 func Test_GetRepositoryResourceContent(t *testing.T) {
-	tmpl, _ := GetRepositoryResourceContent(nil, translations.NullTranslationHelper)
+	tmpl, _ := GetRepositoryResourceContent(nil, nil, translations.NullTranslationHelper)
+	require.NoError(t, toolsnaps.Test("resource_repository_content", tmpl))
 	require.Equal(t, "repo://{owner}/{repo}/contents{/path*}", tmpl.URITemplate.Raw())
 }
 
 func Test_GetRepositoryResourceBranchContent(t *testing.T) {
-	tmpl, _ := GetRepositoryResourceBranchContent(nil, translations.NullTranslationHelper)
+	tmpl, _ := GetRepositoryResourceBranchContent(nil, nil, translations.NullTranslationHelper)
+	require.NoError(t, toolsnaps.Test("resource_repository_content_branch", tmpl))
 	require.Equal(t, "repo://{owner}/{repo}/refs/heads/{branch}/contents{/path*}", tmpl.URITemplate.Raw())
 }
 func Test_GetRepositoryResourceCommitContent(t *testing.T) {
-	tmpl, _ := GetRepositoryResourceCommitContent(nil, translations.NullTranslationHelper)
+	tmpl, _ := GetRepositoryResourceCommitContent(nil, nil, translations.NullTranslationHelper)
+	require.NoError(t, toolsnaps.Test("resource_repository_content_commit", tmpl))
 	require.Equal(t, "repo://{owner}/{repo}/sha/{sha}/contents{/path*}", tmpl.URITemplate.Raw())
 }
 
 func Test_GetRepositoryResourceTagContent(t *testing.T) {
-	tmpl, _ := GetRepositoryResourceTagContent(nil, translations.NullTranslationHelper)
+	tmpl, _ := GetRepositoryResourceTagContent(nil, nil, translations.NullTranslationHelper)
+	require.NoError(t, toolsnaps.Test("resource_repository_content_tag", tmpl))
 	require.Equal(t, "repo://{owner}/{repo}/refs/tags/{tag}/contents{/path*}", tmpl.URITemplate.Raw())
 }
 
 func Test_GetRepositoryResourcePrContent(t *testing.T) {
-	tmpl, _ := GetRepositoryResourcePrContent(nil, translations.NullTranslationHelper)
+	tmpl, _ := GetRepositoryResourcePrContent(nil, nil, translations.NullTranslationHelper)
+	require.NoError(t, toolsnaps.Test("resource_repository_content_pr", tmpl))
 	require.Equal(t, "repo://{owner}/{repo}/refs/pull/{prNumber}/head/contents{/path*}", tmpl.URITemplate.Raw())
 }
+
+func Test_ResourceReader(t *testing.T) {
+	mockedClient := mock.NewMockedHTTPClient(
+		mock.WithRequestMatchHandler(
+			mock.GetReposContentsByOwnerByRepoByPath,
+			expect(t, expectations{
+				path:        "/repos/octocat/hello-world/contents/docs/README.md",
+				queryParams: map[string]string{"ref": "refs/heads/main"},
+			}).andThen(
+				mockResponse(t, http.StatusOK, &github.RepositoryContent{
+					Type:        github.Ptr("file"),
+					Name:        github.Ptr("README.md"),
+					Content:     github.Ptr("IyBIZWxsbwo="),
+					DownloadURL: github.Ptr("https://raw.githubusercontent.com/octocat/hello-world/main/docs/README.md"),
+				}),
+			),
+		),
+		mock.WithRequestMatchHandler(
+			GetRawReposContentsByOwnerByRepoByPath,
+			http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				w.Header().Set("Content-Type", "text/plain")
+				_, _ = w.Write([]byte("# Hello\n"))
+			}),
+		),
+	)
+	readResource := ResourceReader(stubGetClientFn(github.NewClient(mockedClient)), func(owner, repo string) error {
+		if repo == "secret" {
+			return fmt.Errorf("repository %s/%s is out of scope for reading", owner, repo)
+		}
+		return nil
+	}, translations.NullTranslationHelper)
+
+	contents, err := readResource(context.Background(), "repo://octocat/hello-world/refs/heads/main/contents/docs/README.md")
+	require.NoError(t, err)
+	require.Equal(t, []mcp.ResourceContents{
+		mcp.TextResourceContents{
+			URI:      "repo://octocat/hello-world/refs/heads/main/contents/docs/README.md",
+			MIMEType: "text/markdown",
+			Text:     "# Hello\n",
+		},
+	}, contents)
+
+	_, err = readResource(context.Background(), "repo://octocat/secret/contents/README.md")
+	require.EqualError(t, err, "repository octocat/secret is out of scope for reading")
+
+	_, err = readResource(context.Background(), "https://github.com/octocat/hello-world")
+	require.EqualError(t, err, "no resource matches https://github.com/octocat/hello-world")
+}
@@ -1,14 +1,61 @@
 package github
 
 import (
+	"context"
+	"fmt"
+
 	"github.com/github/github-mcp-server/pkg/translations"
+	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
 )
 
-func RegisterResources(s *server.MCPServer, getClient GetClientFn, t translations.TranslationHelperFunc) {
-	s.AddResourceTemplate(GetRepositoryResourceContent(getClient, t))
-	s.AddResourceTemplate(GetRepositoryResourceBranchContent(getClient, t))
-	s.AddResourceTemplate(GetRepositoryResourceCommitContent(getClient, t))
-	s.AddResourceTemplate(GetRepositoryResourceTagContent(getClient, t))
-	s.AddResourceTemplate(GetRepositoryResourcePrContent(getClient, t))
+// ReadResourceFn reads a resource served by this server by its URI, so that tools can take
+// resource references as arguments.
+type ReadResourceFn func(ctx context.Context, uri string) ([]mcp.ResourceContents, error)
+
+type resourceTemplate struct {
+	template mcp.ResourceTemplate
+	handler  server.ResourceTemplateHandlerFunc
+}
+
+func resourceTemplates(getClient GetClientFn, checkRepository CheckRepositoryFn, t translations.TranslationHelperFunc) []resourceTemplate {
+	var templates []resourceTemplate
+	for _, create := range []func(GetClientFn, CheckRepositoryFn, translations.TranslationHelperFunc) (mcp.ResourceTemplate, server.ResourceTemplateHandlerFunc){
+		GetRepositoryResourceContent,
+		GetRepositoryResourceBranchContent,
+		GetRepositoryResourceCommitContent,
+		GetRepositoryResourceTagContent,
+		GetRepositoryResourcePrContent,
+	} {
+		template, handler := create(getClient, checkRepository, t)
+		templates = append(templates, resourceTemplate{template: template, handler: handler})
+	}
+	return templates
+}
+
+func RegisterResources(s *server.MCPServer, getClient GetClientFn, checkRepository CheckRepositoryFn, t translations.TranslationHelperFunc) {
+	for _, rt := range resourceTemplates(getClient, checkRepository, t) {
+		s.AddResourceTemplate(rt.template, rt.handler)
+	}
+}
+
+// ResourceReader returns a ReadResourceFn for the resources that RegisterResources registers,
+// which matches URIs against their templates the way the server does.
+func ResourceReader(getClient GetClientFn, checkRepository CheckRepositoryFn, t translations.TranslationHelperFunc) ReadResourceFn {
+	templates := resourceTemplates(getClient, checkRepository, t)
+	return func(ctx context.Context, uri string) ([]mcp.ResourceContents, error) {
+		for _, rt := range templates {
+			if !rt.template.URITemplate.Regexp().MatchString(uri) {
+				continue
+			}
+			request := mcp.ReadResourceRequest{}
+			request.Params.URI = uri
+			request.Params.Arguments = make(map[string]any)
+			for name, value := range rt.template.URITemplate.Match(uri) {
+				request.Params.Arguments[name] = value.V
+			}
+			return rt.handler(ctx, request)
+		}
+		return nil, fmt.Errorf("no resource matches %s", uri)
+	}
 }
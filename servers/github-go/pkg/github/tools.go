@@ -15,7 +15,7 @@ type GetGQLClientFn func(context.Context) (*githubv4.Client, error)
 
 var DefaultTools = []string{"all"}
 
-func InitToolsets(passedToolsets []string, readOnly bool, getClient GetClientFn, getGQLClient GetGQLClientFn, t translations.TranslationHelperFunc) (*toolsets.ToolsetGroup, error) {
+func InitToolsets(passedToolsets []string, readOnly bool, getClient GetClientFn, getGQLClient GetGQLClientFn, readResource ReadResourceFn, t translations.TranslationHelperFunc) (*toolsets.ToolsetGroup, error) {
 	// Create a new toolset group
 	tsg := toolsets.NewToolsetGroup(readOnly)
 
@@ -104,6 +104,62 @@ func InitToolsets(passedToolsets []string, readOnly bool, getClient GetClientFn,
 			toolsets.NewServerTool(ManageRepositoryNotificationSubscription(getClient, t)),
 		)
 
+	actions := toolsets.NewToolset("actions", "GitHub Actions workflows and CI/CD operations").
+		AddReadTools(
+			toolsets.NewServerTool(ListWorkflows(getClient, t)),
+			toolsets.NewServerTool(ListWorkflowRuns(getClient, t)),
+			toolsets.NewServerTool(GetWorkflowRun(getClient, t)),
+			toolsets.NewServerTool(ListWorkflowJobs(getClient, t)),
+			toolsets.NewServerTool(GetJobLogs(getClient, t)),
+			toolsets.NewServerTool(GetWorkflowRunLogs(getClient, t)),
+			toolsets.NewServerTool(ListWorkflowRunArtifacts(getClient, t)),
+		).
+		AddWriteTools(
+			toolsets.NewServerTool(RerunFailedJobs(getClient, t)),
+			toolsets.NewServerTool(CancelWorkflowRun(getClient, t)),
+			toolsets.NewServerTool(RunWorkflow(getClient, t)),
+		)
+
+	releases := toolsets.NewToolset("releases", "GitHub Releases related tools").
+		AddReadTools(
+			toolsets.NewServerTool(ListReleases(getClient, t)),
+			toolsets.NewServerTool(GetLatestRelease(getClient, t)),
+			toolsets.NewServerTool(GetReleaseByTag(getClient, t)),
+			toolsets.NewServerTool(GenerateReleaseNotes(getClient, t)),
+		).
+		AddWriteTools(
+			toolsets.NewServerTool(CreateRelease(getClient, t)),
+			toolsets.NewServerTool(UpdateRelease(getClient, t)),
+			toolsets.NewServerTool(DeleteRelease(getClient, t)),
+			toolsets.NewServerTool(UploadReleaseAsset(getClient, readResource, t)),
+			toolsets.NewServerTool(DeleteReleaseAsset(getClient, t)),
+		)
+
+	discussions := toolsets.NewToolset("discussions", "GitHub Discussions related tools").
+		AddReadTools(
+			toolsets.NewServerTool(ListDiscussionCategories(getGQLClient, t)),
+			toolsets.NewServerTool(ListDiscussions(getGQLClient, t)),
+			toolsets.NewServerTool(SearchDiscussions(getGQLClient, t)),
+			toolsets.NewServerTool(GetDiscussion(getGQLClient, t)),
+		).
+		AddWriteTools(
+			toolsets.NewServerTool(CreateDiscussion(getGQLClient, t)),
+			toolsets.NewServerTool(AddDiscussionComment(getGQLClient, t)),
+			toolsets.NewServerTool(MarkDiscussionCommentAsAnswer(getGQLClient, t)),
+		)
+
+	projects := toolsets.NewToolset("projects", "GitHub Projects related tools").
+		AddReadTools(
+			toolsets.NewServerTool(ListProjects(getGQLClient, t)),
+			toolsets.NewServerTool(GetProjectFields(getGQLClient, t)),
+			toolsets.NewServerTool(ListProjectItems(getGQLClient, t)),
+		).
+		AddWriteTools(
+			toolsets.NewServerTool(AddProjectItem(getGQLClient, t)),
+			toolsets.NewServerTool(UpdateProjectItemField(getGQLClient, t)),
+			toolsets.NewServerTool(ArchiveProjectItem(getGQLClient, t)),
+		)
+
 	// Keep experiments alive so the system doesn't error out when it's always enabled
 	experiments := toolsets.NewToolset("experiments", "Experimental features that are not considered stable yet")
 
@@ -115,6 +171,10 @@ func InitToolsets(passedToolsets []string, readOnly bool, getClient GetClientFn,
 	tsg.AddToolset(codeSecurity)
 	tsg.AddToolset(secretProtection)
 	tsg.AddToolset(notifications)
+	tsg.AddToolset(actions)
+	tsg.AddToolset(releases)
+	tsg.AddToolset(discussions)
+	tsg.AddToolset(projects)
 	tsg.AddToolset(experiments)
 	// Enable the requested features
 
@@ -125,25 +185,28 @@ func InitToolsets(passedToolsets []string, readOnly bool, getClient GetClientFn,
 	return tsg, nil
 }
 
-func InitContextToolset(getClient GetClientFn, t translations.TranslationHelperFunc) *toolsets.Toolset {
+func InitContextToolset(getClient GetClientFn, getRateLimits GetRateLimitsFn, accounts []string, t translations.TranslationHelperFunc) *toolsets.Toolset {
 	// Create a new context toolset
 	contextTools := toolsets.NewToolset("context", "Tools that provide context about the current user and GitHub context you are operating in").
 		AddReadTools(
-			toolsets.NewServerTool(GetMe(getClient, t)),
+			toolsets.NewServerTool(GetMe(getClient, accounts, t)),
+			toolsets.NewServerTool(GetRateLimit(getClient, getRateLimits, t)),
 		)
 	contextTools.Enabled = true
 	return contextTools
 }
 
-// InitDynamicToolset creates a dynamic toolset that can be used to enable other toolsets, and so requires the server and toolset group as arguments
-func InitDynamicToolset(s *server.MCPServer, tsg *toolsets.ToolsetGroup, t translations.TranslationHelperFunc) *toolsets.Toolset {
+// InitDynamicToolset creates a dynamic toolset that can be used to enable and disable other toolsets
+// for the calling session, and so requires the server and the per-session toolset state as arguments
+func InitDynamicToolset(s *server.MCPServer, sessionToolsets *toolsets.SessionToolsets, t translations.TranslationHelperFunc) *toolsets.Toolset {
 	// Create a new dynamic toolset
 	// Need to add the dynamic toolset last so it can be used to enable other toolsets
 	dynamicToolSelection := toolsets.NewToolset("dynamic", "Discover GitHub MCP tools that can help achieve tasks by enabling additional sets of tools, you can control the enablement of any toolset to access its tools when this toolset is enabled.").
 		AddReadTools(
-			toolsets.NewServerTool(ListAvailableToolsets(tsg, t)),
-			toolsets.NewServerTool(GetToolsetsTools(tsg, t)),
-			toolsets.NewServerTool(EnableToolset(s, tsg, t)),
+			toolsets.NewServerTool(ListAvailableToolsets(sessionToolsets, t)),
+			toolsets.NewServerTool(GetToolsetsTools(sessionToolsets.Group(), t)),
+			toolsets.NewServerTool(EnableToolset(s, sessionToolsets, t)),
+			toolsets.NewServerTool(DisableToolset(s, sessionToolsets, t)),
 		)
 
 	dynamicToolSelection.Enabled = true
@@ -1,45 +1,126 @@
 package log
 
 import (
+	"bytes"
+	"encoding/json"
 	"io"
+	"sync"
+	"time"
 
 	log "github.com/sirupsen/logrus"
 )
 
 // IOLogger is a wrapper around io.Reader and io.Writer that can be used
-// to log the data being read and written from the underlying streams
+// to log the JSON-RPC messages being read and written from the underlying streams.
+// Messages are expected to be newline delimited, as they are on the stdio transport,
+// and are logged one entry per message with their method, id, size and, for responses,
+// the time since the request was received. Secrets are redacted before logging.
 type IOLogger struct {
-	reader io.Reader
-	writer io.Writer
-	logger *log.Logger
+	reader   io.Reader
+	writer   io.Writer
+	logger   *log.Logger
+	redactor *Redactor
+
+	now func() time.Time
+
+	mu       sync.Mutex
+	readBuf  []byte
+	writeBuf []byte
+	// pending holds when each request still awaiting a response was received, by id
+	pending map[string]time.Time
 }
 
-// NewIOLogger creates a new IOLogger instance
-func NewIOLogger(r io.Reader, w io.Writer, logger *log.Logger) *IOLogger {
+// NewIOLogger creates a new IOLogger instance. The redactor may be nil, in which case
+// messages are logged as they are.
+func NewIOLogger(r io.Reader, w io.Writer, logger *log.Logger, redactor *Redactor) *IOLogger {
 	return &IOLogger{
-		reader: r,
-		writer: w,
-		logger: logger,
+		reader:   r,
+		writer:   w,
+		logger:   logger,
+		redactor: redactor,
+		now:      time.Now,
+		pending:  make(map[string]time.Time),
 	}
 }
 
-// Read reads data from the underlying io.Reader and logs it.
+// Read reads data from the underlying io.Reader and logs the messages it completes.
 func (l *IOLogger) Read(p []byte) (n int, err error) {
 	if l.reader == nil {
 		return 0, io.EOF
 	}
 	n, err = l.reader.Read(p)
 	if n > 0 {
-		l.logger.Infof("[stdin]: received %d bytes: %s", n, string(p[:n]))
+		l.mu.Lock()
+		l.readBuf = l.logMessages("stdin", append(l.readBuf, p[:n]...))
+		l.mu.Unlock()
 	}
 	return n, err
 }
 
-// Write writes data to the underlying io.Writer and logs it.
+// Write writes data to the underlying io.Writer and logs the messages it completes.
 func (l *IOLogger) Write(p []byte) (n int, err error) {
 	if l.writer == nil {
 		return 0, io.ErrClosedPipe
 	}
-	l.logger.Infof("[stdout]: sending %d bytes: %s", len(p), string(p))
+	l.mu.Lock()
+	l.writeBuf = l.logMessages("stdout", append(l.writeBuf, p...))
+	l.mu.Unlock()
 	return l.writer.Write(p)
 }
+
+// logMessages logs every complete line in buf and returns what is left of it.
+func (l *IOLogger) logMessages(stream string, buf []byte) []byte {
+	for {
+		i := bytes.IndexByte(buf, '\n')
+		if i < 0 {
+			return buf
+		}
+		if line := bytes.TrimSpace(buf[:i]); len(line) > 0 {
+			l.logMessage(stream, line)
+		}
+		buf = buf[i+1:]
+	}
+}
+
+func (l *IOLogger) logMessage(stream string, message []byte) {
+	var decoded struct {
+		Method string          `json:"method"`
+		ID     json.RawMessage `json:"id"`
+	}
+	fields := log.Fields{
+		"stream": stream,
+		"size":   len(message),
+	}
+
+	if err := json.Unmarshal(message, &decoded); err != nil {
+		fields["message"] = l.redactor.Redact(string(message))
+		l.logger.WithFields(fields).Warn("invalid JSON-RPC message")
+		return
+	}
+
+	id := string(decoded.ID)
+	if decoded.Method != "" {
+		fields["method"] = decoded.Method
+	}
+	if id != "" {
+		fields["id"] = id
+	}
+
+	// Requests are received on stdin, and their responses, which have no method, are sent on stdout
+	switch {
+	case stream == "stdin" && decoded.Method != "" && id != "":
+		l.pending[id] = l.now()
+	case stream == "stdout" && decoded.Method == "" && id != "":
+		if received, ok := l.pending[id]; ok {
+			fields["duration"] = l.now().Sub(received).String()
+			delete(l.pending, id)
+		}
+	}
+
+	fields["message"] = l.redactor.Redact(string(message))
+	if stream == "stdin" {
+		l.logger.WithFields(fields).Info("received message")
+	} else {
+		l.logger.WithFields(fields).Info("sent message")
+	}
+}
@@ -2,55 +2,89 @@ package log
 
 import (
 	"bytes"
+	"encoding/json"
+	"io"
 	"strings"
 	"testing"
+	"time"
 
 	log "github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
+// newTestLogger returns a logger that writes JSON entries to the returned buffer.
+func newTestLogger() (*log.Logger, *bytes.Buffer) {
+	var logBuffer bytes.Buffer
+	logger := log.New()
+	logger.SetOutput(&logBuffer)
+	logger.SetFormatter(&log.JSONFormatter{DisableTimestamp: true})
+	return logger, &logBuffer
+}
+
+// logEntries decodes the JSON entries written to logBuffer.
+func logEntries(t *testing.T, logBuffer *bytes.Buffer) []map[string]any {
+	t.Helper()
+	var entries []map[string]any
+	for _, line := range strings.Split(strings.TrimSpace(logBuffer.String()), "\n") {
+		if line == "" {
+			continue
+		}
+		var entry map[string]any
+		require.NoError(t, json.Unmarshal([]byte(line), &entry))
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+// chunkReader returns the data it holds a few bytes at a time, like a pipe can.
+type chunkReader struct {
+	data []byte
+	size int
+}
+
+func (r *chunkReader) Read(p []byte) (int, error) {
+	if len(r.data) == 0 {
+		return 0, io.EOF
+	}
+	n := copy(p[:min(len(p), r.size)], r.data)
+	r.data = r.data[n:]
+	return n, nil
+}
+
 func TestLoggedReadWriter(t *testing.T) {
 	t.Run("Read method logs and passes data", func(t *testing.T) {
 		// Setup
-		inputData := "test input data"
-		reader := strings.NewReader(inputData)
-
-		// Create logger with buffer to capture output
-		var logBuffer bytes.Buffer
-		logger := log.New()
-		logger.SetOutput(&logBuffer)
-		logger.SetFormatter(&log.TextFormatter{
-			DisableTimestamp: true,
-		})
+		inputData := `{"jsonrpc":"2.0","id":1,"method":"tools/list"}` + "\n"
+		reader := &chunkReader{data: []byte(inputData), size: 7}
 
-		lrw := NewIOLogger(reader, nil, logger)
+		logger, logBuffer := newTestLogger()
+		lrw := NewIOLogger(reader, nil, logger, nil)
 
 		// Test Read
-		buf := make([]byte, 100)
-		n, err := lrw.Read(buf)
+		data, err := io.ReadAll(lrw)
 
 		// Assertions
-		assert.NoError(t, err)
-		assert.Equal(t, len(inputData), n)
-		assert.Equal(t, inputData, string(buf[:n]))
-		assert.Contains(t, logBuffer.String(), "[stdin]")
-		assert.Contains(t, logBuffer.String(), inputData)
+		require.NoError(t, err)
+		assert.Equal(t, inputData, string(data))
+
+		entries := logEntries(t, logBuffer)
+		require.Len(t, entries, 1, "one entry per message, not per chunk")
+		assert.Equal(t, "received message", entries[0]["msg"])
+		assert.Equal(t, "stdin", entries[0]["stream"])
+		assert.Equal(t, "tools/list", entries[0]["method"])
+		assert.Equal(t, "1", entries[0]["id"])
+		assert.Equal(t, float64(len(inputData)-1), entries[0]["size"])
+		assert.Equal(t, strings.TrimSpace(inputData), entries[0]["message"])
 	})
 
 	t.Run("Write method logs and passes data", func(t *testing.T) {
 		// Setup
-		outputData := "test output data"
+		outputData := `{"jsonrpc":"2.0","method":"notifications/tools/list_changed"}` + "\n"
 		var writeBuffer bytes.Buffer
 
-		// Create logger with buffer to capture output
-		var logBuffer bytes.Buffer
-		logger := log.New()
-		logger.SetOutput(&logBuffer)
-		logger.SetFormatter(&log.TextFormatter{
-			DisableTimestamp: true,
-		})
-
-		lrw := NewIOLogger(nil, &writeBuffer, logger)
+		logger, logBuffer := newTestLogger()
+		lrw := NewIOLogger(nil, &writeBuffer, logger, nil)
 
 		// Test Write
 		n, err := lrw.Write([]byte(outputData))
@@ -59,7 +93,54 @@ func TestLoggedReadWriter(t *testing.T) {
 		assert.NoError(t, err)
 		assert.Equal(t, len(outputData), n)
 		assert.Equal(t, outputData, writeBuffer.String())
-		assert.Contains(t, logBuffer.String(), "[stdout]")
-		assert.Contains(t, logBuffer.String(), outputData)
+
+		entries := logEntries(t, logBuffer)
+		require.Len(t, entries, 1)
+		assert.Equal(t, "sent message", entries[0]["msg"])
+		assert.Equal(t, "stdout", entries[0]["stream"])
+		assert.Equal(t, "notifications/tools/list_changed", entries[0]["method"])
+		assert.NotContains(t, entries[0], "id")
+	})
+
+	t.Run("responses are logged with the duration of the request", func(t *testing.T) {
+		now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+		request := `{"jsonrpc":"2.0","id":"abc","method":"tools/call","params":{"name":"get_me"}}` + "\n"
+
+		logger, logBuffer := newTestLogger()
+		lrw := NewIOLogger(strings.NewReader(request), io.Discard, logger, nil)
+		lrw.now = func() time.Time { return now }
+
+		_, err := io.ReadAll(lrw)
+		require.NoError(t, err)
+
+		now = now.Add(1500 * time.Millisecond)
+		// Responses can be written in several parts
+		_, err = lrw.Write([]byte(`{"jsonrpc":"2.0","id":"abc",`))
+		require.NoError(t, err)
+		_, err = lrw.Write([]byte(`"result":{}}` + "\n"))
+		require.NoError(t, err)
+
+		entries := logEntries(t, logBuffer)
+		require.Len(t, entries, 2)
+		assert.NotContains(t, entries[0], "duration")
+		assert.Equal(t, `"abc"`, entries[1]["id"])
+		assert.NotContains(t, entries[1], "method")
+		assert.Equal(t, "1.5s", entries[1]["duration"])
+	})
+
+	t.Run("secrets are redacted", func(t *testing.T) {
+		redactor, err := NewRedactor(RedactionConfig{})
+		require.NoError(t, err)
+
+		token := "ghp_" + strings.Repeat("a1B2", 9)
+		response := `{"jsonrpc":"2.0","id":2,"result":{"content":[{"type":"text","text":"token=` + token + `"}]}}` + "\n"
+
+		logger, logBuffer := newTestLogger()
+		lrw := NewIOLogger(nil, io.Discard, logger, redactor)
+		_, err = lrw.Write([]byte(response))
+		require.NoError(t, err)
+
+		assert.NotContains(t, logBuffer.String(), token)
+		assert.Contains(t, logBuffer.String(), "token=[REDACTED:github-token]")
 	})
 }
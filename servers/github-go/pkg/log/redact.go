@@ -0,0 +1,161 @@
+package log
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// DefaultEntropyThreshold is the Shannon entropy, in bits per character, above which
+	// long tokens are considered secrets. It is above the 4 bits per character that hex
+	// strings such as commit SHAs can reach, so that those are kept.
+	DefaultEntropyThreshold = 4.2
+
+	// DefaultEntropyMinLength is the length from which tokens are checked for entropy.
+	DefaultEntropyMinLength = 32
+)
+
+// Detector finds secrets of one kind with a regular expression.
+type Detector struct {
+	Name    string
+	Pattern *regexp.Regexp
+}
+
+// DefaultDetectors find GitHub tokens, private keys and common cloud provider keys.
+var DefaultDetectors = []Detector{
+	{Name: "github-token", Pattern: regexp.MustCompile(`\b(?:gh[pousr]_[A-Za-z0-9]{36,255}|github_pat_[A-Za-z0-9_]{22,255})\b`)},
+	{Name: "private-key", Pattern: regexp.MustCompile(`(?s)-----BEGIN[A-Z ]*PRIVATE KEY-----.*?-----END[A-Z ]*PRIVATE KEY-----`)},
+	{Name: "aws-access-key", Pattern: regexp.MustCompile(`\b(?:AKIA|ASIA|AGPA|AIDA|AROA)[0-9A-Z]{16}\b`)},
+	{Name: "google-api-key", Pattern: regexp.MustCompile(`\bAIza[0-9A-Za-z_\-]{35}\b`)},
+	{Name: "azure-storage-key", Pattern: regexp.MustCompile(`AccountKey=[A-Za-z0-9+/=]{40,}`)},
+	{Name: "slack-token", Pattern: regexp.MustCompile(`\bxox[abposr]-[A-Za-z0-9-]{10,}\b`)},
+	{Name: "stripe-key", Pattern: regexp.MustCompile(`\b[rs]k_live_[A-Za-z0-9]{20,}\b`)},
+}
+
+// candidatePattern matches the tokens that are checked for entropy.
+var candidatePattern = regexp.MustCompile(`[A-Za-z0-9+/_\-=]+`)
+
+// RedactionConfig configures a Redactor.
+type RedactionConfig struct {
+	// Patterns are additional regular expressions whose matches are redacted
+	Patterns []string
+
+	// EntropyThreshold is the Shannon entropy in bits per character above which tokens
+	// are redacted, DefaultEntropyThreshold if zero, and negative to disable entropy detection
+	EntropyThreshold float64
+
+	// EntropyMinLength is the length from which tokens are checked for entropy,
+	// DefaultEntropyMinLength if zero
+	EntropyMinLength int
+}
+
+// Redactor removes secrets from text before it is logged.
+type Redactor struct {
+	detectors        []Detector
+	entropyThreshold float64
+	entropyMinLength int
+}
+
+// NewRedactor creates a Redactor with the default detectors and those configured in cfg.
+func NewRedactor(cfg RedactionConfig) (*Redactor, error) {
+	r := &Redactor{
+		detectors:        append([]Detector(nil), DefaultDetectors...),
+		entropyThreshold: cfg.EntropyThreshold,
+		entropyMinLength: cfg.EntropyMinLength,
+	}
+	if r.entropyThreshold == 0 {
+		r.entropyThreshold = DefaultEntropyThreshold
+	}
+	if r.entropyMinLength == 0 {
+		r.entropyMinLength = DefaultEntropyMinLength
+	}
+
+	for i, pattern := range cfg.Patterns {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid redaction pattern %q: %w", pattern, err)
+		}
+		r.detectors = append(r.detectors, Detector{Name: fmt.Sprintf("pattern-%d", i+1), Pattern: re})
+	}
+	return r, nil
+}
+
+// Redact replaces the secrets found in s with a marker naming the detector that found them.
+func (r *Redactor) Redact(s string) string {
+	if r == nil {
+		return s
+	}
+	for _, detector := range r.detectors {
+		marker := "[REDACTED:" + detector.Name + "]"
+		s = detector.Pattern.ReplaceAllLiteralString(s, marker)
+	}
+	if r.entropyThreshold > 0 {
+		s = candidatePattern.ReplaceAllStringFunc(s, func(token string) string {
+			if len(token) >= r.entropyMinLength && isMixed(token) && shannonEntropy(token) > r.entropyThreshold {
+				return "[REDACTED:high-entropy]"
+			}
+			return token
+		})
+	}
+	return s
+}
+
+// isMixed reports whether s has upper case letters, lower case letters and digits, as
+// generated secrets do, unlike file paths and identifiers of similar entropy.
+func isMixed(s string) bool {
+	var upper, lower, digit bool
+	for _, c := range s {
+		switch {
+		case c >= 'A' && c <= 'Z':
+			upper = true
+		case c >= 'a' && c <= 'z':
+			lower = true
+		case c >= '0' && c <= '9':
+			digit = true
+		}
+	}
+	return upper && lower && digit
+}
+
+// shannonEntropy returns the Shannon entropy of s in bits per character.
+func shannonEntropy(s string) float64 {
+	counts := make(map[rune]int)
+	for _, c := range s {
+		counts[c]++
+	}
+	var entropy float64
+	n := float64(len(s))
+	for _, count := range counts {
+		p := float64(count) / n
+		entropy -= p * math.Log2(p)
+	}
+	return entropy
+}
+
+// RedactionHook is a logrus hook that redacts the message and string fields of every
+// entry, so that secrets never reach the log output.
+type RedactionHook struct {
+	Redactor *Redactor
+}
+
+// Levels returns all levels, since secrets must be redacted at every level.
+func (h *RedactionHook) Levels() []log.Level {
+	return log.AllLevels
+}
+
+// Fire redacts the entry in place.
+func (h *RedactionHook) Fire(entry *log.Entry) error {
+	entry.Message = h.Redactor.Redact(entry.Message)
+	for key, value := range entry.Data {
+		switch v := value.(type) {
+		case string:
+			entry.Data[key] = h.Redactor.Redact(v)
+		case error:
+			entry.Data[key] = h.Redactor.Redact(v.Error())
+		}
+	}
+	return nil
+}
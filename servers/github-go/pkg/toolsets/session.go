@@ -0,0 +1,151 @@
+package toolsets
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// SessionToolsets tracks which toolsets of a group each MCP session has enabled. Sessions
+// start out with the toolsets that are enabled in the group, and enabling or disabling a
+// toolset only affects the session that asked for it. Requests outside of a session get
+// the toolsets of the group and can't change them.
+//
+// All available tools are registered with the server, and the tool filter and middleware
+// of SessionToolsets hide and reject the tools of toolsets a session hasn't enabled.
+type SessionToolsets struct {
+	group *ToolsetGroup
+
+	toolsetOfTool     map[string]string
+	toolsetOfToolOnce sync.Once
+
+	mu sync.RWMutex
+	// sessions holds the toolsets each session has enabled or disabled, overriding the group
+	sessions map[string]map[string]bool
+}
+
+func NewSessionToolsets(group *ToolsetGroup) *SessionToolsets {
+	return &SessionToolsets{
+		group:    group,
+		sessions: make(map[string]map[string]bool),
+	}
+}
+
+// Group returns the toolset group whose toolsets are tracked.
+func (st *SessionToolsets) Group() *ToolsetGroup {
+	return st.group
+}
+
+// IsEnabled reports whether the named toolset is enabled for the session.
+func (st *SessionToolsets) IsEnabled(sessionID, name string) bool {
+	st.mu.RLock()
+	defer st.mu.RUnlock()
+	return st.isEnabled(sessionID, name)
+}
+
+// isEnabled is IsEnabled for callers that hold mu.
+func (st *SessionToolsets) isEnabled(sessionID, name string) bool {
+	if enabled, ok := st.sessions[sessionID][name]; ok {
+		return enabled
+	}
+	return st.group.IsEnabled(name)
+}
+
+// Enable enables the named toolset for the session. It reports whether this changed anything.
+func (st *SessionToolsets) Enable(sessionID, name string) (bool, error) {
+	return st.set(sessionID, name, true)
+}
+
+// Disable disables the named toolset for the session. It reports whether this changed anything.
+func (st *SessionToolsets) Disable(sessionID, name string) (bool, error) {
+	return st.set(sessionID, name, false)
+}
+
+func (st *SessionToolsets) set(sessionID, name string, enabled bool) (bool, error) {
+	if _, exists := st.group.Toolsets[name]; !exists {
+		return false, fmt.Errorf("toolset %s does not exist", name)
+	}
+	// Requests without a session would all share the state kept for the empty ID
+	if sessionID == "" {
+		return false, errors.New("toolsets can only be enabled or disabled within a session")
+	}
+
+	// The check and the update are made under one lock, so that of two concurrent calls
+	// only one reports a change
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	if st.isEnabled(sessionID, name) == enabled {
+		return false, nil
+	}
+	if st.sessions[sessionID] == nil {
+		st.sessions[sessionID] = make(map[string]bool)
+	}
+	st.sessions[sessionID][name] = enabled
+	return true, nil
+}
+
+// Forget drops the state of a session, e.g. once it has ended.
+func (st *SessionToolsets) Forget(sessionID string) {
+	st.mu.Lock()
+	delete(st.sessions, sessionID)
+	st.mu.Unlock()
+}
+
+// RegisterTools registers the tools of every toolset of the group, whether or not it is enabled.
+func (st *SessionToolsets) RegisterTools(s *server.MCPServer) {
+	for _, toolset := range st.group.Toolsets {
+		s.AddTools(toolset.GetAvailableTools()...)
+	}
+}
+
+// toolsetOf returns the name of the toolset the named tool belongs to, if it is in the group.
+func (st *SessionToolsets) toolsetOf(tool string) (string, bool) {
+	st.toolsetOfToolOnce.Do(func() {
+		st.toolsetOfTool = make(map[string]string)
+		for name, toolset := range st.group.Toolsets {
+			for _, tool := range toolset.GetAvailableTools() {
+				st.toolsetOfTool[tool.Tool.Name] = name
+			}
+		}
+	})
+	name, ok := st.toolsetOfTool[tool]
+	return name, ok
+}
+
+// FilterTools is a server.ToolFilterFunc that hides the tools of toolsets the calling
+// session hasn't enabled. Tools that don't belong to the group are left alone.
+func (st *SessionToolsets) FilterTools(ctx context.Context, tools []mcp.Tool) []mcp.Tool {
+	sessionID := SessionIDFromContext(ctx)
+	filtered := make([]mcp.Tool, 0, len(tools))
+	for _, tool := range tools {
+		if toolset, ok := st.toolsetOf(tool.Name); ok && !st.IsEnabled(sessionID, toolset) {
+			continue
+		}
+		filtered = append(filtered, tool)
+	}
+	return filtered
+}
+
+// Middleware rejects calls to tools of toolsets the calling session hasn't enabled, since
+// clients can call tools that FilterTools didn't list to them.
+func (st *SessionToolsets) Middleware(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		if toolset, ok := st.toolsetOf(request.Params.Name); ok && !st.IsEnabled(SessionIDFromContext(ctx), toolset) {
+			return mcp.NewToolResultError(fmt.Sprintf("tool %s belongs to the %s toolset, which is not enabled, enable it with enable_toolset first", request.Params.Name, toolset)), nil
+		}
+		return next(ctx, request)
+	}
+}
+
+// SessionIDFromContext returns the ID of the MCP session ctx belongs to, or an empty string
+// outside of a session.
+func SessionIDFromContext(ctx context.Context) string {
+	if session := server.ClientSessionFromContext(ctx); session != nil {
+		return session.SessionID()
+	}
+	return ""
+}
@@ -0,0 +1,98 @@
+package toolsets
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestSessionToolsets(t *testing.T) {
+	readOnly := true
+	tsg := NewToolsetGroup(false)
+	issues := NewToolset("issues", "Issue tools").
+		AddReadTools(NewServerTool(mcp.NewTool("get_issue", mcp.WithToolAnnotation(mcp.ToolAnnotation{ReadOnlyHint: &readOnly})), nil))
+	repos := NewToolset("repos", "Repository tools").
+		AddReadTools(NewServerTool(mcp.NewTool("get_file_contents", mcp.WithToolAnnotation(mcp.ToolAnnotation{ReadOnlyHint: &readOnly})), nil))
+	tsg.AddToolset(issues)
+	tsg.AddToolset(repos)
+	if err := tsg.EnableToolset("repos"); err != nil {
+		t.Fatal(err)
+	}
+
+	st := NewSessionToolsets(tsg)
+
+	// Sessions start out with the toolsets enabled in the group
+	if st.IsEnabled("a", "issues") || !st.IsEnabled("a", "repos") {
+		t.Fatal("Expected sessions to start with the group's toolsets")
+	}
+
+	changed, err := st.Enable("a", "issues")
+	if err != nil || !changed {
+		t.Fatalf("Expected enabling issues to change session a, got %v, %v", changed, err)
+	}
+	if changed, _ := st.Enable("a", "issues"); changed {
+		t.Error("Expected enabling an enabled toolset not to change anything")
+	}
+	if _, err := st.Disable("a", "repos"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := st.Enable("a", "unknown"); err == nil {
+		t.Error("Expected enabling an unknown toolset to fail")
+	}
+	if _, err := st.Enable("", "issues"); err == nil {
+		t.Error("Expected enabling a toolset outside of a session to fail")
+	}
+	if st.IsEnabled("", "issues") {
+		t.Error("Expected requests outside of a session to keep the group's toolsets")
+	}
+
+	// Other sessions and the group are unaffected
+	if !st.IsEnabled("a", "issues") || st.IsEnabled("a", "repos") {
+		t.Error("Expected session a to have issues but not repos enabled")
+	}
+	if st.IsEnabled("b", "issues") || !st.IsEnabled("b", "repos") {
+		t.Error("Expected session b to be unaffected by session a")
+	}
+	if issues.Enabled || !repos.Enabled {
+		t.Error("Expected the group to be unaffected by session a")
+	}
+
+	tools := []mcp.Tool{{Name: "get_issue"}, {Name: "get_file_contents"}, {Name: "enable_toolset"}}
+	filtered := st.FilterTools(context.Background(), tools)
+	if len(filtered) != 2 || filtered[0].Name != "get_file_contents" || filtered[1].Name != "enable_toolset" {
+		t.Errorf("Expected tools outside of a session to follow the group, got %v", filtered)
+	}
+
+	st.Forget("a")
+	if st.IsEnabled("a", "issues") {
+		t.Error("Expected a forgotten session to start over")
+	}
+}
+
+func TestSessionToolsetsConcurrentEnable(t *testing.T) {
+	tsg := NewToolsetGroup(false)
+	tsg.AddToolset(NewToolset("issues", "Issue tools"))
+	st := NewSessionToolsets(tsg)
+
+	// Only one of the calls that race to enable the toolset changes the session, so that it
+	// is notified once
+	var changes atomic.Int32
+	var wg sync.WaitGroup
+	for range 50 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if changed, err := st.Enable("a", "issues"); err == nil && changed {
+				changes.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if n := changes.Load(); n != 1 {
+		t.Errorf("Expected one call to change the session, got %d", n)
+	}
+}
@@ -11,6 +11,33 @@ func NewServerTool(tool mcp.Tool, handler server.ToolHandlerFunc) server.ServerT
 	return server.ServerTool{Tool: tool, Handler: handler}
 }
 
+// ToolFilter selects individual tools of the enabled toolsets by name.
+type ToolFilter struct {
+	// Allowed, if not empty, lists the only tools that are offered
+	Allowed []string
+
+	// Denied lists tools that are never offered, even if they are allowed
+	Denied []string
+}
+
+// Allows reports whether the tool called name passes the filter.
+func (f ToolFilter) Allows(name string) bool {
+	for _, denied := range f.Denied {
+		if denied == name {
+			return false
+		}
+	}
+	if len(f.Allowed) == 0 {
+		return true
+	}
+	for _, allowed := range f.Allowed {
+		if allowed == name {
+			return true
+		}
+	}
+	return false
+}
+
 type Toolset struct {
 	Name        string
 	Description string
@@ -51,6 +78,81 @@ func (t *Toolset) RegisterTools(s *server.MCPServer) {
 	}
 }
 
+// ApplyToolOptions applies opts to every tool in the toolset, e.g. to add a parameter
+// that all tools accept. It must be called before the tools are registered.
+func (t *Toolset) ApplyToolOptions(opts ...mcp.ToolOption) {
+	for _, tools := range [][]server.ServerTool{t.readTools, t.writeTools} {
+		for i := range tools {
+			for _, opt := range opts {
+				opt(&tools[i].Tool)
+			}
+		}
+	}
+}
+
+// WrapWriteToolHandlers wraps the handler of every write tool in the toolset with mw,
+// e.g. to act on the side effects of write tools. It must be called before the tools are registered.
+func (t *Toolset) WrapWriteToolHandlers(mw server.ToolHandlerMiddleware) {
+	for i := range t.writeTools {
+		t.writeTools[i].Handler = mw(t.writeTools[i].Handler)
+	}
+}
+
+// WrapReadToolHandlers wraps the handler of every read tool in the toolset with mw.
+// It must be called before the tools are registered.
+func (t *Toolset) WrapReadToolHandlers(mw server.ToolHandlerMiddleware) {
+	for i := range t.readTools {
+		t.readTools[i].Handler = mw(t.readTools[i].Handler)
+	}
+}
+
+// WrapDestructiveToolHandlers wraps the handler of every write tool in the toolset that is
+// annotated as destructive with mw, e.g. to ask the user for confirmation. It must be
+// called before the tools are registered.
+func (t *Toolset) WrapDestructiveToolHandlers(mw server.ToolHandlerMiddleware) {
+	for i := range t.writeTools {
+		if *t.writeTools[i].Tool.Annotations.DestructiveHint {
+			t.writeTools[i].Handler = mw(t.writeTools[i].Handler)
+		}
+	}
+}
+
+// WrapToolHandlers wraps the handler of every tool in the toolset, read and write, with mw,
+// e.g. to measure tool calls. It must be called before the tools are registered.
+func (t *Toolset) WrapToolHandlers(mw server.ToolHandlerMiddleware) {
+	t.WrapReadToolHandlers(mw)
+	t.WrapWriteToolHandlers(mw)
+}
+
+// ApplyToolFilter removes the tools that don't pass f from the toolset, so that they are
+// neither registered nor listed. It must be called before the tools are registered.
+func (t *Toolset) ApplyToolFilter(f ToolFilter) {
+	t.readTools = filterTools(t.readTools, f)
+	t.writeTools = filterTools(t.writeTools, f)
+}
+
+func filterTools(tools []server.ServerTool, f ToolFilter) []server.ServerTool {
+	filtered := make([]server.ServerTool, 0, len(tools))
+	for _, tool := range tools {
+		if f.Allows(tool.Tool.Name) {
+			filtered = append(filtered, tool)
+		}
+	}
+	return filtered
+}
+
+// SetToolDescriptions replaces the descriptions of the tools named in descriptions.
+// It must be called before the tools are registered.
+func (t *Toolset) SetToolDescriptions(descriptions map[string]string) {
+	for _, tools := range [][]server.ServerTool{t.readTools, t.writeTools} {
+		for i := range tools {
+			if description, ok := descriptions[tools[i].Tool.Name]; ok {
+				tools[i].Tool.Description = description
+			}
+		}
+	}
+}
+
 func (t *Toolset) SetReadOnly() {
 	// Set the toolset to read-only
 	t.readOnly = true
@@ -62,6 +164,10 @@ func (t *Toolset) AddWriteTools(tools ...server.ServerTool) *Toolset {
 		if *tool.Tool.Annotations.ReadOnlyHint {
 			panic(fmt.Sprintf("tool (%s) is incorrectly annotated as read-only", tool.Tool.Name))
 		}
+		// Clients treat write tools without the hint as destructive, so it must be explicit
+		if tool.Tool.Annotations.DestructiveHint == nil {
+			panic(fmt.Sprintf("tool (%s) must be annotated as destructive or not", tool.Tool.Name))
+		}
 	}
 	if !t.readOnly {
 		t.writeTools = append(t.writeTools, tools...)
@@ -157,6 +263,55 @@ func (tg *ToolsetGroup) EnableToolset(name string) error {
 	return nil
 }
 
+// ApplyToolOptions applies opts to every tool in every toolset of the group.
+func (tg *ToolsetGroup) ApplyToolOptions(opts ...mcp.ToolOption) {
+	for _, toolset := range tg.Toolsets {
+		toolset.ApplyToolOptions(opts...)
+	}
+}
+
+// ApplyToolFilter removes the tools that don't pass f from every toolset of the group.
+func (tg *ToolsetGroup) ApplyToolFilter(f ToolFilter) {
+	for _, toolset := range tg.Toolsets {
+		toolset.ApplyToolFilter(f)
+	}
+}
+
+// SetToolDescriptions replaces the descriptions of the named tools in every toolset of the group.
+func (tg *ToolsetGroup) SetToolDescriptions(descriptions map[string]string) {
+	for _, toolset := range tg.Toolsets {
+		toolset.SetToolDescriptions(descriptions)
+	}
+}
+
+// WrapWriteToolHandlers wraps the handler of every write tool in every toolset of the group with mw.
+func (tg *ToolsetGroup) WrapWriteToolHandlers(mw server.ToolHandlerMiddleware) {
+	for _, toolset := range tg.Toolsets {
+		toolset.WrapWriteToolHandlers(mw)
+	}
+}
+
+// WrapReadToolHandlers wraps the handler of every read tool in every toolset of the group with mw.
+func (tg *ToolsetGroup) WrapReadToolHandlers(mw server.ToolHandlerMiddleware) {
+	for _, toolset := range tg.Toolsets {
+		toolset.WrapReadToolHandlers(mw)
+	}
+}
+
+// WrapDestructiveToolHandlers wraps the handler of every destructive write tool in every toolset of the group with mw.
+func (tg *ToolsetGroup) WrapDestructiveToolHandlers(mw server.ToolHandlerMiddleware) {
+	for _, toolset := range tg.Toolsets {
+		toolset.WrapDestructiveToolHandlers(mw)
+	}
+}
+
+// WrapToolHandlers wraps the handler of every tool in every toolset of the group with mw.
+func (tg *ToolsetGroup) WrapToolHandlers(mw server.ToolHandlerMiddleware) {
+	for _, toolset := range tg.Toolsets {
+		toolset.WrapToolHandlers(mw)
+	}
+}
+
 func (tg *ToolsetGroup) RegisterTools(s *server.MCPServer) {
 	for _, toolset := range tg.Toolsets {
 		toolset.RegisterTools(s)
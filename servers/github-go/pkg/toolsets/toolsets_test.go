@@ -1,7 +1,11 @@
 package toolsets
 
 import (
+	"context"
 	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
 )
 
 func TestNewToolsetGroupIsEmptyWithoutEverythingOn(t *testing.T) {
@@ -222,3 +226,183 @@ func TestIsEnabledWithEverythingOn(t *testing.T) {
 		t.Error("Expected IsEnabled to return true for any toolset when everythingOn is true")
 	}
 }
+
+func TestApplyToolOptions(t *testing.T) {
+	tsg := NewToolsetGroup(false)
+
+	readOnly, notReadOnly := true, false
+	toolset := NewToolset("test-toolset", "A test toolset").
+		AddReadTools(NewServerTool(mcp.NewTool("read", mcp.WithToolAnnotation(mcp.ToolAnnotation{ReadOnlyHint: &readOnly})), nil)).
+		AddWriteTools(NewServerTool(mcp.NewTool("write", mcp.WithToolAnnotation(mcp.ToolAnnotation{ReadOnlyHint: &notReadOnly, DestructiveHint: &notReadOnly})), nil))
+	tsg.AddToolset(toolset)
+
+	tsg.ApplyToolOptions(mcp.WithString("extra", mcp.Description("An extra parameter")))
+
+	for _, tool := range toolset.GetAvailableTools() {
+		if _, ok := tool.Tool.InputSchema.Properties["extra"]; !ok {
+			t.Errorf("Expected tool %s to have the extra parameter", tool.Tool.Name)
+		}
+	}
+}
+
+func TestWrapWriteToolHandlers(t *testing.T) {
+	tsg := NewToolsetGroup(false)
+
+	handler := func(_ context.Context, _ mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultText("called"), nil
+	}
+
+	readOnly, notReadOnly := true, false
+	toolset := NewToolset("test-toolset", "A test toolset").
+		AddReadTools(NewServerTool(mcp.NewTool("read", mcp.WithToolAnnotation(mcp.ToolAnnotation{ReadOnlyHint: &readOnly})), handler)).
+		AddWriteTools(NewServerTool(mcp.NewTool("write", mcp.WithToolAnnotation(mcp.ToolAnnotation{ReadOnlyHint: &notReadOnly, DestructiveHint: &notReadOnly})), handler))
+	tsg.AddToolset(toolset)
+
+	var wrapped []string
+	tsg.WrapWriteToolHandlers(func(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+		return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			wrapped = append(wrapped, request.Params.Name)
+			return next(ctx, request)
+		}
+	})
+
+	for _, tool := range toolset.GetAvailableTools() {
+		request := mcp.CallToolRequest{}
+		request.Params.Name = tool.Tool.Name
+		if _, err := tool.Handler(context.Background(), request); err != nil {
+			t.Fatalf("Unexpected error calling %s: %v", tool.Tool.Name, err)
+		}
+	}
+
+	if len(wrapped) != 1 || wrapped[0] != "write" {
+		t.Errorf("Expected only the write tool to be wrapped, got %v", wrapped)
+	}
+}
+
+func TestWrapToolHandlers(t *testing.T) {
+	tsg := NewToolsetGroup(false)
+
+	handler := func(_ context.Context, _ mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultText("called"), nil
+	}
+
+	readOnly, notReadOnly := true, false
+	toolset := NewToolset("test-toolset", "A test toolset").
+		AddReadTools(NewServerTool(mcp.NewTool("read", mcp.WithToolAnnotation(mcp.ToolAnnotation{ReadOnlyHint: &readOnly})), handler)).
+		AddWriteTools(NewServerTool(mcp.NewTool("write", mcp.WithToolAnnotation(mcp.ToolAnnotation{ReadOnlyHint: &notReadOnly, DestructiveHint: &notReadOnly})), handler))
+	tsg.AddToolset(toolset)
+
+	wrapped := make(map[string]int)
+	tsg.WrapToolHandlers(func(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+		return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			wrapped[request.Params.Name]++
+			return next(ctx, request)
+		}
+	})
+
+	for _, tool := range toolset.GetAvailableTools() {
+		request := mcp.CallToolRequest{}
+		request.Params.Name = tool.Tool.Name
+		if _, err := tool.Handler(context.Background(), request); err != nil {
+			t.Fatalf("Unexpected error calling %s: %v", tool.Tool.Name, err)
+		}
+	}
+
+	if len(wrapped) != 2 || wrapped["read"] != 1 || wrapped["write"] != 1 {
+		t.Errorf("Expected both tools to be wrapped once, got %v", wrapped)
+	}
+}
+
+func TestWrapDestructiveToolHandlers(t *testing.T) {
+	tsg := NewToolsetGroup(false)
+
+	handler := func(_ context.Context, _ mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultText("called"), nil
+	}
+
+	readOnly, notReadOnly, destructive := true, false, true
+	toolset := NewToolset("test-toolset", "A test toolset").
+		AddReadTools(NewServerTool(mcp.NewTool("read", mcp.WithToolAnnotation(mcp.ToolAnnotation{ReadOnlyHint: &readOnly})), handler)).
+		AddWriteTools(
+			NewServerTool(mcp.NewTool("create", mcp.WithToolAnnotation(mcp.ToolAnnotation{ReadOnlyHint: &notReadOnly, DestructiveHint: &notReadOnly})), handler),
+			NewServerTool(mcp.NewTool("delete", mcp.WithToolAnnotation(mcp.ToolAnnotation{ReadOnlyHint: &notReadOnly, DestructiveHint: &destructive})), handler),
+		)
+	tsg.AddToolset(toolset)
+
+	wrapped := make(map[string]int)
+	tsg.WrapDestructiveToolHandlers(func(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+		return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			wrapped[request.Params.Name]++
+			return next(ctx, request)
+		}
+	})
+
+	for _, tool := range toolset.GetAvailableTools() {
+		request := mcp.CallToolRequest{}
+		request.Params.Name = tool.Tool.Name
+		if _, err := tool.Handler(context.Background(), request); err != nil {
+			t.Fatalf("Unexpected error calling %s: %v", tool.Tool.Name, err)
+		}
+	}
+
+	if len(wrapped) != 1 || wrapped["delete"] != 1 {
+		t.Errorf("Expected only the destructive tool to be wrapped, got %v", wrapped)
+	}
+}
+
+func TestAddWriteToolsRequiresDestructiveHint(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("Expected adding a write tool without a destructive hint to panic")
+		}
+	}()
+
+	notReadOnly := false
+	NewToolset("test-toolset", "A test toolset").
+		AddWriteTools(NewServerTool(mcp.NewTool("write", mcp.WithToolAnnotation(mcp.ToolAnnotation{ReadOnlyHint: &notReadOnly})), nil))
+}
+
+func TestToolFilterAllows(t *testing.T) {
+	tests := []struct {
+		name     string
+		filter   ToolFilter
+		tool     string
+		expected bool
+	}{
+		{name: "empty filter allows everything", filter: ToolFilter{}, tool: "merge_pull_request", expected: true},
+		{name: "denied tool", filter: ToolFilter{Denied: []string{"merge_pull_request"}}, tool: "merge_pull_request", expected: false},
+		{name: "tool not denied", filter: ToolFilter{Denied: []string{"merge_pull_request"}}, tool: "get_pull_request", expected: true},
+		{name: "allowed tool", filter: ToolFilter{Allowed: []string{"get_pull_request"}}, tool: "get_pull_request", expected: true},
+		{name: "tool not allowed", filter: ToolFilter{Allowed: []string{"get_pull_request"}}, tool: "merge_pull_request", expected: false},
+		{name: "deny wins over allow", filter: ToolFilter{Allowed: []string{"delete_file"}, Denied: []string{"delete_file"}}, tool: "delete_file", expected: false},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := tc.filter.Allows(tc.tool); got != tc.expected {
+				t.Errorf("Expected Allows(%q) to be %v, got %v", tc.tool, tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestApplyToolFilterAndDescriptions(t *testing.T) {
+	tsg := NewToolsetGroup(false)
+
+	readOnly, notReadOnly := true, false
+	toolset := NewToolset("pull_requests", "Pull request tools").
+		AddReadTools(NewServerTool(mcp.NewTool("get_pull_request", mcp.WithDescription("original"), mcp.WithToolAnnotation(mcp.ToolAnnotation{ReadOnlyHint: &readOnly})), nil)).
+		AddWriteTools(NewServerTool(mcp.NewTool("merge_pull_request", mcp.WithToolAnnotation(mcp.ToolAnnotation{ReadOnlyHint: &notReadOnly, DestructiveHint: &notReadOnly})), nil))
+	tsg.AddToolset(toolset)
+
+	tsg.ApplyToolFilter(ToolFilter{Denied: []string{"merge_pull_request"}})
+	tsg.SetToolDescriptions(map[string]string{"get_pull_request": "overridden"})
+
+	tools := toolset.GetAvailableTools()
+	if len(tools) != 1 || tools[0].Tool.Name != "get_pull_request" {
+		t.Fatalf("Expected only get_pull_request to remain, got %v", tools)
+	}
+	if tools[0].Tool.Description != "overridden" {
+		t.Errorf("Expected description to be overridden, got %q", tools[0].Tool.Description)
+	}
+}
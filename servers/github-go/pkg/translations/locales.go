@@ -0,0 +1,137 @@
+package translations
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// DefaultLocale is the locale of the texts built into the server, which needs no file.
+const DefaultLocale = "en"
+
+// Locales holds the translations of a locale directory, which has one JSON file per locale
+// named after it, e.g. "fr.json" or "pt-BR.json". Every file maps translation keys to texts,
+// like github-mcp-server-config.json.
+type Locales struct {
+	texts map[string]map[string]string
+}
+
+// LoadLocales reads every locale file in dir.
+func LoadLocales(dir string) (*Locales, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read locale directory: %w", err)
+	}
+
+	l := &Locales{texts: make(map[string]map[string]string)}
+	for _, entry := range entries {
+		name, ok := strings.CutSuffix(entry.Name(), ".json")
+		if !ok || entry.IsDir() {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read locale %s: %w", name, err)
+		}
+		var texts map[string]string
+		if err := json.Unmarshal(data, &texts); err != nil {
+			return nil, fmt.Errorf("failed to parse locale %s: %w", name, err)
+		}
+		keyed := make(map[string]string, len(texts))
+		for key, text := range texts {
+			keyed[strings.ToUpper(key)] = text
+		}
+		l.texts[NormalizeLocale(name)] = keyed
+	}
+	return l, nil
+}
+
+// NormalizeLocale spells locales the same way, so that "pt_br" and "pt-BR" are the same.
+func NormalizeLocale(locale string) string {
+	return strings.ToLower(strings.ReplaceAll(locale, "_", "-"))
+}
+
+// baseLanguage returns the language of a locale without its region, e.g. "pt" for "pt-br".
+func baseLanguage(locale string) string {
+	language, _, _ := strings.Cut(locale, "-")
+	return language
+}
+
+// Names returns the locales that have a file, sorted.
+func (l *Locales) Names() []string {
+	names := make([]string, 0, len(l.texts))
+	for name := range l.texts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Has reports whether texts can be shown in locale, either because it or its base language
+// has a file, or because it is the default locale.
+func (l *Locales) Has(locale string) bool {
+	locale = NormalizeLocale(locale)
+	if baseLanguage(locale) == DefaultLocale {
+		return true
+	}
+	_, ok := l.texts[locale]
+	if !ok {
+		_, ok = l.texts[baseLanguage(locale)]
+	}
+	return ok
+}
+
+// Helper returns a translation helper for locale. Keys are looked up in the file of the
+// locale, then in the file of its base language, and then passed on to fallback.
+func (l *Locales) Helper(locale string, fallback TranslationHelperFunc) TranslationHelperFunc {
+	locale = NormalizeLocale(locale)
+	var chain []map[string]string
+	if texts, ok := l.texts[locale]; ok {
+		chain = append(chain, texts)
+	}
+	if texts, ok := l.texts[baseLanguage(locale)]; ok && baseLanguage(locale) != locale {
+		chain = append(chain, texts)
+	}
+
+	return func(key string, defaultValue string) string {
+		key = strings.ToUpper(key)
+		for _, texts := range chain {
+			if text, ok := texts[key]; ok {
+				return text
+			}
+		}
+		return fallback(key, defaultValue)
+	}
+}
+
+// Template returns the texts of locale for every key in defaults, with the default text for
+// those it doesn't translate yet. It also returns the keys missing from the file of the
+// locale, and the stale keys in it that are not in defaults anymore, both sorted. A nil
+// Locales has no files, so all keys are missing.
+func (l *Locales) Template(locale string, defaults map[string]string) (template map[string]string, missing, stale []string) {
+	var texts map[string]string
+	if l != nil {
+		texts = l.texts[NormalizeLocale(locale)]
+	}
+
+	template = make(map[string]string, len(defaults))
+	for key, defaultValue := range defaults {
+		if text, ok := texts[key]; ok {
+			template[key] = text
+			continue
+		}
+		template[key] = defaultValue
+		missing = append(missing, key)
+	}
+	for key := range texts {
+		if _, ok := defaults[key]; !ok {
+			stale = append(stale, key)
+		}
+	}
+	sort.Strings(missing)
+	sort.Strings(stale)
+	return template, missing, stale
+}
@@ -0,0 +1,57 @@
+package translations
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Locales(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "pt.json"), []byte(`{"TOOL_A_DESCRIPTION": "a (pt)", "tool_b_description": "b (pt)"}`), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "pt-BR.json"), []byte(`{"TOOL_A_DESCRIPTION": "a (pt-BR)", "TOOL_OLD_DESCRIPTION": "old"}`), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "README.md"), []byte("not a locale"), 0600))
+
+	locales, err := LoadLocales(dir)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"pt", "pt-br"}, locales.Names())
+
+	assert.True(t, locales.Has("pt_BR"))
+	assert.True(t, locales.Has("pt-PT"), "the base language has a file")
+	assert.True(t, locales.Has("en-GB"), "the built-in texts are English")
+	assert.False(t, locales.Has("fr"))
+
+	t.Run("helper", func(t *testing.T) {
+		helper := locales.Helper("pt_BR", NullTranslationHelper)
+		assert.Equal(t, "a (pt-BR)", helper("TOOL_A_DESCRIPTION", "a"))
+		assert.Equal(t, "b (pt)", helper("TOOL_B_DESCRIPTION", "b"), "keys fall back to the base language")
+		assert.Equal(t, "c", helper("TOOL_C_DESCRIPTION", "c"), "keys fall back to the fallback helper")
+
+		helper = locales.Helper("fr", NullTranslationHelper)
+		assert.Equal(t, "a", helper("TOOL_A_DESCRIPTION", "a"))
+	})
+
+	t.Run("template", func(t *testing.T) {
+		defaults := map[string]string{
+			"TOOL_A_DESCRIPTION": "a",
+			"TOOL_B_DESCRIPTION": "b",
+		}
+
+		template, missing, stale := locales.Template("pt-BR", defaults)
+		assert.Equal(t, map[string]string{"TOOL_A_DESCRIPTION": "a (pt-BR)", "TOOL_B_DESCRIPTION": "b"}, template)
+		assert.Equal(t, []string{"TOOL_B_DESCRIPTION"}, missing)
+		assert.Equal(t, []string{"TOOL_OLD_DESCRIPTION"}, stale)
+
+		var none *Locales
+		template, missing, stale = none.Template("fr", defaults)
+		assert.Equal(t, defaults, template)
+		assert.Equal(t, []string{"TOOL_A_DESCRIPTION", "TOOL_B_DESCRIPTION"}, missing)
+		assert.Empty(t, stale)
+	})
+
+	_, err = LoadLocales(filepath.Join(dir, "missing"))
+	assert.Error(t, err)
+}
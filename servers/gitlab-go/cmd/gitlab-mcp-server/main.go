@@ -5,11 +5,13 @@ import (
 	"log"
 	"os"
 
+	"github.com/hashicorp/go-cleanhttp"
 	"github.com/joho/godotenv"
 	gitlab "gitlab.com/gitlab-org/api/client-go"
 
 	"gitlab.com/fforster/gitlab-mcp/cmd"
 	"gitlab.com/fforster/gitlab-mcp/lib/build"
+	"gitlab.com/fforster/gitlab-mcp/lib/metrics"
 )
 
 func main() {
@@ -20,7 +22,13 @@ func main() {
 
 	ctx := context.Background()
 
+	m := metrics.New()
+
+	httpClient := cleanhttp.DefaultPooledClient()
+	httpClient.Transport = m.Transport(httpClient.Transport)
+
 	client, err := gitlab.NewClient(os.Getenv("GITLAB_TOKEN"),
+		gitlab.WithHTTPClient(httpClient),
 		gitlab.WithRequestOptions(
 			gitlab.WithHeader("User-Agent", "gitlab-mcp/"+build.Version()),
 		),
@@ -29,7 +37,7 @@ func main() {
 		log.Fatal(err)
 	}
 
-	if err := cmd.New(client).ExecuteContext(ctx); err != nil {
+	if err := cmd.New(client, m).ExecuteContext(ctx); err != nil {
 		log.Fatal(err)
 	}
 }
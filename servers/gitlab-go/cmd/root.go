@@ -2,6 +2,7 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/mark3labs/mcp-go/server"
@@ -9,12 +10,14 @@ import (
 	gitlab "gitlab.com/gitlab-org/api/client-go"
 
 	"gitlab.com/fforster/gitlab-mcp/lib/build"
+	"gitlab.com/fforster/gitlab-mcp/lib/metrics"
 	"gitlab.com/fforster/gitlab-mcp/lib/tools"
 )
 
 // New creates a new command hierarchy for Cobra with the provided GitLab client.
-func New(client *gitlab.Client) *cobra.Command {
-	cmd := newRootCommand(client)
+// The metrics collect the GitLab API requests made by client.
+func New(client *gitlab.Client, m *metrics.Metrics) *cobra.Command {
+	cmd := newRootCommand(client, m)
 
 	cmd.AddCommand(newVersionCommand())
 
@@ -22,21 +25,44 @@ func New(client *gitlab.Client) *cobra.Command {
 }
 
 type rootCommand struct {
-	client *gitlab.Client
+	client  *gitlab.Client
+	metrics *metrics.Metrics
+
+	metricsAddress string
 }
 
 // newRootCommand returns the root command for the CLI.
-func newRootCommand(client *gitlab.Client) *cobra.Command {
-	return &cobra.Command{
+func newRootCommand(client *gitlab.Client, m *metrics.Metrics) *cobra.Command {
+	c := &rootCommand{client: client, metrics: m}
+
+	cmd := &cobra.Command{
 		Use:   "gitlab-mcp",
 		Short: "GitLab MCP server",
 		Long:  "A command-line tool that provides an MCP server for interacting with GitLab.",
-		RunE:  (&rootCommand{client}).run,
+		RunE:  c.run,
 		Args:  cobra.NoArgs,
 	}
+
+	cmd.Flags().StringVar(&c.metricsAddress, "metrics-address", "",
+		`Address to serve Prometheus metrics on at /metrics, e.g. "127.0.0.1:9090". Disabled if empty.`)
+
+	return cmd
 }
 
-func (c *rootCommand) run(_ *cobra.Command, _ []string) error {
+func (c *rootCommand) run(cmd *cobra.Command, _ []string) error {
+	ctx, cancel := context.WithCancel(cmd.Context())
+	defer cancel()
+
+	// metricsErr stays nil, and never receives, if metrics are disabled
+	var metricsErr chan error
+	if c.metricsAddress != "" {
+		metricsErr = make(chan error, 1)
+
+		go func() {
+			metricsErr <- c.metrics.Serve(ctx, c.metricsAddress)
+		}()
+	}
+
 	// Create a new MCP server
 	s := server.NewMCPServer(
 		"GitLab",
@@ -52,13 +78,26 @@ func (c *rootCommand) run(_ *cobra.Command, _ []string) error {
 	}
 
 	tools := tools.New(c.client, user.Username)
+	tools.Middleware = c.metrics.ToolMiddleware
 
 	tools.AddTo(s)
 
 	// Start the server
-	if err := server.ServeStdio(s); err != nil {
-		return fmt.Errorf("ServeStdio: %w", err)
-	}
+	stdioErr := make(chan error, 1)
 
-	return nil
+	go func() {
+		stdioErr <- server.ServeStdio(s)
+	}()
+
+	select {
+	case err := <-stdioErr:
+		if err != nil {
+			return fmt.Errorf("ServeStdio: %w", err)
+		}
+
+		return nil
+	case err := <-metricsErr:
+		// Serve only returns nil once ctx is done, which it isn't before run returns
+		return fmt.Errorf("Serve: %w", err)
+	}
 }
@@ -0,0 +1,154 @@
+// Package metrics collects Prometheus metrics about tool calls and the GitLab API
+// requests they make, and serves them in the OpenMetrics format.
+package metrics
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Tool call outcomes.
+const (
+	OutcomeSuccess   = "success"
+	OutcomeToolError = "tool_error"
+	OutcomeFailure   = "failure"
+)
+
+// Metrics holds the metrics of a server in a registry of their own.
+type Metrics struct {
+	registry *prometheus.Registry
+
+	toolCalls        *prometheus.CounterVec
+	toolCallDuration *prometheus.HistogramVec
+	upstreamRequests *prometheus.CounterVec
+	rateLimit        *prometheus.GaugeVec
+}
+
+// New creates the metrics, along with the Go runtime and process metrics.
+func New() *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		toolCalls: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "gitlab_mcp_tool_calls_total",
+			Help: "Tool calls by tool and outcome, which is success, tool_error or failure.",
+		}, []string{"tool", "outcome"}),
+		toolCallDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "gitlab_mcp_tool_call_duration_seconds",
+			Help:    "How long tool calls took, by tool.",
+			Buckets: []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60},
+		}, []string{"tool"}),
+		upstreamRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "gitlab_mcp_upstream_requests_total",
+			Help: "Requests made to the GitLab API by HTTP status code, which is 0 for requests that got no response.",
+		}, []string{"code"}),
+		rateLimit: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "gitlab_mcp_rate_limit_remaining",
+			Help: "Requests remaining in the current GitLab API rate limit window of the server's token by rate limit name, as last reported by the API.",
+		}, []string{"name"}),
+	}
+
+	m.registry.MustRegister(
+		m.toolCalls,
+		m.toolCallDuration,
+		m.upstreamRequests,
+		m.rateLimit,
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+
+	return m
+}
+
+// Handler serves the metrics in the Prometheus text format, or in OpenMetrics to scrapers that ask for it.
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{EnableOpenMetrics: true})
+}
+
+// ToolMiddleware counts and times the calls of the tool handler it wraps.
+func (m *Metrics) ToolMiddleware(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		start := time.Now()
+		result, err := next(ctx, request)
+
+		outcome := OutcomeSuccess
+
+		switch {
+		case err != nil:
+			outcome = OutcomeFailure
+		case result != nil && result.IsError:
+			outcome = OutcomeToolError
+		}
+
+		m.toolCalls.WithLabelValues(request.Params.Name, outcome).Inc()
+		m.toolCallDuration.WithLabelValues(request.Params.Name).Observe(time.Since(start).Seconds())
+
+		return result, err
+	}
+}
+
+// Transport returns an http.RoundTripper that counts the requests made through next
+// and records the remaining rate limit reported by the GitLab API.
+func (m *Metrics) Transport(next http.RoundTripper) http.RoundTripper {
+	return &transport{next: next, metrics: m}
+}
+
+type transport struct {
+	next    http.RoundTripper
+	metrics *Metrics
+}
+
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		t.metrics.upstreamRequests.WithLabelValues("0").Inc()
+
+		return nil, err //nolint:wrapcheck
+	}
+
+	t.metrics.upstreamRequests.WithLabelValues(strconv.Itoa(resp.StatusCode)).Inc()
+
+	if remaining, err := strconv.Atoi(resp.Header.Get("RateLimit-Remaining")); err == nil {
+		t.metrics.rateLimit.WithLabelValues(resp.Header.Get("RateLimit-Name")).Set(float64(remaining))
+	}
+
+	return resp, nil
+}
+
+// Serve serves the metrics at /metrics on address until ctx is done, and then returns nil.
+// Otherwise, it returns the error that stopped it from listening or serving.
+func (m *Metrics) Serve(ctx context.Context, address string) error {
+	listener, err := net.Listen("tcp", address)
+	if err != nil {
+		return fmt.Errorf("listening for metrics on %s: %w", address, err)
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", m.Handler())
+
+	srv := &http.Server{
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	stop := context.AfterFunc(ctx, func() {
+		_ = srv.Close()
+	})
+	defer stop()
+
+	if err := srv.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("serving metrics on %s: %w", address, err)
+	}
+
+	return nil
+}
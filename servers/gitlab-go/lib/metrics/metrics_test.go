@@ -0,0 +1,127 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestToolMiddleware(t *testing.T) {
+	m := New()
+
+	handler := m.ToolMiddleware(func(_ context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		switch request.Params.Name {
+		case "get_issue":
+			return mcp.NewToolResultText("{}"), nil
+		case "edit_issue":
+			return mcp.NewToolResultError("not found"), nil
+		default:
+			return nil, errors.New("invalid argument type")
+		}
+	})
+
+	for _, name := range []string{"get_issue", "get_issue", "edit_issue", "create_issue"} {
+		var req mcp.CallToolRequest
+		req.Params.Name = name
+
+		_, _ = handler(t.Context(), req)
+	}
+
+	tests := []struct {
+		tool, outcome string
+		want          float64
+	}{
+		{"get_issue", OutcomeSuccess, 2},
+		{"edit_issue", OutcomeToolError, 1},
+		{"create_issue", OutcomeFailure, 1},
+	}
+
+	for _, tt := range tests {
+		if got := testutil.ToFloat64(m.toolCalls.WithLabelValues(tt.tool, tt.outcome)); got != tt.want {
+			t.Errorf("tool calls of %s with outcome %s = %v, want %v", tt.tool, tt.outcome, got, tt.want)
+		}
+	}
+
+	if got := testutil.CollectAndCount(m.toolCallDuration); got != 3 {
+		t.Errorf("got %d duration histograms, want 3", got)
+	}
+}
+
+func TestTransport(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("RateLimit-Name", "throttle_authenticated_api")
+		w.Header().Set("RateLimit-Remaining", "1999")
+
+		if r.URL.Path == "/api/v4/missing" {
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer ts.Close()
+
+	m := New()
+	client := &http.Client{Transport: m.Transport(http.DefaultTransport)}
+
+	for _, path := range []string{"/api/v4/user", "/api/v4/missing"} {
+		resp, err := client.Get(ts.URL + path)
+		if err != nil {
+			t.Fatalf("Get(%q): %v", path, err)
+		}
+
+		_ = resp.Body.Close()
+	}
+
+	if got := testutil.ToFloat64(m.upstreamRequests.WithLabelValues("404")); got != 1 {
+		t.Errorf("requests with status 404 = %v, want 1", got)
+	}
+
+	if got := testutil.ToFloat64(m.rateLimit.WithLabelValues("throttle_authenticated_api")); got != 1999 {
+		t.Errorf("remaining rate limit = %v, want 1999", got)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+	req.Header.Set("Accept", "application/openmetrics-text; version=1.0.0")
+
+	rec := httptest.NewRecorder()
+	m.Handler().ServeHTTP(rec, req)
+
+	body, err := io.ReadAll(rec.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want := `gitlab_mcp_upstream_requests_total{code="200"} 1`; !strings.Contains(string(body), want) {
+		t.Errorf("metrics do not contain %q:\n%s", want, body)
+	}
+
+	if !strings.HasSuffix(string(body), "# EOF\n") {
+		t.Errorf("metrics are not in the OpenMetrics format:\n%s", body)
+	}
+}
+
+func TestServe(t *testing.T) {
+	m := New()
+
+	ctx, cancel := context.WithCancel(t.Context())
+	served := make(chan error, 1)
+
+	go func() {
+		served <- m.Serve(ctx, "127.0.0.1:0")
+	}()
+
+	cancel()
+
+	if err := <-served; err != nil {
+		t.Errorf("Serve() after cancellation = %v, want nil", err)
+	}
+
+	if err := m.Serve(t.Context(), "invalid address"); err == nil {
+		t.Error("Serve() with an invalid address succeeded, want an error")
+	}
+}
@@ -15,7 +15,7 @@ import (
 // DiscussionServiceInterface defines the interface for discussion-related GitLab operations.
 type DiscussionServiceInterface interface {
 	// AddTo registers all discussion-related tools with the provided MCPServer.
-	AddTo(srv *server.MCPServer)
+	AddTo(srv ToolAdder)
 
 	// Discussion tools
 	NewDiscussion() server.ServerTool
@@ -36,7 +36,7 @@ type DiscussionService struct {
 }
 
 // AddTo registers all discussion-related tools with the provided MCPServer.
-func (d *DiscussionService) AddTo(srv *server.MCPServer) {
+func (d *DiscussionService) AddTo(srv ToolAdder) {
 	srv.AddTools(
 		d.NewDiscussion(),
 		d.ListDiscussions(),
@@ -15,7 +15,7 @@ import (
 // It provides methods for retrieving and listing epics and their associated issues.
 type EpicServiceInterface interface {
 	// AddTo registers all epic-related tools with the provided MCPServer.
-	AddTo(srv *server.MCPServer)
+	AddTo(srv ToolAdder)
 
 	// ListGroupEpics returns a tool for listing all epics in a specific group.
 	ListGroupEpics() server.ServerTool
@@ -42,7 +42,7 @@ type EpicService struct {
 
 // AddTo registers all epic-related tools with the provided MCPServer.
 // It adds tools for listing, retrieving, and managing epics and their associated issues.
-func (e *EpicService) AddTo(srv *server.MCPServer) {
+func (e *EpicService) AddTo(srv ToolAdder) {
 	srv.AddTools(
 		e.ListGroupEpics(),
 		e.GetEpic(),
@@ -13,7 +13,7 @@ import (
 
 type EventsServiceInterface interface {
 	// AddTo registers all issue-related tools with the provided MCPServer.
-	AddTo(srv *server.MCPServer)
+	AddTo(srv ToolAdder)
 
 	ListUserEvents() server.ServerTool
 }
@@ -28,7 +28,7 @@ type EventsService struct {
 
 // AddTo registers all issue-related tools with the provided MCPServer.
 // It adds tools for listing, retrieving, and managing issues and their related merge requests.
-func (e *EventsService) AddTo(srv *server.MCPServer) {
+func (e *EventsService) AddTo(srv ToolAdder) {
 	srv.AddTools(
 		e.ListUserEvents(),
 	)
@@ -23,7 +23,7 @@ import (
 // It provides methods for retrieving and listing issues and related merge requests.
 type IssuesServiceInterface interface {
 	// AddTo registers all issue-related tools with the provided MCPServer.
-	AddTo(srv *server.MCPServer)
+	AddTo(srv ToolAdder)
 
 	// ListUserIssues returns a tool for listing all issues assigned to a user.
 	ListUserIssues() server.ServerTool
@@ -68,7 +68,7 @@ const (
 
 // AddTo registers all issue-related tools with the provided MCPServer.
 // It adds tools for listing, retrieving, and managing issues and their related merge requests.
-func (i *IssuesService) AddTo(srv *server.MCPServer) {
+func (i *IssuesService) AddTo(srv ToolAdder) {
 	srv.AddTools(
 		i.ListUserIssues(),
 		i.ListGroupIssues(),
@@ -57,6 +57,32 @@ type Tools struct {
 
 	// Users provides tools for looking up user IDs, a user's activity and status, etc.
 	Users UsersServiceInterface
+
+	// Middleware, if set, wraps the handler of every tool registered by AddTo,
+	// e.g. to collect metrics about tool calls.
+	Middleware server.ToolHandlerMiddleware
+}
+
+// ToolAdder is implemented by *server.MCPServer. Services register their tools with it.
+type ToolAdder interface {
+	AddTools(tools ...server.ServerTool)
+}
+
+// middlewareAdder wraps the handler of every tool with a middleware before registering it.
+type middlewareAdder struct {
+	next       ToolAdder
+	middleware server.ToolHandlerMiddleware
+}
+
+func (a middlewareAdder) AddTools(tools ...server.ServerTool) {
+	wrapped := make([]server.ServerTool, len(tools))
+
+	for i, tool := range tools {
+		tool.Handler = a.middleware(tool.Handler)
+		wrapped[i] = tool
+	}
+
+	a.next.AddTools(wrapped...)
 }
 
 // New creates a new instance of Tools with the provided GitLab client and current user.
@@ -78,7 +104,17 @@ func New(client *gitlab.Client, currentUser string) *Tools {
 
 // AddTo registers all GitLab tools with the provided MCPServer.
 // It calls AddTo on all service interfaces to register their respective tools.
+// If Middleware is set, every tool handler is wrapped with it.
 func (s *Tools) AddTo(srv *server.MCPServer) {
+	var adder ToolAdder = srv
+	if s.Middleware != nil {
+		adder = middlewareAdder{next: srv, middleware: s.Middleware}
+	}
+
+	s.addTo(adder)
+}
+
+func (s *Tools) addTo(srv ToolAdder) {
 	s.Discussions.AddTo(srv)
 	s.Epics.AddTo(srv)
 	s.Events.AddTo(srv)
@@ -0,0 +1,94 @@
+package tools
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/mark3labs/mcp-go/server"
+	glabtest "gitlab.com/gitlab-org/api/client-go/testing"
+)
+
+const toolsnapsDir = "__toolsnaps__"
+
+// toolRecorder collects the tools that are registered with it.
+type toolRecorder struct {
+	tools []server.ServerTool
+}
+
+func (r *toolRecorder) AddTools(tools ...server.ServerTool) {
+	r.tools = append(r.tools, tools...)
+}
+
+// TestToolSnapshots compares the JSON of every tool with its snapshot in __toolsnaps__, in the
+// same format as the snapshots of the GitHub MCP server, so that its toolsnaps command can
+// classify the changes between two versions. Run with UPDATE_TOOLSNAPS=true to update them.
+func TestToolSnapshots(t *testing.T) {
+	gitlabClient := glabtest.NewTestClient(t)
+
+	var recorder toolRecorder
+	New(gitlabClient.Client, "test_user").addTo(&recorder)
+
+	update := os.Getenv("UPDATE_TOOLSNAPS") == "true"
+	if update {
+		if err := os.RemoveAll(toolsnapsDir); err != nil {
+			t.Fatalf("removing snapshots: %v", err)
+		}
+		if err := os.MkdirAll(toolsnapsDir, 0o700); err != nil {
+			t.Fatalf("creating snapshot directory: %v", err)
+		}
+	}
+
+	snapshots := make(map[string]bool)
+	for _, tool := range recorder.tools {
+		snapshots[tool.Tool.Name+".snap"] = true
+
+		t.Run(tool.Tool.Name, func(t *testing.T) {
+			got, err := json.MarshalIndent(tool.Tool, "", "  ")
+			if err != nil {
+				t.Fatalf("json.MarshalIndent: %v", err)
+			}
+
+			path := filepath.Join(toolsnapsDir, tool.Tool.Name+".snap")
+			if update {
+				if err := os.WriteFile(path, got, 0o600); err != nil {
+					t.Fatalf("writing snapshot: %v", err)
+				}
+
+				return
+			}
+
+			want, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatalf("reading snapshot, run the tests with UPDATE_TOOLSNAPS=true to create it: %v", err)
+			}
+
+			var gotJSON, wantJSON any
+			if err := json.Unmarshal(got, &gotJSON); err != nil {
+				t.Fatalf("json.Unmarshal(tool): %v", err)
+			}
+
+			if err := json.Unmarshal(want, &wantJSON); err != nil {
+				t.Fatalf("json.Unmarshal(snapshot): %v", err)
+			}
+
+			if diff := cmp.Diff(wantJSON, gotJSON); diff != "" {
+				t.Errorf("tool schema changed, run the tests with UPDATE_TOOLSNAPS=true to update the snapshot (-want +got):\n%s", diff)
+			}
+		})
+	}
+
+	entries, err := os.ReadDir(toolsnapsDir)
+	if err != nil {
+		t.Fatalf("reading snapshot directory: %v", err)
+	}
+
+	for _, entry := range entries {
+		if strings.HasSuffix(entry.Name(), ".snap") && !snapshots[entry.Name()] {
+			t.Errorf("snapshot %s belongs to no tool, run the tests with UPDATE_TOOLSNAPS=true to remove it", entry.Name())
+		}
+	}
+}